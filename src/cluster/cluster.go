@@ -0,0 +1,240 @@
+// Package cluster 实现多节点部署：coordinator 持有直播间列表并按节点分片，
+// worker 定期发送心跳并录制分配给自己的直播间，节点停止心跳后其直播间重新分配给其他节点
+package cluster
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/consts"
+	"github.com/bililive-go/bililive-go/src/instance"
+	"github.com/bililive-go/bililive-go/src/live"
+	"github.com/bililive-go/bililive-go/src/pkg/apiclient"
+	"github.com/bililive-go/bililive-go/src/types"
+)
+
+// ErrNotCoordinator 当前节点不是 coordinator，无法提供集群汇总信息
+var ErrNotCoordinator = errors.New("当前节点不是 coordinator")
+
+// LiveStatus 节点上报的直播间状态
+type LiveStatus struct {
+	URL       string `json:"url"`
+	LiveID    string `json:"live_id"`
+	Platform  string `json:"platform"`
+	HostName  string `json:"host_name"`
+	RoomName  string `json:"room_name"`
+	Living    bool   `json:"living"`
+	Listening bool   `json:"listening"`
+	Recording bool   `json:"recording"`
+}
+
+// NodeStatus 集群节点状态
+type NodeStatus struct {
+	ID            string       `json:"id"`
+	AdvertiseURL  string       `json:"advertise_url,omitempty"`
+	Version       string       `json:"version"`
+	Local         bool         `json:"local"`
+	Alive         bool         `json:"alive"`
+	LastSeen      time.Time    `json:"last_seen"`
+	AssignedCount int          `json:"assigned_count"`
+	Lives         []LiveStatus `json:"lives"`
+}
+
+// ClusterLive 集群汇总视图中的直播间
+type ClusterLive struct {
+	URL       string `json:"url"`
+	Listening bool   `json:"listening"`
+	// Node 负责该直播间的节点，未监控或没有可用节点时为空
+	Node      string      `json:"node"`
+	NodeAlive bool        `json:"node_alive"`
+	Status    *LiveStatus `json:"status,omitempty"`
+}
+
+// HeartbeatRequest worker 发送的心跳
+type HeartbeatRequest struct {
+	NodeID       string       `json:"node_id"`
+	AdvertiseURL string       `json:"advertise_url,omitempty"`
+	Version      string       `json:"version"`
+	Lives        []LiveStatus `json:"lives"`
+}
+
+// HeartbeatResponse coordinator 对心跳的响应
+type HeartbeatResponse struct {
+	// Assigned 分配给该节点的直播间
+	Assigned       []configs.LiveRoom `json:"assigned"`
+	NodeTimeoutSec int                `json:"node_timeout_sec"`
+}
+
+// Status 本节点的集群状态
+type Status struct {
+	Mode           string     `json:"mode"`
+	NodeID         string     `json:"node_id"`
+	CoordinatorURL string     `json:"coordinator_url,omitempty"`
+	LastHeartbeat  *time.Time `json:"last_heartbeat,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	AssignedCount  int        `json:"assigned_count"`
+}
+
+// Service 本节点的集群服务
+type Service struct {
+	ctx    context.Context
+	inst   *instance.Instance
+	cfg    configs.ClusterConfig
+	nodeID string
+
+	// coordinator 模式
+	coordinator *Coordinator
+
+	// worker 模式
+	client        *apiclient.Client
+	mu            sync.RWMutex
+	assigned      map[string]configs.LiveRoom // 分配给本节点的直播间，按地址索引
+	managed       map[string]types.LiveID     // 由集群添加到本节点的直播间，仅在 reconcileLoop 中访问
+	reconcileCh   chan struct{}
+	lastHeartbeat time.Time
+	lastError     string
+	nodeTimeout   time.Duration
+}
+
+var defaultService atomic.Pointer[Service]
+
+// Default 返回当前进程的集群服务，单机模式下为 nil
+func Default() *Service {
+	return defaultService.Load()
+}
+
+// CurrentStatus 返回本节点的集群状态，单机模式下 Mode 为空
+func CurrentStatus() Status {
+	if s := Default(); s != nil {
+		return s.Status()
+	}
+	return Status{Mode: configs.ClusterModeStandalone}
+}
+
+// NewService 根据集群配置创建服务并设为当前进程的集群服务
+func NewService(ctx context.Context, cfg configs.ClusterConfig) (*Service, error) {
+	nodeID := cfg.NodeID
+	if nodeID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, err
+		}
+		nodeID = hostname
+	}
+	s := &Service{
+		ctx:         ctx,
+		inst:        instance.GetInstance(ctx),
+		cfg:         cfg,
+		nodeID:      nodeID,
+		assigned:    make(map[string]configs.LiveRoom),
+		managed:     make(map[string]types.LiveID),
+		reconcileCh: make(chan struct{}, 1),
+		nodeTimeout: cfg.NodeTimeout(),
+	}
+	switch cfg.Mode {
+	case configs.ClusterModeCoordinator:
+		s.coordinator = NewCoordinator(nodeID, cfg.CoordinatorRecords, cfg.NodeTimeout())
+	case configs.ClusterModeWorker:
+		token, err := configs.LookupSecret(cfg.Token)
+		if err != nil {
+			return nil, err
+		}
+		s.client = apiclient.New(cfg.CoordinatorURL, token)
+	default:
+		return nil, errors.New("未启用集群模式")
+	}
+	defaultService.Store(s)
+	return s, nil
+}
+
+// Run 运行 coordinator 的分配循环或 worker 的心跳循环，直到 ctx 结束
+func (s *Service) Run(ctx context.Context) {
+	if s.coordinator != nil {
+		s.runCoordinator(ctx)
+	} else {
+		s.runWorker(ctx)
+	}
+}
+
+// OwnsLocally 直播间是否由本节点负责监控
+func (s *Service) OwnsLocally(l live.Live) bool {
+	if s.coordinator != nil {
+		return s.coordinator.NodeFor(l.GetRawUrl()) == s.nodeID
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.assigned[l.GetRawUrl()]
+	return ok
+}
+
+// Status 返回本节点的集群状态
+func (s *Service) Status() Status {
+	status := Status{
+		Mode:           s.cfg.Mode,
+		NodeID:         s.nodeID,
+		CoordinatorURL: s.cfg.CoordinatorURL,
+	}
+	if s.coordinator != nil {
+		for _, node := range s.coordinator.Assign(configs.GetCurrentConfig().LiveRooms) {
+			if node == s.nodeID {
+				status.AssignedCount++
+			}
+		}
+		return status
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !s.lastHeartbeat.IsZero() {
+		t := s.lastHeartbeat
+		status.LastHeartbeat = &t
+	}
+	status.LastError = s.lastError
+	status.AssignedCount = len(s.assigned)
+	return status
+}
+
+// HandleHeartbeat 处理 worker 心跳（仅 coordinator）
+func (s *Service) HandleHeartbeat(req HeartbeatRequest) (*HeartbeatResponse, error) {
+	if s.coordinator == nil {
+		return nil, ErrNotCoordinator
+	}
+	assigned, err := s.coordinator.Heartbeat(req, configs.GetCurrentConfig().LiveRooms)
+	if err != nil {
+		return nil, err
+	}
+	return &HeartbeatResponse{
+		Assigned:       assigned,
+		NodeTimeoutSec: s.cfg.NodeTimeoutSec,
+	}, nil
+}
+
+// Nodes 返回集群节点列表（仅 coordinator）
+func (s *Service) Nodes() ([]NodeStatus, error) {
+	if s.coordinator == nil {
+		return nil, ErrNotCoordinator
+	}
+	return s.coordinator.Nodes(s.selfStatus(), configs.GetCurrentConfig().LiveRooms), nil
+}
+
+// Lives 返回跨节点汇总的直播间列表（仅 coordinator）
+func (s *Service) Lives() ([]ClusterLive, error) {
+	if s.coordinator == nil {
+		return nil, ErrNotCoordinator
+	}
+	return s.coordinator.Lives(s.selfStatus(), configs.GetCurrentConfig().LiveRooms), nil
+}
+
+func (s *Service) selfStatus() NodeStatus {
+	return NodeStatus{
+		ID:           s.nodeID,
+		AdvertiseURL: s.cfg.AdvertiseURL,
+		Version:      consts.AppVersion,
+		LastSeen:     time.Now(),
+		Lives:        collectLocalLives(s.ctx, s.inst),
+	}
+}
@@ -0,0 +1,201 @@
+package cluster
+
+import (
+	"errors"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+)
+
+// staleNodeRetention 下线超过该时间的节点从节点列表中移除
+const staleNodeRetention = time.Hour
+
+// Coordinator 维护节点注册表，并把监控中的直播间分配给存活的节点。
+// 分配使用最高随机权重（rendezvous）哈希：节点上下线时只有该节点负责的直播间会迁移
+type Coordinator struct {
+	selfID      string
+	selfRecords bool
+	timeout     time.Duration
+	now         func() time.Time
+
+	mu    sync.RWMutex
+	nodes map[string]*NodeStatus // worker 节点，不含 coordinator 自身
+}
+
+// NewCoordinator 创建 coordinator，selfRecords 表示 coordinator 自身是否参与分配
+func NewCoordinator(selfID string, selfRecords bool, timeout time.Duration) *Coordinator {
+	return &Coordinator{
+		selfID:      selfID,
+		selfRecords: selfRecords,
+		timeout:     timeout,
+		now:         time.Now,
+		nodes:       make(map[string]*NodeStatus),
+	}
+}
+
+// Heartbeat 记录 worker 心跳，返回当前分配给该节点的直播间
+func (c *Coordinator) Heartbeat(req HeartbeatRequest, rooms []configs.LiveRoom) ([]configs.LiveRoom, error) {
+	if req.NodeID == "" {
+		return nil, errors.New("节点标识不能为空")
+	}
+	if req.NodeID == c.selfID {
+		return nil, errors.New("节点标识与 coordinator 重复: " + req.NodeID)
+	}
+
+	c.mu.Lock()
+	c.nodes[req.NodeID] = &NodeStatus{
+		ID:           req.NodeID,
+		AdvertiseURL: req.AdvertiseURL,
+		Version:      req.Version,
+		LastSeen:     c.now(),
+		Lives:        req.Lives,
+	}
+	candidates := c.candidatesLocked()
+	c.mu.Unlock()
+
+	assigned := make([]configs.LiveRoom, 0)
+	for _, room := range rooms {
+		if room.IsListening && pickNode(candidates, room.Url) == req.NodeID {
+			assigned = append(assigned, room)
+		}
+	}
+	return assigned, nil
+}
+
+// NodeFor 返回负责该直播间的节点，没有可用节点时返回空字符串
+func (c *Coordinator) NodeFor(url string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return pickNode(c.candidatesLocked(), url)
+}
+
+// Assign 计算所有监控中直播间的分配结果（直播间地址 -> 节点标识）
+func (c *Coordinator) Assign(rooms []configs.LiveRoom) map[string]string {
+	c.mu.RLock()
+	candidates := c.candidatesLocked()
+	c.mu.RUnlock()
+
+	result := make(map[string]string, len(rooms))
+	for _, room := range rooms {
+		if room.IsListening {
+			result[room.Url] = pickNode(candidates, room.Url)
+		}
+	}
+	return result
+}
+
+// Nodes 返回包含 coordinator 自身在内的节点列表，并统计各节点分配到的直播间数量
+func (c *Coordinator) Nodes(self NodeStatus, rooms []configs.LiveRoom) []NodeStatus {
+	assignment := c.Assign(rooms)
+	counts := make(map[string]int)
+	for _, node := range assignment {
+		counts[node]++
+	}
+
+	c.mu.Lock()
+	now := c.now()
+	nodes := make([]NodeStatus, 0, len(c.nodes)+1)
+	for id, node := range c.nodes {
+		if now.Sub(node.LastSeen) > staleNodeRetention {
+			delete(c.nodes, id)
+			continue
+		}
+		n := *node
+		n.Alive = c.aliveLocked(node, now)
+		n.AssignedCount = counts[id]
+		nodes = append(nodes, n)
+	}
+	c.mu.Unlock()
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	self.ID = c.selfID
+	self.Local = true
+	self.Alive = true
+	self.AssignedCount = counts[c.selfID]
+	return append([]NodeStatus{self}, nodes...)
+}
+
+// Lives 汇总所有节点上报的直播间状态，按配置中的直播间顺序返回
+func (c *Coordinator) Lives(self NodeStatus, rooms []configs.LiveRoom) []ClusterLive {
+	nodes := c.Nodes(self, rooms)
+	assignment := c.Assign(rooms)
+
+	alive := make(map[string]bool, len(nodes))
+	reported := make(map[string]map[string]LiveStatus, len(nodes))
+	for _, node := range nodes {
+		alive[node.ID] = node.Alive
+		lives := make(map[string]LiveStatus, len(node.Lives))
+		for _, l := range node.Lives {
+			lives[l.URL] = l
+		}
+		reported[node.ID] = lives
+	}
+
+	result := make([]ClusterLive, 0, len(rooms))
+	for _, room := range rooms {
+		cl := ClusterLive{URL: room.Url, Listening: room.IsListening}
+		if node := assignment[room.Url]; node != "" {
+			cl.Node = node
+			cl.NodeAlive = alive[node]
+			if status, ok := reported[node][room.Url]; ok {
+				cl.Status = &status
+			}
+		}
+		result = append(result, cl)
+	}
+	return result
+}
+
+// candidatesLocked 返回可参与分配的节点，调用方需持有锁
+func (c *Coordinator) candidatesLocked() []string {
+	now := c.now()
+	candidates := make([]string, 0, len(c.nodes)+1)
+	if c.selfRecords {
+		candidates = append(candidates, c.selfID)
+	}
+	for id, node := range c.nodes {
+		if c.aliveLocked(node, now) {
+			candidates = append(candidates, id)
+		}
+	}
+	return candidates
+}
+
+func (c *Coordinator) aliveLocked(node *NodeStatus, now time.Time) bool {
+	return now.Sub(node.LastSeen) <= c.timeout
+}
+
+// pickNode 选出对该直播间权重最高的节点
+func pickNode(candidates []string, url string) string {
+	var (
+		best      string
+		bestScore uint64
+	)
+	urlHash := hashString(url)
+	for _, id := range candidates {
+		score := mix64(hashString(id) ^ urlHash)
+		if best == "" || score > bestScore || (score == bestScore && id < best) {
+			best, bestScore = id, score
+		}
+	}
+	return best
+}
+
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// mix64 打散 FNV 哈希的高位，使各节点得到的权重分布均匀
+func mix64(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
@@ -0,0 +1,74 @@
+package cluster
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+)
+
+func TestCoordinatorShardingAndFailover(t *testing.T) {
+	now := time.Now()
+	c := NewCoordinator("main", true, 30*time.Second)
+	c.now = func() time.Time { return now }
+
+	rooms := make([]configs.LiveRoom, 0, 60)
+	for i := 0; i < 60; i++ {
+		rooms = append(rooms, configs.LiveRoom{Url: fmt.Sprintf("https://live.bilibili.com/%d", i), IsListening: true})
+	}
+	rooms = append(rooms, configs.LiveRoom{Url: "https://live.bilibili.com/stopped", IsListening: false})
+
+	assigned := func(node string) map[string]bool {
+		got, err := c.Heartbeat(HeartbeatRequest{NodeID: node}, rooms)
+		assert.NoError(t, err)
+		result := make(map[string]bool, len(got))
+		for _, room := range got {
+			result[room.Url] = true
+		}
+		return result
+	}
+
+	_, err := c.Heartbeat(HeartbeatRequest{NodeID: "main"}, rooms)
+	assert.Error(t, err)
+
+	// 每个监控中的直播间恰好分配给一个节点
+	assigned("a")
+	b, a := assigned("b"), assigned("a")
+	before := c.Assign(rooms)
+	assert.Len(t, before, 60)
+	counts := map[string]int{}
+	for url, node := range before {
+		counts[node]++
+		assert.Equal(t, node == "a", a[url])
+		assert.Equal(t, node == "b", b[url])
+	}
+	assert.Len(t, counts, 3)
+	assert.Equal(t, "", c.Assign(rooms)["https://live.bilibili.com/stopped"])
+
+	// 节点 a 停止心跳后，只有它负责的直播间迁移到其他节点
+	now = now.Add(20 * time.Second)
+	assigned("b")
+	now = now.Add(20 * time.Second)
+	after := c.Assign(rooms)
+	for url, node := range before {
+		if node == "a" {
+			assert.NotEqual(t, "a", after[url])
+		} else {
+			assert.Equal(t, node, after[url])
+		}
+	}
+	nodes := c.Nodes(NodeStatus{}, rooms)
+	if assert.Len(t, nodes, 3) {
+		assert.True(t, nodes[0].Local)
+		assert.False(t, nodes[1].Alive)
+		assert.Equal(t, 0, nodes[1].AssignedCount)
+		assert.Equal(t, 60, nodes[0].AssignedCount+nodes[2].AssignedCount)
+	}
+
+	// 节点恢复心跳后重新分配回来
+	assigned("a")
+	assert.Equal(t, before, c.Assign(rooms))
+}
@@ -0,0 +1,91 @@
+package cluster
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/instance"
+	"github.com/bililive-go/bililive-go/src/listeners"
+	"github.com/bililive-go/bililive-go/src/live"
+	applog "github.com/bililive-go/bililive-go/src/log"
+	"github.com/bililive-go/bililive-go/src/recorders"
+)
+
+// collectLocalLives 收集本节点所有直播间的状态
+func collectLocalLives(ctx context.Context, inst *instance.Instance) []LiveStatus {
+	lm, _ := inst.ListenerManager.(listeners.Manager)
+	rm, _ := inst.RecorderManager.(recorders.Manager)
+	lives := make([]LiveStatus, 0, inst.Lives.Len())
+	for id, l := range inst.Lives.Snapshot() {
+		status := LiveStatus{
+			URL:      l.GetRawUrl(),
+			LiveID:   string(id),
+			Platform: l.GetPlatformCNName(),
+		}
+		if lm != nil {
+			status.Listening = lm.HasListener(ctx, id)
+		}
+		if rm != nil {
+			status.Recording = rm.HasRecorder(ctx, id)
+		}
+		if obj, err := inst.Cache.Get(l); err == nil {
+			if info, ok := obj.(*live.Info); ok {
+				status.HostName = info.HostName
+				status.RoomName = info.RoomName
+				status.Living = info.Status
+			}
+		}
+		lives = append(lives, status)
+	}
+	sort.Slice(lives, func(i, j int) bool { return lives[i].URL < lives[j].URL })
+	return lives
+}
+
+// runCoordinator 定期按分配结果启停本机的 Listener：
+// 分配给其他节点的直播间停止监控，节点下线后分配回本机的直播间重新开始监控
+func (s *Service) runCoordinator(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.HeartbeatInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.reconcileCoordinator(ctx)
+		}
+	}
+}
+
+func (s *Service) reconcileCoordinator(ctx context.Context) {
+	lm, ok := s.inst.ListenerManager.(listeners.Manager)
+	if !ok {
+		return
+	}
+	logger := applog.GetLogger()
+	cfg := configs.GetCurrentConfig()
+	for id, l := range s.inst.Lives.Snapshot() {
+		room, err := cfg.GetLiveRoomByUrl(l.GetRawUrl())
+		if err != nil || !room.IsListening {
+			continue
+		}
+		owned := s.OwnsLocally(l)
+		hasListener := lm.HasListener(ctx, id)
+		switch {
+		case owned && !hasListener:
+			logger.WithField("url", room.Url).Info("直播间已分配到本节点，开始监控")
+			if err := lm.AddListener(ctx, l); err != nil {
+				logger.WithField("url", room.Url).WithError(err).Warn("开始监控失败")
+			}
+		case !owned && hasListener:
+			logger.WithFields(map[string]any{
+				"url":  room.Url,
+				"node": s.coordinator.NodeFor(room.Url),
+			}).Info("直播间已分配到其他节点，停止本机监控")
+			if err := lm.RemoveListener(ctx, id); err != nil {
+				logger.WithField("url", room.Url).WithError(err).Warn("停止监控失败")
+			}
+		}
+	}
+}
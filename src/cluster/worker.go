@@ -0,0 +1,159 @@
+package cluster
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/consts"
+	"github.com/bililive-go/bililive-go/src/listeners"
+	"github.com/bililive-go/bililive-go/src/live"
+	applog "github.com/bililive-go/bililive-go/src/log"
+	"github.com/bililive-go/bililive-go/src/pkg/cookiepool"
+	bilisentry "github.com/bililive-go/bililive-go/src/pkg/sentry"
+)
+
+// runWorker 心跳循环：上报本节点状态并记录 coordinator 的分配结果。
+// 创建直播间需要请求平台接口，耗时较长，因此在单独的 goroutine 中增删直播间，避免心跳超时
+func (s *Service) runWorker(ctx context.Context) {
+	bilisentry.GoWithContext(ctx, s.reconcileLoop)
+	ticker := time.NewTicker(s.cfg.HeartbeatInterval())
+	defer ticker.Stop()
+	s.heartbeat(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.heartbeat(ctx)
+		}
+	}
+}
+
+func (s *Service) reconcileLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.reconcileCh:
+			s.reconcileWorker(ctx)
+		}
+	}
+}
+
+// setAssigned 更新分配结果并通知 reconcileLoop
+func (s *Service) setAssigned(assigned []configs.LiveRoom) {
+	assignedMap := make(map[string]configs.LiveRoom, len(assigned))
+	for _, room := range assigned {
+		assignedMap[room.Url] = room
+	}
+	s.mu.Lock()
+	s.assigned = assignedMap
+	s.mu.Unlock()
+	select {
+	case s.reconcileCh <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Service) heartbeat(ctx context.Context) {
+	logger := applog.GetLogger()
+	req := HeartbeatRequest{
+		NodeID:       s.nodeID,
+		AdvertiseURL: s.cfg.AdvertiseURL,
+		Version:      consts.AppVersion,
+		Lives:        collectLocalLives(ctx, s.inst),
+	}
+	var resp HeartbeatResponse
+	if err := s.client.Do(ctx, http.MethodPost, "/api/cluster/heartbeat", req, &resp); err != nil {
+		s.mu.Lock()
+		s.lastError = err.Error()
+		// 超过节点超时时间仍未连上 coordinator 时，直播间已被重新分配，停止录制避免重复
+		expired := !s.lastHeartbeat.IsZero() && time.Since(s.lastHeartbeat) > s.nodeTimeout && len(s.assigned) > 0
+		s.mu.Unlock()
+		logger.WithError(err).Warn("发送集群心跳失败")
+		if expired {
+			logger.Warn("长时间未连接到 coordinator，停止录制所有分配的直播间")
+			s.setAssigned(nil)
+		}
+		return
+	}
+
+	s.mu.Lock()
+	s.lastHeartbeat = time.Now()
+	s.lastError = ""
+	if resp.NodeTimeoutSec > 0 {
+		s.nodeTimeout = time.Duration(resp.NodeTimeoutSec) * time.Second
+	}
+	s.mu.Unlock()
+	s.setAssigned(resp.Assigned)
+}
+
+// reconcileWorker 使本节点的直播间与分配结果一致。
+// 分配的直播间只写入内存中的配置，不持久化到本机配置文件
+func (s *Service) reconcileWorker(ctx context.Context) {
+	logger := applog.GetLogger()
+	lm, ok := s.inst.ListenerManager.(listeners.Manager)
+	if !ok {
+		return
+	}
+
+	s.mu.RLock()
+	assignedMap := s.assigned
+	s.mu.RUnlock()
+
+	// 移除不再分配给本节点的直播间
+	for url, id := range s.managed {
+		if _, ok := assignedMap[url]; ok {
+			continue
+		}
+		if lm.HasListener(ctx, id) {
+			if err := lm.RemoveListener(ctx, id); err != nil {
+				logger.WithField("url", url).WithError(err).Warn("停止监控失败")
+			}
+		}
+		s.inst.Lives.Delete(id)
+		cookiepool.Default().Release(id)
+		if _, err := configs.RemoveLiveRoomByUrlTransient(url); err != nil {
+			logger.WithField("url", url).WithError(err).Warn("移除直播间配置失败")
+		}
+		delete(s.managed, url)
+		logger.WithField("url", url).Info("直播间已分配到其他节点，停止监控")
+	}
+
+	// 添加新分配的直播间
+	for url, room := range assignedMap {
+		if ctx.Err() != nil {
+			return
+		}
+		id, ok := s.managed[url]
+		if !ok {
+			if _, err := configs.GetCurrentConfig().GetLiveRoomByUrl(url); err != nil {
+				room.IsListening = true
+				if _, err := configs.AppendLiveRoomTransient(room); err != nil {
+					logger.WithField("url", url).WithError(err).Warn("添加直播间配置失败")
+					continue
+				}
+			}
+			l, err := live.New(ctx, &room, s.inst.Cache)
+			if err != nil {
+				logger.WithField("url", url).WithError(err).Warn("创建直播间失败")
+				continue
+			}
+			id = l.GetLiveId()
+			if !s.inst.Lives.SetIfAbsent(id, l) {
+				logger.WithField("url", url).Warn("直播间已存在")
+				continue
+			}
+			configs.SetLiveRoomId(url, id)
+			s.managed[url] = id
+			logger.WithField("url", url).Info("直播间已分配到本节点，开始监控")
+		}
+		if l, ok := s.inst.Lives.Get(id); ok && !lm.HasListener(ctx, id) {
+			if err := lm.AddListener(ctx, l); err != nil {
+				logger.WithField("url", url).WithError(err).Warn("开始监控失败")
+			}
+		}
+	}
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -13,10 +15,15 @@ import (
 
 	"github.com/bluele/gcache"
 
+	"github.com/bililive-go/bililive-go/src/cluster"
 	_ "github.com/bililive-go/bililive-go/src/cmd/bililive/internal"
+	"github.com/bililive-go/bililive-go/src/cmd/bililive/internal/ctl"
 	"github.com/bililive-go/bililive-go/src/cmd/bililive/internal/flag"
+	"github.com/bililive-go/bililive-go/src/cmd/bililive/internal/record"
 	"github.com/bililive-go/bililive-go/src/configs"
 	"github.com/bililive-go/bililive-go/src/consts"
+	"github.com/bililive-go/bililive-go/src/eventbus"
+	"github.com/bililive-go/bililive-go/src/eventlog"
 	"github.com/bililive-go/bililive-go/src/instance"
 	"github.com/bililive-go/bililive-go/src/listeners"
 	"github.com/bililive-go/bililive-go/src/live"
@@ -25,6 +32,8 @@ import (
 	"github.com/bililive-go/bililive-go/src/metrics"
 	"github.com/bililive-go/bililive-go/src/pipeline"
 	"github.com/bililive-go/bililive-go/src/pipeline/stages"
+	"github.com/bililive-go/bililive-go/src/pkg/cookiekeeper"
+	"github.com/bililive-go/bililive-go/src/pkg/cookiepool"
 	"github.com/bililive-go/bililive-go/src/pkg/events"
 	"github.com/bililive-go/bililive-go/src/pkg/iostats"
 	"github.com/bililive-go/bililive-go/src/pkg/kliveproxy"
@@ -33,7 +42,9 @@ import (
 	"github.com/bililive-go/bililive-go/src/pkg/memwatch"
 	"github.com/bililive-go/bililive-go/src/pkg/metadata"
 	"github.com/bililive-go/bililive-go/src/pkg/openlist"
+	"github.com/bililive-go/bililive-go/src/pkg/proxy"
 	"github.com/bililive-go/bililive-go/src/pkg/ratelimit"
+	"github.com/bililive-go/bililive-go/src/pkg/secrets"
 	bilisentryPkg "github.com/bililive-go/bililive-go/src/pkg/sentry"
 	"github.com/bililive-go/bililive-go/src/pkg/telemetry"
 	"github.com/bililive-go/bililive-go/src/pkg/update"
@@ -192,6 +203,15 @@ func main() {
 	// 捕获主 goroutine 的 panic
 	defer bilisentryPkg.Recover()
 
+	// ctl 子命令只作为客户端访问正在运行的实例，不启动服务
+	if flag.IsCtl() {
+		os.Exit(ctl.Run())
+	}
+	// record 子命令单次录制后退出，不启动 Web 服务、数据库与 Launcher
+	if flag.IsRecord() {
+		os.Exit(record.Run())
+	}
+
 	// 如果提供了 --sync-built-in-tools-to-path，则进行同步（下载容器内置工具并清理其他版本/其他工具）后退出
 	if flag.SyncBuiltInToolsToPath != nil && *flag.SyncBuiltInToolsToPath != "" {
 		if err := tools.SyncBuiltInTools(*flag.SyncBuiltInToolsToPath); err != nil {
@@ -225,6 +245,11 @@ func main() {
 	}
 	defer metadata.Close()
 
+	// 初始化加密密钥库，配置中的 "secret://名称" 引用从这里解析
+	if err := secrets.Init(config.AppDataPath); err != nil {
+		fmt.Fprintf(os.Stderr, "警告: 密钥库初始化失败，配置中的密钥引用将无法解析: %v\n", err)
+	}
+
 	// 初始化 Sentry 错误监控
 	// DSN 来源优先级：编译时注入 > 环境变量 SENTRY_DSN
 	sentryDSN := SentryDSN
@@ -381,6 +406,8 @@ func main() {
 		if err := liveStateManager.Start(); err != nil {
 			logger.WithError(err).Warn("启动直播间状态管理器失败")
 		}
+		// 自适应检测间隔依赖会话历史
+		live.SetIntervalAdjuster(liveStateManager.AdaptiveInterval)
 	}
 
 	// 先初始化 manager（不启动），因为 server 依赖它们
@@ -454,6 +481,59 @@ func main() {
 		logger.Fatalf("failed to init metrics collector, error: %s", err)
 	}
 
+	// 设置请求状态追踪回调（从 live 包调用，避免循环依赖）
+	// IO 统计模块未启用时 TrackRequest* 为空操作，账号池的健康跟踪仍然生效
+	live.SetRequestStatusCallback(func(liveID, platform string, err error, anonymous bool) {
+		if err == nil {
+			iostats.TrackRequestSuccess(liveID, platform)
+		} else {
+			iostats.TrackRequestFailure(liveID, platform, err.Error())
+		}
+		// 匿名请求（批量查询）没有使用账号的 Cookie，不影响账号健康度
+		if anonymous {
+			return
+		}
+		// 账号触发风控或登录失效时，为受影响的直播间重新分配 Cookie
+		for _, id := range cookiepool.Default().ReportResult(types.LiveID(liveID), err) {
+			reapplyLiveOptions(ctx, inst, id)
+		}
+	})
+
+	// 定期校验 Cookie，哔哩哔哩 Cookie 即将过期时自动刷新，需要重新登录时发送通知
+	keeper := cookiekeeper.New(configs.GetCurrentConfig, func(host string) {
+		for id, l := range inst.Lives.Snapshot() {
+			if u, err := url.Parse(l.GetRawUrl()); err == nil && u.Host == host {
+				reapplyLiveOptions(ctx, inst, id)
+			}
+		}
+	})
+	bilisentryPkg.GoWithContext(ctx, keeper.Run)
+
+	// 定期检查代理池中代理的可用性，用于故障切换
+	bilisentryPkg.GoWithContext(ctx, proxy.StartHealthChecks)
+
+	// 对外事件流：内部事件转换为带版本号的消息，经 WebSocket（/api/events/ws）与可选的 MQTT 发布
+	eventbus.GetBus().Register(ed, inst.Cache)
+	bilisentryPkg.GoWithContext(ctx, func(ctx context.Context) {
+		eventbus.RunMQTTPublisher(ctx, eventbus.GetBus())
+	})
+	bilisentryPkg.GoWithContext(ctx, func(ctx context.Context) {
+		eventbus.RunDiskMonitor(ctx, ed)
+	})
+
+	// 事件日志：持久化记录事件，供 /api/events?since=<id> 回放
+	eventLogDbPath := filepath.Join(config.AppDataPath, "db", "events.db")
+	eventLogStore, err := eventlog.NewSQLiteStore(eventLogDbPath)
+	if err != nil {
+		logger.WithError(err).Warn("初始化事件日志数据库失败，事件回放功能将不可用")
+	} else {
+		inst.EventLogStore = eventLogStore
+		eventlog.NewLogger(eventLogStore, inst.Cache).Register(ed)
+		bilisentryPkg.GoWithContext(ctx, func(ctx context.Context) {
+			eventlog.RunCleanup(ctx, eventLogStore)
+		})
+	}
+
 	// 初始化 IO 统计模块
 	iostatsConfig := iostats.DefaultConfig()
 	if iostatsModule, err := iostats.NewModule(ctx, iostatsConfig); err != nil {
@@ -464,15 +544,6 @@ func main() {
 			logger.WithError(err).Warn("启动 IO 统计模块失败")
 		}
 
-		// 设置请求状态追踪回调（从 live 包调用，避免循环依赖）
-		live.SetRequestStatusCallback(func(liveID, platform string, success bool, errMsg string) {
-			if success {
-				iostats.TrackRequestSuccess(liveID, platform)
-			} else {
-				iostats.TrackRequestFailure(liveID, platform, errMsg)
-			}
-		})
-
 		// 设置录制器状态提供者（用于收集录制写入速度）
 		iostats.SetRecorderStatusProvider(func() []iostats.RecorderStatus {
 			if inst.RecorderManager == nil {
@@ -527,12 +598,31 @@ func main() {
 	memWatcher.Start()
 	servers.SetMemoryWatcher(memWatcher)
 
+	// 多节点集群：在添加 Listener 之前设置归属检查，避免监控分配给其他节点的直播间
+	if config.Cluster.Mode != configs.ClusterModeStandalone {
+		clusterService, err := cluster.NewService(ctx, config.Cluster)
+		if err != nil {
+			logger.WithError(err).Fatalf("failed to init cluster")
+		}
+		listeners.SetOwnershipChecker(clusterService.OwnsLocally)
+		bilisentryPkg.GoWithContext(ctx, clusterService.Run)
+		logger.Infof("集群模式: %s", config.Cluster.Mode)
+	}
+
 	// 初始化 live rooms
 	// 第一步：立即为所有配置的直播间创建 InitializingLive，让前端可以看到
 	cfg := configs.GetCurrentConfig()
+	liveRooms := cfg.LiveRooms
+	if cfg.Cluster.Mode == configs.ClusterModeWorker {
+		// worker 的直播间由 coordinator 分配，本机配置的直播间不生效
+		if len(liveRooms) > 0 {
+			logger.Warnf("worker 模式下忽略本机配置的 %d 个直播间", len(liveRooms))
+		}
+		liveRooms = nil
+	}
 
 	// 确保所有平台都有最小访问限制（用于控制并行初始化时的请求速度）
-	for _, room := range cfg.LiveRooms {
+	for _, room := range liveRooms {
 		platformKey := configs.GetPlatformKeyFromUrl(room.Url)
 		if platformKey != "" {
 			minInterval := cfg.GetPlatformMinAccessInterval(platformKey)
@@ -555,8 +645,8 @@ func main() {
 		}))
 	}
 
-	for index := range cfg.LiveRooms {
-		room := cfg.LiveRooms[index]
+	for index := range liveRooms {
+		room := liveRooms[index]
 
 		// 先创建 InitializingLive，状态为初始化中，让前端立即可见
 		// 传入回调函数，当 GetInfo() 成功时会自动触发事件
@@ -611,7 +701,7 @@ func main() {
 
 	// 优先为监听中的直播间添加 Listener（它们会自动调用 GetInfo）
 	for _, l := range listeningRooms {
-		if err := lm.AddListener(ctx, l); err != nil {
+		if err := lm.AddListener(ctx, l); err != nil && !errors.Is(err, listeners.ErrLiveNotOwned) {
 			logger.WithFields(map[string]any{"url": l.GetRawUrl()}).Error(err)
 		}
 	}
@@ -707,6 +797,12 @@ func main() {
 		if liveStateManager != nil {
 			liveStateManager.Close()
 		}
+		// 关闭事件日志
+		if eventLogStore != nil {
+			if err := eventLogStore.Close(); err != nil {
+				logger.WithError(err).Warn("关闭事件日志数据库失败")
+			}
+		}
 		// 停止内存监控器
 		memWatcher.Stop()
 		// 关闭 IO 统计模块
@@ -758,3 +854,18 @@ func main() {
 
 	logger.Info("Bye~")
 }
+
+// reapplyLiveOptions 按当前配置重新应用直播间的请求选项（包括账号池分配的 Cookie）
+func reapplyLiveOptions(ctx context.Context, inst *instance.Instance, liveID types.LiveID) {
+	l, ok := inst.Lives.Get(liveID)
+	if !ok || l == nil {
+		return
+	}
+	room, err := configs.GetCurrentConfig().GetLiveRoomByUrl(l.GetRawUrl())
+	if err != nil {
+		return
+	}
+	if err := l.UpdateLiveOptionsbyConfig(ctx, room); err != nil {
+		l.GetLogger().WithError(err).Warn("重新应用直播间 Cookie 失败")
+	}
+}
@@ -0,0 +1,179 @@
+package ctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bililive-go/bililive-go/src/pkg/apiclient"
+)
+
+func (r *runner) table(header ...string) *tabwriter.Writer {
+	w := tabwriter.NewWriter(r.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	return w
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "是"
+	}
+	return "否"
+}
+
+func formatUnix(sec int64) string {
+	if sec <= 0 {
+		return "-"
+	}
+	return time.Unix(sec, 0).Format("2006-01-02 15:04:05")
+}
+
+func (r *runner) livesList(ctx context.Context) error {
+	lives, err := r.client.ListLives(ctx)
+	if err != nil {
+		return err
+	}
+	if r.json {
+		return r.printJSON(lives)
+	}
+	w := r.table("ID", "平台", "主播", "直播中", "监控", "录制", "链接")
+	for _, l := range lives {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			l.Id, l.PlatformCNName, l.HostName, yesNo(l.Status), yesNo(l.Listening), yesNo(l.Recording), l.LiveUrl)
+	}
+	return w.Flush()
+}
+
+func (r *runner) livesAdd(ctx context.Context, urls []string, listen bool) error {
+	var errs []error
+	for _, u := range urls {
+		info, err := r.client.AddLive(ctx, u, listen)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		r.printf("已添加 %s（ID: %s）\n", info.LiveUrl, info.Id)
+	}
+	return errors.Join(errs...)
+}
+
+// eachLive 对每个直播间（ID 或链接）执行操作，全部执行完后汇总错误
+func (r *runner) eachLive(ctx context.Context, rooms []string, done string, fn func(context.Context, string) error) error {
+	var errs []error
+	for _, room := range rooms {
+		id, err := r.client.ResolveLiveID(ctx, room)
+		if err == nil {
+			err = fn(ctx, id)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", room, err))
+			continue
+		}
+		r.printf("%s %s\n", done, room)
+	}
+	return errors.Join(errs...)
+}
+
+func (r *runner) configGet(ctx context.Context, key string) error {
+	if key == "" {
+		if r.json {
+			cfg, err := r.client.GetConfig(ctx)
+			if err != nil {
+				return err
+			}
+			return r.printJSON(cfg)
+		}
+		raw, err := r.client.GetRawConfig(ctx)
+		if err != nil {
+			return err
+		}
+		r.printf("%s", raw)
+		return nil
+	}
+	cfg, err := r.client.GetConfig(ctx)
+	if err != nil {
+		return err
+	}
+	v, ok := lookupPath(cfg, key)
+	if !ok {
+		return fmt.Errorf("配置项不存在: %s", key)
+	}
+	if s, ok := v.(string); ok && !r.json {
+		r.printf("%s\n", s)
+		return nil
+	}
+	return r.printJSON(v)
+}
+
+func (r *runner) configSet(ctx context.Context, key, value string) error {
+	result, err := r.client.PatchConfig(ctx, buildPatch(key, parseValue(value)))
+	if err != nil {
+		return err
+	}
+	// 未识别的键已被服务端拒绝；此处兜底空对象等未应用任何键的情况
+	if len(result.Applied) == 0 {
+		return fmt.Errorf("配置项未被应用，请检查键名和值的类型: %s", key)
+	}
+	for _, k := range result.Applied {
+		r.printf("已更新 %s\n", k)
+	}
+	return nil
+}
+
+func (r *runner) tasksList(ctx context.Context, filter apiclient.TaskFilter) error {
+	tasks, err := r.client.ListTasks(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if r.json {
+		return r.printJSON(tasks)
+	}
+	w := r.table("ID", "状态", "进度", "直播间", "主播", "创建时间", "错误")
+	for _, t := range tasks {
+		fmt.Fprintf(w, "%d\t%s\t%d%%\t%s\t%s\t%s\t%s\n",
+			t.ID, t.Status, t.Progress, t.RecordInfo.LiveID, t.RecordInfo.HostName,
+			t.CreatedAt.Local().Format("2006-01-02 15:04:05"), t.ErrorMessage)
+	}
+	return w.Flush()
+}
+
+func (r *runner) tasksRetry(ctx context.Context, ids []int64) error {
+	var errs []error
+	for _, id := range ids {
+		if err := r.client.RetryTask(ctx, id); err != nil {
+			errs = append(errs, fmt.Errorf("任务 %d: %w", id, err))
+			continue
+		}
+		r.printf("已重试任务 %d\n", id)
+	}
+	return errors.Join(errs...)
+}
+
+func (r *runner) filesSearch(ctx context.Context, query string, limit int) error {
+	resp, err := r.client.SearchFiles(ctx, query, limit)
+	if err != nil {
+		return err
+	}
+	if r.json {
+		return r.printJSON(resp)
+	}
+	w := r.table("大小", "修改时间", "路径")
+	for _, f := range resp.Files {
+		size := strconv.FormatInt(f.Size, 10)
+		if f.IsFolder {
+			size = "<目录>"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", size, formatUnix(f.LastModified), f.Path)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if resp.Truncated {
+		r.printf("结果已截断，仅显示前 %d 条，可通过 --limit 调整\n", len(resp.Files))
+	}
+	return nil
+}
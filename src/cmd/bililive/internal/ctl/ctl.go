@@ -0,0 +1,163 @@
+// Package ctl 实现 bililive ctl 子命令：通过 HTTP API 控制正在运行的实例
+package ctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/bililive-go/bililive-go/src/cmd/bililive/internal/flag"
+	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/pkg/apiclient"
+)
+
+const defaultServer = "http://127.0.0.1:8080"
+
+// runner 执行一个 ctl 子命令
+type runner struct {
+	client *apiclient.Client
+	out    io.Writer
+	json   bool
+}
+
+// Run 执行 flag 解析得到的 ctl 子命令，返回进程退出码
+func Run() int {
+	client, err := newClient()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	r := &runner{client: client, out: os.Stdout, json: *flag.CtlJSON}
+	if err := r.run(ctx, flag.Command); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func (r *runner) run(ctx context.Context, command string) error {
+	switch command {
+	case flag.CtlLivesList:
+		return r.livesList(ctx)
+	case flag.CtlLivesAdd:
+		return r.livesAdd(ctx, *flag.CtlLivesAddURLs, !*flag.CtlLivesAddNoListen)
+	case flag.CtlLivesRemove:
+		return r.eachLive(ctx, *flag.CtlLivesRemoveRooms, "已删除", r.client.RemoveLive)
+	case flag.CtlLivesStart:
+		return r.eachLive(ctx, *flag.CtlLivesStartRooms, "已开始监控", r.client.StartLive)
+	case flag.CtlLivesStop:
+		return r.eachLive(ctx, *flag.CtlLivesStopRooms, "已停止监控", r.client.StopLive)
+	case flag.CtlConfigGet:
+		return r.configGet(ctx, *flag.CtlConfigGetKey)
+	case flag.CtlConfigSet:
+		return r.configSet(ctx, *flag.CtlConfigSetKey, *flag.CtlConfigSetValue)
+	case flag.CtlTasksList:
+		return r.tasksList(ctx, apiclient.TaskFilter{
+			Status: *flag.CtlTasksListStatus,
+			LiveID: *flag.CtlTasksListLive,
+			Limit:  *flag.CtlTasksListLimit,
+		})
+	case flag.CtlTasksRetry:
+		return r.tasksRetry(ctx, *flag.CtlTasksRetryIDs)
+	case flag.CtlFilesSearch:
+		return r.filesSearch(ctx, *flag.CtlFilesSearchQuery, *flag.CtlFilesSearchLimit)
+	default:
+		return fmt.Errorf("未知的命令: %s", command)
+	}
+}
+
+// newClient 根据命令行参数创建客户端。未指定 --server 或 --token 时，
+// 若通过 --config 指定了配置文件，则从其中的 rpc.bind 与 rpc.token 推断
+func newClient() (*apiclient.Client, error) {
+	server, token := *flag.CtlServer, *flag.CtlToken
+	if *flag.Conf != "" && (server == "" || token == "") {
+		// 只读取配置文件，不像服务端那样补全字段后写回
+		b, err := os.ReadFile(*flag.Conf)
+		if err != nil {
+			return nil, fmt.Errorf("读取配置文件失败: %w", err)
+		}
+		cfg, err := configs.NewConfigWithBytes(b)
+		if err != nil {
+			return nil, fmt.Errorf("解析配置文件失败: %w", err)
+		}
+		if server == "" {
+			server = serverFromBind(cfg.RPC.Bind)
+		}
+		if token == "" {
+			if configs.SecretRefName(cfg.RPC.Token) != "" {
+				return nil, fmt.Errorf("rpc.token 引用了密钥库（%s），请通过 --token 或 BILILIVE_TOKEN 提供令牌", cfg.RPC.Token)
+			}
+			token = cfg.RPC.Token
+		}
+	}
+	if server == "" {
+		server = defaultServer
+	}
+	return apiclient.New(server, token), nil
+}
+
+// serverFromBind 把 rpc.bind 转换为本机可访问的地址，监听所有地址时使用回环地址
+func serverFromBind(bind string) string {
+	host, port, err := net.SplitHostPort(bind)
+	if err != nil {
+		return defaultServer
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+	return "http://" + net.JoinHostPort(host, port)
+}
+
+// printJSON 以缩进格式输出原始数据，用于 --json 与 config get
+func (r *runner) printJSON(v any) error {
+	enc := json.NewEncoder(r.out)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
+
+func (r *runner) printf(format string, args ...any) {
+	fmt.Fprintf(r.out, format, args...)
+}
+
+// lookupPath 按点分隔的路径取出嵌套对象中的值
+func lookupPath(v any, key string) (any, bool) {
+	for _, part := range strings.Split(key, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// buildPatch 把点分隔的路径与值转换为嵌套对象，如 a.b=1 转换为 {"a": {"b": 1}}
+func buildPatch(key string, value any) map[string]any {
+	parts := strings.Split(key, ".")
+	patch := map[string]any{parts[len(parts)-1]: value}
+	for i := len(parts) - 2; i >= 0; i-- {
+		patch = map[string]any{parts[i]: patch}
+	}
+	return patch
+}
+
+// parseValue 按 JSON 解析命令行传入的值（数字、布尔值、对象等），失败时作为字符串
+func parseValue(s string) any {
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err == nil {
+		return v
+	}
+	return s
+}
@@ -0,0 +1,52 @@
+package flag
+
+// ctl 子命令：通过 API 控制正在运行的实例，如 bililive ctl lives list
+var (
+	ctlCmd    = app.Command("ctl", "Control a running instance through its HTTP API.")
+	CtlServer = ctlCmd.Flag("server", "Address of the running instance (default: derived from --config, or http://127.0.0.1:8080).").Envar("BILILIVE_SERVER").String()
+	CtlToken  = ctlCmd.Flag("token", "API token (rpc.token of the running instance).").Envar("BILILIVE_TOKEN").String()
+	CtlJSON   = ctlCmd.Flag("json", "Print raw JSON instead of tables.").Default("false").Bool()
+
+	ctlLivesCmd         = ctlCmd.Command("lives", "Manage live rooms.")
+	ctlLivesListCmd     = ctlLivesCmd.Command("list", "List live rooms.").Alias("ls")
+	ctlLivesAddCmd      = ctlLivesCmd.Command("add", "Add live rooms.")
+	CtlLivesAddURLs     = ctlLivesAddCmd.Arg("url", "Live room URLs.").Required().Strings()
+	CtlLivesAddNoListen = ctlLivesAddCmd.Flag("no-listen", "Add without starting to monitor.").Default("false").Bool()
+	ctlLivesRemoveCmd   = ctlLivesCmd.Command("remove", "Remove live rooms.").Alias("rm")
+	CtlLivesRemoveRooms = ctlLivesRemoveCmd.Arg("room", "Live room IDs or URLs.").Required().Strings()
+	ctlLivesStartCmd    = ctlLivesCmd.Command("start", "Start monitoring live rooms.")
+	CtlLivesStartRooms  = ctlLivesStartCmd.Arg("room", "Live room IDs or URLs.").Required().Strings()
+	ctlLivesStopCmd     = ctlLivesCmd.Command("stop", "Stop monitoring live rooms.")
+	CtlLivesStopRooms   = ctlLivesStopCmd.Arg("room", "Live room IDs or URLs.").Required().Strings()
+	ctlConfigCmd        = ctlCmd.Command("config", "Read or update the configuration.")
+	ctlConfigGetCmd     = ctlConfigCmd.Command("get", "Print the configuration, or a single dotted key such as rpc.bind.")
+	CtlConfigGetKey     = ctlConfigGetCmd.Arg("key", "Dotted configuration key.").String()
+	ctlConfigSetCmd     = ctlConfigCmd.Command("set", "Update a single dotted configuration key. The value is parsed as JSON, falling back to a string.")
+	CtlConfigSetKey     = ctlConfigSetCmd.Arg("key", "Dotted configuration key, such as interval or notify.telegram.enable.").Required().String()
+	CtlConfigSetValue   = ctlConfigSetCmd.Arg("value", "New value.").Required().String()
+	ctlTasksCmd         = ctlCmd.Command("tasks", "Manage post-processing tasks.")
+	ctlTasksListCmd     = ctlTasksCmd.Command("list", "List post-processing tasks.").Alias("ls")
+	CtlTasksListStatus  = ctlTasksListCmd.Flag("status", "Only show tasks in this status (pending, running, completed, failed, cancelled).").String()
+	CtlTasksListLive    = ctlTasksListCmd.Flag("live", "Only show tasks of this live room ID.").String()
+	CtlTasksListLimit   = ctlTasksListCmd.Flag("limit", "Maximum number of tasks.").Default("50").Int()
+	ctlTasksRetryCmd    = ctlTasksCmd.Command("retry", "Retry failed post-processing tasks.")
+	CtlTasksRetryIDs    = ctlTasksRetryCmd.Arg("id", "Task IDs.").Required().Int64List()
+	ctlFilesCmd         = ctlCmd.Command("files", "Browse recorded files.")
+	ctlFilesSearchCmd   = ctlFilesCmd.Command("search", "Search recorded files by name.")
+	CtlFilesSearchQuery = ctlFilesSearchCmd.Arg("query", "Case-insensitive part of the file name.").Required().String()
+	CtlFilesSearchLimit = ctlFilesSearchCmd.Flag("limit", "Maximum number of results.").Default("100").Int()
+)
+
+// ctl 子命令的完整名称，与 Command 比较
+var (
+	CtlLivesList   = ctlLivesListCmd.FullCommand()
+	CtlLivesAdd    = ctlLivesAddCmd.FullCommand()
+	CtlLivesRemove = ctlLivesRemoveCmd.FullCommand()
+	CtlLivesStart  = ctlLivesStartCmd.FullCommand()
+	CtlLivesStop   = ctlLivesStopCmd.FullCommand()
+	CtlConfigGet   = ctlConfigGetCmd.FullCommand()
+	CtlConfigSet   = ctlConfigSetCmd.FullCommand()
+	CtlTasksList   = ctlTasksListCmd.FullCommand()
+	CtlTasksRetry  = ctlTasksRetryCmd.FullCommand()
+	CtlFilesSearch = ctlFilesSearchCmd.FullCommand()
+)
@@ -3,6 +3,7 @@ package flag
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/alecthomas/kingpin"
@@ -44,10 +45,21 @@ var (
 	SyncBuiltInToolsToPath = app.Flag("sync-built-in-tools-to-path", "Sync built-in tools into the target folder (remove others), then exit.").Default("").String()
 	// 跳过 Launcher 检查，强制使用当前二进制运行（用于本地开发调试，等同于设置 BILILIVE_LAUNCHER=1 环境变量）
 	NoLauncher = app.Flag("no-launcher", "跳过 Launcher 版本检查，直接运行当前编译的版本（开发调试用）").Default("false").Bool()
+
+	// 未指定子命令时启动录制服务
+	serverCmd = app.Command("server", "Start the recording server (default).").Default()
 )
 
+// Command 解析得到的子命令全名，如 "server"、"ctl lives list"
+var Command string
+
 func init() {
-	kingpin.MustParse(app.Parse(os.Args[1:]))
+	Command = kingpin.MustParse(app.Parse(os.Args[1:]))
+}
+
+// IsCtl 当前命令是否为 ctl 子命令
+func IsCtl() bool {
+	return strings.HasPrefix(Command, ctlCmd.FullCommand()+" ")
 }
 
 // GenConfigFromFlags generates configuration by parsing command line parameters.
@@ -0,0 +1,18 @@
+package flag
+
+// record 子命令：不启动服务，单次录制一个直播间后退出，如 bililive record <url> -o out.flv
+var (
+	recordCmd         = app.Command("record", "Record a single live stream once and exit, without starting the server. --output may be a file or a folder.")
+	RecordURL         = recordCmd.Arg("url", "Live room URL.").Required().String()
+	RecordUntilEnd    = recordCmd.Flag("until-end", "Reconnect after interruptions until the live ends, instead of exiting when the stream drops.").Default("false").Bool()
+	RecordQuality     = recordCmd.Flag("quality", "Preferred stream quality, e.g. 原画 or 1080p (see --list-streams).").String()
+	RecordAttributes  = recordCmd.Flag("attr", "Preferred stream attribute, e.g. --attr format=flv (repeatable).").StringMap()
+	RecordDownloader  = recordCmd.Flag("downloader", "Downloader to use: ffmpeg, native or bililive-recorder (default: from config).").Enum("ffmpeg", "native", "bililive-recorder")
+	RecordPipeline    = recordCmd.Flag("pipeline", "Post-processing after recording: comma separated stage names such as fix_flv,convert_mp4, or \"config\" to use the configured pipeline.").String()
+	RecordListStreams = recordCmd.Flag("list-streams", "List available streams and exit.").Default("false").Bool()
+)
+
+// IsRecord 当前命令是否为 record 子命令
+func IsRecord() bool {
+	return Command == recordCmd.FullCommand()
+}
@@ -0,0 +1,355 @@
+// Package record 实现 bililive record 子命令：不启动 Web 服务、数据库与 Launcher，
+// 单次录制一个直播间，可选执行后处理管道后退出
+package record
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bluele/gcache"
+	"github.com/sirupsen/logrus"
+
+	"github.com/bililive-go/bililive-go/src/cmd/bililive/internal/flag"
+	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/instance"
+	"github.com/bililive-go/bililive-go/src/live"
+	"github.com/bililive-go/bililive-go/src/pipeline"
+	"github.com/bililive-go/bililive-go/src/pipeline/stages"
+	"github.com/bililive-go/bililive-go/src/pkg/livelogger"
+	"github.com/bililive-go/bililive-go/src/pkg/proxy"
+	"github.com/bililive-go/bililive-go/src/pkg/secrets"
+	"github.com/bililive-go/bililive-go/src/pkg/utils"
+	"github.com/bililive-go/bililive-go/src/recorders"
+)
+
+// reconnectInterval --until-end 模式下断流后重新获取流地址的间隔
+const reconnectInterval = 5 * time.Second
+
+// Run 执行 record 子命令，返回进程退出码
+func Run() int {
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	configs.SetCurrentConfig(cfg)
+	if cfg.Debug {
+		logrus.SetLevel(logrus.DebugLevel)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	inst := &instance.Instance{Cache: gcache.New(16).LRU().Build()}
+	ctx = context.WithValue(ctx, instance.Key, inst)
+	inst.Ctx = ctx
+
+	if err := run(ctx, cfg, inst); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// loadConfig 读取 --config 指定的配置文件（只读取，不写回），未指定时由命令行参数生成配置
+func loadConfig() (*configs.Config, error) {
+	if *flag.Conf == "" {
+		return flag.GenConfigFromFlags(), nil
+	}
+	b, err := os.ReadFile(*flag.Conf)
+	if err != nil {
+		return nil, fmt.Errorf("读取配置文件失败: %w", err)
+	}
+	cfg, err := configs.NewConfigWithBytes(b)
+	if err != nil {
+		return nil, fmt.Errorf("解析配置文件失败: %w", err)
+	}
+	// Cookie 等字段可能引用密钥库
+	if err := secrets.Init(cfg.AppDataPath); err != nil {
+		logrus.WithError(err).Warn("密钥库初始化失败，配置中的密钥引用将无法解析")
+	}
+	return cfg, nil
+}
+
+func run(ctx context.Context, cfg *configs.Config, inst *instance.Instance) error {
+	roomURL := strings.TrimSpace(*flag.RecordURL)
+	if !strings.HasPrefix(roomURL, "http://") && !strings.HasPrefix(roomURL, "https://") {
+		roomURL = "https://" + roomURL
+	}
+	room, err := cfg.GetLiveRoomByUrl(roomURL)
+	if err != nil {
+		room = &configs.LiveRoom{Url: roomURL, IsListening: true}
+	}
+	resolvedConfig := cfg.ResolveConfigForRoom(room, configs.GetPlatformKeyFromUrl(roomURL))
+
+	l, err := live.New(ctx, room, inst.Cache)
+	if err != nil {
+		return fmt.Errorf("无法解析直播间 %s: %w", roomURL, err)
+	}
+	info, err := l.GetInfo()
+	if err != nil {
+		return fmt.Errorf("获取直播间信息失败: %w", err)
+	}
+	if !info.Status {
+		if info.HostName == "" {
+			return fmt.Errorf("%s 未开播或无法获取直播间信息", roomURL)
+		}
+		return fmt.Errorf("%s（%s）未开播", info.HostName, roomURL)
+	}
+
+	logger := livelogger.New(livelogger.DefaultBufferSize, logrus.Fields{
+		"host": info.HostName,
+		"room": info.RoomName,
+	})
+
+	if *flag.RecordListStreams {
+		streamInfos, err := getStreamInfos(l)
+		if err != nil {
+			return err
+		}
+		return printStreams(streamInfos)
+	}
+
+	var files []string
+	for part := 0; ; part++ {
+		file, err := recordOnce(ctx, cfg, resolvedConfig, l, info, logger, part)
+		if file != "" {
+			files = append(files, file)
+		}
+		if ctx.Err() != nil || !*flag.RecordUntilEnd {
+			if err != nil && ctx.Err() == nil && len(files) == 0 {
+				return err
+			}
+			break
+		}
+		if err != nil {
+			logger.WithError(err).Warn("录制中断")
+		}
+		// 断流后确认是否已下播，仍在直播则重新连接
+		if info, err = l.GetInfo(); err == nil && !info.Status {
+			logger.Info("直播已结束")
+			break
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(reconnectInterval):
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if len(files) == 0 {
+		return errors.New("没有录制到任何内容")
+	}
+	for _, f := range files {
+		fmt.Println(f)
+	}
+	if *flag.RecordPipeline == "" {
+		return nil
+	}
+	// Ctrl+C 结束录制后仍然执行后处理，再次中断才取消
+	pipelineCtx, stop := signal.NotifyContext(context.WithoutCancel(ctx), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return runPipeline(pipelineCtx, resolvedConfig, info, files)
+}
+
+// recordOnce 获取流地址并录制到断流为止，返回非空的录制文件
+func recordOnce(ctx context.Context, cfg *configs.Config, resolvedConfig configs.ResolvedConfig, l live.Live, info *live.Info, logger *livelogger.LiveLogger, part int) (string, error) {
+	streamInfos, err := getStreamInfos(l)
+	if err != nil {
+		return "", err
+	}
+	streamInfo, matched := recorders.SelectPreferredStream(streamInfos, streamPreference(resolvedConfig))
+	if !matched {
+		logger.Warn("没有流匹配指定的偏好，使用第一个可用流")
+	}
+	logger.Infof("录制流: 清晰度=%s, 格式=%s", streamInfo.Quality, streamInfo.Format)
+
+	fileName, err := outputFileName(cfg, resolvedConfig, info, streamInfo.Url, part)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(fileName), os.ModePerm); err != nil {
+		return "", err
+	}
+
+	parserCfg := map[string]string{
+		"timeout_in_us": strconv.Itoa(resolvedConfig.TimeoutInUs),
+		"audio_only":    strconv.FormatBool(info.AudioOnly),
+	}
+	if downloadProxy := proxy.GetDownloadProxyURLForRoom(l.GetRawUrl()); downloadProxy != "" {
+		parserCfg["proxy_url"] = downloadProxy
+	}
+	downloaderType := resolvedConfig.Feature.GetEffectiveDownloaderType()
+	if *flag.RecordDownloader != "" {
+		downloaderType = configs.DownloaderType(*flag.RecordDownloader)
+	}
+	p, err := recorders.NewParser(streamInfo.Url, downloaderType, parserCfg, logger)
+	if err != nil {
+		return "", fmt.Errorf("创建下载器失败: %w", err)
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			p.Stop()
+		case <-done:
+		}
+	}()
+
+	logger.Infof("开始录制: %s", fileName)
+	err = p.ParseLiveStream(ctx, streamInfo, l, fileName)
+	if stat, statErr := os.Stat(fileName); statErr != nil || stat.Size() == 0 {
+		os.Remove(fileName)
+		return "", err
+	}
+	logger.Infof("录制结束: %s", fileName)
+	return fileName, err
+}
+
+func getStreamInfos(l live.Live) ([]*live.StreamUrlInfo, error) {
+	streamInfos, err := l.GetStreamInfos()
+	if err == live.ErrNotImplemented {
+		var urls []*url.URL
+		// TODO: remove deprecated method GetStreamUrls
+		//nolint:staticcheck
+		if urls, err = l.GetStreamUrls(); err == nil {
+			streamInfos = utils.GenUrlInfos(urls, make(map[string]string))
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("获取流地址失败: %w", err)
+	}
+	if len(streamInfos) == 0 {
+		return nil, errors.New("没有可用的流")
+	}
+	return streamInfos, nil
+}
+
+// streamPreference 命令行指定的清晰度与属性覆盖配置中的流偏好
+func streamPreference(resolvedConfig configs.ResolvedConfig) configs.StreamPreference {
+	pref := resolvedConfig.StreamPreference
+	if *flag.RecordQuality != "" {
+		pref.Quality = flag.RecordQuality
+	}
+	if len(*flag.RecordAttributes) > 0 {
+		pref.Attributes = flag.RecordAttributes
+	}
+	return pref
+}
+
+// outputFileName 确定录制文件路径：--output 指向文件时直接使用（--until-end 续录的文件追加序号），
+// 指向目录时按输出模板在该目录下生成
+func outputFileName(cfg *configs.Config, resolvedConfig configs.ResolvedConfig, info *live.Info, streamURL *url.URL, part int) (string, error) {
+	output := *flag.Output
+	if isOutputFile(output) {
+		if part == 0 {
+			return output, nil
+		}
+		ext := filepath.Ext(output)
+		return fmt.Sprintf("%s_%d%s", strings.TrimSuffix(output, ext), part, ext), nil
+	}
+	// 未使用配置文件时 --output 已作为 out_put_path；使用配置文件时，显式指定的目录覆盖配置
+	if *flag.Conf != "" && output != "./" {
+		resolvedConfig.OutPutPath = output
+	}
+	fileName, err := recorders.RenderOutputFileName(cfg, resolvedConfig, info)
+	if err != nil {
+		return "", fmt.Errorf("生成文件名失败: %w", err)
+	}
+	return recorders.FileNameForStream(fileName, streamURL, info.AudioOnly), nil
+}
+
+// isOutputFile --output 带扩展名且不是已存在的目录时视为文件路径
+func isOutputFile(output string) bool {
+	if output == "" || strings.HasSuffix(output, "/") || strings.HasSuffix(output, string(os.PathSeparator)) {
+		return false
+	}
+	if stat, err := os.Stat(output); err == nil && stat.IsDir() {
+		return false
+	}
+	return filepath.Ext(output) != ""
+}
+
+func printStreams(streamInfos []*live.StreamUrlInfo) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "清晰度\t格式\t编码\t分辨率\t码率\t属性")
+	for _, s := range streamInfos {
+		resolution := "-"
+		if s.Width > 0 && s.Height > 0 {
+			resolution = fmt.Sprintf("%dx%d", s.Width, s.Height)
+		}
+		bitrate := "-"
+		if s.Bitrate > 0 {
+			bitrate = strconv.Itoa(s.Bitrate)
+		}
+		var attrs []string
+		for k, v := range s.AttributesForStreamSelect {
+			attrs = append(attrs, k+"="+v)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", s.Quality, s.Format, s.Codec, resolution, bitrate, strings.Join(attrs, ","))
+	}
+	return w.Flush()
+}
+
+// runPipeline 在前台依次执行后处理阶段，不经过任务队列与数据库
+func runPipeline(ctx context.Context, resolvedConfig configs.ResolvedConfig, info *live.Info, files []string) error {
+	var pipelineConfig *pipeline.PipelineConfig
+	if *flag.RecordPipeline == "config" {
+		pipelineConfig = pipeline.GetEffectivePipelineConfig(&resolvedConfig.OnRecordFinished)
+	} else {
+		pipelineConfig = &pipeline.PipelineConfig{}
+		for _, name := range strings.Split(*flag.RecordPipeline, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				pipelineConfig.Stages = append(pipelineConfig.Stages, pipeline.StageConfig{Name: name})
+			}
+		}
+	}
+	if len(pipelineConfig.Stages) == 0 {
+		return nil
+	}
+
+	executor := pipeline.NewExecutor(logrus.StandardLogger())
+	stages.RegisterBuiltinStages(executor)
+	if err := executor.ValidateConfig(pipelineConfig); err != nil {
+		return fmt.Errorf("后处理配置无效: %w", err)
+	}
+	initialFiles := make([]pipeline.FileInfo, len(files))
+	for i, f := range files {
+		initialFiles[i] = pipeline.NewVideoFileInfo(f)
+	}
+	recordInfo := pipeline.NewRecordInfo(info)
+	pipelineCtx := &pipeline.PipelineContext{
+		Ctx:        ctx,
+		RecordInfo: recordInfo,
+		Logger: livelogger.New(livelogger.DefaultBufferSize, logrus.Fields{
+			"platform": recordInfo.Platform,
+			"host":     recordInfo.HostName,
+			"room":     recordInfo.RoomName,
+		}),
+	}
+	results, err := executor.Execute(pipelineCtx, pipelineConfig, initialFiles,
+		func(stageIndex int, stageName string, status pipeline.StageStatus) {
+			logrus.Infof("后处理阶段 %s: %s", stageName, status)
+		})
+	if err != nil {
+		return fmt.Errorf("后处理失败: %w", err)
+	}
+	if len(results) > 0 {
+		for _, f := range results[len(results)-1].OutputFiles {
+			fmt.Println(f.Path)
+		}
+	}
+	return nil
+}
@@ -8,6 +8,8 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -22,6 +24,8 @@ import (
 type RPC struct {
 	Enable bool   `yaml:"enable" json:"enable"`
 	Bind   string `yaml:"bind" json:"bind"`
+	// Token 非空时，/api、/osrp、/files 下的请求需要携带该令牌（支持 secret:// 引用）
+	Token string `yaml:"token" json:"token"`
 	// SSE 配置
 	SSEListThreshold int `yaml:"sse_list_threshold" json:"sse_list_threshold"` // 监控列表超过此阈值时仅为详情页启用SSE
 }
@@ -60,6 +64,14 @@ type Feature struct {
 	// 当检测到视频编码参数变化（新的 SPS/PPS）时，会主动断开连接触发 FFmpeg 分段
 	// 这可以避免因编码参数变化导致的花屏问题
 	EnableFlvProxySegment bool `yaml:"enable_flv_proxy_segment,omitempty" json:"enable_flv_proxy_segment,omitempty"`
+
+	// HlsAdHandling 拼接广告（如 Twitch 贴片广告）的处理方式
+	// 可选值: "drop" (默认，从录制中剔除), "mark" (保留并记录位置), "off" (不处理)
+	HlsAdHandling string `yaml:"hls_ad_handling,omitempty" json:"hls_ad_handling,omitempty"`
+
+	// EnableLiveWatcher 启用开播推送监听（目前支持哔哩哔哩）
+	// 通过弹幕 WebSocket 的开播/下播消息立即触发录制，推送连接断开时回退到轮询
+	EnableLiveWatcher bool `yaml:"enable_live_watcher,omitempty" json:"enable_live_watcher,omitempty"`
 }
 
 // GetEffectiveDownloaderType 获取实际生效的下载器类型
@@ -91,6 +103,22 @@ type DanmakuConfig struct {
 	RecordSuperChat *bool  `yaml:"record_super_chat,omitempty" json:"record_super_chat,omitempty"` // 是否录制SC
 	GuardPosition   string `yaml:"guard_position,omitempty" json:"guard_position"`     // 上舰位置: bottom-left, bottom-right, top-left, top-right
 	ScPosition      string `yaml:"sc_position,omitempty" json:"sc_position"`           // SC位置: bottom-left, bottom-right, top-left, top-right
+	StreamDelayMs   *int   `yaml:"stream_delay_ms,omitempty" json:"stream_delay_ms,omitempty"` // 直播流相对弹幕的延迟毫秒数 (-60000~60000)，正值使弹幕整体后移
+	BlockKeywords   []string `yaml:"block_keywords,omitempty" json:"block_keywords,omitempty"` // 屏蔽关键词，包含任一关键词的弹幕不写入；nil 表示继承
+	BlockPatterns   []string `yaml:"block_patterns,omitempty" json:"block_patterns,omitempty"` // 屏蔽正则表达式，匹配任一表达式的弹幕不写入；nil 表示继承
+	BlockUsers      []string `yaml:"block_users,omitempty" json:"block_users,omitempty"`       // 屏蔽用户名，这些用户的弹幕不写入；nil 表示继承
+	FoldWindow      *int     `yaml:"fold_window,omitempty" json:"fold_window,omitempty"`       // 重复弹幕合并窗口秒数 (0~60)，窗口内相同内容合并为一条并标注次数，0 表示不合并
+	MaxPerSecond    *int     `yaml:"max_per_second,omitempty" json:"max_per_second,omitempty"` // 每秒最多写入的弹幕和礼物数 (0~200)，超出部分丢弃，SC 与上舰消息不受限制，0 表示不限制
+	MinUserLevel    *int     `yaml:"min_user_level,omitempty" json:"min_user_level,omitempty"` // 最低用户等级，仅对提供等级的平台（哔哩哔哩、斗鱼）生效，0 表示不限制
+	SubtitleFormats []string `yaml:"subtitle_formats,omitempty" json:"subtitle_formats,omitempty"` // 在 ASS 之外额外输出的字幕格式: srt, vtt；nil 表示继承
+}
+
+// StreamDelay 返回配置的直播流延迟，未设置时为 0
+func (d DanmakuConfig) StreamDelay() time.Duration {
+	if d.StreamDelayMs == nil {
+		return 0
+	}
+	return time.Duration(*d.StreamDelayMs) * time.Millisecond
 }
 
 func BoolPtr(b bool) *bool { return &b }
@@ -111,6 +139,10 @@ var defaultDanmakuConfig = DanmakuConfig{
 	RecordSuperChat: BoolPtr(true),
 	GuardPosition:   "bottom-left",
 	ScPosition:      "bottom-left",
+	StreamDelayMs:   IntPtr(0),
+	FoldWindow:      IntPtr(0),
+	MaxPerSecond:    IntPtr(0),
+	MinUserLevel:    IntPtr(0),
 }
 
 // validScrollAreas 支持的滚动区域
@@ -138,6 +170,12 @@ var validMessagePositions = map[string]bool{
 	"top-right":    true,
 }
 
+// validSubtitleFormats 支持的附加字幕格式
+var validSubtitleFormats = map[string]bool{
+	"srt": true, // SubRip
+	"vtt": true, // WebVTT，可直接用于 HTML5 播放器
+}
+
 // SetDefaults 将空字段设为默认值（应在 Validate 之前调用）
 func (d *DanmakuConfig) SetDefaults() {
 	d.SetDefaultsWithPlatform("")
@@ -166,6 +204,18 @@ func (d *DanmakuConfig) SetDefaultsWithPlatform(platformKey string) {
 	if d.Opacity == nil {
 		d.Opacity = IntPtr(*defaultDanmakuConfig.Opacity)
 	}
+	if d.StreamDelayMs == nil {
+		d.StreamDelayMs = IntPtr(*defaultDanmakuConfig.StreamDelayMs)
+	}
+	if d.FoldWindow == nil {
+		d.FoldWindow = IntPtr(*defaultDanmakuConfig.FoldWindow)
+	}
+	if d.MaxPerSecond == nil {
+		d.MaxPerSecond = IntPtr(*defaultDanmakuConfig.MaxPerSecond)
+	}
+	if d.MinUserLevel == nil {
+		d.MinUserLevel = IntPtr(*defaultDanmakuConfig.MinUserLevel)
+	}
 	// Bilibili 专属字段
 	if platformKey == "" || platformKey == "bilibili" {
 		if d.RecordGift == nil {
@@ -237,6 +287,36 @@ func (d *DanmakuConfig) ValidateWithPlatform(platformKey string) error {
 	if *d.Opacity < 0 || *d.Opacity > 255 {
 		return fmt.Errorf("背景透明度必须在 0~255 之间，当前值: %d", *d.Opacity)
 	}
+	if *d.StreamDelayMs < -60000 || *d.StreamDelayMs > 60000 {
+		return fmt.Errorf("直播流延迟必须在 -60000~60000 毫秒之间，当前值: %d", *d.StreamDelayMs)
+	}
+	for _, pattern := range d.BlockPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("无效的屏蔽正则表达式 %q: %v", pattern, err)
+		}
+	}
+	if *d.FoldWindow < 0 || *d.FoldWindow > 60 {
+		return fmt.Errorf("重复弹幕合并窗口必须在 0~60 秒之间，当前值: %d", *d.FoldWindow)
+	}
+	if *d.MaxPerSecond < 0 || *d.MaxPerSecond > 200 {
+		return fmt.Errorf("每秒弹幕上限必须在 0~200 之间，当前值: %d", *d.MaxPerSecond)
+	}
+	if *d.MinUserLevel < 0 {
+		return fmt.Errorf("最低用户等级不能为负数，当前值: %d", *d.MinUserLevel)
+	}
+	formats := make([]string, 0, len(d.SubtitleFormats))
+	for _, f := range d.SubtitleFormats {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if !validSubtitleFormats[f] {
+			return fmt.Errorf("不支持的字幕格式: %s，可选值: srt, vtt", f)
+		}
+		if !slices.Contains(formats, f) {
+			formats = append(formats, f)
+		}
+	}
+	if d.SubtitleFormats != nil {
+		d.SubtitleFormats = formats
+	}
 	if d.GuardPosition != "" && !validMessagePositions[d.GuardPosition] {
 		return fmt.Errorf("不支持的上舰消息位置: %s，可选值: bottom-left, bottom-right, top-left, top-right", d.GuardPosition)
 	}
@@ -274,6 +354,30 @@ func mergeDanmakuConfig(base, override *DanmakuConfig) DanmakuConfig {
 	if override.Opacity != nil {
 		result.Opacity = IntPtr(*override.Opacity)
 	}
+	if override.StreamDelayMs != nil {
+		result.StreamDelayMs = IntPtr(*override.StreamDelayMs)
+	}
+	if override.BlockKeywords != nil {
+		result.BlockKeywords = override.BlockKeywords
+	}
+	if override.BlockPatterns != nil {
+		result.BlockPatterns = override.BlockPatterns
+	}
+	if override.BlockUsers != nil {
+		result.BlockUsers = override.BlockUsers
+	}
+	if override.SubtitleFormats != nil {
+		result.SubtitleFormats = override.SubtitleFormats
+	}
+	if override.FoldWindow != nil {
+		result.FoldWindow = IntPtr(*override.FoldWindow)
+	}
+	if override.MaxPerSecond != nil {
+		result.MaxPerSecond = IntPtr(*override.MaxPerSecond)
+	}
+	if override.MinUserLevel != nil {
+		result.MinUserLevel = IntPtr(*override.MinUserLevel)
+	}
 	if override.RecordGift != nil {
 		result.RecordGift = override.RecordGift
 	}
@@ -371,6 +475,10 @@ type OnRecordFinished struct {
 	BurnSubtitlesPreset   string       `yaml:"burn_subtitles_preset" json:"burn_subtitles_preset"`           // 烧录用编码预设，默认 medium
 	BurnDeleteAss         bool         `yaml:"burn_delete_ass" json:"burn_delete_ass"`                       // 烧录后删除 ASS 文件
 	BurnDeleteSource      bool         `yaml:"burn_delete_source" json:"burn_delete_source"`                 // 烧录后删除源视频文件
+	ExtractHighlights     bool         `yaml:"extract_highlights" json:"extract_highlights"`                 // 按弹幕识别的高光片段剪辑出独立视频
+	HighlightCount        int          `yaml:"highlight_count" json:"highlight_count"`                       // 剪辑的高光片段数，默认 5
+	GenerateStoryboard    bool         `yaml:"generate_storyboard" json:"generate_storyboard"`               // 生成拖动预览用的缩略图雪碧图和 WebVTT 轨道
+	StoryboardInterval    int          `yaml:"storyboard_interval" json:"storyboard_interval"`               // 缩略图间隔秒数，默认 10
 }
 
 type Log struct {
@@ -416,10 +524,15 @@ var defaultTaskQueue = TaskQueue{
 	MaxConcurrent: 3,
 }
 
+// ProxyDirect 作为代理地址时表示直连，不使用任何代理（包括系统环境变量）
+const ProxyDirect = "direct"
+
 // ProxyEntry 单个代理配置项
 type ProxyEntry struct {
 	Enable bool   `yaml:"enable" json:"enable"`
 	URL    string `yaml:"url" json:"url"`
+	// Pool 使用 proxy_pools 中的代理池（设置后优先于 URL）
+	Pool string `yaml:"pool,omitempty" json:"pool,omitempty"`
 }
 
 // Proxy 代理配置
@@ -428,8 +541,10 @@ type ProxyEntry struct {
 type Proxy struct {
 	// Enable 是否启用配置的代理（false 时使用系统环境变量 HTTP_PROXY 等）
 	Enable bool `yaml:"enable" json:"enable"`
-	// URL 代理地址，支持 http://host:port 或 socks5://host:port
+	// URL 代理地址，支持 http://host:port 或 socks5://host:port，"direct" 表示直连
 	URL string `yaml:"url" json:"url"`
+	// Pool 使用 proxy_pools 中的代理池（设置后优先于 URL）
+	Pool string `yaml:"pool,omitempty" json:"pool,omitempty"`
 
 	// InfoProxy 信息获取专用代理（覆盖通用设置）
 	// 用于获取直播间信息、平台 API 请求等
@@ -446,6 +561,118 @@ var defaultProxy = Proxy{
 	URL:    "",
 }
 
+// ProxyPool 代理池：按顺序使用第一个健康的代理，不可用时自动切换到下一个
+type ProxyPool struct {
+	URLs []string `yaml:"urls" json:"urls"`
+	// HealthCheckURL 健康检查地址（默认 https://www.gstatic.com/generate_204）
+	HealthCheckURL string `yaml:"health_check_url,omitempty" json:"health_check_url,omitempty"`
+	// HealthCheckIntervalSec 健康检查间隔（秒，默认 60）
+	HealthCheckIntervalSec int `yaml:"health_check_interval_sec,omitempty" json:"health_check_interval_sec,omitempty"`
+}
+
+// validateProxyURL 校验代理地址格式
+func validateProxyURL(raw string) error {
+	if raw == "" || raw == ProxyDirect {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("代理地址 %q 无效: %w", raw, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return fmt.Errorf("代理地址 %q 的协议不受支持，可选: http, https, socks5, socks5h", raw)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("代理地址 %q 缺少主机", raw)
+	}
+	return nil
+}
+
+// validate 校验代理配置，pools 为已定义的代理池
+func (p *Proxy) validate(pools map[string]ProxyPool) error {
+	entries := []struct {
+		name string
+		url  string
+		pool string
+	}{{"proxy", p.URL, p.Pool}}
+	if p.InfoProxy != nil {
+		entries = append(entries, struct{ name, url, pool string }{"info_proxy", p.InfoProxy.URL, p.InfoProxy.Pool})
+	}
+	if p.DownloadProxy != nil {
+		entries = append(entries, struct{ name, url, pool string }{"download_proxy", p.DownloadProxy.URL, p.DownloadProxy.Pool})
+	}
+	for _, e := range entries {
+		if err := validateProxyURL(e.url); err != nil {
+			return fmt.Errorf("%s: %w", e.name, err)
+		}
+		if e.pool != "" {
+			if _, ok := pools[e.pool]; !ok {
+				return fmt.Errorf("%s: 代理池 %q 不存在", e.name, e.pool)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateAdaptiveIntervals 校验全局、平台、房间级自适应检测间隔配置
+func (c *Config) ValidateAdaptiveIntervals() error {
+	if err := c.AdaptiveInterval.Validate(); err != nil {
+		return err
+	}
+	for name, pc := range c.PlatformConfigs {
+		if pc.AdaptiveInterval != nil {
+			if err := pc.AdaptiveInterval.Validate(); err != nil {
+				return fmt.Errorf("平台 %s: %w", name, err)
+			}
+		}
+	}
+	for _, room := range c.LiveRooms {
+		if room.AdaptiveInterval != nil {
+			if err := room.AdaptiveInterval.Validate(); err != nil {
+				return fmt.Errorf("直播间 %s: %w", room.Url, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateProxies 校验全局、平台、房间级代理配置与代理池
+func (c *Config) ValidateProxies() error {
+	for name, pool := range c.ProxyPools {
+		if len(pool.URLs) == 0 {
+			return fmt.Errorf("代理池 %s 未配置任何代理地址", name)
+		}
+		for _, u := range pool.URLs {
+			if u == ProxyDirect {
+				continue
+			}
+			if err := validateProxyURL(u); err != nil {
+				return fmt.Errorf("代理池 %s: %w", name, err)
+			}
+		}
+	}
+	if err := c.Proxy.validate(c.ProxyPools); err != nil {
+		return err
+	}
+	for platform, pc := range c.PlatformConfigs {
+		if pc.Proxy != nil {
+			if err := pc.Proxy.validate(c.ProxyPools); err != nil {
+				return fmt.Errorf("平台 '%s': %w", platform, err)
+			}
+		}
+	}
+	for _, room := range c.LiveRooms {
+		if room.Proxy != nil {
+			if err := room.Proxy.validate(c.ProxyPools); err != nil {
+				return fmt.Errorf("直播间 %s: %w", room.Url, err)
+			}
+		}
+	}
+	return nil
+}
+
 // OpenListConfig OpenList 服务配置
 type OpenListConfig struct {
 	Port     int    `yaml:"port" json:"port"`           // OpenList 监听端口（默认 5244）
@@ -477,6 +704,149 @@ var defaultUpdateConfig = UpdateConfig{
 	IncludePrerelease:  false,
 }
 
+// CookieKeeperConfig Cookie 保活配置
+type CookieKeeperConfig struct {
+	// Enable 是否定期校验已保存的 Cookie 并自动刷新哔哩哔哩 Cookie（默认 true）
+	Enable bool `yaml:"enable" json:"enable"`
+	// CheckIntervalHours 校验间隔（小时，默认 6）
+	CheckIntervalHours int `yaml:"check_interval_hours" json:"check_interval_hours"`
+	// RefreshBeforeDays Cookie 距离过期不足该天数时主动刷新（默认 7）
+	RefreshBeforeDays int `yaml:"refresh_before_days" json:"refresh_before_days"`
+}
+
+var defaultCookieKeeperConfig = CookieKeeperConfig{
+	Enable:             true,
+	CheckIntervalHours: 6,
+	RefreshBeforeDays:  7,
+}
+
+// EventStreamConfig 对外事件流配置。
+// WebSocket 事件流（/api/events/ws）随 RPC 服务一同提供，MQTT 为可选
+type EventStreamConfig struct {
+	// DiskAlertMB 输出目录剩余空间低于该值（MB）时发布 disk_alert 事件，0 表示不检查
+	DiskAlertMB int `yaml:"disk_alert_mb" json:"disk_alert_mb"`
+	// LogRetentionDays 事件日志（/api/events）保留天数，0 表示永久保留
+	LogRetentionDays int `yaml:"log_retention_days" json:"log_retention_days"`
+	// MQTT 发布到 MQTT 服务器
+	MQTT MQTTConfig `yaml:"mqtt" json:"mqtt"`
+}
+
+// MQTTConfig MQTT 发布配置（QoS 0）
+type MQTTConfig struct {
+	Enable bool `yaml:"enable" json:"enable"`
+	// Broker 服务器地址，如 tcp://127.0.0.1:1883 或 ssl://broker:8883
+	Broker   string `yaml:"broker" json:"broker"`
+	ClientID string `yaml:"client_id" json:"client_id"`
+	Username string `yaml:"username" json:"username"`
+	Password string `yaml:"password" json:"password"`
+	// TopicPrefix 主题前缀（默认 bililive-go）
+	TopicPrefix string `yaml:"topic_prefix" json:"topic_prefix"`
+}
+
+var defaultEventStreamConfig = EventStreamConfig{
+	DiskAlertMB:      0,
+	LogRetentionDays: 30,
+	MQTT: MQTTConfig{
+		Enable:      false,
+		Broker:      "tcp://127.0.0.1:1883",
+		ClientID:    "bililive-go",
+		TopicPrefix: "bililive-go",
+	},
+}
+
+// PreviewConfig 录制中直播的本地预览配置。
+// 预览转发录制器正在接收的 FLV 流（/api/lives/{id}/preview.flv）或 HLS 分段
+// （/api/lives/{id}/preview.ts），不会额外请求 CDN
+type PreviewConfig struct {
+	Enable bool `yaml:"enable" json:"enable"`
+	// MaxViewers 每个直播间同时预览的人数上限
+	MaxViewers int `yaml:"max_viewers" json:"max_viewers"`
+}
+
+// defaultPreviewConfig 预览默认关闭：开启后任何能访问 Web 界面的人都能观看录制中的直播，
+// 建议同时设置 rpc.token
+var defaultPreviewConfig = PreviewConfig{
+	Enable:     false,
+	MaxViewers: 3,
+}
+
+// Validate 验证预览配置
+func (c *PreviewConfig) Validate() error {
+	if !c.Enable {
+		return nil
+	}
+	if c.MaxViewers < 1 || c.MaxViewers > 100 {
+		return fmt.Errorf("预览人数上限必须在 1~100 之间，当前值: %d", c.MaxViewers)
+	}
+	return nil
+}
+
+// 集群运行模式
+const (
+	ClusterModeStandalone  = ""
+	ClusterModeCoordinator = "coordinator"
+	ClusterModeWorker      = "worker"
+)
+
+// ClusterConfig 多节点集群配置。
+// coordinator 持有直播间列表，把监控中的直播间分配给存活的节点；worker 只录制分配给自己的直播间
+type ClusterConfig struct {
+	// Mode 运行模式：留空为单机，coordinator 或 worker
+	Mode string `yaml:"mode" json:"mode"`
+	// NodeID 节点标识，留空时使用主机名
+	NodeID string `yaml:"node_id" json:"node_id"`
+	// CoordinatorURL worker 连接的 coordinator 地址，如 http://10.0.0.1:8080
+	CoordinatorURL string `yaml:"coordinator_url" json:"coordinator_url"`
+	// Token worker 访问 coordinator 使用的令牌，对应 coordinator 的 rpc.token（支持 secret:// 引用）
+	Token string `yaml:"token" json:"token"`
+	// AdvertiseURL 本节点 Web 界面的访问地址，用于在汇总视图中跳转，可留空
+	AdvertiseURL string `yaml:"advertise_url" json:"advertise_url"`
+	// HeartbeatIntervalSec worker 心跳间隔（秒，默认 10）
+	HeartbeatIntervalSec int `yaml:"heartbeat_interval_sec" json:"heartbeat_interval_sec"`
+	// NodeTimeoutSec 超过该时间没有心跳的节点视为下线，其直播间重新分配（秒，默认 30）
+	NodeTimeoutSec int `yaml:"node_timeout_sec" json:"node_timeout_sec"`
+	// CoordinatorRecords coordinator 自身是否参与分配并录制（默认 true）
+	CoordinatorRecords bool `yaml:"coordinator_records" json:"coordinator_records"`
+}
+
+var defaultClusterConfig = ClusterConfig{
+	HeartbeatIntervalSec: 10,
+	NodeTimeoutSec:       30,
+	CoordinatorRecords:   true,
+}
+
+// Validate 验证集群配置
+func (c *ClusterConfig) Validate() error {
+	switch c.Mode {
+	case ClusterModeStandalone:
+		return nil
+	case ClusterModeCoordinator:
+	case ClusterModeWorker:
+		if strings.TrimSpace(c.CoordinatorURL) == "" {
+			return fmt.Errorf("worker 模式需要配置 coordinator_url")
+		}
+	default:
+		return fmt.Errorf("不支持的集群模式: %s，可选值: coordinator, worker", c.Mode)
+	}
+	if c.HeartbeatIntervalSec <= 0 {
+		return fmt.Errorf("心跳间隔必须大于 0")
+	}
+	if c.NodeTimeoutSec <= c.HeartbeatIntervalSec {
+		return fmt.Errorf("节点超时时间必须大于心跳间隔")
+	}
+	return nil
+}
+
+// HeartbeatInterval 返回心跳间隔
+func (c *ClusterConfig) HeartbeatInterval() time.Duration {
+	return time.Duration(c.HeartbeatIntervalSec) * time.Second
+}
+
+// NodeTimeout 返回节点超时时间
+func (c *ClusterConfig) NodeTimeout() time.Duration {
+	return time.Duration(c.NodeTimeoutSec) * time.Second
+}
+
 // StreamPreference 流偏好配置
 // 采用指针模式以区分"未设置"和"设置为零值"
 type StreamPreference struct {
@@ -501,8 +871,49 @@ type OverridableConfig struct {
 	OnRecordFinished     *OnRecordFinished     `yaml:"on_record_finished,omitempty" json:"on_record_finished,omitempty"`         // 录制完成后的动作
 	TimeoutInUs          *int                  `yaml:"timeout_in_us,omitempty" json:"timeout_in_us,omitempty"`                   // 超时设置(微秒)
 	StreamPreference     *StreamPreference     `yaml:"stream_preference,omitempty" json:"stream_preference,omitempty"`           // 流偏好配置
-	DanmakuEnable        *bool                 `yaml:"danmaku_enable,omitempty" json:"danmaku_enable,omitempty"`                 // 是否录制弹幕（支持哔哩哔哩、抖音、斗鱼）
+	DanmakuEnable        *bool                 `yaml:"danmaku_enable,omitempty" json:"danmaku_enable,omitempty"`                 // 是否录制弹幕（支持哔哩哔哩、抖音、斗鱼、虎牙、快手、AcFun、Twitch、SOOP）
 	Danmaku              *DanmakuConfig        `yaml:"danmaku,omitempty" json:"danmaku,omitempty"`                               // 弹幕录制参数
+	Proxy                *Proxy                `yaml:"proxy,omitempty" json:"proxy,omitempty"`                                   // 代理配置（整体覆盖上一级）
+	AdaptiveInterval     *AdaptiveInterval     `yaml:"adaptive_interval,omitempty" json:"adaptive_interval,omitempty"`           // 自适应检测间隔（整体覆盖上一级）
+}
+
+// AdaptiveInterval 自适应检测间隔配置
+// 根据主播的历史开播时间，在常规开播时段前后加快检测，在历史上很少开播的时段放慢检测
+type AdaptiveInterval struct {
+	Enable      bool `yaml:"enable" json:"enable"`
+	MinInterval int  `yaml:"min_interval,omitempty" json:"min_interval,omitempty"` // 临近常规开播时间时使用的最短间隔(秒)
+	MaxInterval int  `yaml:"max_interval,omitempty" json:"max_interval,omitempty"` // 历史上很少开播的时段使用的最长间隔(秒)
+}
+
+var defaultAdaptiveInterval = AdaptiveInterval{
+	Enable:      false,
+	MinInterval: 10,
+	MaxInterval: 120,
+}
+
+// Validate 校验自适应检测间隔配置
+func (a *AdaptiveInterval) Validate() error {
+	if !a.Enable {
+		return nil
+	}
+	if a.MinInterval <= 0 {
+		return fmt.Errorf("自适应检测的最短间隔必须大于 0")
+	}
+	if a.MaxInterval < a.MinInterval {
+		return fmt.Errorf("自适应检测的最长间隔不能小于最短间隔")
+	}
+	return nil
+}
+
+// ClampInterval 将检测间隔限制在 [MinInterval, MaxInterval] 范围内
+func (a *AdaptiveInterval) ClampInterval(interval int) int {
+	if interval < a.MinInterval {
+		return a.MinInterval
+	}
+	if a.MaxInterval > 0 && interval > a.MaxInterval {
+		return a.MaxInterval
+	}
+	return interval
 }
 
 // PlatformConfig 包含平台特定的设置
@@ -540,6 +951,62 @@ type SoopLiveAuth struct {
 	Password string `yaml:"password,omitempty" json:"password,omitempty"`
 }
 
+// CookiePoolStrategy 账号池向直播间分配 Cookie 的策略
+type CookiePoolStrategy string
+
+const (
+	// CookiePoolRoundRobin 依次轮流分配账号（默认）
+	CookiePoolRoundRobin CookiePoolStrategy = "round_robin"
+	// CookiePoolSticky 按直播间固定分配账号，账号不可用时才切换
+	CookiePoolSticky CookiePoolStrategy = "sticky"
+)
+
+// IsValid 检查策略是否有效（空值表示默认策略）
+func (s CookiePoolStrategy) IsValid() bool {
+	switch s {
+	case "", CookiePoolRoundRobin, CookiePoolSticky:
+		return true
+	}
+	return false
+}
+
+// CookiePool 同一 host 下的多账号 Cookie 池
+// 配置了账号池的 host 优先使用账号池，账号全部不可用时回退到 cookies 中的单个 Cookie
+type CookiePool struct {
+	Strategy CookiePoolStrategy `yaml:"strategy,omitempty" json:"strategy,omitempty"`
+	Accounts []CookieAccount    `yaml:"accounts" json:"accounts"`
+}
+
+// CookieAccount 账号池中的单个账号
+type CookieAccount struct {
+	Name     string `yaml:"name" json:"name"`
+	Cookie   string `yaml:"cookie" json:"cookie"`
+	Disabled bool   `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+	// ExpiresAt 账号 Cookie 的过期时间（可选），到期后不再分配
+	// 哔哩哔哩的 SESSDATA 自带过期时间，无需手动填写
+	ExpiresAt *time.Time `yaml:"expires_at,omitempty" json:"expires_at,omitempty"`
+}
+
+// ValidateCookiePools 校验账号池配置
+func (c *Config) ValidateCookiePools() error {
+	for host, pool := range c.CookiePools {
+		if !pool.Strategy.IsValid() {
+			return fmt.Errorf("账号池 %s 的分配策略 %q 无效，可选值: round_robin, sticky", host, pool.Strategy)
+		}
+		names := make(map[string]struct{}, len(pool.Accounts))
+		for i, account := range pool.Accounts {
+			if strings.TrimSpace(account.Name) == "" {
+				return fmt.Errorf("账号池 %s 的第 %d 个账号缺少 name", host, i+1)
+			}
+			if _, ok := names[account.Name]; ok {
+				return fmt.Errorf("账号池 %s 中存在重名账号: %s", host, account.Name)
+			}
+			names[account.Name] = struct{}{}
+		}
+	}
+	return nil
+}
+
 // Config content all config info.
 type Config struct {
 	// 核心配置
@@ -560,6 +1027,7 @@ type Config struct {
 	TimeoutInUs          int                  `yaml:"timeout_in_us" json:"timeout_in_us"`
 	DanmakuEnable        bool                 `yaml:"danmaku_enable" json:"danmaku_enable"`
 	Danmaku              DanmakuConfig        `yaml:"danmaku" json:"danmaku"`
+	AdaptiveInterval     AdaptiveInterval     `yaml:"adaptive_interval" json:"adaptive_interval"`
 
 	// 流偏好配置 - 两套系统并存
 	StreamPreference StreamPreference `yaml:"stream_preference,omitempty" json:"stream_preference,omitempty"` // 新版（渐进迁移中）
@@ -570,6 +1038,9 @@ type Config struct {
 	// Cookies 配置
 	Cookies map[string]string `yaml:"cookies" json:"cookies"`
 
+	// CookiePools 多账号 Cookie 池（按 host 配置）
+	CookiePools map[string]CookiePool `yaml:"cookie_pools,omitempty" json:"cookie_pools,omitempty"`
+
 	// SoopLive 账号配置
 	SoopLiveAuth SoopLiveAuth `yaml:"sooplive_auth,omitempty" json:"sooplive_auth,omitempty"`
 
@@ -586,6 +1057,8 @@ type Config struct {
 
 	// 代理配置
 	Proxy Proxy `yaml:"proxy" json:"proxy"`
+	// ProxyPools 命名代理池，可在全局、平台、房间级代理配置中通过 pool 引用
+	ProxyPools map[string]ProxyPool `yaml:"proxy_pools,omitempty" json:"proxy_pools,omitempty"`
 
 	// OpenList 配置
 	OpenList OpenListConfig `yaml:"openlist" json:"openlist"`
@@ -593,11 +1066,26 @@ type Config struct {
 	// 自动更新配置
 	Update UpdateConfig `yaml:"update" json:"update"`
 
+	// Cookie 保活配置
+	CookieKeeper CookieKeeperConfig `yaml:"cookie_keeper" json:"cookie_keeper"`
+
+	// 对外事件流配置
+	EventStream EventStreamConfig `yaml:"event_stream" json:"event_stream"`
+
+	// 录制中直播的本地预览
+	Preview PreviewConfig `yaml:"preview" json:"preview"`
+
+	// 多节点集群配置
+	Cluster ClusterConfig `yaml:"cluster" json:"cluster"`
+
 	// 平台特定配置（层级覆盖，使用 OverridableConfig 中的指针模式）
 	PlatformConfigs map[string]PlatformConfig `yaml:"platform_configs,omitempty" json:"platform_configs,omitempty"`
 
 	// 内部缓存
 	liveRoomIndexCache map[string]int `json:"-"`
+
+	// pendingSecrets 本次更新中待写入密钥库的条目，配置提交成功后写入
+	pendingSecrets map[string]string
 }
 
 // 使用 atomic.Value 存放当前配置指针，避免并发读写造成 data race
@@ -729,6 +1217,7 @@ func updateCASImpl(expectedVersion int64, mutator func(c *Config) error, persist
 		} else {
 			base = CloneConfigShallow(cur)
 		}
+		base.pendingSecrets = nil
 		if err := mutator(base); err != nil {
 			updateErr = err
 			return
@@ -745,6 +1234,10 @@ func updateCASImpl(expectedVersion int64, mutator func(c *Config) error, persist
 			}
 		}
 		SetCurrentConfig(newCfg)
+		// 配置已提交，再把引用字段的新明文写入密钥库
+		if err := newCfg.applyPendingSecrets(); err != nil {
+			updateErr = fmt.Errorf("配置已保存，但更新密钥库失败: %w", err)
+		}
 	}()
 
 	if updateErr != nil {
@@ -811,7 +1304,11 @@ func SetCookie(host, cookie string) (*Config, error) {
 			delete(c.Cookies, host)
 			return nil
 		}
-		c.Cookies[host] = cookie
+		v := c.Cookies[host]
+		if err := c.AssignSecret(&v, cookie); err != nil {
+			return err
+		}
+		c.Cookies[host] = v
 		return nil
 	}, 3, 10*time.Millisecond)
 }
@@ -826,7 +1323,12 @@ func SetCookies(hostCookies map[string]string) (*Config, error) {
 				delete(c.Cookies, host)
 				continue
 			}
-			c.Cookies[host] = cookie
+			// 原值为密钥库引用时更新密钥库，配置中继续保留引用
+			v := c.Cookies[host]
+			if err := c.AssignSecret(&v, cookie); err != nil {
+				return err
+			}
+			c.Cookies[host] = v
 		}
 		return nil
 	}, 3, 10*time.Millisecond)
@@ -865,6 +1367,20 @@ func RemoveLiveRoomByUrl(url string) (*Config, error) {
 	}, 3, 10*time.Millisecond)
 }
 
+// RemoveLiveRoomByUrlTransient 从配置中移除指定 URL 的房间（仅更新内存，不持久化）
+func RemoveLiveRoomByUrlTransient(url string) (*Config, error) {
+	return UpdateWithRetryTransient(func(c *Config) error {
+		out := c.LiveRooms[:0]
+		for _, r := range c.LiveRooms {
+			if r.Url != url {
+				out = append(out, r)
+			}
+		}
+		c.LiveRooms = out
+		return nil
+	}, 3, 10*time.Millisecond)
+}
+
 // SetLiveRoomListening 设置指定 URL 的房间监听状态
 func SetLiveRoomListening(url string, listening bool) (*Config, error) {
 	return UpdateWithRetry(func(c *Config) error {
@@ -907,11 +1423,55 @@ type LiveRoom struct {
 	NickName    string       `yaml:"nick_name,omitempty" json:"nick_name,omitempty"`
 	SchemeUrl   string       `yaml:"scheme" json:"scheme,omitempty"`
 	NotifyOnly  bool         `yaml:"notify_only,omitempty" json:"notify_only,omitempty"` // 仅开播提醒，不自动录制
+	// RestreamTargets 录制时同时转推的地址，支持 rtmp://、rtmps://、srt://，
+	// 也可以写成密钥库引用 secret://<名称>，避免推流码以明文出现在配置和接口中
+	RestreamTargets []string `yaml:"restream_targets,omitempty" json:"restream_targets,omitempty"`
 
 	// 房间级可覆盖配置
 	OverridableConfig `yaml:",inline" json:",inline"` // 房间级配置覆盖
 }
 
+// ValidateRestreamTarget 验证转推地址，密钥库引用在开始转推时解析后再验证
+func ValidateRestreamTarget(target string) error {
+	if SecretRefName(target) != "" {
+		return nil
+	}
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("无效的转推地址: %s", target)
+	}
+	switch u.Scheme {
+	case "rtmp", "rtmps", "srt":
+		return nil
+	default:
+		return fmt.Errorf("不支持的转推协议: %s，可选值: rtmp, rtmps, srt", u.Scheme)
+	}
+}
+
+// RedactRestreamTarget 隐去转推地址中的推流码、用户信息和查询参数（SRT 的 streamid、passphrase），
+// 用于日志、状态输出和配置接口
+func RedactRestreamTarget(target string) string {
+	u, err := url.Parse(target)
+	if err != nil {
+		return ""
+	}
+	u.User = nil
+	hasQuery := u.RawQuery != ""
+	u.RawQuery = ""
+	redacted := u.String()
+	if u.Scheme != "srt" {
+		if i := strings.LastIndex(u.Path, "/"); i > 0 && i < len(u.Path)-1 {
+			u.Path = u.Path[:i+1]
+			u.RawPath = ""
+			redacted = u.String() + "***"
+		}
+	}
+	if hasQuery {
+		redacted += "?***"
+	}
+	return redacted
+}
+
 type liveRoomAlias LiveRoom
 
 // allow both string and LiveRoom format in config
@@ -984,9 +1544,14 @@ var defaultConfig = Config{
 		BurnSubtitlesPreset: "medium",
 		BurnDeleteAss:       false,
 		BurnDeleteSource:    false,
+		ExtractHighlights:   false,
+		HighlightCount:      5,
+		GenerateStoryboard:  false,
+		StoryboardInterval:  10,
 	},
-	TimeoutInUs: 60000000,
-	Danmaku:     defaultDanmakuConfig,
+	TimeoutInUs:      60000000,
+	Danmaku:          defaultDanmakuConfig,
+	AdaptiveInterval: defaultAdaptiveInterval,
 	Notify: Notify{
 		SendRecordingSummary: false,
 		Telegram: Telegram{
@@ -1028,6 +1593,10 @@ var defaultConfig = Config{
 	Proxy:           defaultProxy,
 	OpenList:        defaultOpenListConfig,
 	Update:          defaultUpdateConfig,
+	CookieKeeper:    defaultCookieKeeperConfig,
+	EventStream:     defaultEventStreamConfig,
+	Preview:         defaultPreviewConfig,
+	Cluster:         defaultClusterConfig,
 	PlatformConfigs: map[string]PlatformConfig{},
 }
 
@@ -1111,6 +1680,39 @@ func (c *Config) Verify() error {
 		return fmt.Errorf("弹幕配置无效: %w", err)
 	}
 
+	// 验证账号池配置
+	if err := c.ValidateCookiePools(); err != nil {
+		return err
+	}
+
+	if err := c.ValidateProxies(); err != nil {
+		return err
+	}
+
+	if err := c.ValidateAdaptiveIntervals(); err != nil {
+		return err
+	}
+
+	for _, room := range c.LiveRooms {
+		for _, target := range room.RestreamTargets {
+			if err := ValidateRestreamTarget(target); err != nil {
+				return fmt.Errorf("直播间 %s 的转推配置无效: %w", room.Url, err)
+			}
+		}
+	}
+
+	if err := c.Preview.Validate(); err != nil {
+		return fmt.Errorf("预览配置无效: %w", err)
+	}
+
+	if err := c.Cluster.Validate(); err != nil {
+		return fmt.Errorf("集群配置无效: %w", err)
+	}
+	// coordinator 依靠 rpc.token 校验 worker 心跳，未设置时任何人都能冒充节点领取直播间
+	if c.Cluster.Mode == ClusterModeCoordinator && strings.TrimSpace(c.RPC.Token) == "" {
+		return fmt.Errorf("集群配置无效: coordinator 模式需要配置 rpc.token")
+	}
+
 	return nil
 }
 
@@ -1254,6 +1856,20 @@ func CloneConfigShallow(src *Config) *Config {
 			cp.Cookies[k] = v
 		}
 	}
+	if src.CookiePools != nil {
+		cp.CookiePools = make(map[string]CookiePool, len(src.CookiePools))
+		for k, v := range src.CookiePools {
+			v.Accounts = append([]CookieAccount(nil), v.Accounts...)
+			cp.CookiePools[k] = v
+		}
+	}
+	if src.ProxyPools != nil {
+		cp.ProxyPools = make(map[string]ProxyPool, len(src.ProxyPools))
+		for k, v := range src.ProxyPools {
+			v.URLs = append([]string(nil), v.URLs...)
+			cp.ProxyPools[k] = v
+		}
+	}
 	// PlatformConfigs 拷贝
 	if src.PlatformConfigs != nil {
 		cp.PlatformConfigs = make(map[string]PlatformConfig, len(src.PlatformConfigs))
@@ -1288,6 +1904,8 @@ func (c *Config) ResolveConfigForRoom(room *LiveRoom, platformName string) Resol
 		TimeoutInUs:          c.TimeoutInUs,
 		DanmakuEnable:        c.DanmakuEnable,
 		Danmaku:              c.Danmaku,
+		Proxy:                c.Proxy,
+		AdaptiveInterval:     c.AdaptiveInterval,
 	}
 
 	// 应用平台级覆盖
@@ -1349,6 +1967,8 @@ type ResolvedConfig struct {
 	StreamPreference     StreamPreference     `json:"stream_preference"`
 	DanmakuEnable        bool                 `json:"danmaku_enable"`
 	Danmaku              DanmakuConfig        `json:"danmaku"`
+	Proxy                Proxy                `json:"proxy"`
+	AdaptiveInterval     AdaptiveInterval     `json:"adaptive_interval"`
 }
 
 // applyOverrides 将可覆盖配置中的非空值应用到解析配置中
@@ -1389,6 +2009,12 @@ func (r *ResolvedConfig) applyOverrides(override *OverridableConfig) {
 	if override.Danmaku != nil {
 		r.Danmaku = mergeDanmakuConfig(&r.Danmaku, override.Danmaku)
 	}
+	if override.Proxy != nil {
+		r.Proxy = *override.Proxy
+	}
+	if override.AdaptiveInterval != nil {
+		r.AdaptiveInterval = *override.AdaptiveInterval
+	}
 }
 
 // GetPlatformKeyFromUrl 从URL中提取平台键，用于配置查找
@@ -22,6 +22,65 @@ func DecorateConfigNode(node *yaml.Node) {
 # ./平台名称/主播名字/[时间戳][主播名字][房间名字].flv
 # https://github.com/bililive-go/bililive-go/wiki/More-Tips`, "")
 
+	setFieldComment(root, "cookies",
+		`# Cookie、密码、令牌等敏感字段可以写为 secret://名称，引用加密密钥库中的条目（通过 /api/secrets 管理）
+# 主密钥来自环境变量 BILILIVE_SECRETS_KEY、BILILIVE_SECRETS_KEY_FILE 指向的文件，或数据目录下自动生成的 secrets.key`, "")
+
+	setFieldComment(root, "cookie_pools",
+		`# 多账号 Cookie 池（按 host 配置），优先于 cookies 中的单个 Cookie
+# strategy: round_robin（默认，轮流分配给直播间）或 sticky（每个直播间固定使用同一账号）
+# 账号触发风控时会暂时冷却并切换到其他账号；登录失效或过期的账号不再分配
+# 账号健康状态可通过 /api/cookie-pools 查看`, "")
+
+	setFieldComment(root, "cookie_keeper",
+		`# Cookie 保活：定期校验 cookies 与 cookie_pools 中的哔哩哔哩 Cookie
+# 即将过期或平台要求刷新时，使用扫码登录时保存的 refresh_token 自动刷新
+# 需要重新扫码登录时会通过已启用的通知渠道提醒`, "")
+
+	if rpcNode := findNode(root, "rpc"); rpcNode != nil {
+		setFieldComment(rpcNode, "token",
+			`# 访问令牌：非空时 /api、/osrp、/files 下的请求必须携带令牌，留空表示不鉴权
+# 令牌可通过 Authorization: Bearer <令牌> 请求头或 ?token=<令牌> 查询参数传递，
+# 浏览器访问一次 /?token=<令牌> 后会写入 Cookie，之后 Web 界面无需再次携带`, "")
+	}
+
+	setFieldComment(root, "event_stream",
+		`# 对外事件流：开播/下播、标题变化、录制开始/结束、后处理任务、磁盘空间告警
+# WebSocket 事件流地址为 /api/events/ws，消息格式见 docs/events.md`, "")
+	if eventStreamNode := findNode(root, "event_stream"); eventStreamNode != nil {
+		setFieldComment(eventStreamNode, "disk_alert_mb", "# 输出目录剩余空间低于该值（MB）时发布 disk_alert 事件，0 表示不检查", "")
+		setFieldComment(eventStreamNode, "log_retention_days", "# 事件日志保留天数，可通过 /api/events?since=<id> 回放错过的事件，0 表示永久保留", "")
+		if mqttNode := findNode(eventStreamNode, "mqtt"); mqttNode != nil {
+			setFieldComment(mqttNode, "enable", "# 是否同时发布到 MQTT 服务器（QoS 0），可用于 Home Assistant 等", "")
+			setFieldComment(mqttNode, "broker", "# MQTT 服务器地址，如 tcp://127.0.0.1:1883 或 ssl://broker:8883", "")
+			setFieldComment(mqttNode, "topic_prefix",
+				`# 主题前缀：事件发布到 <前缀>/events/<事件类型>，直播间最新状态保留在 <前缀>/rooms/<直播间ID>
+# 程序在线状态保留在 <前缀>/status（online/offline）`, "")
+		}
+	}
+
+	setFieldComment(root, "preview",
+		`# 录制中直播的本地预览：转发录制器正在接收的直播流，在 Web 界面检查画质时不会额外占用 CDN 带宽
+# FLV 直播流的预览地址为 /api/lives/<直播间ID>/preview.flv；
+# HLS 直播流的预览地址为 /api/lives/<直播间ID>/preview.ts（开启后 HLS 录制会经过本地代理，仅支持 MPEG-TS 分段）
+# 默认关闭；开启后建议同时设置 rpc.token，避免录制内容被未授权访问`, "")
+	if previewNode := findNode(root, "preview"); previewNode != nil {
+		setFieldComment(previewNode, "max_viewers", "# 每个直播间同时预览的人数上限", "")
+	}
+
+	setFieldComment(root, "cluster",
+		`# 多节点集群：coordinator 持有直播间列表，把监控中的直播间按节点分片，worker 只录制分配给自己的直播间
+# 节点超过 node_timeout_sec 没有心跳时，其直播间会重新分配给其他存活节点，详见 docs/cluster.md`, "")
+	if clusterNode := findNode(root, "cluster"); clusterNode != nil {
+		setFieldComment(clusterNode, "mode", "# 运行模式：留空为单机，coordinator 或 worker", "")
+		setFieldComment(clusterNode, "node_id", "# 节点标识，留空时使用主机名，集群内必须唯一", "")
+		setFieldComment(clusterNode, "token",
+			`# worker 访问 coordinator 使用的令牌，对应 coordinator 的 rpc.token
+# worker 模式下本机 live_rooms 不生效，直播间由 coordinator 分配`, "")
+		setFieldComment(clusterNode, "advertise_url", "# 本节点 Web 界面的访问地址，用于在 coordinator 的集群页面中跳转，可留空", "")
+		setFieldComment(clusterNode, "coordinator_records", "# coordinator 自身是否参与分配并录制", "")
+	}
+
 	splitNode := findNode(root, "video_split_strategies")
 	if splitNode != nil {
 		setFieldComment(splitNode, "max_file_size",
@@ -71,6 +130,25 @@ func DecorateConfigNode(node *yaml.Node) {
 			`# 烧录完成后是否删除源视频文件（如 MP4/FLV）
 # 默认 false（保留源文件，同时存在源文件和烧录后的 MKV）
 # 开启后仅保留烧录完成的 MKV 文件`, "")
+
+		setFieldComment(finishNode, "extract_highlights",
+			`# 是否根据弹幕密度和礼物活跃度剪辑高光片段
+# 录制时统计每段时间的弹幕、礼物、SC 和上舰，得分明显高于平时的片段记录在 .meta.json 中，
+# 录制结束后按关键帧无损剪辑为 <文件名>.highlightNN.<扩展名>，并生成 <文件名>.highlights.json 汇总
+# 需要同时开启弹幕录制（danmaku_enable）`, "")
+
+		setFieldComment(finishNode, "highlight_count",
+			`# 每个录制文件最多剪辑的高光片段数，按得分从高到低选取
+# 默认 5`, "")
+
+		setFieldComment(finishNode, "generate_storyboard",
+			`# 是否生成拖动进度条时的预览缩略图
+# 每隔 storyboard_interval 秒截取一帧拼成 <文件名>.storyboard.jpg，
+# 并生成对应的 WebVTT 缩略图轨道 <文件名>.storyboard.vtt，WebUI 和支持缩略图轨道的播放器可直接使用`, "")
+
+		setFieldComment(finishNode, "storyboard_interval",
+			`# 缩略图间隔秒数，默认 10
+# 录制较长时会自动加大间隔，单张雪碧图最多 400 帧`, "")
 	}
 
 	setFieldHeadComment(root, "notify", "# 通知服务配置")
@@ -141,7 +219,26 @@ func DecorateConfigNode(node *yaml.Node) {
 			`# 下载专用代理（可选，覆盖通用代理设置）
 # 仅用于下载直播流数据
 # 如果不想让下载流量走代理，可以将此项的 enable 设为 false`, "")
+		setFieldComment(proxyNode, "pool",
+			`# 使用 proxy_pools 中的代理池（设置后优先于 url）
+# 平台级和房间级配置同样支持 proxy 字段，整体覆盖上一级的代理设置
+# url 设为 direct 表示该平台/房间直连，不使用任何代理`, "")
 	}
+	setFieldHeadComment(root, "proxy_pools",
+		`# 代理池：按顺序使用第一个健康的代理，出错或健康检查失败时自动切换到下一个
+# 示例:
+# proxy_pools:
+#   jp:
+#     urls: [socks5://10.0.0.1:1080, http://10.0.0.2:8080]
+#     health_check_url: https://www.gstatic.com/generate_204
+#     health_check_interval_sec: 60`)
+
+	// AdaptiveInterval 自适应检测间隔注释
+	setFieldHeadComment(root, "adaptive_interval",
+		`# 自适应检测间隔：根据主播历史开播时间调整检测频率（需要至少 3 场直播记录）
+# 临近常规开播时间时使用 min_interval，历史上很少开播的时段使用 max_interval
+# 直播中或历史记录不足时使用 interval
+# 平台级和房间级配置同样支持 adaptive_interval 字段，整体覆盖上一级的设置`)
 
 	// Feature 功能配置注释
 	featureNode := findNode(root, "feature")
@@ -156,6 +253,16 @@ func DecorateConfigNode(node *yaml.Node) {
 # 当检测到视频编码参数变化（新的 SPS/PPS）时，会主动断开连接触发 FFmpeg 分段
 # 这可以避免因编码参数变化导致的花屏问题
 # 注意：启用后会在本地启动一个 FLV 代理服务器，FFmpeg 从代理读取流`, "")
+		setFieldComment(featureNode, "hls_ad_handling",
+			`# HLS 拼接广告处理方式（目前用于 Twitch）
+# drop（默认）: 从录制中剔除广告分段
+# mark: 保留广告分段，仅记录广告位置
+# off: 不做处理
+# 广告位置会写入录制文件旁的 .meta.json`, "")
+		setFieldComment(featureNode, "enable_live_watcher",
+			`# 开播推送监听（目前支持哔哩哔哩）
+# 为监控中的直播间保持弹幕 WebSocket 连接，收到开播/下播消息时立即开始/结束录制
+# 推送连接正常时轮询降为每 10 分钟一次兜底，连接断开时自动回退到正常轮询`, "")
 	}
 }
 
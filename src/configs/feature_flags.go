@@ -7,4 +7,4 @@ package configs
 // EnableProxyConfig 控制是否启用代理配置功能
 // false: 隐藏前端代理配置 UI，后端代理函数回退到环境变量
 // true: 启用完整的代理配置（通用代理 + 信息获取代理 + 下载代理）
-const EnableProxyConfig = false
+const EnableProxyConfig = true
@@ -0,0 +1,248 @@
+package configs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SecretRefPrefix 配置中引用加密密钥库条目的前缀，如 "secret://bilibili_cookie"。
+// 引用在使用时才解析，配置文件与配置接口中只出现引用名，不出现明文。
+const SecretRefPrefix = "secret://"
+
+// SecretMask 配置接口返回时用于替换明文敏感字段的占位符。
+// 提交配置时字段值为该占位符表示"保持原值不变"。
+const SecretMask = "******"
+
+// SecretBackend 加密密钥库（由 pkg/secrets 实现并注册，避免循环依赖）
+type SecretBackend interface {
+	Get(name string) (string, error)
+	Set(name, value string) error
+}
+
+var (
+	secretBackendMu sync.RWMutex
+	secretBackend   SecretBackend
+)
+
+// SetSecretBackend 注册密钥库
+func SetSecretBackend(b SecretBackend) {
+	secretBackendMu.Lock()
+	defer secretBackendMu.Unlock()
+	secretBackend = b
+}
+
+func getSecretBackend() SecretBackend {
+	secretBackendMu.RLock()
+	defer secretBackendMu.RUnlock()
+	return secretBackend
+}
+
+// SecretRefName 返回引用的密钥名；v 不是引用时返回空字符串
+func SecretRefName(v string) string {
+	v = strings.TrimSpace(v)
+	if !strings.HasPrefix(v, SecretRefPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(v, SecretRefPrefix)
+}
+
+// LookupSecret 解析配置值：引用则从密钥库读取明文，否则原样返回
+func LookupSecret(v string) (string, error) {
+	name := SecretRefName(v)
+	if name == "" {
+		return v, nil
+	}
+	backend := getSecretBackend()
+	if backend == nil {
+		return "", fmt.Errorf("密钥库未初始化，无法解析 %s", v)
+	}
+	return backend.Get(name)
+}
+
+// ResolveSecret 同 LookupSecret，解析失败时返回空字符串（视为未配置）
+func ResolveSecret(v string) string {
+	resolved, err := LookupSecret(v)
+	if err != nil {
+		return ""
+	}
+	return resolved
+}
+
+// AssignSecret 写入敏感字段：新值为遮盖占位符时保持原值；原值为引用且新值是明文时，
+// 保留引用，明文记为待写入密钥库的条目；其余情况直接覆盖。
+// 待写入的条目在配置更新成功提交后才写入密钥库（见 applyPendingSecrets），
+// 校验失败或版本冲突重试时密钥库不会被修改
+func (c *Config) AssignSecret(dst *string, value string) error {
+	if value == SecretMask {
+		return nil
+	}
+	if name := SecretRefName(*dst); name != "" && value != "" && SecretRefName(value) == "" {
+		if getSecretBackend() == nil {
+			return fmt.Errorf("密钥库未初始化，无法更新 %s", *dst)
+		}
+		if c.pendingSecrets == nil {
+			c.pendingSecrets = make(map[string]string)
+		}
+		c.pendingSecrets[name] = value
+		return nil
+	}
+	*dst = value
+	return nil
+}
+
+// applyPendingSecrets 将 AssignSecret 记录的条目写入密钥库并清空记录
+func (c *Config) applyPendingSecrets() error {
+	pending := c.pendingSecrets
+	c.pendingSecrets = nil
+	if len(pending) == 0 {
+		return nil
+	}
+	backend := getSecretBackend()
+	if backend == nil {
+		return fmt.Errorf("密钥库未初始化")
+	}
+	names := make([]string, 0, len(pending))
+	for name := range pending {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := backend.Set(name, pending[name]); err != nil {
+			return fmt.Errorf("写入密钥 %s 失败: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// secretFields 返回配置中除 Cookie 以外的敏感字段，顺序固定
+func secretFields(c *Config) []*string {
+	return []*string{
+		&c.SoopLiveAuth.Password,
+		&c.Notify.Telegram.BotToken,
+		&c.Notify.Email.SenderPassword,
+		&c.Notify.Ntfy.Token,
+		&c.Notify.Bark.DeviceKey,
+		&c.Notify.WxPusher.AppToken,
+		&c.EventStream.MQTT.Password,
+		&c.RPC.Token,
+		&c.Cluster.Token,
+	}
+}
+
+// MaskSecretValue 将明文敏感值替换为占位符，空值与密钥库引用保持原样
+func MaskSecretValue(v string) string {
+	if v == "" || SecretRefName(v) != "" {
+		return v
+	}
+	return SecretMask
+}
+
+// MaskSecrets 返回敏感字段被替换为占位符的配置副本，用于配置接口的响应。
+// 密钥库引用本身不是秘密，保持原样以便用户查看。
+func (c *Config) MaskSecrets() *Config {
+	cp := CloneConfigShallow(c)
+	if cp == nil {
+		return nil
+	}
+	for _, p := range secretFields(cp) {
+		*p = MaskSecretValue(*p)
+	}
+	for host, v := range cp.Cookies {
+		cp.Cookies[host] = MaskSecretValue(v)
+	}
+	for host, pool := range cp.CookiePools {
+		for i := range pool.Accounts {
+			pool.Accounts[i].Cookie = MaskSecretValue(pool.Accounts[i].Cookie)
+		}
+		cp.CookiePools[host] = pool
+	}
+	for i := range cp.LiveRooms {
+		cp.LiveRooms[i].RestreamTargets = MaskRestreamTargets(cp.LiveRooms[i].RestreamTargets)
+	}
+	return cp
+}
+
+// MaskRestreamTargets 返回隐去推流码后的转推地址副本，密钥库引用保持原样
+func MaskRestreamTargets(targets []string) []string {
+	if targets == nil {
+		return nil
+	}
+	masked := make([]string, len(targets))
+	for i, target := range targets {
+		if SecretRefName(target) != "" {
+			masked[i] = target
+		} else if redacted := RedactRestreamTarget(target); redacted != "" {
+			masked[i] = redacted
+		} else {
+			masked[i] = SecretMask
+		}
+	}
+	return masked
+}
+
+// RestoreMaskedRestreamTargets 将 targets 中与 old 某一项遮盖结果相同的地址恢复为该项原值，
+// 每个原值只使用一次；其余地址视为用户新填写的值原样保留
+func RestoreMaskedRestreamTargets(targets, old []string) []string {
+	if len(targets) == 0 || len(old) == 0 {
+		return targets
+	}
+	maskedOld := MaskRestreamTargets(old)
+	used := make([]bool, len(old))
+	restored := make([]string, len(targets))
+	for i, target := range targets {
+		restored[i] = target
+		for j := range old {
+			if !used[j] && maskedOld[j] == target {
+				used[j] = true
+				restored[i] = old[j]
+				break
+			}
+		}
+	}
+	return restored
+}
+
+// RestoreMaskedSecrets 将仍为占位符的敏感字段恢复为 old 中的原值，
+// 用于接收由 MaskSecrets 输出修改而来的配置。
+func (c *Config) RestoreMaskedSecrets(old *Config) {
+	if old == nil {
+		return
+	}
+	oldFields := secretFields(old)
+	for i, p := range secretFields(c) {
+		if *p == SecretMask {
+			*p = *oldFields[i]
+		}
+	}
+	for host, v := range c.Cookies {
+		if v == SecretMask {
+			c.Cookies[host] = old.Cookies[host]
+		}
+	}
+	oldRooms := make(map[string]LiveRoom, len(old.LiveRooms))
+	for _, room := range old.LiveRooms {
+		oldRooms[room.Url] = room
+	}
+	for i, room := range c.LiveRooms {
+		if oldRoom, ok := oldRooms[room.Url]; ok {
+			c.LiveRooms[i].RestreamTargets = RestoreMaskedRestreamTargets(room.RestreamTargets, oldRoom.RestreamTargets)
+		}
+	}
+	for host, pool := range c.CookiePools {
+		oldPool := old.CookiePools[host]
+		for i, account := range pool.Accounts {
+			if account.Cookie != SecretMask {
+				continue
+			}
+			pool.Accounts[i].Cookie = ""
+			for _, oldAccount := range oldPool.Accounts {
+				if oldAccount.Name == account.Name {
+					pool.Accounts[i].Cookie = oldAccount.Cookie
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package configs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memorySecretBackend map[string]string
+
+func (m memorySecretBackend) Get(name string) (string, error) {
+	v, ok := m[name]
+	if !ok {
+		return "", fmt.Errorf("not found: %s", name)
+	}
+	return v, nil
+}
+
+func (m memorySecretBackend) Set(name, value string) error {
+	m[name] = value
+	return nil
+}
+
+func TestMaskAndRestoreSecrets(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Cookies = map[string]string{
+		"live.bilibili.com": "SESSDATA=plain",
+		"www.douyu.com":     "secret://douyu",
+	}
+	cfg.CookiePools = map[string]CookiePool{
+		"live.bilibili.com": {Accounts: []CookieAccount{{Name: "a", Cookie: "SESSDATA=a"}}},
+	}
+	cfg.Notify.Telegram.BotToken = "bot-token"
+	cfg.LiveRooms = []LiveRoom{{
+		Url:             "https://live.bilibili.com/1",
+		RestreamTargets: []string{"rtmp://live.example.com/app/key-a", "secret://push", "rtmp://live.example.com/app/key-b"},
+	}}
+
+	masked := cfg.MaskSecrets()
+	assert.Equal(t, SecretMask, masked.Cookies["live.bilibili.com"])
+	assert.Equal(t, "secret://douyu", masked.Cookies["www.douyu.com"], "引用不需要遮盖")
+	assert.Equal(t, SecretMask, masked.CookiePools["live.bilibili.com"].Accounts[0].Cookie)
+	assert.Equal(t, SecretMask, masked.Notify.Telegram.BotToken)
+	assert.Equal(t, "", masked.Notify.Email.SenderPassword)
+	assert.Equal(t, []string{"rtmp://live.example.com/app/***", "secret://push", "rtmp://live.example.com/app/***"}, masked.LiveRooms[0].RestreamTargets)
+	// 原配置不受影响
+	assert.Equal(t, "SESSDATA=plain", cfg.Cookies["live.bilibili.com"])
+	assert.Equal(t, "SESSDATA=a", cfg.CookiePools["live.bilibili.com"].Accounts[0].Cookie)
+
+	masked.Notify.Email.SenderPassword = "new-password"
+	masked.LiveRooms[0].RestreamTargets = append(masked.LiveRooms[0].RestreamTargets, "rtmp://other.example.com/app/new-key")
+	masked.RestoreMaskedSecrets(cfg)
+	assert.Equal(t, []string{"rtmp://live.example.com/app/key-a", "secret://push", "rtmp://live.example.com/app/key-b", "rtmp://other.example.com/app/new-key"}, masked.LiveRooms[0].RestreamTargets)
+	assert.Equal(t, "rtmp://live.example.com/app/key-a", cfg.LiveRooms[0].RestreamTargets[0], "原配置不受影响")
+	assert.Equal(t, "SESSDATA=plain", masked.Cookies["live.bilibili.com"])
+	assert.Equal(t, "SESSDATA=a", masked.CookiePools["live.bilibili.com"].Accounts[0].Cookie)
+	assert.Equal(t, "bot-token", masked.Notify.Telegram.BotToken)
+	assert.Equal(t, "new-password", masked.Notify.Email.SenderPassword)
+}
+
+func TestResolveAndAssignSecret(t *testing.T) {
+	backend := memorySecretBackend{"douyu": "acf_uid=1"}
+	SetSecretBackend(backend)
+	defer SetSecretBackend(nil)
+
+	assert.Equal(t, "plain", ResolveSecret("plain"))
+	assert.Equal(t, "acf_uid=1", ResolveSecret("secret://douyu"))
+	assert.Equal(t, "", ResolveSecret("secret://missing"))
+
+	SetCurrentConfig(NewConfig())
+	defer SetCurrentConfig(nil)
+	assign := func(dst *string, value string, fail bool) error {
+		_, err := UpdateWithRetryTransient(func(c *Config) error {
+			if err := c.AssignSecret(dst, value); err != nil {
+				return err
+			}
+			if fail {
+				return fmt.Errorf("校验失败")
+			}
+			return nil
+		}, 0, 0)
+		return err
+	}
+
+	ref := "secret://douyu"
+	assert.Error(t, assign(&ref, "acf_uid=2", true))
+	assert.Equal(t, "acf_uid=1", backend["douyu"], "更新未提交时不应写入密钥库")
+
+	assert.NoError(t, assign(&ref, "acf_uid=2", false))
+	assert.Equal(t, "secret://douyu", ref, "写入引用字段时应更新密钥库而非配置")
+	assert.Equal(t, "acf_uid=2", backend["douyu"])
+
+	assert.NoError(t, assign(&ref, SecretMask, false))
+	assert.Equal(t, "acf_uid=2", backend["douyu"], "遮盖占位符表示保持原值")
+
+	assert.NoError(t, assign(&ref, "secret://other", false))
+	assert.Equal(t, "secret://other", ref)
+
+	plain := "a"
+	assert.NoError(t, assign(&plain, "b", false))
+	assert.Equal(t, "b", plain)
+}
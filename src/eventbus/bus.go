@@ -0,0 +1,106 @@
+package eventbus
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bluele/gcache"
+
+	"github.com/bililive-go/bililive-go/src/pkg/events"
+)
+
+// subscriberBuffer 每个订阅者的消息缓冲，消费过慢时丢弃新消息（可通过 seq 发现）
+const subscriberBuffer = 256
+
+// Bus 对外事件总线：把内部事件转换为带版本号的 Message 并分发给各订阅者
+// （WebSocket 连接、MQTT 发布器等）
+type Bus struct {
+	mu   sync.RWMutex
+	subs map[*subscriber]struct{}
+	seq  atomic.Uint64
+}
+
+type subscriber struct {
+	ch    chan Message
+	types map[Type]bool // 为空表示订阅全部类型
+}
+
+var defaultBus = New()
+
+// GetBus 返回全局事件总线
+func GetBus() *Bus {
+	return defaultBus
+}
+
+// New 创建事件总线
+func New() *Bus {
+	return &Bus{subs: make(map[*subscriber]struct{})}
+}
+
+// Register 将需要对外发布的内部事件接入总线
+func (b *Bus) Register(ed events.Dispatcher, cache gcache.Cache) {
+	handler := events.NewEventListener(func(event *events.Event) {
+		if typ, data, ok := Convert(event, cache); ok {
+			b.Publish(typ, data)
+		}
+	})
+	for eventType := range internalTypes {
+		ed.AddEventListener(eventType, handler)
+	}
+}
+
+// Publish 发布事件，不会阻塞
+func (b *Bus) Publish(typ Type, data any) {
+	msg := Message{
+		Version: SchemaVersion,
+		Seq:     b.seq.Add(1),
+		Type:    typ,
+		Time:    time.Now(),
+		Data:    data,
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for sub := range b.subs {
+		if len(sub.types) > 0 && !sub.types[typ] {
+			continue
+		}
+		select {
+		case sub.ch <- msg:
+		default:
+			// 订阅者消费过慢，丢弃
+		}
+	}
+}
+
+// Subscribe 订阅事件，types 为空表示订阅全部类型。
+// 返回的取消函数会关闭消息通道
+func (b *Bus) Subscribe(types ...Type) (<-chan Message, func()) {
+	sub := &subscriber{ch: make(chan Message, subscriberBuffer)}
+	if len(types) > 0 {
+		sub.types = make(map[Type]bool, len(types))
+		for _, t := range types {
+			sub.types[t] = true
+		}
+	}
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, sub)
+			b.mu.Unlock()
+			close(sub.ch)
+		})
+	}
+}
+
+// SubscriberCount 返回当前订阅者数量
+func (b *Bus) SubscriberCount() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs)
+}
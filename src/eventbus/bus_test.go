@@ -0,0 +1,89 @@
+package eventbus
+
+import (
+	"testing"
+
+	"github.com/bluele/gcache"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/listeners"
+	"github.com/bililive-go/bililive-go/src/live"
+	livemock "github.com/bililive-go/bililive-go/src/live/mock"
+	"github.com/bililive-go/bililive-go/src/pkg/events"
+	evtmock "github.com/bililive-go/bililive-go/src/pkg/events/mock"
+	"github.com/bililive-go/bililive-go/src/types"
+)
+
+func TestConvertAndSubscribe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	l := livemock.NewMockLive(ctrl)
+	l.EXPECT().GetLiveId().Return(types.LiveID("id1")).AnyTimes()
+	l.EXPECT().GetRawUrl().Return("https://live.bilibili.com/1").AnyTimes()
+	l.EXPECT().GetPlatformCNName().Return("哔哩哔哩").AnyTimes()
+	cache := gcache.New(4).LRU().Build()
+	cache.Set(l, &live.Info{HostName: "host", RoomName: "room", Status: true})
+
+	bus := New()
+	starts, cancelStarts := bus.Subscribe(TypeLiveStart)
+	all, cancelAll := bus.Subscribe()
+	defer cancelAll()
+
+	for _, e := range []*events.Event{
+		events.NewEvent(listeners.LiveStart, l),
+		events.NewEvent(listeners.ListenStart, l), // 不对外发布
+		events.NewEvent(listeners.LiveEnd, l),
+	} {
+		if typ, data, ok := Convert(e, cache); ok {
+			bus.Publish(typ, data)
+		}
+	}
+
+	msg := <-starts
+	assert.Equal(t, SchemaVersion, msg.Version)
+	assert.Equal(t, TypeLiveStart, msg.Type)
+	assert.Equal(t, LivePayload{
+		LiveID: "id1", URL: "https://live.bilibili.com/1", Platform: "哔哩哔哩",
+		HostName: "host", RoomName: "room", Living: true,
+	}, msg.Data)
+	assert.Len(t, starts, 0, "只收到订阅的事件类型")
+	assert.Len(t, all, 2)
+	first, second := <-all, <-all
+	assert.Equal(t, first.Seq+1, second.Seq)
+
+	cancelStarts()
+	cancelStarts()
+	assert.Equal(t, 1, bus.SubscriberCount())
+}
+
+func TestCheckDiskSpace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ed := evtmock.NewMockDispatcher(ctrl)
+	cfg := configs.NewConfig()
+	cfg.EventStream.DiskAlertMB = 100
+	configs.SetCurrentConfig(cfg)
+	defer configs.SetCurrentConfig(nil)
+
+	free := uint64(50 << 20)
+	old := getDiskFreeSpace
+	getDiskFreeSpace = func(string) (uint64, error) { return free, nil }
+	defer func() { getDiskFreeSpace = old }()
+
+	ed.EXPECT().DispatchEvent(gomock.Any()).Do(func(e *events.Event) {
+		assert.Equal(t, DiskSpaceLow, e.Type)
+		assert.True(t, e.Object.(*DiskAlertPayload).Low)
+	})
+	low := checkDiskSpace(ed, false)
+	assert.True(t, low)
+	// 状态未变化时不重复告警
+	assert.True(t, checkDiskSpace(ed, low))
+
+	free = 200 << 20
+	ed.EXPECT().DispatchEvent(gomock.Any()).Do(func(e *events.Event) {
+		assert.Equal(t, DiskSpaceRecovered, e.Type)
+	})
+	assert.False(t, checkDiskSpace(ed, low))
+}
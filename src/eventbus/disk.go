@@ -0,0 +1,67 @@
+package eventbus
+
+import (
+	"context"
+	"time"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/notify"
+	"github.com/bililive-go/bililive-go/src/pkg/events"
+)
+
+const (
+	// DiskSpaceLow 输出目录剩余空间低于阈值
+	DiskSpaceLow events.EventType = "DiskSpaceLow"
+	// DiskSpaceRecovered 输出目录剩余空间恢复到阈值以上
+	DiskSpaceRecovered events.EventType = "DiskSpaceRecovered"
+)
+
+// diskCheckInterval 磁盘空间检查间隔
+const diskCheckInterval = time.Minute
+
+// getDiskFreeSpace 获取剩余空间（测试时替换）
+var getDiskFreeSpace = notify.GetDiskFreeSpace
+
+// RunDiskMonitor 定期检查输出目录剩余空间，跌破/恢复 disk_alert_mb 时各分发一次事件
+func RunDiskMonitor(ctx context.Context, ed events.Dispatcher) {
+	ticker := time.NewTicker(diskCheckInterval)
+	defer ticker.Stop()
+	low := false
+	for {
+		low = checkDiskSpace(ed, low)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkDiskSpace 检查一次剩余空间，返回新的告警状态
+func checkDiskSpace(ed events.Dispatcher, low bool) bool {
+	cfg := configs.GetCurrentConfig()
+	if cfg == nil || cfg.EventStream.DiskAlertMB <= 0 {
+		return false
+	}
+	path := cfg.OutPutPath
+	free, err := getDiskFreeSpace(path)
+	if err != nil {
+		return low
+	}
+	threshold := uint64(cfg.EventStream.DiskAlertMB) * 1024 * 1024
+	nowLow := free < threshold
+	if nowLow == low {
+		return low
+	}
+	eventType := DiskSpaceRecovered
+	if nowLow {
+		eventType = DiskSpaceLow
+	}
+	ed.DispatchEvent(events.NewEvent(eventType, &DiskAlertPayload{
+		Path:           path,
+		Low:            nowLow,
+		FreeBytes:      free,
+		ThresholdBytes: threshold,
+	}))
+	return nowLow
+}
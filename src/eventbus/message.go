@@ -0,0 +1,128 @@
+package eventbus
+
+import (
+	"time"
+
+	"github.com/bluele/gcache"
+
+	"github.com/bililive-go/bililive-go/src/listeners"
+	"github.com/bililive-go/bililive-go/src/live"
+	"github.com/bililive-go/bililive-go/src/pipeline"
+	"github.com/bililive-go/bililive-go/src/pkg/events"
+	"github.com/bililive-go/bililive-go/src/recorders"
+)
+
+// SchemaVersion 对外事件格式的版本号。
+// 只增加字段时保持不变，删除/重命名字段或改变语义时加一，
+// 对应的 JSON Schema 位于 docs/schemas/events.schema.json
+const SchemaVersion = 1
+
+// Type 对外事件类型，与内部的 events.EventType 解耦，保证外部接口稳定
+type Type string
+
+const (
+	TypeLiveStart          Type = "live_start"
+	TypeLiveEnd            Type = "live_end"
+	TypeRoomNameChanged    Type = "room_name_changed"
+	TypeRecorderStart      Type = "recorder_start"
+	TypeRecorderStop       Type = "recorder_stop"
+	TypePipelineTaskUpdate Type = "pipeline_task_update"
+	TypeDiskAlert          Type = "disk_alert"
+)
+
+// internalTypes 内部事件到对外事件类型的映射
+var internalTypes = map[events.EventType]Type{
+	listeners.LiveStart:              TypeLiveStart,
+	listeners.LiveEnd:                TypeLiveEnd,
+	listeners.RoomNameChanged:        TypeRoomNameChanged,
+	recorders.RecorderStart:          TypeRecorderStart,
+	recorders.RecorderStop:           TypeRecorderStop,
+	pipeline.PipelineTaskUpdateEvent: TypePipelineTaskUpdate,
+	DiskSpaceLow:                     TypeDiskAlert,
+	DiskSpaceRecovered:               TypeDiskAlert,
+}
+
+// Message 对外发布的事件
+type Message struct {
+	Version int       `json:"version"`
+	Seq     uint64    `json:"seq"` // 进程内递增序号，可用于检测丢失的消息
+	Type    Type      `json:"type"`
+	Time    time.Time `json:"time"`
+	Data    any       `json:"data"`
+}
+
+// LivePayload 直播间相关事件的数据
+type LivePayload struct {
+	LiveID   string `json:"live_id"`
+	URL      string `json:"url"`
+	Platform string `json:"platform"`
+	HostName string `json:"host_name"`
+	RoomName string `json:"room_name"`
+	Living   bool   `json:"living"`
+}
+
+// PipelineTaskPayload 后处理任务事件的数据
+type PipelineTaskPayload struct {
+	TaskID       int64  `json:"task_id"`
+	LiveID       string `json:"live_id"`
+	Status       string `json:"status"`
+	Progress     int    `json:"progress"`
+	CurrentStage int    `json:"current_stage"`
+	TotalStages  int    `json:"total_stages"`
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+// DiskAlertPayload 磁盘空间告警事件的数据
+type DiskAlertPayload struct {
+	Path           string `json:"path"`
+	Low            bool   `json:"low"` // true 表示空间不足，false 表示已恢复
+	FreeBytes      uint64 `json:"free_bytes"`
+	ThresholdBytes uint64 `json:"threshold_bytes"`
+}
+
+// Convert 将内部事件转换为对外事件，不对外发布的事件返回 false
+func Convert(event *events.Event, cache gcache.Cache) (Type, any, bool) {
+	if event == nil {
+		return "", nil, false
+	}
+	typ, ok := internalTypes[event.Type]
+	if !ok {
+		return "", nil, false
+	}
+	switch obj := event.Object.(type) {
+	case live.Live:
+		return typ, NewLivePayload(obj, cache), true
+	case *pipeline.PipelineTask:
+		return typ, PipelineTaskPayload{
+			TaskID:       obj.ID,
+			LiveID:       string(obj.RecordInfo.LiveID),
+			Status:       string(obj.Status),
+			Progress:     obj.Progress,
+			CurrentStage: obj.CurrentStage,
+			TotalStages:  obj.TotalStages,
+			ErrorMessage: obj.ErrorMessage,
+		}, true
+	case *DiskAlertPayload:
+		return typ, *obj, true
+	}
+	return "", nil, false
+}
+
+// NewLivePayload 生成直播间数据，主播名、标题与直播状态取自缓存的最新信息
+func NewLivePayload(l live.Live, cache gcache.Cache) LivePayload {
+	p := LivePayload{
+		LiveID:   string(l.GetLiveId()),
+		URL:      l.GetRawUrl(),
+		Platform: l.GetPlatformCNName(),
+	}
+	if cache != nil {
+		if v, err := cache.Get(l); err == nil {
+			if info, ok := v.(*live.Info); ok {
+				p.HostName = info.HostName
+				p.RoomName = info.RoomName
+				p.Living = info.Status
+			}
+		}
+	}
+	return p
+}
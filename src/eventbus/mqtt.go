@@ -0,0 +1,152 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/pkg/mqtt"
+)
+
+const (
+	mqttMinBackoff = 5 * time.Second
+	mqttMaxBackoff = 5 * time.Minute
+	// mqttConfigCheckInterval 未启用 MQTT 时检查配置变化的间隔
+	mqttConfigCheckInterval = 30 * time.Second
+)
+
+// RunMQTTPublisher 将总线上的事件发布到 MQTT 服务器，断线后自动重连，配置变更后使用新配置重连。
+//   - <前缀>/events/<事件类型>: 每个事件
+//   - <前缀>/rooms/<直播间ID>: 直播间最新状态（保留消息）
+//   - <前缀>/status: online/offline（保留消息，异常断开时由遗嘱消息置为 offline）
+func RunMQTTPublisher(ctx context.Context, bus *Bus) {
+	backoff := mqttMinBackoff
+	for {
+		cfg := currentMQTTConfig()
+		if !cfg.Enable || cfg.Broker == "" {
+			if !sleepContext(ctx, mqttConfigCheckInterval) {
+				return
+			}
+			continue
+		}
+		connected, err := publishUntilDisconnected(ctx, bus, cfg)
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			backoff = mqttMinBackoff
+		}
+		if err == nil {
+			// 配置已变更，立即使用新配置重连
+			continue
+		}
+		logrus.WithError(err).Warnf("MQTT 连接断开，%s 后重连", backoff)
+		if !sleepContext(ctx, backoff) {
+			return
+		}
+		if !connected {
+			if backoff *= 2; backoff > mqttMaxBackoff {
+				backoff = mqttMaxBackoff
+			}
+		}
+	}
+}
+
+func currentMQTTConfig() configs.MQTTConfig {
+	cfg := configs.GetCurrentConfig()
+	if cfg == nil {
+		return configs.MQTTConfig{}
+	}
+	c := cfg.EventStream.MQTT
+	if c.TopicPrefix == "" {
+		c.TopicPrefix = "bililive-go"
+	}
+	c.TopicPrefix = strings.TrimSuffix(c.TopicPrefix, "/")
+	c.Password = configs.ResolveSecret(c.Password)
+	return c
+}
+
+// publishUntilDisconnected 建立连接并持续发布，直到断线、配置变更或 ctx 取消。
+// connected 表示是否成功建立过连接
+func publishUntilDisconnected(ctx context.Context, bus *Bus, cfg configs.MQTTConfig) (connected bool, err error) {
+	statusTopic := cfg.TopicPrefix + "/status"
+	client, err := mqtt.Dial(ctx, mqtt.Options{
+		Broker:      cfg.Broker,
+		ClientID:    cfg.ClientID,
+		Username:    cfg.Username,
+		Password:    cfg.Password,
+		WillTopic:   statusTopic,
+		WillPayload: []byte("offline"),
+		WillRetain:  true,
+	})
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		client.Publish(statusTopic, []byte("offline"), true)
+		client.Close()
+	}()
+	if err := client.Publish(statusTopic, []byte("online"), true); err != nil {
+		return true, err
+	}
+	logrus.WithField("broker", cfg.Broker).Info("已连接 MQTT 服务器")
+
+	msgs, cancel := bus.Subscribe()
+	defer cancel()
+	configTicker := time.NewTicker(mqttConfigCheckInterval)
+	defer configTicker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return true, ctx.Err()
+		case <-client.Done():
+			return true, client.Err()
+		case <-configTicker.C:
+			if currentMQTTConfig() != cfg {
+				return true, nil
+			}
+		case msg, ok := <-msgs:
+			if !ok {
+				return true, nil
+			}
+			if err := publishMessage(client, cfg.TopicPrefix, msg); err != nil {
+				return true, err
+			}
+		}
+	}
+}
+
+func publishMessage(client *mqtt.Client, prefix string, msg Message) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return nil
+	}
+	if err := client.Publish(prefix+"/events/"+string(msg.Type), payload, false); err != nil {
+		return err
+	}
+	// 直播间状态作为保留消息，新订阅者（如 Home Assistant 重启后）可立即获得当前状态
+	if room, ok := msg.Data.(LivePayload); ok && room.LiveID != "" {
+		state, err := json.Marshal(room)
+		if err != nil {
+			return nil
+		}
+		return client.Publish(prefix+"/rooms/"+room.LiveID, state, true)
+	}
+	return nil
+}
+
+// sleepContext 等待 d，ctx 取消时返回 false
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
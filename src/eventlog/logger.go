@@ -0,0 +1,205 @@
+package eventlog
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bluele/gcache"
+	"github.com/sirupsen/logrus"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/eventbus"
+	"github.com/bililive-go/bililive-go/src/listeners"
+	"github.com/bililive-go/bililive-go/src/live"
+	"github.com/bililive-go/bililive-go/src/pipeline"
+	"github.com/bililive-go/bililive-go/src/pkg/events"
+	"github.com/bililive-go/bililive-go/src/recorders"
+)
+
+// 只记录在事件日志中的事件类型（其余类型与对外事件流一致，见 eventbus.Type）
+const (
+	TypeListenStart     = "listen_start"
+	TypeListenStop      = "listen_stop"
+	TypeRoomInitialized = "room_initialized"
+	TypeRecorderRestart = "recorder_restart"
+	TypeRecorderError   = "recorder_error"
+)
+
+// logOnlyTypes 对外事件流之外额外记录的内部事件
+var logOnlyTypes = map[events.EventType]string{
+	listeners.ListenStart:              TypeListenStart,
+	listeners.ListenStop:               TypeListenStop,
+	listeners.RoomInitializingFinished: TypeRoomInitialized,
+	recorders.RecorderRestart:          TypeRecorderRestart,
+	recorders.RecorderError:            TypeRecorderError,
+}
+
+// loggedEventTypes 需要记录的全部内部事件
+var loggedEventTypes = []events.EventType{
+	listeners.ListenStart,
+	listeners.ListenStop,
+	listeners.LiveStart,
+	listeners.LiveEnd,
+	listeners.RoomNameChanged,
+	listeners.RoomInitializingFinished,
+	recorders.RecorderStart,
+	recorders.RecorderStop,
+	recorders.RecorderRestart,
+	recorders.RecorderError,
+	pipeline.PipelineTaskUpdateEvent,
+	eventbus.DiskSpaceLow,
+	eventbus.DiskSpaceRecovered,
+}
+
+const (
+	// errorDedupWindow 同一直播间同一错误在该时间内只记录一次，避免重试时刷屏
+	errorDedupWindow = 10 * time.Minute
+	// cleanupInterval 过期事件清理间隔
+	cleanupInterval = time.Hour
+	writeTimeout    = 5 * time.Second
+)
+
+// RecorderErrorPayload recorder_error 事件的数据
+type RecorderErrorPayload struct {
+	eventbus.LivePayload
+	Stage string `json:"stage"`
+	Error string `json:"error"`
+}
+
+// Logger 把事件分发器上的事件写入事件日志
+type Logger struct {
+	store Store
+	cache gcache.Cache
+
+	errMu      sync.Mutex
+	lastErrors map[string]time.Time
+}
+
+// NewLogger 创建事件日志记录器
+func NewLogger(store Store, cache gcache.Cache) *Logger {
+	return &Logger{
+		store:      store,
+		cache:      cache,
+		lastErrors: make(map[string]time.Time),
+	}
+}
+
+// Register 注册事件监听
+func (l *Logger) Register(ed events.Dispatcher) {
+	handler := events.NewEventListener(l.handle)
+	for _, eventType := range loggedEventTypes {
+		ed.AddEventListener(eventType, handler)
+	}
+}
+
+func (l *Logger) handle(event *events.Event) {
+	now := time.Now()
+	typ, liveID, data, ok := l.convert(event, now)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+	if _, err := l.store.Append(ctx, now, typ, liveID, data); err != nil {
+		logrus.WithError(err).WithField("type", typ).Warn("写入事件日志失败")
+	}
+}
+
+// convert 将内部事件转换为日志记录，不需要记录的事件返回 false
+func (l *Logger) convert(event *events.Event, now time.Time) (typ, liveID string, data any, ok bool) {
+	if event == nil {
+		return "", "", nil, false
+	}
+	if busType, payload, ok := eventbus.Convert(event, l.cache); ok {
+		switch p := payload.(type) {
+		case eventbus.LivePayload:
+			liveID = p.LiveID
+		case eventbus.PipelineTaskPayload:
+			// 只记录后处理任务的最终结果，进度更新过于频繁
+			if !isFinished(pipeline.PipelineStatus(p.Status)) {
+				return "", "", nil, false
+			}
+			liveID = p.LiveID
+		}
+		return string(busType), liveID, payload, true
+	}
+
+	typ, ok = logOnlyTypes[event.Type]
+	if !ok {
+		return "", "", nil, false
+	}
+	switch obj := event.Object.(type) {
+	case live.Live:
+		payload := eventbus.NewLivePayload(obj, l.cache)
+		return typ, payload.LiveID, payload, true
+	case *recorders.ErrorInfo:
+		payload := RecorderErrorPayload{
+			LivePayload: eventbus.NewLivePayload(obj.Live, l.cache),
+			Stage:       obj.Stage,
+		}
+		if obj.Err != nil {
+			payload.Error = obj.Err.Error()
+		}
+		if l.isDuplicateError(payload, now) {
+			return "", "", nil, false
+		}
+		return typ, payload.LiveID, payload, true
+	}
+	return "", "", nil, false
+}
+
+func isFinished(status pipeline.PipelineStatus) bool {
+	switch status {
+	case pipeline.PipelineStatusCompleted, pipeline.PipelineStatusFailed, pipeline.PipelineStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// isDuplicateError 判断同一错误是否在去重窗口内已记录过
+func (l *Logger) isDuplicateError(p RecorderErrorPayload, now time.Time) bool {
+	key := p.LiveID + "\x00" + p.Stage + "\x00" + p.Error
+	l.errMu.Lock()
+	defer l.errMu.Unlock()
+	for k, t := range l.lastErrors {
+		if now.Sub(t) >= errorDedupWindow {
+			delete(l.lastErrors, k)
+		}
+	}
+	if _, ok := l.lastErrors[key]; ok {
+		return true
+	}
+	l.lastErrors[key] = now
+	return false
+}
+
+// RunCleanup 定期删除超过 event_stream.log_retention_days 的事件
+func RunCleanup(ctx context.Context, store Store) {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+	for {
+		cleanup(ctx, store)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func cleanup(ctx context.Context, store Store) {
+	cfg := configs.GetCurrentConfig()
+	if cfg == nil || cfg.EventStream.LogRetentionDays <= 0 {
+		return
+	}
+	before := time.Now().AddDate(0, 0, -cfg.EventStream.LogRetentionDays)
+	n, err := store.Cleanup(ctx, before)
+	if err != nil {
+		logrus.WithError(err).Warn("清理事件日志失败")
+		return
+	}
+	if n > 0 {
+		logrus.Debugf("已清理 %d 条过期事件日志", n)
+	}
+}
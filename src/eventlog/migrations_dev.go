@@ -0,0 +1,36 @@
+//go:build dev
+
+package eventlog
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/bililive-go/bililive-go/src/pkg/migration"
+)
+
+type eventLogMigrationSource struct{}
+
+// GetFS 返回迁移文件目录的文件系统（dev 模式使用实际文件）
+func (s *eventLogMigrationSource) GetFS() (fs.FS, error) {
+	_, currentFile, _, _ := runtime.Caller(0)
+	migrationsDir := filepath.Join(filepath.Dir(currentFile), "migrations")
+	return os.DirFS(migrationsDir), nil
+}
+
+// GetSubDir 返回迁移文件在 FS 中的子目录
+func (s *eventLogMigrationSource) GetSubDir() string {
+	return "."
+}
+
+// IsEmbedded 返回迁移文件是否嵌入
+func (s *eventLogMigrationSource) IsEmbedded() bool {
+	return false
+}
+
+// GetMigrationSource 获取 事件日志数据库迁移源
+func GetMigrationSource() migration.MigrationSource {
+	return &eventLogMigrationSource{}
+}
@@ -0,0 +1,20 @@
+package eventlog
+
+import (
+	"github.com/bililive-go/bililive-go/src/pkg/migration"
+)
+
+// DatabaseTypeEventLog 事件日志数据库类型
+const DatabaseTypeEventLog migration.DatabaseType = "eventlog"
+
+// EventLogDatabaseSchema 事件日志数据库模式定义
+var EventLogDatabaseSchema = &migration.DatabaseSchema{
+	Type:            DatabaseTypeEventLog,
+	Category:        migration.CategoryNormal,
+	MigrationSource: GetMigrationSource(),
+	Description:     "事件日志数据库，按顺序记录开播/下播、录制、后处理结果等事件，用于回放与审计",
+}
+
+func init() {
+	migration.MustRegisterSchema(EventLogDatabaseSchema)
+}
@@ -0,0 +1,213 @@
+package eventlog
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	_ "modernc.org/sqlite"
+
+	"github.com/bililive-go/bililive-go/src/pkg/migration"
+)
+
+const (
+	// DefaultLimit 单次查询默认返回的事件数
+	DefaultLimit = 100
+	// MaxLimit 单次查询最多返回的事件数
+	MaxLimit = 1000
+)
+
+// Entry 事件日志中的一条记录
+type Entry struct {
+	ID     int64           `json:"id"` // 单调递增，可作为回放游标
+	Time   time.Time       `json:"time"`
+	Type   string          `json:"type"`
+	LiveID string          `json:"live_id,omitempty"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// Query 事件查询条件
+type Query struct {
+	Since  int64    // 只返回 id 大于该值的事件
+	Limit  int      // 最多返回条数，<=0 时使用 DefaultLimit
+	Types  []string // 为空表示全部类型
+	LiveID string   // 为空表示全部直播间
+}
+
+// Store 事件日志存储接口
+type Store interface {
+	// Append 追加一条事件，返回其 id
+	Append(ctx context.Context, t time.Time, typ, liveID string, data any) (int64, error)
+	// List 按 id 升序返回游标之后的事件
+	List(ctx context.Context, query Query) ([]Entry, error)
+	// Cleanup 删除 before 之前的事件，返回删除条数
+	Cleanup(ctx context.Context, before time.Time) (int64, error)
+	// Close 关闭存储
+	Close() error
+}
+
+// SQLiteStore SQLite 存储实现
+type SQLiteStore struct {
+	db     *sql.DB
+	dbPath string
+	mu     sync.Mutex
+}
+
+// NewSQLiteStore 创建 SQLite 存储
+func NewSQLiteStore(dbPath string) (*SQLiteStore, error) {
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	db.SetMaxOpenConns(1) // SQLite 单写入
+	db.SetMaxIdleConns(1)
+
+	store := &SQLiteStore{
+		db:     db,
+		dbPath: dbPath,
+	}
+	if err := store.runMigrations(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+	return store, nil
+}
+
+// runMigrations 运行数据库迁移
+func (s *SQLiteStore) runMigrations() error {
+	config := &migration.MigrationConfig{
+		DBPath: s.dbPath,
+		Schema: EventLogDatabaseSchema,
+		DB:     s.db,
+	}
+
+	migrator, err := migration.NewMigrator(config)
+	if err != nil {
+		return fmt.Errorf("创建迁移器失败: %w", err)
+	}
+
+	recovered, err := migrator.CheckAndRecover()
+	if err != nil {
+		logrus.WithError(err).Warn("事件日志数据库迁移恢复检查失败")
+	}
+	if recovered {
+		logrus.Info("事件日志数据库从未完成的迁移中恢复")
+		s.db.Close()
+		db, err := sql.Open("sqlite", s.dbPath)
+		if err != nil {
+			return fmt.Errorf("恢复后重新打开数据库失败: %w", err)
+		}
+		s.db = db
+		config.DB = s.db
+		migrator, err = migration.NewMigrator(config)
+		if err != nil {
+			return fmt.Errorf("恢复后重新创建迁移器失败: %w", err)
+		}
+	}
+
+	if _, err := migrator.Run(); err != nil {
+		return fmt.Errorf("迁移失败: %w", err)
+	}
+	return nil
+}
+
+// Append 追加一条事件
+func (s *SQLiteStore) Append(ctx context.Context, t time.Time, typ, liveID string, data any) (int64, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal event data: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res, err := s.db.ExecContext(ctx,
+		`INSERT INTO events (timestamp, type, live_id, data) VALUES (?, ?, ?, ?)`,
+		t.UnixMilli(), typ, liveID, string(payload),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// List 按 id 升序返回游标之后的事件
+func (s *SQLiteStore) List(ctx context.Context, query Query) ([]Entry, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	sqlStr := `SELECT id, timestamp, type, live_id, data FROM events WHERE id > ?`
+	args := []any{query.Since}
+	if len(query.Types) > 0 {
+		sqlStr += ` AND type IN (?` + strings.Repeat(`, ?`, len(query.Types)-1) + `)`
+		for _, t := range query.Types {
+			args = append(args, t)
+		}
+	}
+	if query.LiveID != "" {
+		sqlStr += ` AND live_id = ?`
+		args = append(args, query.LiveID)
+	}
+	sqlStr += ` ORDER BY id LIMIT ?`
+	args = append(args, limit)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows, err := s.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]Entry, 0)
+	for rows.Next() {
+		var (
+			e    Entry
+			ts   int64
+			data string
+		)
+		if err := rows.Scan(&e.ID, &ts, &e.Type, &e.LiveID, &data); err != nil {
+			return nil, err
+		}
+		e.Time = time.UnixMilli(ts)
+		e.Data = json.RawMessage(data)
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// Cleanup 删除 before 之前的事件
+func (s *SQLiteStore) Cleanup(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM events WHERE timestamp < ?`, before.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("failed to cleanup events: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// Close 关闭存储
+func (s *SQLiteStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.db.Close()
+}
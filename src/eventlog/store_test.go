@@ -0,0 +1,101 @@
+package eventlog
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+
+	"github.com/bililive-go/bililive-go/src/listeners"
+	livemock "github.com/bililive-go/bililive-go/src/live/mock"
+	"github.com/bililive-go/bililive-go/src/pipeline"
+	"github.com/bililive-go/bililive-go/src/pkg/events"
+	"github.com/bililive-go/bililive-go/src/recorders"
+	"github.com/bililive-go/bililive-go/src/types"
+)
+
+func TestStoreReplay(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "events.db"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	old := time.Now().Add(-48 * time.Hour)
+	now := time.Now()
+	for i, e := range []struct {
+		t      time.Time
+		typ    string
+		liveID string
+	}{
+		{old, "live_start", "a"},
+		{now, "live_start", "b"},
+		{now, "live_end", "a"},
+		{now, "disk_alert", ""},
+	} {
+		id, err := store.Append(ctx, e.t, e.typ, e.liveID, map[string]int{"n": i})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(i+1), id)
+	}
+
+	all, err := store.List(ctx, Query{})
+	assert.NoError(t, err)
+	assert.Len(t, all, 4)
+	assert.JSONEq(t, `{"n":0}`, string(all[0].Data))
+
+	page, err := store.List(ctx, Query{Since: 1, Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 3}, []int64{page[0].ID, page[1].ID})
+
+	filtered, err := store.List(ctx, Query{Types: []string{"live_start", "live_end"}, LiveID: "a"})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 3}, []int64{filtered[0].ID, filtered[1].ID})
+
+	n, err := store.Cleanup(ctx, now.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	// 清理后 id 不会复用，游标仍然有效
+	id, err := store.Append(ctx, now, "live_start", "a", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), id)
+}
+
+func TestLoggerConvert(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	l := livemock.NewMockLive(ctrl)
+	l.EXPECT().GetLiveId().Return(types.LiveID("id1")).AnyTimes()
+	l.EXPECT().GetRawUrl().Return("https://live.bilibili.com/1").AnyTimes()
+	l.EXPECT().GetPlatformCNName().Return("哔哩哔哩").AnyTimes()
+	logger := NewLogger(nil, nil)
+	now := time.Now()
+
+	typ, liveID, _, ok := logger.convert(events.NewEvent(listeners.ListenStart, l), now)
+	assert.True(t, ok)
+	assert.Equal(t, TypeListenStart, typ)
+	assert.Equal(t, "id1", liveID)
+
+	task := &pipeline.PipelineTask{Status: pipeline.PipelineStatusRunning}
+	_, _, _, ok = logger.convert(events.NewEvent(pipeline.PipelineTaskUpdateEvent, task), now)
+	assert.False(t, ok, "进行中的后处理任务不记录")
+	task.Status = pipeline.PipelineStatusFailed
+	typ, _, _, ok = logger.convert(events.NewEvent(pipeline.PipelineTaskUpdateEvent, task), now)
+	assert.True(t, ok)
+	assert.Equal(t, "pipeline_task_update", typ)
+
+	recErr := events.NewEvent(recorders.RecorderError, &recorders.ErrorInfo{Live: l, Stage: "parse", Err: errors.New("404")})
+	typ, _, data, ok := logger.convert(recErr, now)
+	assert.True(t, ok)
+	assert.Equal(t, TypeRecorderError, typ)
+	assert.Equal(t, "404", data.(RecorderErrorPayload).Error)
+	_, _, _, ok = logger.convert(recErr, now.Add(time.Minute))
+	assert.False(t, ok, "重试时的相同错误不重复记录")
+	_, _, _, ok = logger.convert(recErr, now.Add(errorDedupWindow+time.Minute))
+	assert.True(t, ok)
+}
@@ -21,4 +21,5 @@ type Instance struct {
 	LiveStateManager interface{}       // 直播间状态持久化管理器 (*livestate.Manager)
 	LiveStateStore   interface{}       // 直播间状态存储 (livestate.Store)
 	IOStatsModule    interfaces.Module // IO 统计模块 (*iostats.Module)
+	EventLogStore    interface{}       // 事件日志存储 (eventlog.Store)
 }
@@ -0,0 +1,170 @@
+package listeners
+
+import (
+	"context"
+	"time"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/live"
+	applog "github.com/bililive-go/bililive-go/src/log"
+	"github.com/bililive-go/bililive-go/src/pkg/proxy"
+	"github.com/bililive-go/bililive-go/src/pkg/ratelimit"
+)
+
+// batchTickInterval 批量查询循环检查到期直播间的间隔
+var batchTickInterval = time.Second
+
+// batchCandidate 一个可参与批量查询的直播间
+type batchCandidate struct {
+	receiver live.BatchInfoReceiver
+	inner    live.Live
+	next     time.Time     // 预计下次刷新时间
+	interval time.Duration // 配置的访问间隔
+}
+
+// batchGroup 同一平台、同一代理的直播间合并查询
+type batchGroup struct {
+	provider   live.BatchInfoProvider
+	platform   string
+	candidates []batchCandidate
+}
+
+// runBatchLoop 周期性地把到期的直播间按平台合并为批量请求，
+// 结果通过 ApplyBatchInfo 分发给各直播间的调度器，再由调度器通知等待的监听器
+func (m *manager) runBatchLoop(ctx context.Context) {
+	ticker := time.NewTicker(batchTickInterval)
+	defer ticker.Stop()
+	attempts := make(map[live.BatchInfoReceiver]time.Time)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.batchRefresh(ctx, attempts, time.Now())
+		}
+	}
+}
+
+// collectBatchGroups 收集正在监听且平台支持批量查询的直播间
+func (m *manager) collectBatchGroups(attempts map[live.BatchInfoReceiver]time.Time) map[string]*batchGroup {
+	m.lock.RLock()
+	receivers := make([]live.BatchInfoReceiver, 0, len(m.savers))
+	for _, saver := range m.savers {
+		if l, ok := saver.(*listener); ok {
+			if receiver, ok := l.Live.(live.BatchInfoReceiver); ok {
+				receivers = append(receivers, receiver)
+			}
+		}
+	}
+	m.lock.RUnlock()
+
+	groups := make(map[string]*batchGroup)
+	seen := make(map[live.BatchInfoReceiver]struct{}, len(receivers))
+	for _, receiver := range receivers {
+		seen[receiver] = struct{}{}
+		inner := receiver.Unwrap()
+		provider := live.GetBatchInfoProvider(inner.GetRawUrl())
+		if provider == nil || !provider.CanBatch(inner) {
+			receiver.SetBatched(false)
+			continue
+		}
+		receiver.SetBatched(true)
+
+		status := receiver.GetSchedulerStatus()
+		if !status.HasWaiters {
+			continue
+		}
+		last := status.LastRequestAt
+		if attempt := attempts[receiver]; attempt.After(last) {
+			last = attempt
+		}
+		interval := time.Duration(status.IntervalSeconds) * time.Second
+
+		// 使用不同代理的直播间不能合并到同一个请求中
+		key := configs.GetPlatformKeyFromUrl(inner.GetRawUrl()) + "|" + proxy.GetInfoProxyURLForRoom(inner.GetRawUrl())
+		group, ok := groups[key]
+		if !ok {
+			group = &batchGroup{provider: provider, platform: configs.GetPlatformKeyFromUrl(inner.GetRawUrl())}
+			groups[key] = group
+		}
+		group.candidates = append(group.candidates, batchCandidate{
+			receiver: receiver,
+			inner:    inner,
+			next:     last.Add(interval),
+			interval: interval,
+		})
+	}
+	// 清理已移除的直播间
+	for receiver := range attempts {
+		if _, ok := seen[receiver]; !ok {
+			delete(attempts, receiver)
+		}
+	}
+	return groups
+}
+
+// batchRefresh 对每个有到期直播间的分组发起批量查询
+func (m *manager) batchRefresh(ctx context.Context, attempts map[live.BatchInfoReceiver]time.Time, now time.Time) {
+	for _, group := range m.collectBatchGroups(attempts) {
+		due := selectDueCandidates(group.candidates, now)
+		size := group.provider.MaxBatchSize()
+		if size <= 0 {
+			size = len(due)
+		}
+		for start := 0; start < len(due); start += size {
+			end := min(start+size, len(due))
+			if !m.fetchBatch(ctx, group, due[start:end], attempts) {
+				return
+			}
+		}
+	}
+}
+
+// selectDueCandidates 只要分组内有直播间到期就发起请求，
+// 并顺带刷新半个间隔内即将到期的直播间，使各直播间的刷新时间逐渐对齐
+func selectDueCandidates(candidates []batchCandidate, now time.Time) []batchCandidate {
+	hasDue := false
+	for _, c := range candidates {
+		if !c.next.After(now) {
+			hasDue = true
+			break
+		}
+	}
+	if !hasDue {
+		return nil
+	}
+	due := make([]batchCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		if !c.next.After(now.Add(c.interval / 2)) {
+			due = append(due, c)
+		}
+	}
+	return due
+}
+
+// fetchBatch 发送一次批量请求并分发结果，ctx 取消时返回 false
+func (m *manager) fetchBatch(ctx context.Context, group *batchGroup, candidates []batchCandidate, attempts map[live.BatchInfoReceiver]time.Time) bool {
+	// 一次批量请求只占用一次平台访问频率配额
+	if group.platform != "" && !ratelimit.GetGlobalRateLimiter().WaitForPlatformWithContext(ctx, group.platform) {
+		return false
+	}
+	lives := make([]live.Live, 0, len(candidates))
+	now := time.Now()
+	for _, c := range candidates {
+		lives = append(lives, c.inner)
+		attempts[c.receiver] = now
+	}
+	infos, err := group.provider.GetInfos(lives)
+	if err != nil {
+		// 失败时不分发错误，由各直播间的调度器在兜底间隔后单独请求
+		applog.GetLogger().WithError(err).WithField("platform", group.platform).
+			Warnf("批量查询 %d 个直播间失败", len(lives))
+		return true
+	}
+	for _, c := range candidates {
+		if info, ok := infos[c.inner]; ok && info != nil {
+			c.receiver.ApplyBatchInfo(info)
+		}
+	}
+	return true
+}
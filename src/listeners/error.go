@@ -5,4 +5,5 @@ import "errors"
 var (
 	ErrListenerExist    = errors.New("this live has a listener")
 	ErrListenerNotExist = errors.New("this live has not a listener")
+	ErrLiveNotOwned     = errors.New("this live is assigned to another cluster node")
 )
@@ -3,6 +3,7 @@ package listeners
 
 import (
 	"context"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -23,6 +24,9 @@ const (
 	stopped
 )
 
+// watcherFallbackInterval 推送连接正常时的兜底轮询间隔
+var watcherFallbackInterval = 10 * time.Minute
+
 type Listener interface {
 	Start() error
 	Close()
@@ -33,13 +37,14 @@ func NewListener(ctx context.Context, live live.Live) Listener {
 	// 创建一个可取消的 context，用于控制 run 循环中的等待
 	runCtx, cancel := context.WithCancel(ctx)
 	return &listener{
-		Live:      live,
-		status:    status{},
-		stop:      make(chan struct{}),
-		ed:        inst.EventDispatcher.(events.Dispatcher),
-		state:     begin,
-		runCtx:    runCtx,
-		runCancel: cancel,
+		Live:        live,
+		status:      status{},
+		stop:        make(chan struct{}),
+		ed:          inst.EventDispatcher.(events.Dispatcher),
+		state:       begin,
+		runCtx:      runCtx,
+		runCancel:   cancel,
+		watcherWake: make(chan struct{}, 1),
 	}
 }
 
@@ -52,6 +57,15 @@ type listener struct {
 	stop      chan struct{}
 	runCtx    context.Context    // 用于控制 run 循环中的等待
 	runCancel context.CancelFunc // 取消 runCtx
+
+	// statusMu 保护 status 与 lastInfo，推送与轮询可能并发处理
+	statusMu sync.Mutex
+	lastInfo *live.Info
+
+	// watcherConnected 开播推送连接是否正常，正常时轮询降为低频兜底
+	watcherConnected atomic.Bool
+	// watcherWake 推送连接断开时唤醒 run 循环，立即恢复正常轮询
+	watcherWake chan struct{}
 }
 
 func (l *listener) Start() error {
@@ -62,10 +76,62 @@ func (l *listener) Start() error {
 
 	l.ed.DispatchEvent(events.NewEvent(ListenStart, l.Live))
 	l.refresh()
+	if watcher := newWatcher(l.Live); watcher != nil {
+		bilisentry.GoWithContext(l.runCtx, func(ctx context.Context) {
+			watcher.Run(ctx, l.onPushStatus, l.onWatcherConnected)
+		})
+	}
 	bilisentry.Go(func() { l.run() })
 	return nil
 }
 
+// onWatcherConnected 推送连接状态变化
+func (l *listener) onWatcherConnected(connected bool) {
+	if l.watcherConnected.Swap(connected) == connected {
+		return
+	}
+	if connected {
+		l.Live.GetLogger().Info("开播推送已连接，轮询降为低频兜底")
+		return
+	}
+	l.Live.GetLogger().Info("开播推送已断开，回退到轮询")
+	select {
+	case l.watcherWake <- struct{}{}:
+	default:
+	}
+}
+
+// onPushStatus 收到开播/下播推送，立即刷新直播间状态
+func (l *listener) onPushStatus(living bool) {
+	l.statusMu.Lock()
+	current := l.status.roomStatus
+	l.statusMu.Unlock()
+	if current == living {
+		return
+	}
+	l.Live.GetLogger().Infof("收到直播状态推送: living=%v", living)
+
+	info, err := l.Live.GetInfo()
+	if err != nil || info.Status != living {
+		// 平台接口的状态可能滞后于推送，以推送为准
+		info = l.pushedInfo(living)
+	}
+	l.processInfo(info)
+}
+
+// pushedInfo 基于最近一次获取的直播间信息构造推送后的状态
+func (l *listener) pushedInfo(living bool) *live.Info {
+	l.statusMu.Lock()
+	defer l.statusMu.Unlock()
+	info := &live.Info{Live: l.Live}
+	if l.lastInfo != nil {
+		cp := *l.lastInfo
+		info = &cp
+	}
+	info.Status = living
+	return info
+}
+
 func (l *listener) Close() {
 	if !atomic.CompareAndSwapUint32(&l.state, running, stopped) {
 		return
@@ -112,6 +178,19 @@ func (l *listener) run() {
 		case <-l.stop:
 			return
 		default:
+			// 推送连接正常时只做低频兜底轮询，连接断开时立即恢复正常轮询
+			if l.watcherConnected.Load() {
+				timer := time.NewTimer(watcherFallbackInterval)
+				select {
+				case <-l.stop:
+					timer.Stop()
+					return
+				case <-l.watcherWake:
+					timer.Stop()
+					continue
+				case <-timer.C:
+				}
+			}
 			// 使用 GetInfoWithInterval，它会等待配置的间隔时间后再发送请求
 			info, err := l.Live.GetInfoWithInterval(l.runCtx)
 			if err != nil {
@@ -132,6 +211,10 @@ func (l *listener) run() {
 
 // processInfo 处理获取到的直播间信息，检测状态变化并触发事件
 func (l *listener) processInfo(info *live.Info) {
+	l.statusMu.Lock()
+	defer l.statusMu.Unlock()
+	l.lastInfo = info
+
 	// 尝试从缓存中获取主播姓名，以防API调用失败
 	hostName := info.HostName
 	if hostName == "" {
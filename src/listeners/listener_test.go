@@ -134,3 +134,51 @@ func TestListenerStartAndClose(t *testing.T) {
 	l.Close()
 	l.Close()
 }
+
+func TestPushStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ed := evtmock.NewMockDispatcher(ctrl)
+	configs.SetCurrentConfig(configs.NewConfig())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = context.WithValue(ctx, instance.Key, &instance.Instance{
+		EventDispatcher: ed,
+	})
+	log.New(ctx)
+	live := livemock.NewMockLive(ctrl)
+	testLogger := livelogger.New(1024, logrus.Fields{"test": "listener"})
+	live.EXPECT().GetLogger().Return(testLogger).AnyTimes()
+	live.EXPECT().GetRawUrl().Return("").AnyTimes()
+	live.EXPECT().GetPlatformCNName().Return("platform").AnyTimes()
+	l := NewListener(ctx, live).(*listener)
+
+	live.EXPECT().GetInfo().Return(&livepkg.Info{Status: false, RoomName: "room"}, nil)
+	l.refresh()
+
+	// 接口状态滞后于推送时以推送为准，并保留已有的直播间信息
+	live.EXPECT().GetInfo().Return(&livepkg.Info{Status: false, RoomName: "room"}, nil)
+	live.EXPECT().SetLastStartTime(gomock.Any())
+	ed.EXPECT().DispatchEvent(events.NewEvent(LiveStart, live))
+	l.onPushStatus(true)
+	assert.True(t, l.status.roomStatus)
+	assert.Equal(t, "room", l.status.roomName)
+
+	// 重复推送不触发请求
+	l.onPushStatus(true)
+
+	live.EXPECT().GetInfo().Return(&livepkg.Info{Status: false, RoomName: "room"}, nil)
+	ed.EXPECT().DispatchEvent(events.NewEvent(LiveEnd, live))
+	l.onPushStatus(false)
+	assert.False(t, l.status.roomStatus)
+
+	// 推送连接断开时唤醒轮询
+	l.onWatcherConnected(true)
+	assert.True(t, l.watcherConnected.Load())
+	l.onWatcherConnected(false)
+	select {
+	case <-l.watcherWake:
+	default:
+		t.Fatal("推送断开后应唤醒轮询")
+	}
+}
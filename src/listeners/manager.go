@@ -9,12 +9,28 @@ import (
 	"github.com/bililive-go/bililive-go/src/interfaces"
 	"github.com/bililive-go/bililive-go/src/live"
 	"github.com/bililive-go/bililive-go/src/pkg/events"
+	bilisentry "github.com/bililive-go/bililive-go/src/pkg/sentry"
 	"github.com/bililive-go/bililive-go/src/types"
 )
 
 // for test
 var newListener = NewListener
 
+// OwnershipChecker 判断直播间是否由本节点负责监控（由 cluster 包设置，避免循环依赖）
+type OwnershipChecker func(live live.Live) bool
+
+var ownershipChecker OwnershipChecker
+
+// SetOwnershipChecker 设置直播间归属检查函数，集群模式下只为分配给本节点的直播间添加 Listener
+func SetOwnershipChecker(checker OwnershipChecker) {
+	ownershipChecker = checker
+}
+
+// IsOwnedLocally 直播间是否由本节点负责监控，未启用集群时总是 true
+func IsOwnedLocally(live live.Live) bool {
+	return ownershipChecker == nil || ownershipChecker(live)
+}
+
 func NewManager(ctx context.Context) Manager {
 	lm := &manager{
 		savers: make(map[types.LiveID]Listener),
@@ -88,6 +104,7 @@ func (m *manager) Start(ctx context.Context) error {
 		inst.WaitGroup.Add(1)
 	}
 	m.registryListener(ctx, inst.EventDispatcher.(events.Dispatcher))
+	bilisentry.GoWithContext(ctx, m.runBatchLoop)
 	return nil
 }
 
@@ -103,6 +120,9 @@ func (m *manager) Close(ctx context.Context) {
 }
 
 func (m *manager) AddListener(ctx context.Context, live live.Live) error {
+	if !IsOwnedLocally(live) {
+		return ErrLiveNotOwned
+	}
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
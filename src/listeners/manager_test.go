@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/bluele/gcache"
 	"github.com/stretchr/testify/assert"
 	gomock "go.uber.org/mock/gomock"
 
@@ -74,3 +76,82 @@ func TestManagerStartAndClose(t *testing.T) {
 	}
 	m.Close(ctx)
 }
+
+type fakeBatchProvider struct {
+	calls int
+	lives []live.Live
+}
+
+func (p *fakeBatchProvider) MaxBatchSize() int { return 10 }
+
+func (p *fakeBatchProvider) CanBatch(l live.Live) bool { return true }
+
+func (p *fakeBatchProvider) GetInfos(lives []live.Live) (map[live.Live]*live.Info, error) {
+	p.calls++
+	p.lives = lives
+	infos := make(map[live.Live]*live.Info)
+	for _, l := range lives {
+		infos[l] = &live.Info{Live: l, Status: true, RoomName: l.GetRawUrl()}
+	}
+	return infos, nil
+}
+
+func TestManagerBatchRefresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cfg := configs.NewConfig()
+	cfg.Interval = 30
+	configs.SetCurrentConfig(cfg)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	provider := &fakeBatchProvider{}
+	live.RegisterBatchInfoProvider("batch.test", provider)
+
+	m := NewManager(context.WithValue(ctx, instance.Key, &instance.Instance{})).(*manager)
+	cache := gcache.New(4).LRU().Build()
+	wrapped := make([]live.Live, 0, 2)
+	for i := 1; i <= 2; i++ {
+		inner := livemock.NewMockLive(ctrl)
+		inner.EXPECT().GetRawUrl().Return(fmt.Sprintf("https://batch.test/%d", i)).AnyTimes()
+		w := live.NewWrappedLive(ctx, inner, cache)
+		wrapped = append(wrapped, w)
+		m.savers[types.LiveID(fmt.Sprint(i))] = &listener{Live: w}
+	}
+
+	// 两个监听器等待下一次刷新
+	results := make(chan *live.Info, 2)
+	for _, w := range wrapped {
+		go func(w live.Live) {
+			info, err := w.GetInfoWithInterval(ctx)
+			assert.NoError(t, err)
+			results <- info
+		}(w)
+	}
+	assert.Eventually(t, func() bool {
+		for _, w := range wrapped {
+			if !w.(live.SchedulerStatusProvider).GetSchedulerStatus().HasWaiters {
+				return false
+			}
+		}
+		return true
+	}, time.Second, 10*time.Millisecond)
+
+	attempts := make(map[live.BatchInfoReceiver]time.Time)
+	m.batchRefresh(ctx, attempts, time.Now())
+	assert.Equal(t, 1, provider.calls, "两个直播间合并为一次请求")
+	assert.Len(t, provider.lives, 2)
+	for range wrapped {
+		info := <-results
+		assert.True(t, info.Status)
+	}
+	for _, w := range wrapped {
+		status := w.(live.SchedulerStatusProvider).GetSchedulerStatus()
+		assert.True(t, status.Batched)
+		assert.False(t, status.LastRequestAt.IsZero())
+	}
+
+	// 未到期且没有等待者时不会再次请求
+	m.batchRefresh(ctx, attempts, time.Now())
+	assert.Equal(t, 1, provider.calls)
+}
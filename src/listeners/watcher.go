@@ -0,0 +1,71 @@
+package listeners
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/live"
+)
+
+// LiveStatusWatcher 通过平台的长连接推送（如弹幕 WebSocket）实时感知开播/下播，
+// 避免轮询间隔导致错过直播开头
+type LiveStatusWatcher interface {
+	// Run 保持连接直到 ctx 取消。onStatus 在收到开播(true)/下播(false)推送时调用；
+	// onConnected 在连接建立(true)/断开(false)时调用，断开期间监听器回退到轮询
+	Run(ctx context.Context, onStatus func(living bool), onConnected func(connected bool))
+}
+
+// WatcherBuilder 为直播间创建推送监听器，不支持该直播间时返回 nil
+type WatcherBuilder func(l live.Live) LiveStatusWatcher
+
+var (
+	watcherBuildersMu sync.RWMutex
+	watcherBuilders   = make(map[string]WatcherBuilder)
+)
+
+// RegisterWatcher 为平台域名注册推送监听器
+func RegisterWatcher(host string, builder WatcherBuilder) {
+	watcherBuildersMu.Lock()
+	defer watcherBuildersMu.Unlock()
+	watcherBuilders[host] = builder
+}
+
+// newWatcher 按配置为直播间创建推送监听器，未启用或平台不支持时返回 nil
+var newWatcher = func(l live.Live) LiveStatusWatcher {
+	cfg := configs.GetCurrentConfig()
+	if cfg == nil || !cfg.GetEffectiveConfigForRoom(l.GetRawUrl()).Feature.EnableLiveWatcher {
+		return nil
+	}
+	u, err := url.Parse(l.GetRawUrl())
+	if err != nil {
+		return nil
+	}
+	watcherBuildersMu.RLock()
+	builder, ok := watcherBuilders[u.Host]
+	watcherBuildersMu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return builder(l)
+}
+
+// cookieString 返回直播间当前使用的 Cookie（"k=v; k2=v2" 格式）
+func cookieString(l live.Live) string {
+	opts := l.GetOptions()
+	if opts == nil || opts.Cookies == nil {
+		return ""
+	}
+	u, err := url.Parse(l.GetRawUrl())
+	if err != nil {
+		return ""
+	}
+	cookies := opts.Cookies.Cookies(u)
+	parts := make([]string, 0, len(cookies))
+	for _, c := range cookies {
+		parts = append(parts, c.Name+"="+c.Value)
+	}
+	return strings.Join(parts, "; ")
+}
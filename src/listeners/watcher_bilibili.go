@@ -0,0 +1,73 @@
+package listeners
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/bililive-go/bililive-go/src/live"
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/bilibili"
+)
+
+const (
+	bilibiliWatcherMinBackoff = 30 * time.Second
+	bilibiliWatcherMaxBackoff = 5 * time.Minute
+)
+
+func init() {
+	RegisterWatcher("live.bilibili.com", newBilibiliWatcher)
+}
+
+// bilibiliWatcher 通过哔哩哔哩弹幕 WebSocket 的 LIVE/PREPARING 消息感知开播/下播
+type bilibiliWatcher struct {
+	roomID  int
+	cookies string
+	logger  *logrus.Entry
+}
+
+func newBilibiliWatcher(l live.Live) LiveStatusWatcher {
+	u, err := url.Parse(l.GetRawUrl())
+	if err != nil {
+		return nil
+	}
+	roomID, err := strconv.Atoi(strings.Trim(u.Path, "/"))
+	if err != nil || roomID <= 0 {
+		return nil
+	}
+	return &bilibiliWatcher{
+		roomID:  roomID,
+		cookies: cookieString(l),
+		logger:  l.GetLogger().WithField("module", "live_watcher"),
+	}
+}
+
+func (w *bilibiliWatcher) Run(ctx context.Context, onStatus func(bool), onConnected func(bool)) {
+	backoff := bilibiliWatcherMinBackoff
+	for {
+		client := bilibili.NewClient(w.roomID, w.cookies, w.logger)
+		client.OnLiveStatus(onStatus)
+		client.OnConnectionChange(onConnected)
+		// Start 成功后客户端会自行重连，直到 Stop
+		err := client.Start()
+		if err == nil {
+			<-ctx.Done()
+			client.Stop()
+			onConnected(false)
+			return
+		}
+		w.logger.WithError(err).Warnf("开播推送连接失败，%s 后重试（期间使用轮询）", backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > bilibiliWatcherMaxBackoff {
+			backoff = bilibiliWatcherMaxBackoff
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package live
+
+import (
+	"net/url"
+	"sync"
+)
+
+// BatchInfoProvider 由支持多直播间查询接口的平台实现。
+// 监听器管理器会把同一平台的多个直播间合并为一次请求，减少触发平台风控的概率。
+// 目前只有哔哩哔哩实现；斗鱼没有无需登录的多直播间查询接口，仍按直播间单独查询
+type BatchInfoProvider interface {
+	// MaxBatchSize 单次请求最多查询的直播间数量
+	MaxBatchSize() int
+	// CanBatch 判断直播间是否可以参与批量查询（例如需要已解析出真实房间号）
+	CanBatch(l Live) bool
+	// GetInfos 一次请求查询多个直播间的信息。
+	// 结果中缺失的直播间由各自的调度器回退到单独查询
+	GetInfos(lives []Live) (map[Live]*Info, error)
+}
+
+// BatchInfoReceiver 由 WrappedLive 实现，用于接收批量查询的结果
+type BatchInfoReceiver interface {
+	SchedulerStatusProvider
+	// Unwrap 返回被包装的平台 Live
+	Unwrap() Live
+	// SetBatched 标记直播间由批量查询负责刷新，此时调度器只在批量结果长时间缺失时单独请求
+	SetBatched(batched bool)
+	// ApplyBatchInfo 应用批量查询得到的结果，效果等同于一次成功的 GetInfo
+	ApplyBatchInfo(info *Info)
+}
+
+var (
+	batchProvidersMu sync.RWMutex
+	batchProviders   = make(map[string]BatchInfoProvider)
+)
+
+// RegisterBatchInfoProvider 为平台域名注册批量查询实现
+func RegisterBatchInfoProvider(domain string, p BatchInfoProvider) {
+	batchProvidersMu.Lock()
+	defer batchProvidersMu.Unlock()
+	batchProviders[domain] = p
+}
+
+// GetBatchInfoProvider 返回直播间所属平台的批量查询实现，不支持时返回 nil
+func GetBatchInfoProvider(rawURL string) BatchInfoProvider {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil
+	}
+	batchProvidersMu.RLock()
+	defer batchProvidersMu.RUnlock()
+	return batchProviders[u.Host]
+}
@@ -0,0 +1,242 @@
+package bilibili
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/bililive-go/bililive-go/src/pkg/utils"
+)
+
+// 哔哩哔哩 Web 端 Cookie 刷新流程：
+// 1. cookie/info 判断是否需要刷新；
+// 2. 用公钥加密 "refresh_{毫秒时间戳}" 得到 correspondPath，访问 correspond 页面取 refresh_csrf；
+// 3. cookie/refresh 用旧 refresh_token 换取新 Cookie 与新 refresh_token；
+// 4. confirm/refresh 使旧 refresh_token 失效。
+var (
+	navURL            = "https://api.bilibili.com/x/web-interface/nav"
+	cookieInfoURL     = "https://passport.bilibili.com/x/passport-login/web/cookie/info"
+	correspondURL     = "https://www.bilibili.com/correspond/1/"
+	cookieRefreshURL  = "https://passport.bilibili.com/x/passport-login/web/cookie/refresh"
+	confirmRefreshURL = "https://passport.bilibili.com/x/passport-login/web/confirm/refresh"
+
+	newHTTPClient = utils.CreateDefaultClient
+	nowFunc       = time.Now
+)
+
+// correspondPublicKey 生成 correspondPath 使用的 RSA 公钥
+const correspondPublicKey = `-----BEGIN PUBLIC KEY-----
+MIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQDLgd2OAkcGVtoE3ThUREbio0Eg
+Uc/prcajMKXvkCKFCWhJYJcLkcM2DKKcSeFpD/j6Boy538YXnR6VhcuUJOhH2x71
+nzPjfdTcqMz7djHum0qSZA0AyCBDABUqCrfNgCiJ00Ra7GmRj+YCK1NJEuewlb40
+JNrRuoEUXpabUzGB8QIDAQAB
+-----END PUBLIC KEY-----`
+
+var refreshCsrfRegexp = regexp.MustCompile(`<div id="1-name">([^<]+)</div>`)
+
+// CookieStatus Cookie 校验结果
+type CookieStatus struct {
+	IsLogin bool   `json:"is_login"`
+	UID     int64  `json:"uid,omitempty"`
+	Uname   string `json:"uname,omitempty"`
+	// VipStatus 大会员状态，1 为有效；部分高画质需要大会员
+	VipStatus int `json:"vip_status,omitempty"`
+}
+
+// RefreshResult Cookie 刷新结果
+type RefreshResult struct {
+	Cookie       string
+	RefreshToken string
+}
+
+// VerifyCookie 通过 nav 接口校验 Cookie 是否仍处于登录状态
+func VerifyCookie(cookie string) (*CookieStatus, error) {
+	body, _, err := doAuthRequest(http.MethodGet, navURL, cookie, nil)
+	if err != nil {
+		return nil, fmt.Errorf("校验哔哩哔哩 Cookie 失败: %w", err)
+	}
+	// 未登录时 code 为 -101，data.isLogin 为 false，属于正常结果
+	status := &CookieStatus{
+		IsLogin:   gjson.GetBytes(body, "data.isLogin").Bool(),
+		UID:       gjson.GetBytes(body, "data.mid").Int(),
+		Uname:     gjson.GetBytes(body, "data.uname").String(),
+		VipStatus: int(gjson.GetBytes(body, "data.vipStatus").Int()),
+	}
+	return status, nil
+}
+
+// NeedsRefresh 查询 Cookie 是否需要刷新
+func NeedsRefresh(cookie string) (bool, error) {
+	csrf := CookieValue(cookie, "bili_jct")
+	if csrf == "" {
+		return false, fmt.Errorf("cookie 中缺少 bili_jct")
+	}
+	body, _, err := doAuthRequest(http.MethodGet, cookieInfoURL+"?csrf="+url.QueryEscape(csrf), cookie, nil)
+	if err != nil {
+		return false, fmt.Errorf("查询哔哩哔哩 Cookie 刷新状态失败: %w", err)
+	}
+	if code := gjson.GetBytes(body, "code").Int(); code != 0 {
+		return false, fmt.Errorf("查询哔哩哔哩 Cookie 刷新状态失败: code %d, %s", code, gjson.GetBytes(body, "message").String())
+	}
+	return gjson.GetBytes(body, "data.refresh").Bool(), nil
+}
+
+// RefreshCookie 使用 refresh_token 刷新 Cookie，返回新的 Cookie 字符串和新的 refresh_token。
+// 旧 refresh_token 在确认刷新后失效，调用方必须保存新的 refresh_token。
+func RefreshCookie(cookie, refreshToken string) (*RefreshResult, error) {
+	if refreshToken == "" {
+		return nil, fmt.Errorf("缺少 refresh_token，无法刷新 Cookie")
+	}
+	csrf := CookieValue(cookie, "bili_jct")
+	if csrf == "" {
+		return nil, fmt.Errorf("cookie 中缺少 bili_jct")
+	}
+
+	path, err := correspondPath(nowFunc().UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	page, _, err := doAuthRequest(http.MethodGet, correspondURL+path, cookie, nil)
+	if err != nil {
+		return nil, fmt.Errorf("获取 refresh_csrf 失败: %w", err)
+	}
+	match := refreshCsrfRegexp.FindSubmatch(page)
+	if match == nil {
+		return nil, fmt.Errorf("获取 refresh_csrf 失败: 页面中未找到 refresh_csrf")
+	}
+	refreshCsrf := strings.TrimSpace(string(match[1]))
+
+	body, setCookies, err := doAuthRequest(http.MethodPost, cookieRefreshURL, cookie, url.Values{
+		"csrf":          {csrf},
+		"refresh_csrf":  {refreshCsrf},
+		"source":        {"main_web"},
+		"refresh_token": {refreshToken},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("刷新哔哩哔哩 Cookie 失败: %w", err)
+	}
+	if code := gjson.GetBytes(body, "code").Int(); code != 0 {
+		return nil, fmt.Errorf("刷新哔哩哔哩 Cookie 失败: code %d, %s", code, gjson.GetBytes(body, "message").String())
+	}
+	newRefreshToken := gjson.GetBytes(body, "data.refresh_token").String()
+	newCookie := MergeCookies(cookie, setCookies)
+	if newRefreshToken == "" || CookieValue(newCookie, "SESSDATA") == CookieValue(cookie, "SESSDATA") {
+		return nil, fmt.Errorf("刷新哔哩哔哩 Cookie 失败: 响应中缺少新的 Cookie 或 refresh_token")
+	}
+
+	// 确认刷新，使旧 refresh_token 失效；失败不影响新 Cookie 的使用
+	if body, _, err := doAuthRequest(http.MethodPost, confirmRefreshURL, newCookie, url.Values{
+		"csrf":          {CookieValue(newCookie, "bili_jct")},
+		"refresh_token": {refreshToken},
+	}); err != nil || gjson.GetBytes(body, "code").Int() != 0 {
+		return &RefreshResult{Cookie: newCookie, RefreshToken: newRefreshToken},
+			fmt.Errorf("cookie 已刷新，但确认刷新失败: %v %s", err, gjson.GetBytes(body, "message").String())
+	}
+
+	return &RefreshResult{Cookie: newCookie, RefreshToken: newRefreshToken}, nil
+}
+
+// correspondPath 用公钥以 RSA-OAEP(SHA-256) 加密 "refresh_{毫秒时间戳}"，结果为小写十六进制
+func correspondPath(timestampMs int64) (string, error) {
+	block, _ := pem.Decode([]byte(correspondPublicKey))
+	if block == nil {
+		return "", fmt.Errorf("解析 correspond 公钥失败")
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return "", fmt.Errorf("解析 correspond 公钥失败: %w", err)
+	}
+	rsaPub, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return "", fmt.Errorf("correspond 公钥不是 RSA 公钥")
+	}
+	encrypted, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, rsaPub, []byte(fmt.Sprintf("refresh_%d", timestampMs)), nil)
+	if err != nil {
+		return "", fmt.Errorf("生成 correspondPath 失败: %w", err)
+	}
+	return hex.EncodeToString(encrypted), nil
+}
+
+func doAuthRequest(method, target, cookie string, form url.Values) ([]byte, []*http.Cookie, error) {
+	var reqBody io.Reader
+	if form != nil {
+		reqBody = bytes.NewBufferString(form.Encode())
+	}
+	req, err := http.NewRequest(method, target, reqBody)
+	if err != nil {
+		return nil, nil, err
+	}
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	req.Header.Set("Cookie", cookie)
+	req.Header.Set("User-Agent", biliWebAgent)
+	req.Header.Set("Referer", "https://www.bilibili.com/")
+
+	resp, err := newHTTPClient().Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return body, nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return body, resp.Cookies(), nil
+}
+
+// CookieValue 从 "k1=v1; k2=v2" 格式的 Cookie 字符串中取值
+func CookieValue(cookie, name string) string {
+	for _, part := range strings.Split(cookie, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok && key == name {
+			return value
+		}
+	}
+	return ""
+}
+
+// MergeCookies 用响应中的 Set-Cookie 更新 Cookie 字符串，保留原有顺序，新增项追加在末尾
+func MergeCookies(cookie string, updates []*http.Cookie) string {
+	updated := make(map[string]string, len(updates))
+	for _, c := range updates {
+		if c.Value != "" {
+			updated[c.Name] = c.Value
+		}
+	}
+	parts := make([]string, 0)
+	for _, part := range strings.Split(cookie, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || key == "" {
+			continue
+		}
+		if v, exists := updated[key]; exists {
+			value = v
+			delete(updated, key)
+		}
+		parts = append(parts, key+"="+value)
+	}
+	for _, c := range updates {
+		if v, exists := updated[c.Name]; exists {
+			parts = append(parts, c.Name+"="+v)
+			delete(updated, c.Name)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
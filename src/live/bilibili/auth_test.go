@@ -0,0 +1,105 @@
+package bilibili
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func overrideAuthEndpoints(t *testing.T, baseURL string) func() {
+	t.Helper()
+
+	oldNav, oldInfo, oldCorrespond := navURL, cookieInfoURL, correspondURL
+	oldRefresh, oldConfirm := cookieRefreshURL, confirmRefreshURL
+	oldNewHTTPClient := newHTTPClient
+
+	navURL = baseURL + "/nav"
+	cookieInfoURL = baseURL + "/cookie/info"
+	correspondURL = baseURL + "/correspond/1/"
+	cookieRefreshURL = baseURL + "/cookie/refresh"
+	confirmRefreshURL = baseURL + "/confirm/refresh"
+	newHTTPClient = func() *http.Client { return &http.Client{} }
+
+	return func() {
+		navURL, cookieInfoURL, correspondURL = oldNav, oldInfo, oldCorrespond
+		cookieRefreshURL, confirmRefreshURL = oldRefresh, oldConfirm
+		newHTTPClient = oldNewHTTPClient
+	}
+}
+
+func TestCorrespondPath(t *testing.T) {
+	path, err := correspondPath(1700000000000)
+	assert.NoError(t, err)
+	// 1024 位 RSA 密文，十六进制编码后为 256 个字符
+	assert.Len(t, path, 256)
+}
+
+func TestMergeCookies(t *testing.T) {
+	merged := MergeCookies("SESSDATA=old; bili_jct=a; DedeUserID=1", []*http.Cookie{
+		{Name: "SESSDATA", Value: "new"},
+		{Name: "bili_jct", Value: "b"},
+		{Name: "sid", Value: "s"},
+	})
+	assert.Equal(t, "SESSDATA=new; bili_jct=b; DedeUserID=1; sid=s", merged)
+	assert.Equal(t, "1", CookieValue(merged, "DedeUserID"))
+	assert.Equal(t, "", CookieValue(merged, "missing"))
+}
+
+func TestVerifyCookieNotLogin(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"code":-101,"message":"账号未登录","data":{"isLogin":false}}`))
+	}))
+	defer server.Close()
+	defer overrideAuthEndpoints(t, server.URL)()
+
+	status, err := VerifyCookie("SESSDATA=x")
+	assert.NoError(t, err)
+	assert.False(t, status.IsLogin)
+}
+
+func TestRefreshCookie(t *testing.T) {
+	var confirmed bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/cookie/info":
+			assert.Equal(t, "old_csrf", r.URL.Query().Get("csrf"))
+			_, _ = w.Write([]byte(`{"code":0,"data":{"refresh":true,"timestamp":1700000000000}}`))
+		case strings.HasPrefix(r.URL.Path, "/correspond/1/"):
+			_, _ = w.Write([]byte(`<html><div id="1-name">csrf_from_page</div></html>`))
+		case r.URL.Path == "/cookie/refresh":
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, "old_csrf", r.PostForm.Get("csrf"))
+			assert.Equal(t, "csrf_from_page", r.PostForm.Get("refresh_csrf"))
+			assert.Equal(t, "old_token", r.PostForm.Get("refresh_token"))
+			http.SetCookie(w, &http.Cookie{Name: "SESSDATA", Value: "new_sess"})
+			http.SetCookie(w, &http.Cookie{Name: "bili_jct", Value: "new_csrf"})
+			_, _ = w.Write([]byte(`{"code":0,"data":{"refresh_token":"new_token"}}`))
+		case r.URL.Path == "/confirm/refresh":
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, "new_csrf", r.PostForm.Get("csrf"))
+			assert.Equal(t, "old_token", r.PostForm.Get("refresh_token"))
+			confirmed = true
+			_, _ = w.Write([]byte(`{"code":0}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	defer overrideAuthEndpoints(t, server.URL)()
+
+	cookie := "SESSDATA=old_sess; bili_jct=old_csrf; DedeUserID=1"
+	need, err := NeedsRefresh(cookie)
+	assert.NoError(t, err)
+	assert.True(t, need)
+
+	result, err := RefreshCookie(cookie, "old_token")
+	assert.NoError(t, err)
+	if assert.NotNil(t, result) {
+		assert.Equal(t, "SESSDATA=new_sess; bili_jct=new_csrf; DedeUserID=1", result.Cookie)
+		assert.Equal(t, "new_token", result.RefreshToken)
+	}
+	assert.True(t, confirmed)
+}
@@ -0,0 +1,100 @@
+package bilibili
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/hr3lxphr6j/requests"
+	"github.com/tidwall/gjson"
+
+	"github.com/bililive-go/bililive-go/src/live"
+)
+
+const (
+	roomBaseInfoUrl = "https://api.live.bilibili.com/xlive/web-room/v1/index/getRoomBaseInfo"
+	// maxBatchSize 单次批量查询的房间数，过多会导致请求 URL 过长
+	maxBatchSize = 50
+)
+
+func init() {
+	live.RegisterBatchInfoProvider(domain, new(batchProvider))
+}
+
+// batchProvider 使用 getRoomBaseInfo 接口一次查询多个直播间的开播状态、标题和主播名
+type batchProvider struct{}
+
+func (p *batchProvider) MaxBatchSize() int {
+	return maxBatchSize
+}
+
+// CanBatch 只有已解析出真实房间号的直播间才能参与批量查询
+func (p *batchProvider) CanBatch(l live.Live) bool {
+	bl, ok := l.(*Live)
+	return ok && bl.loadRealID() != ""
+}
+
+func (p *batchProvider) GetInfos(lives []live.Live) (map[live.Live]*live.Info, error) {
+	byRoomID := make(map[string]*Live, len(lives))
+	opts := make([]requests.RequestOption, 0, len(lives)+3)
+	opts = append(opts, live.CommonUserAgent, requests.Query("req_biz", "web_room_componet"))
+	var first *Live
+	for _, l := range lives {
+		bl, ok := l.(*Live)
+		if !ok {
+			continue
+		}
+		realID := bl.loadRealID()
+		if realID == "" {
+			continue
+		}
+		if first == nil {
+			first = bl
+		}
+		byRoomID[realID] = bl
+		opts = append(opts, requests.Query("room_ids", realID))
+	}
+	if first == nil {
+		return nil, nil
+	}
+
+	// 该接口无需登录，匿名请求：各直播间的 Cookie 可能来自不同账号，
+	// 不能用其中一个账号的身份查询所有直播间，也避免批量请求消耗账号的风控额度
+	resp, err := first.RequestSession.Get(roomBaseInfoUrl, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		if err := apiError(resp.StatusCode, 0); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("response code %d from room base info api", resp.StatusCode)
+	}
+	body, err := resp.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	if code := gjson.GetBytes(body, "code").Int(); code != 0 {
+		if err := apiError(resp.StatusCode, code); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error code %d from room base info api", code)
+	}
+
+	infos := make(map[live.Live]*live.Info, len(byRoomID))
+	gjson.GetBytes(body, "data.by_room_ids").ForEach(func(_, room gjson.Result) bool {
+		bl, ok := byRoomID[room.Get("room_id").String()]
+		if !ok {
+			return true
+		}
+		infos[bl] = &live.Info{
+			Live:      bl,
+			HostName:  room.Get("uname").String(),
+			RoomName:  room.Get("title").String(),
+			Status:    room.Get("live_status").Int() == 1,
+			AudioOnly: bl.GetOptions().AudioOnly,
+		}
+		return true
+	})
+	return infos, nil
+}
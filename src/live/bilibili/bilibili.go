@@ -6,6 +6,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync/atomic"
 
 	"github.com/hr3lxphr6j/requests"
 	"github.com/tidwall/gjson"
@@ -33,6 +34,18 @@ func init() {
 	live.Register(domain, new(builder))
 }
 
+// apiError 将风控和登录失效类的响应转换为对应错误，便于账号池识别并切换 Cookie。
+// 其他错误返回 nil，由调用方按原逻辑处理。
+func apiError(statusCode int, code int64) error {
+	switch {
+	case statusCode == http.StatusPreconditionFailed || code == -352 || code == -412:
+		return fmt.Errorf("%w: status %d, code %d", live.ErrRiskControl, statusCode, code)
+	case code == -101:
+		return fmt.Errorf("%w: code %d", live.ErrLoginRequired, code)
+	}
+	return nil
+}
+
 type builder struct{}
 
 func (b *builder) Build(url *url.URL) (live.Live, error) {
@@ -43,7 +56,25 @@ func (b *builder) Build(url *url.URL) (live.Live, error) {
 
 type Live struct {
 	internal.BaseLive
-	realID string
+	// realID 短号解析得到的真实房间号（string），批量查询会与检测并发读取
+	realID atomic.Value
+}
+
+// loadRealID 返回已解析的真实房间号，尚未解析时返回空字符串
+func (l *Live) loadRealID() string {
+	id, _ := l.realID.Load().(string)
+	return id
+}
+
+// resolveRealID 返回真实房间号，尚未解析时先请求解析
+func (l *Live) resolveRealID() (string, error) {
+	if id := l.loadRealID(); id != "" {
+		return id, nil
+	}
+	if err := l.parseRealId(); err != nil {
+		return "", err
+	}
+	return l.loadRealID(), nil
 }
 
 func (l *Live) parseRealId() error {
@@ -51,7 +82,7 @@ func (l *Live) parseRealId() error {
 	if len(paths) < 2 {
 		return live.ErrRoomUrlIncorrect
 	}
-	cookies := l.Options.Cookies.Cookies(l.Url)
+	cookies := l.GetOptions().Cookies.Cookies(l.Url)
 	cookieKVs := make(map[string]string)
 	for _, item := range cookies {
 		cookieKVs[item.Name] = item.Value
@@ -68,18 +99,17 @@ func (l *Live) parseRealId() error {
 	if err != nil || gjson.GetBytes(body, "code").Int() != 0 {
 		return live.ErrRoomNotExist
 	}
-	l.realID = gjson.GetBytes(body, "data.room_id").String()
+	l.realID.Store(gjson.GetBytes(body, "data.room_id").String())
 	return nil
 }
 
 func (l *Live) GetInfo() (info *live.Info, err error) {
 	// Parse the short id from URL to full id
-	if l.realID == "" {
-		if err := l.parseRealId(); err != nil {
-			return nil, err
-		}
+	realID, err := l.resolveRealID()
+	if err != nil {
+		return nil, err
 	}
-	cookies := l.Options.Cookies.Cookies(l.Url)
+	cookies := l.GetOptions().Cookies.Cookies(l.Url)
 	cookieKVs := make(map[string]string)
 	for _, item := range cookies {
 		cookieKVs[item.Name] = item.Value
@@ -87,7 +117,7 @@ func (l *Live) GetInfo() (info *live.Info, err error) {
 	resp, err := l.RequestSession.Get(
 		roomApiUrl,
 		live.CommonUserAgent,
-		requests.Query("room_id", l.realID),
+		requests.Query("room_id", realID),
 		requests.Query("from", "room"),
 		requests.Cookies(cookieKVs),
 	)
@@ -96,13 +126,19 @@ func (l *Live) GetInfo() (info *live.Info, err error) {
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
+		if err := apiError(resp.StatusCode, 0); err != nil {
+			return nil, err
+		}
 		return nil, live.ErrRoomNotExist
 	}
 	body, err := resp.Bytes()
 	if err != nil {
 		return nil, err
 	}
-	if gjson.GetBytes(body, "code").Int() != 0 {
+	if code := gjson.GetBytes(body, "code").Int(); code != 0 {
+		if err := apiError(resp.StatusCode, code); err != nil {
+			return nil, err
+		}
 		return nil, live.ErrRoomNotExist
 	}
 
@@ -110,10 +146,10 @@ func (l *Live) GetInfo() (info *live.Info, err error) {
 		Live:      l,
 		RoomName:  gjson.GetBytes(body, "data.title").String(),
 		Status:    gjson.GetBytes(body, "data.live_status").Int() == 1,
-		AudioOnly: l.Options.AudioOnly,
+		AudioOnly: l.GetOptions().AudioOnly,
 	}
 
-	resp, err = l.RequestSession.Get(userApiUrl, live.CommonUserAgent, requests.Query("roomid", l.realID))
+	resp, err = l.RequestSession.Get(userApiUrl, live.CommonUserAgent, requests.Query("roomid", realID))
 	if err != nil {
 		return nil, err
 	}
@@ -134,12 +170,11 @@ func (l *Live) GetInfo() (info *live.Info, err error) {
 }
 
 func (l *Live) GetStreamInfos() (infos []*live.StreamUrlInfo, err error) {
-	if l.realID == "" {
-		if err := l.parseRealId(); err != nil {
-			return nil, err
-		}
+	realID, err := l.resolveRealID()
+	if err != nil {
+		return nil, err
 	}
-	cookies := l.Options.Cookies.Cookies(l.Url)
+	cookies := l.GetOptions().Cookies.Cookies(l.Url)
 	cookieKVs := make(map[string]string)
 	for _, item := range cookies {
 		cookieKVs[item.Name] = item.Value
@@ -159,11 +194,11 @@ func (l *Live) GetStreamInfos() (infos []*live.StreamUrlInfo, err error) {
 		}
 	}
 	apiUrl := liveApiUrlv2
-	query := fmt.Sprintf("?room_id=%s&protocol=0,1&format=0,1,2&codec=0,1&qn=%d&platform=web&ptype=8&dolby=5&panorama=1", l.realID, qn)
+	query := fmt.Sprintf("?room_id=%s&protocol=0,1&format=0,1,2&codec=0,1&qn=%d&platform=web&ptype=8&dolby=5&panorama=1", realID, qn)
 	agent := live.CommonUserAgent
 
 	// for audio only use android api
-	if l.Options.AudioOnly {
+	if l.GetOptions().AudioOnly {
 		params := map[string]string{
 			"appkey":      "iVGUTjsxvpLeuDCf",
 			"build":       "6310200",
@@ -175,8 +210,8 @@ func (l *Live) GetStreamInfos() (infos []*live.StreamUrlInfo, err error) {
 			"only_audio":  "1",
 			"platform":    "android",
 			"protocol":    "0,1",
-			"room_id":     l.realID,
-			"qn":          strconv.Itoa(l.Options.Quality),
+			"room_id":     realID,
+			"qn":          strconv.Itoa(l.GetOptions().Quality),
 		}
 		values := url.Values{}
 		for key, value := range params {
@@ -193,6 +228,9 @@ func (l *Live) GetStreamInfos() (infos []*live.StreamUrlInfo, err error) {
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
+		if err := apiError(resp.StatusCode, 0); err != nil {
+			return nil, err
+		}
 		return nil, live.ErrRoomNotExist
 	}
 	body, err := resp.Bytes()
@@ -304,7 +342,7 @@ func (l *Live) GetStreamInfos() (infos []*live.StreamUrlInfo, err error) {
 		urlStrings := make([]string, 0, 4)
 		addr := ""
 
-		if l.Options.Quality == 0 && gjson.GetBytes(body, "data.playurl_info.playurl.stream.1.format.1.codec.#").Int() > 1 {
+		if l.GetOptions().Quality == 0 && gjson.GetBytes(body, "data.playurl_info.playurl.stream.1.format.1.codec.#").Int() > 1 {
 			addr = "data.playurl_info.playurl.stream.1.format.1.codec.1" // hevc m3u8
 			l.GetLogger().Debug("fallback: 选择 HEVC M3U8 流")
 		} else {
@@ -373,16 +411,14 @@ func (l *Live) GetPlatformCNName() string {
 
 // GetRealID returns the resolved real room ID, resolving short IDs if necessary.
 func (l *Live) GetRealID() string {
-	if l.realID == "" {
-		l.parseRealId()
-	}
-	return l.realID
+	id, _ := l.resolveRealID()
+	return id
 }
 
 func (l *Live) getHeadersForDownloader() map[string]string {
 	agent := biliWebAgent
 	referer := l.GetRawUrl()
-	if l.Options.AudioOnly {
+	if l.GetOptions().AudioOnly {
 		agent = biliAppAgent
 		referer = ""
 	}
@@ -2,6 +2,8 @@ package live
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
 )
 
 var (
@@ -10,4 +12,21 @@ var (
 	ErrInternalError    = errors.New("internal error")
 	ErrNotImplemented   = errors.New("not implemented")
 	ErrLiveOffline      = errors.New("live is offline")
+	// ErrRiskControl 请求被平台风控拦截（如哔哩哔哩 -352 / HTTP 412）
+	// 账号池通过 errors.Is 识别该错误并切换 Cookie，返回时需用 %w 包装
+	ErrRiskControl = errors.New("blocked by platform risk control")
+	// ErrLoginRequired Cookie 已失效或需要登录（如哔哩哔哩 -101）
+	ErrLoginRequired = errors.New("cookie expired or login required")
 )
+
+// StatusError 将请求直播流时上游返回的 HTTP 状态码转换为登录失效或风控错误，
+// 其他状态码返回 nil
+func StatusError(statusCode int) error {
+	switch statusCode {
+	case http.StatusUnauthorized:
+		return fmt.Errorf("%w: HTTP %d", ErrLoginRequired, statusCode)
+	case http.StatusPreconditionFailed, http.StatusTooManyRequests:
+		return fmt.Errorf("%w: HTTP %d", ErrRiskControl, statusCode)
+	}
+	return nil
+}
@@ -72,30 +72,34 @@ type Info struct {
 type InfoCookie struct {
 	Platform_cn_name string
 	Host             string
-	Cookie           string
+	Cookie           string // 明文 Cookie 会被遮盖，密钥库引用原样返回
+	HasCookie        bool
+}
+
+// InfoJSON Info 序列化为 JSON 后的结构
+type InfoJSON struct {
+	Id                        types.LiveID           `json:"id"`
+	LiveUrl                   string                 `json:"live_url"`
+	PlatformCNName            string                 `json:"platform_cn_name"`
+	HostName                  string                 `json:"host_name"`
+	RoomName                  string                 `json:"room_name"`
+	Status                    bool                   `json:"status"`
+	Listening                 bool                   `json:"listening"`
+	Recording                 bool                   `json:"recording"`
+	RecordingPreparing        bool                   `json:"recording_preparing,omitempty"`
+	Initializing              bool                   `json:"initializing"`
+	LastStartTime             string                 `json:"last_start_time,omitempty"`
+	LastStartTimeUnix         int64                  `json:"last_start_time_unix,omitempty"`
+	AudioOnly                 bool                   `json:"audio_only"`
+	NotifyOnly                bool                   `json:"notify_only"`
+	NickName                  string                 `json:"nick_name"`
+	LastError                 string                 `json:"last_error,omitempty"`
+	AvailableStreams          []*AvailableStreamInfo `json:"available_streams,omitempty"`
+	AvailableStreamsUpdatedAt int64                  `json:"available_streams_updated_at,omitempty"`
 }
 
 func (i *Info) MarshalJSON() ([]byte, error) {
-	t := struct {
-		Id                        types.LiveID           `json:"id"`
-		LiveUrl                   string                 `json:"live_url"`
-		PlatformCNName            string                 `json:"platform_cn_name"`
-		HostName                  string                 `json:"host_name"`
-		RoomName                  string                 `json:"room_name"`
-		Status                    bool                   `json:"status"`
-		Listening                 bool                   `json:"listening"`
-		Recording                 bool                   `json:"recording"`
-		RecordingPreparing        bool                   `json:"recording_preparing,omitempty"`
-		Initializing              bool                   `json:"initializing"`
-		LastStartTime             string                 `json:"last_start_time,omitempty"`
-		LastStartTimeUnix         int64                  `json:"last_start_time_unix,omitempty"`
-		AudioOnly                 bool                   `json:"audio_only"`
-		NotifyOnly                bool                   `json:"notify_only"`
-		NickName                  string                 `json:"nick_name"`
-		LastError                 string                 `json:"last_error,omitempty"`
-		AvailableStreams          []*AvailableStreamInfo `json:"available_streams,omitempty"`
-		AvailableStreamsUpdatedAt int64                  `json:"available_streams_updated_at,omitempty"`
-	}{
+	t := InfoJSON{
 		Id:                        i.Live.GetLiveId(),
 		LiveUrl:                   i.Live.GetRawUrl(),
 		PlatformCNName:            i.Live.GetPlatformCNName(),
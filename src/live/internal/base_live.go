@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"sync/atomic"
 	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/bililive-go/bililive-go/src/configs"
 	"github.com/bililive-go/bililive-go/src/live"
+	"github.com/bililive-go/bililive-go/src/pkg/cookiepool"
 	"github.com/bililive-go/bililive-go/src/pkg/livelogger"
 	"github.com/bililive-go/bililive-go/src/pkg/utils"
 	"github.com/bililive-go/bililive-go/src/types"
@@ -20,9 +22,11 @@ type BaseLive struct {
 	Url            *url.URL
 	LastStartTime  time.Time
 	LiveId         types.LiveID
-	Options        *live.Options
 	RequestSession *requests.Session
 	Logger         *livelogger.LiveLogger
+
+	// options 请求选项，账号池切换 Cookie 时会在检测和录制进行中整体替换，读取需通过 GetOptions
+	options atomic.Pointer[live.Options]
 }
 
 func genLiveId(url *url.URL) types.LiveID {
@@ -37,7 +41,7 @@ func NewBaseLive(url *url.URL) BaseLive {
 	var requestSession *requests.Session
 	config := configs.GetCurrentConfig()
 	if config != nil && config.Debug {
-		client, _ := utils.CreateConnCounterClient()
+		client := utils.CreateConnCounterClientForRoom(url.String())
 		requestSession = requests.NewSession(client)
 	} else {
 		// 注意：这里刻意改变了非调试模式下的默认行为。
@@ -52,7 +56,8 @@ func NewBaseLive(url *url.URL) BaseLive {
 		//       可能会回落到弱 TLS 1.2 加密套件，仅作为兼容性权衡使用。后续如对 TLS 安全性
 		//       有更高要求，或 edgesrv.com 升级了自身的 TLS 配置，应优先检查并收紧/移除
 		//       utils.CreateDefaultClient 中相关的弱套件配置。
-		client := utils.CreateDefaultClient()
+		// 客户端按直播间解析代理（全局 -> 平台 -> 房间），以支持需要特定出口的直播间。
+		client := utils.CreateDefaultClientForRoom(url.String())
 		requestSession = requests.NewSession(client)
 	}
 
@@ -80,14 +85,17 @@ func (a *BaseLive) UpdateLiveOptionsbyConfig(ctx context.Context, room *configs.
 	}
 	opts := make([]live.Option, 0)
 	if cfg := configs.GetCurrentConfig(); cfg != nil {
-		if v, ok := cfg.Cookies[url.Host]; ok {
+		// 配置了账号池的 host 优先使用账号池分配的 Cookie
+		if _, v, ok := cookiepool.Default().CookieFor(url.Host, a.LiveId); ok {
 			opts = append(opts, live.WithKVStringCookies(url, v))
+		} else if v, ok := cfg.Cookies[url.Host]; ok {
+			opts = append(opts, live.WithKVStringCookies(url, configs.ResolveSecret(v)))
 		}
 	}
 	opts = append(opts, live.WithQuality(room.Quality))
 	opts = append(opts, live.WithAudioOnly(room.AudioOnly))
 	opts = append(opts, live.WithNickName(room.NickName))
-	a.Options = live.MustNewOptions(opts...)
+	a.SetOptions(live.MustNewOptions(opts...))
 	return
 }
 
@@ -112,7 +120,12 @@ func (a *BaseLive) SetLastStartTime(time time.Time) {
 }
 
 func (a *BaseLive) GetOptions() *live.Options {
-	return a.Options
+	return a.options.Load()
+}
+
+// SetOptions 替换请求选项，可与 GetOptions 并发调用
+func (a *BaseLive) SetOptions(options *live.Options) {
+	a.options.Store(options)
 }
 
 // GetLogger 返回直播间专属的日志记录器
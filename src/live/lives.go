@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bililive-go/bililive-go/src/configs"
@@ -22,15 +23,22 @@ import (
 // SchedulerRefreshCallback 调度器刷新完成的回调函数类型
 type SchedulerRefreshCallback func(live Live, status SchedulerStatus)
 
-// RequestStatusCallback 请求状态追踪的回调函数类型
-type RequestStatusCallback func(liveID, platform string, success bool, errMsg string)
+// RequestStatusCallback 请求状态追踪的回调函数类型，请求成功时 err 为 nil；
+// anonymous 为 true 表示请求没有携带直播间的 Cookie（如批量查询），结果与账号无关
+type RequestStatusCallback func(liveID, platform string, err error, anonymous bool)
 
 // 全局调度器刷新回调（由外部包设置，避免循环依赖）
 var schedulerRefreshCallback SchedulerRefreshCallback
 
+// IntervalAdjuster 根据直播间历史调整检测间隔的函数类型
+type IntervalAdjuster func(liveID string, interval int, cfg configs.AdaptiveInterval) int
+
 // 全局请求状态追踪回调（由 iostats 包设置，避免循环依赖）
 var requestStatusCallback RequestStatusCallback
 
+// 全局检测间隔调整函数（由 livestate 包设置，避免循环依赖）
+var intervalAdjuster IntervalAdjuster
+
 // SetSchedulerRefreshCallback 设置调度器刷新完成的回调函数
 func SetSchedulerRefreshCallback(callback SchedulerRefreshCallback) {
 	schedulerRefreshCallback = callback
@@ -41,6 +49,20 @@ func SetRequestStatusCallback(callback RequestStatusCallback) {
 	requestStatusCallback = callback
 }
 
+// ReportRequestError 上报信息查询以外的请求失败（获取直播流地址、下载直播流等）。
+// 只上报风控和登录失效类错误，供账号池切换 Cookie；其他错误由调用方自行处理
+func ReportRequestError(l Live, err error) {
+	if requestStatusCallback == nil || !(errors.Is(err, ErrRiskControl) || errors.Is(err, ErrLoginRequired)) {
+		return
+	}
+	requestStatusCallback(string(l.GetLiveId()), l.GetPlatformCNName(), err, false)
+}
+
+// SetIntervalAdjuster 设置自适应检测间隔的计算函数
+func SetIntervalAdjuster(adjuster IntervalAdjuster) {
+	intervalAdjuster = adjuster
+}
+
 var (
 	m                               = make(map[string]Builder)
 	InitializingLiveBuilderInstance InitializingLiveBuilder
@@ -186,6 +208,9 @@ type StreamUrlInfo struct {
 
 	HeadersForDownloader map[string]string
 
+	// HasStitchedAds 平台会在 HLS 流中拼接广告（如 Twitch），录制时需经 hlsproxy 识别处理
+	HasStitchedAds bool `json:"has_stitched_ads,omitempty"`
+
 	IsPlaceHolder bool `json:"is_placeholder"`
 }
 
@@ -242,6 +267,8 @@ type SchedulerStatus struct {
 	SecondsSinceLastRequest float64 `json:"seconds_since_last_request"`
 	// SchedulerRunning 调度器是否在运行
 	SchedulerRunning bool `json:"scheduler_running"`
+	// Batched 是否由批量查询负责刷新
+	Batched bool `json:"batched"`
 }
 
 // SchedulerStatusProvider 提供调度器状态的接口
@@ -262,6 +289,9 @@ type WrappedLive struct {
 	schedulerStop    chan struct{} // 停止调度器的信号
 	schedulerCtx     context.Context
 	schedulerCancel  context.CancelFunc
+
+	// batched 为 true 时由监听器管理器批量查询刷新，调度器仅作兜底
+	batched atomic.Bool
 }
 
 // NewWrappedLive 创建一个带有缓存功能的 Live 包装器
@@ -310,16 +340,30 @@ func (w *WrappedLive) GetInfo() (*Info, error) {
 	}
 
 	i, err := w.Live.GetInfo()
+	return w.handleInfoResult(i, err, false)
+}
 
+// Unwrap 返回被包装的平台 Live
+func (w *WrappedLive) Unwrap() Live {
+	return w.Live
+}
+
+// SetBatched 标记直播间是否由批量查询负责刷新
+func (w *WrappedLive) SetBatched(batched bool) {
+	w.batched.Store(batched)
+}
+
+// ApplyBatchInfo 应用批量查询得到的结果，通知等待方并更新缓存与调度状态。
+// 批量查询是匿名请求，结果不计入账号池的账号健康度
+func (w *WrappedLive) ApplyBatchInfo(info *Info) {
+	w.handleInfoResult(info, nil, true)
+}
+
+// handleInfoResult 处理一次信息查询的结果（单独请求或批量请求）
+func (w *WrappedLive) handleInfoResult(i *Info, err error, anonymous bool) (*Info, error) {
 	// 记录请求状态到 IO 统计（通过回调避免循环依赖）
 	if requestStatusCallback != nil {
-		liveID := string(w.GetLiveId())
-		platform := w.GetPlatformCNName()
-		if err != nil {
-			requestStatusCallback(liveID, platform, false, err.Error())
-		} else {
-			requestStatusCallback(liveID, platform, true, "")
-		}
+		requestStatusCallback(string(w.GetLiveId()), w.GetPlatformCNName(), err, anonymous)
 	}
 
 	// 不管成功还是失败，都通知所有等待的调用方
@@ -439,6 +483,7 @@ func (w *WrappedLive) GetSchedulerStatus() SchedulerStatus {
 		LastRequestAt:    w.lastRequestAt,
 		IntervalSeconds:  interval,
 		SchedulerRunning: w.schedulerStarted,
+		Batched:          w.batched.Load(),
 	}
 
 	// 计算距离上次请求的秒数
@@ -486,10 +531,15 @@ func (w *WrappedLive) runScheduler() {
 
 		// 有等待者，计算需要等待的时间
 		interval := time.Duration(w.getConfiguredInterval()) * time.Second
+		if w.batched.Load() {
+			// 由批量查询刷新时，只在批量结果长时间缺失时才单独请求
+			interval *= batchFallbackFactor
+		}
 
 		w.mu.Lock()
-		nextRequestAt := w.lastRequestAt.Add(interval)
+		lastRequestAt := w.lastRequestAt
 		w.mu.Unlock()
+		nextRequestAt := lastRequestAt.Add(interval)
 
 		now := time.Now()
 		var waitDuration time.Duration
@@ -517,9 +567,10 @@ func (w *WrappedLive) runScheduler() {
 			}
 		}
 
-		// 再次检查是否还有等待者（可能在等待期间被取消了）
+		// 再次检查是否还有等待者（可能在等待期间被取消了），
+		// 以及等待期间是否已经由其他途径（如批量查询）刷新过
 		w.mu.Lock()
-		hasWaiters = len(w.waiters) > 0
+		hasWaiters = len(w.waiters) > 0 && w.lastRequestAt.Equal(lastRequestAt)
 		w.mu.Unlock()
 
 		if hasWaiters {
@@ -529,6 +580,9 @@ func (w *WrappedLive) runScheduler() {
 	}
 }
 
+// batchFallbackFactor 批量刷新模式下调度器兜底请求的间隔倍数
+const batchFallbackFactor = 2
+
 // getConfiguredInterval 获取此直播间配置的访问间隔（秒）
 func (w *WrappedLive) getConfiguredInterval() int {
 	cfg := configs.GetCurrentConfig()
@@ -546,7 +600,22 @@ func (w *WrappedLive) getConfiguredInterval() int {
 
 	platformKey := configs.GetPlatformKeyFromUrl(w.GetRawUrl())
 	resolvedConfig := cfg.ResolveConfigForRoom(room, platformKey)
-	return resolvedConfig.Interval
+	return w.adjustInterval(resolvedConfig)
+}
+
+// adjustInterval 启用自适应检测时，根据主播的历史开播时间调整检测间隔
+func (w *WrappedLive) adjustInterval(resolved configs.ResolvedConfig) int {
+	interval := resolved.Interval
+	if !resolved.AdaptiveInterval.Enable || intervalAdjuster == nil {
+		return interval
+	}
+	// 直播中检测的是下播和标题变化，使用配置的间隔
+	if w.cache != nil {
+		if info, err := w.cache.Get(w); err == nil && info.(*Info).Status {
+			return interval
+		}
+	}
+	return intervalAdjuster(string(w.GetLiveId()), interval, resolved.AdaptiveInterval)
 }
 
 // randomJitter 生成 -3000 到 +3000 毫秒的随机抖动
@@ -134,7 +134,7 @@ func (l *Live) GetInfo() (*live.Info, error) {
 		HostName:  meta.HostName,
 		RoomName:  meta.RoomName,
 		Status:    false,
-		AudioOnly: l.Options.AudioOnly,
+		AudioOnly: l.GetOptions().AudioOnly,
 	}
 
 	// 页面明确离线（nBroadNo=null 或 /null）或既没有页面 broadNo 也没有路径 broadNo 时，
@@ -534,7 +534,7 @@ func (l *Live) getCookieMap() map[string]string {
 		return map[string]string{}
 	}
 
-	cookies := l.Options.Cookies.Cookies(l.Url)
+	cookies := l.GetOptions().Cookies.Cookies(l.Url)
 	cookieMap := make(map[string]string, len(cookies))
 	for _, item := range cookies {
 		cookieMap[item.Name] = item.Value
@@ -542,8 +542,8 @@ func (l *Live) getCookieMap() map[string]string {
 
 	if cfg := configs.GetCurrentConfig(); cfg != nil && cfg.Cookies != nil {
 		for _, rawCookie := range []string{
-			cfg.Cookies[l.Url.Host],
-			cfg.Cookies[domainPlaySoop],
+			configs.ResolveSecret(cfg.Cookies[l.Url.Host]),
+			configs.ResolveSecret(cfg.Cookies[domainPlaySoop]),
 		} {
 			if rawCookie == "" {
 				continue
@@ -594,7 +594,7 @@ func (l *Live) resolveChannelInfo(channel, broadNo string) (*channelInfo, error)
 func (l *Live) tryVerifyAndReloginIfNeeded() error {
 	cookie := l.getPrimaryCookieString()
 	cfg := configs.GetCurrentConfig()
-	hasCredential := cfg != nil && strings.TrimSpace(cfg.SoopLiveAuth.Username) != "" && strings.TrimSpace(cfg.SoopLiveAuth.Password) != ""
+	hasCredential := cfg != nil && strings.TrimSpace(cfg.SoopLiveAuth.Username) != "" && strings.TrimSpace(configs.ResolveSecret(cfg.SoopLiveAuth.Password)) != ""
 	runtimeCookie, ignoreStoredCookie := l.getRuntimeState()
 	l.GetLogger().Debugf("Soop 登录态预检开始: hasCookie=%v hasCredential=%v ignoreStoredCookie=%v runtimeCookie=%v",
 		cookie != "", hasCredential, ignoreStoredCookie, strings.TrimSpace(runtimeCookie) != "")
@@ -649,7 +649,7 @@ func (l *Live) tryAutoLogin() error {
 		return fmt.Errorf("当前配置未加载，无法执行 Soop 自动登录")
 	}
 	username := strings.TrimSpace(cfg.SoopLiveAuth.Username)
-	password := strings.TrimSpace(cfg.SoopLiveAuth.Password)
+	password := strings.TrimSpace(configs.ResolveSecret(cfg.SoopLiveAuth.Password))
 	if username == "" || password == "" {
 		return fmt.Errorf("未配置 Soop 账号密码，无法执行自动登录")
 	}
@@ -676,12 +676,18 @@ func (l *Live) tryAutoLogin() error {
 	l.setRuntimeState(result.Cookie, false)
 	l.GetLogger().Debugf("Soop 自动登录成功: loginID=%s cookie_length=%d", result.Verify.LoginID, len(result.Cookie))
 
+	// 检测和录制可能正在并发读取当前选项，构造新的选项整体替换而不是原地修改
+	opts := make([]live.Option, 0, 5)
+	if old := l.GetOptions(); old != nil {
+		opts = append(opts, live.WithQuality(old.Quality), live.WithAudioOnly(old.AudioOnly), live.WithNickName(old.NickName))
+	}
 	for _, targetURL := range []*url.URL{playSoopURL, l.Url} {
 		if targetURL == nil {
 			continue
 		}
-		live.WithKVStringCookies(targetURL, result.Cookie)(l.Options)
+		opts = append(opts, live.WithKVStringCookies(targetURL, result.Cookie))
 	}
+	l.SetOptions(live.MustNewOptions(opts...))
 
 	return nil
 }
@@ -713,15 +719,15 @@ func (l *Live) getPrimaryCookieString() string {
 
 	cfg := configs.GetCurrentConfig()
 	if cfg == nil || cfg.Cookies == nil {
-		return buildCookieStringFromCookies(l.Options.Cookies.Cookies(l.Url))
+		return buildCookieStringFromCookies(l.GetOptions().Cookies.Cookies(l.Url))
 	}
-	if cookie := strings.TrimSpace(cfg.Cookies[l.Url.Host]); cookie != "" {
+	if cookie := strings.TrimSpace(configs.ResolveSecret(cfg.Cookies[l.Url.Host])); cookie != "" {
 		return cookie
 	}
-	if cookie := strings.TrimSpace(cfg.Cookies[domainPlaySoop]); cookie != "" {
+	if cookie := strings.TrimSpace(configs.ResolveSecret(cfg.Cookies[domainPlaySoop])); cookie != "" {
 		return cookie
 	}
-	return buildCookieStringFromCookies(l.Options.Cookies.Cookies(l.Url))
+	return buildCookieStringFromCookies(l.GetOptions().Cookies.Cookies(l.Url))
 }
 
 func (l *Live) getRuntimeState() (string, bool) {
@@ -126,7 +126,7 @@ window.szBroadTitle = '标题';
 	l := &Live{
 		BaseLive: internal.NewBaseLive(u),
 	}
-	l.Options = livepkg.MustNewOptions(livepkg.WithKVStringCookies(u, "SESS=valid; AUTH=ok"))
+	l.SetOptions(livepkg.MustNewOptions(livepkg.WithKVStringCookies(u, "SESS=valid; AUTH=ok")))
 
 	meta, err := l.fetchPageMeta()
 	assert.NoError(t, err)
@@ -160,7 +160,7 @@ window.szBroadTitle = 'offline';
 	l := &Live{
 		BaseLive: internal.NewBaseLive(u),
 	}
-	l.Options = livepkg.MustNewOptions()
+	l.SetOptions(livepkg.MustNewOptions())
 
 	meta, err := l.fetchPageMeta()
 	assert.NoError(t, err)
@@ -190,7 +190,7 @@ window.szBroadTitle = 'missing nBroadNo';
 	l := &Live{
 		BaseLive: internal.NewBaseLive(u),
 	}
-	l.Options = livepkg.MustNewOptions()
+	l.SetOptions(livepkg.MustNewOptions())
 
 	meta, err := l.fetchPageMeta()
 	assert.NoError(t, err)
@@ -227,7 +227,7 @@ window.szBroadTitle = 'offline by null path';
 	l := &Live{
 		BaseLive: internal.NewBaseLive(u),
 	}
-	l.Options = livepkg.MustNewOptions()
+	l.SetOptions(livepkg.MustNewOptions())
 
 	meta, err := l.fetchPageMeta()
 	assert.NoError(t, err)
@@ -256,7 +256,7 @@ window.szBroadTitle = 'offline';
 	l := &Live{
 		BaseLive: internal.NewBaseLive(u),
 	}
-	l.Options = livepkg.MustNewOptions()
+	l.SetOptions(livepkg.MustNewOptions())
 
 	_, err = l.GetStreamInfos()
 	assert.Error(t, err)
@@ -287,7 +287,7 @@ window.szBroadTitle = 'offline by null path';
 	l := &Live{
 		BaseLive: internal.NewBaseLive(u),
 	}
-	l.Options = livepkg.MustNewOptions()
+	l.SetOptions(livepkg.MustNewOptions())
 
 	_, err = l.GetStreamInfos()
 	assert.Error(t, err)
@@ -359,7 +359,7 @@ func TestTryVerifyAndReloginIfNeededKeepsCookieWhenVerifyEndpointFails(t *testin
 	l := &Live{
 		BaseLive: internal.NewBaseLive(u),
 	}
-	l.Options = livepkg.MustNewOptions(livepkg.WithKVStringCookies(u, "SESS=expired"))
+	l.SetOptions(livepkg.MustNewOptions(livepkg.WithKVStringCookies(u, "SESS=expired")))
 
 	err = l.tryVerifyAndReloginIfNeeded()
 	assert.NoError(t, err)
@@ -398,7 +398,7 @@ func TestTryVerifyAndReloginIfNeededIgnoreInvalidCookieForPublicRoom(t *testing.
 	l := &Live{
 		BaseLive: internal.NewBaseLive(u),
 	}
-	l.Options = livepkg.MustNewOptions(livepkg.WithKVStringCookies(u, "SESS=expired"))
+	l.SetOptions(livepkg.MustNewOptions(livepkg.WithKVStringCookies(u, "SESS=expired")))
 
 	err = l.tryVerifyAndReloginIfNeeded()
 	assert.NoError(t, err)
@@ -437,7 +437,7 @@ func TestTryVerifyAndReloginIfNeededSkipsAutoLoginWhenVerifyEndpointFails(t *tes
 	l := &Live{
 		BaseLive: internal.NewBaseLive(u),
 	}
-	l.Options = livepkg.MustNewOptions(livepkg.WithKVStringCookies(u, "SESS=expired"))
+	l.SetOptions(livepkg.MustNewOptions(livepkg.WithKVStringCookies(u, "SESS=expired")))
 
 	err = l.tryVerifyAndReloginIfNeeded()
 	assert.NoError(t, err)
@@ -480,7 +480,7 @@ func TestTryVerifyAndReloginIfNeededAutoLoginWhenCredentialsExist(t *testing.T)
 	l := &Live{
 		BaseLive: internal.NewBaseLive(u),
 	}
-	l.Options = livepkg.MustNewOptions()
+	l.SetOptions(livepkg.MustNewOptions())
 
 	err = l.tryVerifyAndReloginIfNeeded()
 	assert.NoError(t, err)
@@ -522,7 +522,7 @@ func TestTryVerifyAndReloginIfNeededFallsBackToAnonymousWhenAutoLoginFails(t *te
 		BaseLive:      internal.NewBaseLive(u),
 		runtimeCookie: "SESS=runtime-expired; AUTH=stale",
 	}
-	l.Options = livepkg.MustNewOptions(livepkg.WithKVStringCookies(u, "SESS=option-expired"))
+	l.SetOptions(livepkg.MustNewOptions(livepkg.WithKVStringCookies(u, "SESS=option-expired")))
 
 	err = l.tryVerifyAndReloginIfNeeded()
 	assert.NoError(t, err)
@@ -544,7 +544,7 @@ func TestGetCookieMapPrefersLatestConfigCookieOverStaleOptions(t *testing.T) {
 	l := &Live{
 		BaseLive: internal.NewBaseLive(u),
 	}
-	l.Options = livepkg.MustNewOptions(livepkg.WithKVStringCookies(u, "SESS=expired; AUTH=old"))
+	l.SetOptions(livepkg.MustNewOptions(livepkg.WithKVStringCookies(u, "SESS=expired; AUTH=old")))
 
 	cookieMap := l.getCookieMap()
 	assert.Equal(t, "fresh", cookieMap["SESS"])
@@ -565,7 +565,7 @@ func TestGetPrimaryCookieStringPrefersRuntimeCookie(t *testing.T) {
 		BaseLive:      internal.NewBaseLive(u),
 		runtimeCookie: "SESS=fresh; AUTH=ok",
 	}
-	l.Options = livepkg.MustNewOptions(livepkg.WithKVStringCookies(u, "SESS=older"))
+	l.SetOptions(livepkg.MustNewOptions(livepkg.WithKVStringCookies(u, "SESS=older")))
 
 	assert.Equal(t, "SESS=fresh; AUTH=ok", l.getPrimaryCookieString())
 }
@@ -585,7 +585,7 @@ func TestUpdateLiveOptionsbyConfigClearsRuntimeState(t *testing.T) {
 		runtimeCookie:      "SESS=runtime; AUTH=old",
 		ignoreStoredCookie: true,
 	}
-	l.Options = livepkg.MustNewOptions(livepkg.WithKVStringCookies(u, "SESS=runtime; AUTH=old"))
+	l.SetOptions(livepkg.MustNewOptions(livepkg.WithKVStringCookies(u, "SESS=runtime; AUTH=old")))
 
 	room := &configs.LiveRoom{
 		Url: u.String(),
@@ -613,7 +613,7 @@ func TestUpdateLiveOptionsbyConfigKeepsClearActionEffective(t *testing.T) {
 		BaseLive:      internal.NewBaseLive(u),
 		runtimeCookie: "SESS=runtime; AUTH=old",
 	}
-	l.Options = livepkg.MustNewOptions(livepkg.WithKVStringCookies(u, "SESS=runtime; AUTH=old"))
+	l.SetOptions(livepkg.MustNewOptions(livepkg.WithKVStringCookies(u, "SESS=runtime; AUTH=old")))
 
 	room := &configs.LiveRoom{
 		Url: u.String(),
@@ -632,7 +632,7 @@ func TestRuntimeStateHelpersConcurrentAccess(t *testing.T) {
 	l := &Live{
 		BaseLive: internal.NewBaseLive(u),
 	}
-	l.Options = livepkg.MustNewOptions()
+	l.SetOptions(livepkg.MustNewOptions())
 
 	var wg sync.WaitGroup
 	for i := 0; i < 50; i++ {
@@ -685,7 +685,7 @@ func TestTryVerifyAndReloginIfNeededCachesVerifyResult(t *testing.T) {
 	u, err := url.Parse("https://play.sooplive.com/mbntv")
 	assert.NoError(t, err)
 	l := &Live{BaseLive: internal.NewBaseLive(u)}
-	l.Options = livepkg.MustNewOptions()
+	l.SetOptions(livepkg.MustNewOptions())
 
 	assert.NoError(t, l.tryVerifyAndReloginIfNeeded())
 	assert.NoError(t, l.tryVerifyAndReloginIfNeeded())
@@ -742,7 +742,7 @@ func TestTryVerifyAndReloginIfNeededDeduplicatesAutoLogin(t *testing.T) {
 		u, err := url.Parse("https://play.sooplive.com/mbntv")
 		assert.NoError(t, err)
 		l := &Live{BaseLive: internal.NewBaseLive(u)}
-		l.Options = livepkg.MustNewOptions(livepkg.WithKVStringCookies(u, "SESS=expired"))
+		l.SetOptions(livepkg.MustNewOptions(livepkg.WithKVStringCookies(u, "SESS=expired")))
 		return l
 	}
 
@@ -801,7 +801,7 @@ func TestTryAutoLoginDeduplicatesCookiePersistence(t *testing.T) {
 		u, err := url.Parse("https://play.sooplive.com/mbntv")
 		assert.NoError(t, err)
 		l := &Live{BaseLive: internal.NewBaseLive(u)}
-		l.Options = livepkg.MustNewOptions()
+		l.SetOptions(livepkg.MustNewOptions())
 		return l
 	}
 
@@ -854,7 +854,7 @@ func TestTryAutoLoginRejectsUnverifiedLoginResult(t *testing.T) {
 	u, err := url.Parse("https://play.sooplive.com/mbntv")
 	assert.NoError(t, err)
 	l := &Live{BaseLive: internal.NewBaseLive(u)}
-	l.Options = livepkg.MustNewOptions()
+	l.SetOptions(livepkg.MustNewOptions())
 
 	err = l.tryAutoLogin()
 	assert.Error(t, err)
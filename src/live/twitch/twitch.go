@@ -119,7 +119,9 @@ func (l *Live) GetInfo() (info *live.Info, err error) {
 	return info, nil
 }
 
-func (l *Live) GetStreamUrls() (us []*url.URL, err error) {
+// GetStreamInfos 返回 usher 主播放列表地址。
+// Twitch 会在 HLS 中拼接贴片广告，因此标记 HasStitchedAds，由录制器启用 hlsproxy 的广告处理。
+func (l *Live) GetStreamInfos() (infos []*live.StreamUrlInfo, err error) {
 	if l.hostName == "" || l.roomName == "" {
 		if err := l.parseInfo(); err != nil {
 			return nil, err
@@ -156,7 +158,11 @@ func (l *Live) GetStreamUrls() (us []*url.URL, err error) {
 	v.Add("sig", sig)
 	v.Add("token", token)
 	u.RawQuery = v.Encode()
-	return []*url.URL{u}, nil
+	return []*live.StreamUrlInfo{{
+		Url:            u,
+		Format:         "hls",
+		HasStitchedAds: true,
+	}}, nil
 }
 
 func (l *Live) GetPlatformCNName() string {
@@ -0,0 +1,106 @@
+package livestate
+
+import (
+	"time"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+)
+
+// 自适应检测间隔的统计参数
+const (
+	adaptiveHistoryLimit  = 60                  // 参与统计的最近会话数
+	adaptiveHistoryMaxAge = 60 * 24 * time.Hour // 只统计最近 60 天内的会话
+	adaptiveMinSessions   = 3                   // 会话数不足时不调整
+	adaptiveNearWindow    = 30 * time.Minute    // 常规开播时间前后此范围内加快检测
+	adaptiveQuietWindow   = 3 * time.Hour       // 前后此范围内都没有开播记录时放慢检测
+	adaptiveNearRatio     = 0.2                 // 附近开播次数占比达到此值视为常规开播时段
+	adaptiveCacheTTL      = 10 * time.Minute    // 会话历史缓存时间，避免频繁查询数据库
+)
+
+// startTimesCacheEntry 直播间历史开播时间缓存
+type startTimesCacheEntry struct {
+	starts    []time.Time
+	fetchedAt time.Time
+}
+
+// AdaptiveInterval 根据直播间的历史开播时间计算当前应使用的检测间隔（秒）。
+// 历史记录不足时返回 interval
+func (m *Manager) AdaptiveInterval(liveID string, interval int, cfg configs.AdaptiveInterval) int {
+	now := time.Now()
+	return adaptiveInterval(m.recentStartTimes(liveID, now), interval, cfg, now)
+}
+
+// recentStartTimes 返回直播间最近的开播时间（带缓存）
+func (m *Manager) recentStartTimes(liveID string, now time.Time) []time.Time {
+	m.startTimesMu.Lock()
+	entry, ok := m.startTimesCache[liveID]
+	m.startTimesMu.Unlock()
+	if ok && now.Sub(entry.fetchedAt) < adaptiveCacheTTL {
+		return entry.starts
+	}
+
+	var starts []time.Time
+	for _, session := range m.GetSessionHistory(liveID, adaptiveHistoryLimit) {
+		if session.StartTime.IsZero() || now.Sub(session.StartTime) > adaptiveHistoryMaxAge {
+			continue
+		}
+		starts = append(starts, session.StartTime)
+	}
+
+	m.startTimesMu.Lock()
+	m.startTimesCache[liveID] = startTimesCacheEntry{starts: starts, fetchedAt: now}
+	m.startTimesMu.Unlock()
+	return starts
+}
+
+// invalidateStartTimes 产生新会话时清除缓存
+func (m *Manager) invalidateStartTimes(liveID string) {
+	m.startTimesMu.Lock()
+	delete(m.startTimesCache, liveID)
+	m.startTimesMu.Unlock()
+}
+
+// adaptiveInterval 按开播时间在一天中的分布决定检测间隔：
+// 当前时刻接近常规开播时间时使用最短间隔，前后数小时内从未开播时使用最长间隔，
+// 其余情况使用配置的间隔（限制在最短与最长间隔之间）
+func adaptiveInterval(starts []time.Time, interval int, cfg configs.AdaptiveInterval, now time.Time) int {
+	if !cfg.Enable || len(starts) < adaptiveMinSessions {
+		return interval
+	}
+	near := 0
+	quiet := true
+	for _, start := range starts {
+		d := timeOfDayDistance(start.In(now.Location()), now)
+		if d <= adaptiveNearWindow {
+			near++
+		}
+		if d <= adaptiveQuietWindow {
+			quiet = false
+		}
+	}
+	switch {
+	case float64(near) >= float64(len(starts))*adaptiveNearRatio:
+		return cfg.MinInterval
+	case quiet && cfg.MaxInterval > 0:
+		return cfg.MaxInterval
+	default:
+		return cfg.ClampInterval(interval)
+	}
+}
+
+// timeOfDayDistance 返回两个时间在一天中的时刻之差（跨零点按较短方向计算）
+func timeOfDayDistance(a, b time.Time) time.Duration {
+	const day = 24 * time.Hour
+	sinceMidnight := func(t time.Time) time.Duration {
+		h, m, s := t.Clock()
+		return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+	}
+	d := sinceMidnight(a) - sinceMidnight(b)
+	if d < 0 {
+		d = -d
+	}
+	if d > day/2 {
+		d = day - d
+	}
+	return d
+}
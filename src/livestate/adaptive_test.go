@@ -0,0 +1,26 @@
+package livestate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+)
+
+func TestAdaptiveInterval(t *testing.T) {
+	cfg := configs.AdaptiveInterval{Enable: true, MinInterval: 10, MaxInterval: 120}
+	day := func(d, h, m int) time.Time { return time.Date(2026, 10, d, h, m, 0, 0, time.Local) }
+	// 主播通常在 20:00 前后开播
+	starts := []time.Time{day(10, 20, 0), day(11, 20, 10), day(12, 19, 50), day(13, 23, 50)}
+
+	assert.Equal(t, 10, adaptiveInterval(starts, 30, cfg, day(14, 19, 45)), "临近常规开播时间")
+	assert.Equal(t, 120, adaptiveInterval(starts, 30, cfg, day(14, 10, 0)), "历史上很少开播的时段")
+	assert.Equal(t, 30, adaptiveInterval(starts, 30, cfg, day(14, 22, 0)), "其余时段使用配置的间隔")
+	assert.Equal(t, 10, adaptiveInterval(starts, 30, cfg, day(15, 0, 5)), "跨零点计算")
+	assert.Equal(t, 30, adaptiveInterval(starts[:2], 30, cfg, day(14, 10, 0)), "历史记录不足")
+
+	cfg.Enable = false
+	assert.Equal(t, 30, adaptiveInterval(starts, 30, cfg, day(14, 10, 0)))
+}
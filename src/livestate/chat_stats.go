@@ -0,0 +1,147 @@
+package livestate
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// chatAccumulator 一个直播会话尚未写入数据库的弹幕与营收统计
+type chatAccumulator struct {
+	sessionID int64 // 0 表示该直播间当前没有打开的会话，消息不做统计
+	delta     ChatStatsDelta
+	dirty     bool
+}
+
+func newChatAccumulator(sessionID int64) *chatAccumulator {
+	a := &chatAccumulator{sessionID: sessionID}
+	a.reset()
+	return a
+}
+
+func (a *chatAccumulator) reset() {
+	a.delta = ChatStatsDelta{Chatters: make(map[string]int), Minutes: make(map[int64]int)}
+	a.dirty = false
+}
+
+// add 累计一条消息，valueCNY 小于 0 表示无法折算金额
+func (a *chatAccumulator) add(msgType, username string, at time.Time, valueCNY float64) {
+	d := &a.delta
+	switch msgType {
+	case ChatTypeDanmaku:
+		d.MessageCount++
+		d.Chatters[username]++
+		d.Minutes[at.Truncate(time.Minute).Unix()]++
+	case ChatTypeGift:
+		d.GiftCount++
+	case ChatTypeSuperChat:
+		d.SuperChatCount++
+	case ChatTypeGuard:
+		d.GuardCount++
+	default:
+		return
+	}
+	a.dirty = true
+	if msgType == ChatTypeDanmaku {
+		return
+	}
+	if valueCNY < 0 {
+		d.UnpricedCount++
+		return
+	}
+	switch msgType {
+	case ChatTypeGift:
+		d.GiftValue += valueCNY
+	case ChatTypeSuperChat:
+		d.SuperChatValue += valueCNY
+	case ChatTypeGuard:
+		d.GuardValue += valueCNY
+	}
+}
+
+// RecordChat 记录弹幕录制器收到的一条消息，累计到直播间当前打开的会话。
+// msgType 为 ChatType* 之一；valueCNY 为付费消息折算的人民币金额，小于 0 表示无法折算。
+// 统计先保存在内存中，随心跳定期写入数据库。
+func (m *Manager) RecordChat(liveID, msgType, username string, at time.Time, valueCNY float64) {
+	m.chatMu.Lock()
+	defer m.chatMu.Unlock()
+
+	acc, ok := m.chatStats[liveID]
+	if !ok {
+		// 程序在直播中途启动时没有收到开播事件，沿用数据库中仍打开的会话
+		acc = newChatAccumulator(m.openSessionID(liveID))
+		m.chatStats[liveID] = acc
+	}
+	if acc.sessionID == 0 {
+		return
+	}
+	acc.add(msgType, username, at, valueCNY)
+}
+
+// openSessionID 查找直播间仍打开的会话，没有时返回 0
+func (m *Manager) openSessionID(liveID string) int64 {
+	sessions, err := m.store.GetOpenSessions(m.ctx)
+	if err != nil {
+		logrus.WithError(err).WithField("live_id", liveID).Debug("查找打开的直播会话失败")
+		return 0
+	}
+	var id int64
+	var start time.Time
+	for _, s := range sessions {
+		if s.LiveID == liveID && (id == 0 || s.StartTime.After(start)) {
+			id, start = s.ID, s.StartTime
+		}
+	}
+	return id
+}
+
+// resetChatStats 开播时为新会话开始统计，之前未写入的数据先写入旧会话
+func (m *Manager) resetChatStats(liveID string, sessionID int64) {
+	m.chatMu.Lock()
+	defer m.chatMu.Unlock()
+	if acc, ok := m.chatStats[liveID]; ok {
+		m.flushChatAccumulator(liveID, acc)
+	}
+	m.chatStats[liveID] = newChatAccumulator(sessionID)
+}
+
+// finishChatStats 下播时写入会话剩余的统计并停止统计
+func (m *Manager) finishChatStats(liveID string) {
+	m.chatMu.Lock()
+	defer m.chatMu.Unlock()
+	if acc, ok := m.chatStats[liveID]; ok {
+		m.flushChatAccumulator(liveID, acc)
+		delete(m.chatStats, liveID)
+	}
+}
+
+// flushChatStats 将所有直播间未写入的统计写入数据库
+func (m *Manager) flushChatStats() {
+	m.chatMu.Lock()
+	defer m.chatMu.Unlock()
+	for liveID, acc := range m.chatStats {
+		m.flushChatAccumulator(liveID, acc)
+	}
+}
+
+// flushChatAccumulator 调用方需持有 m.chatMu。写入失败时保留数据，下次心跳重试。
+func (m *Manager) flushChatAccumulator(liveID string, acc *chatAccumulator) {
+	if acc.sessionID == 0 || !acc.dirty {
+		return
+	}
+	if err := m.store.AddSessionChatStats(m.ctx, acc.sessionID, liveID, &acc.delta); err != nil {
+		logrus.WithError(err).WithField("live_id", liveID).Warn("写入会话弹幕统计失败")
+		return
+	}
+	acc.reset()
+}
+
+// GetSessionChatStats 获取会话的弹幕与营收统计，正在进行的会话会先写入内存中的最新数据
+func (m *Manager) GetSessionChatStats(liveID string, sessionID int64, topN int) (*SessionChatStats, error) {
+	m.chatMu.Lock()
+	if acc, ok := m.chatStats[liveID]; ok && acc.sessionID == sessionID {
+		m.flushChatAccumulator(liveID, acc)
+	}
+	m.chatMu.Unlock()
+	return m.store.GetSessionChatStats(m.ctx, liveID, sessionID, topN)
+}
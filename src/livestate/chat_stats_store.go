@@ -0,0 +1,164 @@
+package livestate
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// AddSessionChatStats 将新增的统计累加到会话已有数据上
+func (s *SQLiteStore) AddSessionChatStats(ctx context.Context, sessionID int64, liveID string, delta *ChatStatsDelta) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO session_chat_stats
+		(session_id, live_id, message_count, gift_count, gift_value, super_chat_count, super_chat_value,
+		 guard_count, guard_value, unpriced_count, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(session_id) DO UPDATE SET
+			message_count = message_count + excluded.message_count,
+			gift_count = gift_count + excluded.gift_count,
+			gift_value = gift_value + excluded.gift_value,
+			super_chat_count = super_chat_count + excluded.super_chat_count,
+			super_chat_value = super_chat_value + excluded.super_chat_value,
+			guard_count = guard_count + excluded.guard_count,
+			guard_value = guard_value + excluded.guard_value,
+			unpriced_count = unpriced_count + excluded.unpriced_count,
+			updated_at = excluded.updated_at
+	`, sessionID, liveID, delta.MessageCount, delta.GiftCount, delta.GiftValue, delta.SuperChatCount, delta.SuperChatValue,
+		delta.GuardCount, delta.GuardValue, delta.UnpricedCount, time.Now().Unix())
+	if err != nil {
+		return err
+	}
+
+	if len(delta.Chatters) > 0 {
+		stmt, err := tx.PrepareContext(ctx, `
+			INSERT INTO session_chatters (session_id, username, message_count) VALUES (?, ?, ?)
+			ON CONFLICT(session_id, username) DO UPDATE SET message_count = message_count + excluded.message_count
+		`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		for username, count := range delta.Chatters {
+			if _, err := stmt.ExecContext(ctx, sessionID, username, count); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(delta.Minutes) > 0 {
+		stmt, err := tx.PrepareContext(ctx, `
+			INSERT INTO session_chat_minutes (session_id, minute, message_count) VALUES (?, ?, ?)
+			ON CONFLICT(session_id, minute) DO UPDATE SET message_count = message_count + excluded.message_count
+		`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		for minute, count := range delta.Minutes {
+			if _, err := stmt.ExecContext(ctx, sessionID, minute, count); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetSessionChatStats 获取会话的弹幕与营收统计，topN 为返回的发言最多用户数。
+// 会话不属于该直播间时返回 ErrSessionNotFound；会话存在但没有统计数据时返回全零的统计。
+func (s *SQLiteStore) GetSessionChatStats(ctx context.Context, liveID string, sessionID int64, topN int) (*SessionChatStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var exists int
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM live_sessions WHERE id = ? AND live_id = ?
+	`, sessionID, liveID).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if exists == 0 {
+		return nil, ErrSessionNotFound
+	}
+
+	stats := &SessionChatStats{
+		SessionID:         sessionID,
+		LiveID:            liveID,
+		Currency:          ChatCurrency,
+		TopChatters:       []ChatterStat{},
+		MessagesPerMinute: []MinuteCount{},
+	}
+	var updatedAt int64
+	err = s.db.QueryRowContext(ctx, `
+		SELECT message_count, gift_count, gift_value, super_chat_count, super_chat_value,
+		       guard_count, guard_value, unpriced_count, updated_at
+		FROM session_chat_stats WHERE session_id = ?
+	`, sessionID).Scan(&stats.MessageCount, &stats.GiftCount, &stats.GiftValue, &stats.SuperChatCount, &stats.SuperChatValue,
+		&stats.GuardCount, &stats.GuardValue, &stats.UnpricedCount, &updatedAt)
+	if err == sql.ErrNoRows {
+		return stats, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if updatedAt > 0 {
+		stats.UpdatedAt = time.Unix(updatedAt, 0)
+	}
+	stats.TotalValue = stats.GiftValue + stats.SuperChatValue + stats.GuardValue
+
+	err = s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM session_chatters WHERE session_id = ?
+	`, sessionID).Scan(&stats.UniqueChatters)
+	if err != nil {
+		return nil, err
+	}
+
+	if topN > 0 {
+		rows, err := s.db.QueryContext(ctx, `
+			SELECT username, message_count FROM session_chatters
+			WHERE session_id = ? ORDER BY message_count DESC, username LIMIT ?
+		`, sessionID, topN)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var c ChatterStat
+			if err := rows.Scan(&c.Username, &c.MessageCount); err != nil {
+				return nil, err
+			}
+			stats.TopChatters = append(stats.TopChatters, c)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT minute, message_count FROM session_chat_minutes
+		WHERE session_id = ? ORDER BY minute
+	`, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var minute int64
+		var c MinuteCount
+		if err := rows.Scan(&minute, &c.MessageCount); err != nil {
+			return nil, err
+		}
+		c.Minute = time.Unix(minute, 0)
+		stats.MessagesPerMinute = append(stats.MessagesPerMinute, c)
+	}
+	return stats, rows.Err()
+}
@@ -0,0 +1,57 @@
+package livestate
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionChatStats(t *testing.T) {
+	m, err := NewManager(filepath.Join(t.TempDir(), "lives.db"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer m.Close()
+
+	m.OnLiveStart("room", "https://live.example.com/1", "test", "host", "room")
+	sessions := m.GetSessionHistory("room", 1)
+	if !assert.Len(t, sessions, 1) {
+		return
+	}
+	id := sessions[0].ID
+
+	at := time.Date(2024, 1, 1, 20, 0, 30, 0, time.UTC)
+	m.RecordChat("room", ChatTypeDanmaku, "a", at, -1)
+	m.RecordChat("room", ChatTypeDanmaku, "a", at.Add(time.Minute), -1)
+	m.RecordChat("room", ChatTypeDanmaku, "b", at.Add(time.Minute), -1)
+	m.RecordChat("room", ChatTypeGift, "a", at, 5.2)
+	m.RecordChat("room", ChatTypeGift, "c", at, -1)
+	// 中途写入一次，之后的数据累加
+	m.flushChatStats()
+	m.RecordChat("room", ChatTypeDanmaku, "b", at.Add(time.Minute), -1)
+	m.RecordChat("room", ChatTypeSuperChat, "b", at, 30)
+	m.RecordChat("room", ChatTypeGuard, "c", at, 198)
+	m.OnLiveEnd("room")
+	// 下播后的消息不再计入
+	m.RecordChat("room", ChatTypeDanmaku, "a", at, -1)
+
+	stats, err := m.GetSessionChatStats("room", id, 1)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 4, stats.MessageCount)
+	assert.Equal(t, 2, stats.UniqueChatters)
+	assert.Equal(t, []ChatterStat{{Username: "a", MessageCount: 2}}, stats.TopChatters)
+	assert.Equal(t, 2, stats.GiftCount)
+	assert.Equal(t, 1, stats.UnpricedCount)
+	assert.InDelta(t, 233.2, stats.TotalValue, 1e-9)
+	assert.Equal(t, []MinuteCount{
+		{Minute: time.Unix(at.Truncate(time.Minute).Unix(), 0), MessageCount: 1},
+		{Minute: time.Unix(at.Add(time.Minute).Truncate(time.Minute).Unix(), 0), MessageCount: 3},
+	}, stats.MessagesPerMinute)
+
+	_, err = m.GetSessionChatStats("other", id, 10)
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+}
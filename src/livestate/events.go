@@ -1,10 +1,13 @@
 package livestate
 
 import (
+	"time"
+
 	"github.com/bililive-go/bililive-go/src/listeners"
 	"github.com/bililive-go/bililive-go/src/live"
 	"github.com/bililive-go/bililive-go/src/pkg/events"
 	"github.com/bililive-go/bililive-go/src/recorders"
+	"github.com/bililive-go/bililive-go/src/types"
 	"github.com/bluele/gcache"
 	"github.com/sirupsen/logrus"
 )
@@ -117,5 +120,18 @@ func RegisterEventListeners(ed events.Dispatcher, manager *Manager, cache gcache
 		manager.UpdateInfo(liveID, url, platform, hostName, roomName)
 	}))
 
+	// 弹幕录制器收到的消息计入当前会话的弹幕与营收统计
+	recorders.SetDanmakuStatsFunc(func(liveId types.LiveID, msgType, username, content string, extra map[string]interface{}) {
+		at := time.Now()
+		if ts, ok := extra["timestamp"].(int64); ok && ts > 0 {
+			at = time.Unix(ts, 0)
+		}
+		value := -1.0
+		if v, ok := extra["value_cny"].(float64); ok {
+			value = v
+		}
+		manager.RecordChat(string(liveId), msgType, username, at, value)
+	})
+
 	logrus.Info("直播间状态持久化事件监听器已注册")
 }
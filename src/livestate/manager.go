@@ -22,6 +22,14 @@ type Manager struct {
 	cancel          context.CancelFunc
 	recordingRooms  map[string]bool // 当前正在录制的直播间
 	mu              sync.RWMutex
+
+	// 自适应检测间隔使用的开播时间缓存
+	startTimesMu    sync.Mutex
+	startTimesCache map[string]startTimesCacheEntry
+
+	// 各直播间当前会话尚未写入数据库的弹幕统计
+	chatMu    sync.Mutex
+	chatStats map[string]*chatAccumulator
 }
 
 // NewManager 创建状态管理器
@@ -33,10 +41,12 @@ func NewManager(dbPath string) (*Manager, error) {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Manager{
-		store:          store,
-		ctx:            ctx,
-		cancel:         cancel,
-		recordingRooms: make(map[string]bool),
+		store:           store,
+		ctx:             ctx,
+		cancel:          cancel,
+		recordingRooms:  make(map[string]bool),
+		startTimesCache: make(map[string]startTimesCacheEntry),
+		chatStats:       make(map[string]*chatAccumulator),
 	}, nil
 }
 
@@ -63,6 +73,7 @@ func (m *Manager) heartbeatLoop() {
 			return
 		case <-m.heartbeatTicker.C:
 			m.updateHeartbeats()
+			m.flushChatStats()
 		}
 	}
 }
@@ -86,6 +97,7 @@ func (m *Manager) updateHeartbeats() {
 
 // Close 关闭管理器
 func (m *Manager) Close() error {
+	m.flushChatStats()
 	m.cancel()
 	if m.heartbeatTicker != nil {
 		m.heartbeatTicker.Stop()
@@ -158,9 +170,12 @@ func (m *Manager) OnLiveStart(liveID, url, platform, hostName, roomName string)
 	}
 
 	// 开始新的会话（包含名称信息）
-	if _, err := m.store.StartSession(m.ctx, liveID, hostName, roomName, now); err != nil {
+	sessionID, err := m.store.StartSession(m.ctx, liveID, hostName, roomName, now)
+	if err != nil {
 		logrus.WithError(err).WithField("live_id", liveID).Warn("创建直播会话失败")
 	}
+	m.resetChatStats(liveID, sessionID)
+	m.invalidateStartTimes(liveID)
 
 	logrus.WithFields(logrus.Fields{
 		"live_id":   liveID,
@@ -183,7 +198,8 @@ func (m *Manager) OnLiveEndWithReason(liveID string, reason string) {
 		logrus.WithError(err).WithField("live_id", liveID).Warn("更新关播时间失败")
 	}
 
-	// 结束当前会话
+	// 结束当前会话，先写入会话剩余的弹幕统计
+	m.finishChatStats(liveID)
 	if err := m.store.EndSession(m.ctx, liveID, now, reason); err != nil {
 		logrus.WithError(err).WithField("live_id", liveID).Warn("结束直播会话失败")
 	}
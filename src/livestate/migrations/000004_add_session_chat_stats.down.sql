@@ -0,0 +1,4 @@
+-- 删除会话弹幕与营收统计表
+DROP TABLE IF EXISTS session_chat_minutes;
+DROP TABLE IF EXISTS session_chatters;
+DROP TABLE IF EXISTS session_chat_stats;
@@ -0,0 +1,36 @@
+-- 直播会话弹幕与营收统计（按会话累计，录制期间定期增量写入）
+CREATE TABLE IF NOT EXISTS session_chat_stats (
+    session_id INTEGER PRIMARY KEY,         -- live_sessions.id
+    live_id TEXT NOT NULL,                  -- 直播间ID
+    message_count INTEGER DEFAULT 0,        -- 弹幕条数
+    gift_count INTEGER DEFAULT 0,           -- 礼物消息数
+    gift_value REAL DEFAULT 0,              -- 礼物金额（人民币元）
+    super_chat_count INTEGER DEFAULT 0,     -- SC 条数
+    super_chat_value REAL DEFAULT 0,        -- SC 金额（人民币元）
+    guard_count INTEGER DEFAULT 0,          -- 上舰次数
+    guard_value REAL DEFAULT 0,             -- 上舰金额（人民币元）
+    unpriced_count INTEGER DEFAULT 0,       -- 无法折算金额的礼物/付费消息数
+    updated_at INTEGER DEFAULT 0,           -- 更新时间 (Unix timestamp)
+    FOREIGN KEY (session_id) REFERENCES live_sessions(id) ON DELETE CASCADE
+);
+
+-- 会话内每个用户的发言数
+CREATE TABLE IF NOT EXISTS session_chatters (
+    session_id INTEGER NOT NULL,
+    username TEXT NOT NULL,
+    message_count INTEGER DEFAULT 0,
+    PRIMARY KEY (session_id, username),
+    FOREIGN KEY (session_id) REFERENCES live_sessions(id) ON DELETE CASCADE
+);
+
+-- 会话内每分钟的弹幕数
+CREATE TABLE IF NOT EXISTS session_chat_minutes (
+    session_id INTEGER NOT NULL,
+    minute INTEGER NOT NULL,                -- 该分钟开始时间 (Unix timestamp)
+    message_count INTEGER DEFAULT 0,
+    PRIMARY KEY (session_id, minute),
+    FOREIGN KEY (session_id) REFERENCES live_sessions(id) ON DELETE CASCADE
+);
+
+-- 索引
+CREATE INDEX IF NOT EXISTS idx_session_chat_stats_live_id ON session_chat_stats(live_id);
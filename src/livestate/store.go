@@ -55,6 +55,10 @@ type Store interface {
 	// SaveAvailableStreamsAny 通用接口，避免循环导入（接收 []map[string]interface{} 类型）
 	SaveAvailableStreamsAny(ctx context.Context, liveID string, streams interface{}) error
 
+	// 会话弹幕与营收统计
+	AddSessionChatStats(ctx context.Context, sessionID int64, liveID string, delta *ChatStatsDelta) error
+	GetSessionChatStats(ctx context.Context, liveID string, sessionID int64, topN int) (*SessionChatStats, error)
+
 	// 生命周期
 	Close() error
 }
@@ -450,7 +454,7 @@ func (s *SQLiteStore) GetOpenSessions(ctx context.Context) ([]*LiveSession, erro
 	defer s.mu.RUnlock()
 
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, live_id, start_time, end_time, end_reason, created_at
+		SELECT id, live_id, host_name, room_name, start_time, end_time, end_reason, created_at
 		FROM live_sessions WHERE end_time = 0
 	`)
 	if err != nil {
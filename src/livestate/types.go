@@ -64,3 +64,60 @@ type AvailableStream struct {
 	Attributes  map[string]string `json:"attributes"`   // 流属性键值对（如 "format": "flv", "codec": "h264"）
 	UpdatedAt   time.Time         `json:"updated_at"`   // 更新时间
 }
+
+// 弹幕消息类型，与弹幕录制器广播的 msgType 一致
+const (
+	ChatTypeDanmaku   = "danmaku"
+	ChatTypeGift      = "gift"
+	ChatTypeSuperChat = "super_chat"
+	ChatTypeGuard     = "guard"
+)
+
+// ChatCurrency 会话营收统一折算的币种
+const ChatCurrency = "CNY"
+
+// SessionChatStats 直播会话的弹幕与营收统计，包含被屏蔽规则过滤掉的弹幕
+type SessionChatStats struct {
+	SessionID         int64         `json:"session_id"`
+	LiveID            string        `json:"live_id"`
+	MessageCount      int           `json:"message_count"`       // 弹幕条数
+	UniqueChatters    int           `json:"unique_chatters"`     // 发言人数
+	TopChatters       []ChatterStat `json:"top_chatters"`        // 发言最多的用户
+	GiftCount         int           `json:"gift_count"`          // 礼物消息数
+	GiftValue         float64       `json:"gift_value"`          // 礼物金额
+	SuperChatCount    int           `json:"super_chat_count"`    // SC 条数
+	SuperChatValue    float64       `json:"super_chat_value"`    // SC 金额
+	GuardCount        int           `json:"guard_count"`         // 上舰次数
+	GuardValue        float64       `json:"guard_value"`         // 上舰金额
+	UnpricedCount     int           `json:"unpriced_count"`      // 平台未提供价格、无法折算金额的付费消息数
+	TotalValue        float64       `json:"total_value"`         // 礼物、SC、上舰金额合计
+	Currency          string        `json:"currency"`            // 金额币种，统一为 CNY
+	MessagesPerMinute []MinuteCount `json:"messages_per_minute"` // 每分钟弹幕数，按时间排序，没有弹幕的分钟不返回
+	UpdatedAt         time.Time     `json:"updated_at"`
+}
+
+// ChatterStat 单个用户在会话内的发言数
+type ChatterStat struct {
+	Username     string `json:"username"`
+	MessageCount int    `json:"message_count"`
+}
+
+// MinuteCount 一分钟内的弹幕数
+type MinuteCount struct {
+	Minute       time.Time `json:"minute"`
+	MessageCount int       `json:"message_count"`
+}
+
+// ChatStatsDelta 两次写入之间新增的统计，写入时累加到数据库已有数据上
+type ChatStatsDelta struct {
+	MessageCount   int
+	GiftCount      int
+	GiftValue      float64
+	SuperChatCount int
+	SuperChatValue float64
+	GuardCount     int
+	GuardValue     float64
+	UnpricedCount  int
+	Chatters       map[string]int // 用户名 -> 新增发言数
+	Minutes        map[int64]int  // 分钟开始的 Unix 时间 -> 新增弹幕数
+}
@@ -30,7 +30,7 @@ func SendEmail(subject, body string) error {
 		emailConfig.SMTPHost,
 		emailConfig.SMTPPort,
 		emailConfig.SenderEmail,
-		emailConfig.SenderPassword,
+		configs.ResolveSecret(emailConfig.SenderPassword),
 	)
 
 	if err := d.DialAndSend(m); err != nil {
@@ -65,7 +65,7 @@ func SendNotification(logger *livelogger.LiveLogger, hostName, platform, liveURL
 	if cfg.Notify.Telegram.Enable {
 		// 发送Telegram通知
 		err := telegram.SendMessage(
-			cfg.Notify.Telegram.BotToken,
+			configs.ResolveSecret(cfg.Notify.Telegram.BotToken),
 			cfg.Notify.Telegram.ChatID,
 			telegramMessage,
 			cfg.Notify.Telegram.WithNotification, // 发送带提醒的消息
@@ -105,7 +105,7 @@ func SendNotification(logger *livelogger.LiveLogger, hostName, platform, liveURL
 			// 发送Ntfy开始录制通知
 			err = ntfy.SendMessage(
 				cfg.Notify.Ntfy.URL,
-				cfg.Notify.Ntfy.Token,
+				configs.ResolveSecret(cfg.Notify.Ntfy.Token),
 				cfg.Notify.Ntfy.Tag,
 				hostName,
 				platform,
@@ -117,7 +117,7 @@ func SendNotification(logger *livelogger.LiveLogger, hostName, platform, liveURL
 			// 发送Ntfy停止录制通知
 			err = ntfy.SendStopMessage(
 				cfg.Notify.Ntfy.URL,
-				cfg.Notify.Ntfy.Token,
+				configs.ResolveSecret(cfg.Notify.Ntfy.Token),
 				cfg.Notify.Ntfy.Tag,
 				hostName,
 				platform,
@@ -138,7 +138,7 @@ func SendNotification(logger *livelogger.LiveLogger, hostName, platform, liveURL
 		case consts.LiveStatusStart:
 			err = bark.SendMessage(
 				cfg.Notify.Bark.ServerURL,
-				cfg.Notify.Bark.DeviceKey,
+				configs.ResolveSecret(cfg.Notify.Bark.DeviceKey),
 				cfg.Notify.Bark.Sound,
 				cfg.Notify.Bark.Group,
 				cfg.Notify.Bark.Icon,
@@ -151,7 +151,7 @@ func SendNotification(logger *livelogger.LiveLogger, hostName, platform, liveURL
 		case consts.LiveStatusStop:
 			err = bark.SendStopMessage(
 				cfg.Notify.Bark.ServerURL,
-				cfg.Notify.Bark.DeviceKey,
+				configs.ResolveSecret(cfg.Notify.Bark.DeviceKey),
 				cfg.Notify.Bark.Sound,
 				cfg.Notify.Bark.Group,
 				cfg.Notify.Bark.Icon,
@@ -172,7 +172,7 @@ func SendNotification(logger *livelogger.LiveLogger, hostName, platform, liveURL
 		title := fmt.Sprintf("%s - %s", hostInfo, platform)
 		body := fmt.Sprintf("主播：%s\n平台：%s\n直播地址：%s", hostInfo, platform, liveURL)
 		if err := wxpusher.SendMessage(
-			cfg.Notify.WxPusher.AppToken,
+			configs.ResolveSecret(cfg.Notify.WxPusher.AppToken),
 			cfg.Notify.WxPusher.UIDs,
 			title,
 			body,
@@ -262,24 +262,39 @@ func SendRecordingSummary(logger *livelogger.LiveLogger, hostName, platform stri
 	}
 
 	title, body := buildRecordingSummaryMessage(hostName, platform, files, outputPath)
+	sendTitledMessage(logger, cfg, "recording summary", title, body)
+}
 
+// SendSystemNotification 发送与具体直播间无关的系统通知（如账号 Cookie 失效需要重新登录）
+// 通过所有支持标题+正文格式的已启用渠道发送
+func SendSystemNotification(logger *livelogger.LiveLogger, title, body string) {
+	cfg := configs.GetCurrentConfig()
+	if cfg == nil {
+		return
+	}
+	sendTitledMessage(logger, cfg, "system notification", title, body)
+}
+
+// sendTitledMessage 通过 Telegram、Email、Bark、WxPusher 发送标题+正文格式的消息
+// kind 仅用于日志中区分消息类型
+func sendTitledMessage(logger *livelogger.LiveLogger, cfg *configs.Config, kind, title, body string) {
 	// Telegram
 	if cfg.Notify.Telegram.Enable {
 		msg := fmt.Sprintf("%s\n%s", title, body)
 		if err := telegram.SendMessage(
-			cfg.Notify.Telegram.BotToken,
+			configs.ResolveSecret(cfg.Notify.Telegram.BotToken),
 			cfg.Notify.Telegram.ChatID,
 			msg,
 			cfg.Notify.Telegram.WithNotification,
 		); err != nil {
-			logger.WithError(err).Error("Failed to send recording summary via Telegram")
+			logger.WithError(err).Errorf("Failed to send %s via Telegram", kind)
 		}
 	}
 
 	// Email
 	if cfg.Notify.Email.Enable {
 		if err := email.SendEmail(title, body); err != nil {
-			logger.WithError(err).Error("Failed to send recording summary via Email")
+			logger.WithError(err).Errorf("Failed to send %s via Email", kind)
 		}
 	}
 
@@ -287,7 +302,7 @@ func SendRecordingSummary(logger *livelogger.LiveLogger, hostName, platform stri
 	if cfg.Notify.Bark.Enable {
 		if err := bark.SendSummaryMessage(
 			cfg.Notify.Bark.ServerURL,
-			cfg.Notify.Bark.DeviceKey,
+			configs.ResolveSecret(cfg.Notify.Bark.DeviceKey),
 			cfg.Notify.Bark.Sound,
 			cfg.Notify.Bark.Group,
 			cfg.Notify.Bark.Icon,
@@ -295,19 +310,24 @@ func SendRecordingSummary(logger *livelogger.LiveLogger, hostName, platform stri
 			title,
 			body,
 		); err != nil {
-			logger.WithError(err).Error("Failed to send recording summary via Bark")
+			logger.WithError(err).Errorf("Failed to send %s via Bark", kind)
 		}
 	}
 
 	// WxPusher
 	if cfg.Notify.WxPusher.Enable {
 		if err := wxpusher.SendMessage(
-			cfg.Notify.WxPusher.AppToken,
+			configs.ResolveSecret(cfg.Notify.WxPusher.AppToken),
 			cfg.Notify.WxPusher.UIDs,
 			title,
 			body,
 		); err != nil {
-			logger.WithError(err).Error("Failed to send recording summary via WxPusher")
+			logger.WithError(err).Errorf("Failed to send %s via WxPusher", kind)
 		}
 	}
 }
+
+// GetDiskFreeSpace 获取指定路径所在磁盘的剩余可用空间（字节）
+func GetDiskFreeSpace(path string) (uint64, error) {
+	return getDiskFreeSpace(path)
+}
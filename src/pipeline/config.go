@@ -12,6 +12,8 @@ const (
 	StageNameCloudUpload    = "cloud_upload"
 	StageNameCustomCmd      = "custom_command"
 	StageNameBurnSubtitles  = "burn_subtitles"
+	StageNameHighlightClips = "highlight_clips"
+	StageNameStoryboard     = "storyboard"
 )
 
 // 阶段选项键常量
@@ -38,6 +40,18 @@ const (
 	OptionBurnDeleteAss = "burn_delete_ass"
 	// OptionBurnDeleteSource 烧录后是否删除源视频文件
 	OptionBurnDeleteSource = "burn_delete_source"
+	// OptionHighlightCount 剪辑的高光片段数
+	OptionHighlightCount = "highlight_count"
+	// OptionHighlightPreRoll 高光片段向前扩展的秒数
+	OptionHighlightPreRoll = "highlight_pre_roll"
+	// OptionHighlightPostRoll 高光片段向后扩展的秒数
+	OptionHighlightPostRoll = "highlight_post_roll"
+	// OptionStoryboardInterval 故事板缩略图间隔秒数
+	OptionStoryboardInterval = "storyboard_interval"
+	// OptionStoryboardColumns 故事板雪碧图每行的缩略图数
+	OptionStoryboardColumns = "storyboard_columns"
+	// OptionStoryboardWidth 故事板缩略图宽度（像素）
+	OptionStoryboardWidth = "storyboard_width"
 )
 
 // OnRecordFinishedPipeline 扩展版的录制完成后配置
@@ -57,6 +71,10 @@ type OnRecordFinishedPipeline struct {
 	BurnSubtitlesPreset   string               `yaml:"burn_subtitles_preset,omitempty" json:"burn_subtitles_preset,omitempty"`
 	BurnDeleteAss         bool                 `yaml:"burn_delete_ass,omitempty" json:"burn_delete_ass,omitempty"`
 	BurnDeleteSource      bool                 `yaml:"burn_delete_source,omitempty" json:"burn_delete_source,omitempty"`
+	ExtractHighlights     bool                 `yaml:"extract_highlights,omitempty" json:"extract_highlights,omitempty"`
+	HighlightCount        int                  `yaml:"highlight_count,omitempty" json:"highlight_count,omitempty"`
+	GenerateStoryboard    bool                 `yaml:"generate_storyboard,omitempty" json:"generate_storyboard,omitempty"`
+	StoryboardInterval    int                  `yaml:"storyboard_interval,omitempty" json:"storyboard_interval,omitempty"`
 
 	// 新格式字段
 	Pipeline *PipelineConfig `yaml:"pipeline,omitempty" json:"pipeline,omitempty"`
@@ -109,7 +127,27 @@ func ConvertLegacyConfig(legacy *configs.OnRecordFinished) *PipelineConfig {
 		})
 	}
 
-	// 5. 云上传
+	// 5. 故事板（拖动预览缩略图，随视频一起上传）
+	if legacy.GenerateStoryboard {
+		stages = append(stages, StageConfig{
+			Name: StageNameStoryboard,
+			Options: map[string]any{
+				OptionStoryboardInterval: legacy.StoryboardInterval,
+			},
+		})
+	}
+
+	// 6. 高光片段剪辑（在转换之后，剪出的片段随原视频一起上传）
+	if legacy.ExtractHighlights {
+		stages = append(stages, StageConfig{
+			Name: StageNameHighlightClips,
+			Options: map[string]any{
+				OptionHighlightCount: legacy.HighlightCount,
+			},
+		})
+	}
+
+	// 7. 云上传
 	if legacy.CloudUpload.Enable && legacy.CloudUpload.StorageName != "" {
 		stages = append(stages, StageConfig{
 			Name: StageNameCloudUpload,
@@ -121,7 +159,7 @@ func ConvertLegacyConfig(legacy *configs.OnRecordFinished) *PipelineConfig {
 		})
 	}
 
-	// 6. 自定义命令（在最后执行）
+	// 8. 自定义命令（在最后执行）
 	if legacy.CustomCommandline != "" {
 		stages = append(stages, StageConfig{
 			Name: StageNameCustomCmd,
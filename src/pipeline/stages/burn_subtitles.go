@@ -117,7 +117,7 @@ func (s *BurnSubtitlesStage) Execute(ctx *pipeline.PipelineContext, input []pipe
 		ctx.Logger.Infof("烧录字幕: %s + %s -> %s", file.Path, filepath.Base(assPath), outputPath)
 
 		// 获取视频时长用于进度计算
-		duration := s.getVideoDuration(ctx.Ctx, ffmpegPath, file.Path)
+		duration := getVideoDuration(ctx.Ctx, ffmpegPath, file.Path)
 
 		// 构建 FFmpeg 命令
 		escapedAssPath := escapeAssPath(assPath)
@@ -250,30 +250,6 @@ func escapeAssPath(path string) string {
 	return "'" + escaped + "'"
 }
 
-// getVideoDuration 获取视频时长（秒）
-func (s *BurnSubtitlesStage) getVideoDuration(ctx context.Context, ffmpegPath, inputFile string) float64 {
-	cmd := exec.CommandContext(ctx, ffmpegPath,
-		"-i", inputFile,
-		"-hide_banner",
-	)
-
-	output, _ := cmd.CombinedOutput()
-
-	// 解析 Duration: HH:MM:SS.ms
-	re := regexp.MustCompile(`Duration: (\d{2}):(\d{2}):(\d{2})\.(\d{2})`)
-	matches := re.FindStringSubmatch(string(output))
-	if len(matches) < 5 {
-		return 0
-	}
-
-	hours, _ := strconv.ParseFloat(matches[1], 64)
-	minutes, _ := strconv.ParseFloat(matches[2], 64)
-	seconds, _ := strconv.ParseFloat(matches[3], 64)
-	ms, _ := strconv.ParseFloat(matches[4], 64)
-
-	return hours*3600 + minutes*60 + seconds + ms/100
-}
-
 // parseProgress 解析 ffmpeg 进度输出
 func (s *BurnSubtitlesStage) parseProgress(ctx context.Context, stdout io.Reader, totalDuration float64) {
 	scanner := bufio.NewScanner(stdout)
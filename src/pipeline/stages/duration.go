@@ -0,0 +1,30 @@
+package stages
+
+import (
+	"context"
+	"os/exec"
+	"regexp"
+	"strconv"
+)
+
+// durationRegexp 匹配 ffmpeg -i 输出中的 Duration: HH:MM:SS.cc
+var durationRegexp = regexp.MustCompile(`Duration: (\d{2}):(\d{2}):(\d{2})\.(\d{2})`)
+
+// getVideoDuration 获取视频时长（秒），失败时返回 0
+func getVideoDuration(ctx context.Context, ffmpegPath, inputFile string) float64 {
+	output, _ := exec.CommandContext(ctx, ffmpegPath, "-hide_banner", "-i", inputFile).CombinedOutput()
+	return parseFFmpegDuration(string(output))
+}
+
+// parseFFmpegDuration 从 ffmpeg -i 的输出中解析时长（秒），找不到时返回 0
+func parseFFmpegDuration(output string) float64 {
+	matches := durationRegexp.FindStringSubmatch(output)
+	if len(matches) < 5 {
+		return 0
+	}
+	hours, _ := strconv.ParseFloat(matches[1], 64)
+	minutes, _ := strconv.ParseFloat(matches[2], 64)
+	seconds, _ := strconv.ParseFloat(matches[3], 64)
+	centis, _ := strconv.ParseFloat(matches[4], 64)
+	return hours*3600 + minutes*60 + seconds + centis/100
+}
@@ -0,0 +1,22 @@
+package stages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFFmpegDuration(t *testing.T) {
+	tests := []struct {
+		output string
+		want   float64
+	}{
+		{"  Duration: 00:00:05.50, start: 0.000000, bitrate: 1000 kb/s", 5.5},
+		{"  Duration: 02:03:04.05, start: 0.000000", 2*3600 + 3*60 + 4.05},
+		{"  Duration: N/A, start: 0.000000", 0},
+		{"input.flv: No such file or directory", 0},
+	}
+	for _, tt := range tests {
+		assert.InDelta(t, tt.want, parseFFmpegDuration(tt.output), 1e-9, tt.output)
+	}
+}
@@ -0,0 +1,208 @@
+package stages
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bililive-go/bililive-go/src/pipeline"
+	"github.com/bililive-go/bililive-go/src/pkg/recordmeta"
+	"github.com/bililive-go/bililive-go/src/pkg/utils"
+	"github.com/bililive-go/bililive-go/src/tools"
+)
+
+// HighlightClipsStage 高光片段剪辑阶段
+// 读取录制时写入 .meta.json 的高光片段，按关键帧无损剪出得分最高的若干段，
+// 并生成 <文件名>.highlights.json 汇总。
+type HighlightClipsStage struct {
+	config   pipeline.StageConfig
+	count    int
+	preRoll  int
+	postRoll int
+	commands []string
+	logs     string
+}
+
+// highlightSummary <文件名>.highlights.json 的内容
+type highlightSummary struct {
+	Source string          `json:"source"`
+	Clips  []highlightClip `json:"clips"`
+}
+
+type highlightClip struct {
+	File string `json:"file"`
+	recordmeta.Highlight
+	// ClipStart 片段在源视频中的实际开始秒数（含前后扩展，剪辑时会对齐到之前的关键帧）
+	ClipStart float64 `json:"clip_start"`
+	ClipEnd   float64 `json:"clip_end"`
+}
+
+// NewHighlightClipsStage 创建高光片段剪辑阶段工厂
+func NewHighlightClipsStage(config pipeline.StageConfig) (pipeline.Stage, error) {
+	count := config.GetIntOption(pipeline.OptionHighlightCount, 5)
+	if count <= 0 {
+		count = 5
+	}
+	return &HighlightClipsStage{
+		config:   config,
+		count:    count,
+		preRoll:  config.GetIntOption(pipeline.OptionHighlightPreRoll, 20),
+		postRoll: config.GetIntOption(pipeline.OptionHighlightPostRoll, 5),
+	}, nil
+}
+
+func (s *HighlightClipsStage) Name() string {
+	return pipeline.StageNameHighlightClips
+}
+
+func (s *HighlightClipsStage) Execute(ctx *pipeline.PipelineContext, input []pipeline.FileInfo) ([]pipeline.FileInfo, error) {
+	if len(input) == 0 {
+		s.logs = "没有输入文件"
+		return input, nil
+	}
+
+	ffmpegPath := ctx.FFmpegPath
+	if ffmpegPath == "" {
+		if waitErr := tools.WaitFFmpegAsyncInitDone(ctx.Ctx, nil); waitErr != nil {
+			s.logs = fmt.Sprintf("等待 FFmpeg 就绪被中断: %s", waitErr.Error())
+			return nil, waitErr
+		}
+		var err error
+		ffmpegPath, err = utils.GetFFmpegPath(ctx.Ctx)
+		if err != nil {
+			s.logs = fmt.Sprintf("ffmpeg 不可用: %s", err.Error())
+			return nil, fmt.Errorf("ffmpeg not available: %w", err)
+		}
+	}
+
+	output := append([]pipeline.FileInfo{}, input...)
+	// 转换 MP4 保留源文件时同一录制会有多个视频，只剪辑一次
+	done := make(map[string]bool)
+
+	for _, file := range input {
+		if file.Type != pipeline.FileTypeVideo {
+			continue
+		}
+		base := strings.TrimSuffix(file.Path, filepath.Ext(file.Path))
+		if done[base] {
+			continue
+		}
+		if _, err := os.Stat(file.Path); os.IsNotExist(err) {
+			s.logs += fmt.Sprintf("文件不存在: %s\n", file.Path)
+			continue
+		}
+
+		meta, err := recordmeta.Load(file.Path)
+		if err != nil {
+			s.logs += fmt.Sprintf("读取元数据失败: %s - %s\n", filepath.Base(file.Path), err.Error())
+			continue
+		}
+		if meta == nil || len(meta.Highlights) == 0 {
+			s.logs += fmt.Sprintf("没有高光片段，跳过: %s\n", filepath.Base(file.Path))
+			continue
+		}
+		done[base] = true
+
+		clips, files, err := s.cutClips(ctx, ffmpegPath, file.Path, meta.Highlights)
+		if err != nil {
+			return nil, err
+		}
+		output = append(output, files...)
+
+		summaryPath := base + ".highlights.json"
+		data, err := json.MarshalIndent(highlightSummary{Source: filepath.Base(file.Path), Clips: clips}, "", "  ")
+		if err == nil {
+			err = os.WriteFile(summaryPath, data, 0644)
+		}
+		if err != nil {
+			s.logs += fmt.Sprintf("写入高光汇总失败: %s - %s\n", filepath.Base(summaryPath), err.Error())
+			continue
+		}
+		output = append(output, pipeline.FileInfo{
+			Path:       summaryPath,
+			Type:       pipeline.FileTypeOther,
+			SourcePath: file.Path,
+		})
+		s.logs += fmt.Sprintf("已剪辑 %d 个高光片段: %s\n", len(clips), filepath.Base(file.Path))
+	}
+
+	return output, nil
+}
+
+// cutClips 剪出得分最高的 count 个片段，按时间顺序编号
+func (s *HighlightClipsStage) cutClips(ctx *pipeline.PipelineContext, ffmpegPath, videoPath string, highlights []recordmeta.Highlight) ([]highlightClip, []pipeline.FileInfo, error) {
+	top := append([]recordmeta.Highlight{}, highlights...)
+	sort.SliceStable(top, func(i, j int) bool { return top[i].Score > top[j].Score })
+	if len(top) > s.count {
+		top = top[:s.count]
+	}
+	sort.Slice(top, func(i, j int) bool { return top[i].Start < top[j].Start })
+
+	ext := filepath.Ext(videoPath)
+	base := strings.TrimSuffix(videoPath, ext)
+	var clips []highlightClip
+	var files []pipeline.FileInfo
+	for n, h := range top {
+		start := h.Start - float64(s.preRoll)
+		if start < 0 {
+			start = 0
+		}
+		end := h.End + float64(s.postRoll)
+		outputPath := fmt.Sprintf("%s.highlight%02d%s", base, n+1, ext)
+
+		// -ss 放在 -i 之前并使用流复制，从起点之前最近的关键帧开始剪辑，不重新编码
+		args := []string{
+			"-hide_banner",
+			"-ss", strconv.FormatFloat(start, 'f', 3, 64),
+			"-i", videoPath,
+			"-t", strconv.FormatFloat(end-start, 'f', 3, 64),
+			"-c", "copy",
+			"-avoid_negative_ts", "make_zero",
+			"-y",
+			outputPath,
+		}
+		s.commands = append(s.commands, fmt.Sprintf("%s %s", ffmpegPath, strings.Join(args, " ")))
+		ctx.Logger.Infof("剪辑高光片段: %s [%.0fs, %.0fs] 得分 %.0f -> %s", filepath.Base(videoPath), start, end, h.Score, filepath.Base(outputPath))
+
+		cmd := exec.CommandContext(ctx.Ctx, ffmpegPath, args...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			os.Remove(outputPath)
+			if ctx.Ctx.Err() != nil {
+				return nil, nil, ctx.Ctx.Err()
+			}
+			msg := strings.TrimSpace(string(out))
+			if i := strings.LastIndex(msg, "\n"); i >= 0 {
+				msg = msg[i+1:]
+			}
+			s.logs += fmt.Sprintf("剪辑高光片段失败: %s - %s: %s\n", filepath.Base(outputPath), err.Error(), msg)
+			ctx.Logger.Warnf("剪辑高光片段失败: %s - %s", outputPath, err)
+			continue
+		}
+
+		clips = append(clips, highlightClip{
+			File:      filepath.Base(outputPath),
+			Highlight: h,
+			ClipStart: start,
+			ClipEnd:   end,
+		})
+		files = append(files, pipeline.FileInfo{
+			Path:       outputPath,
+			Type:       pipeline.FileTypeVideo,
+			SourcePath: videoPath,
+		})
+	}
+	return clips, files, nil
+}
+
+func (s *HighlightClipsStage) GetCommands() []string {
+	return s.commands
+}
+
+func (s *HighlightClipsStage) GetLogs() string {
+	return s.logs
+}
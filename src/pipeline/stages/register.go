@@ -17,6 +17,12 @@ func RegisterBuiltinStages(executor *pipeline.Executor) {
 	// 封面提取
 	executor.RegisterStage(pipeline.StageNameExtractCover, NewExtractCoverStage)
 
+	// 故事板
+	executor.RegisterStage(pipeline.StageNameStoryboard, NewStoryboardStage)
+
+	// 高光片段剪辑
+	executor.RegisterStage(pipeline.StageNameHighlightClips, NewHighlightClipsStage)
+
 	// 云上传
 	executor.RegisterStage(pipeline.StageNameCloudUpload, NewCloudUploadStage)
 
@@ -44,6 +50,12 @@ func RegisterBuiltinStagesToManager(manager *pipeline.Manager) {
 	// 封面提取
 	manager.RegisterStage(pipeline.StageNameExtractCover, NewExtractCoverStage)
 
+	// 故事板
+	manager.RegisterStage(pipeline.StageNameStoryboard, NewStoryboardStage)
+
+	// 高光片段剪辑
+	manager.RegisterStage(pipeline.StageNameHighlightClips, NewHighlightClipsStage)
+
 	// 云上传
 	manager.RegisterStage(pipeline.StageNameCloudUpload, NewCloudUploadStage)
 
@@ -0,0 +1,216 @@
+package stages
+
+import (
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bililive-go/bililive-go/src/pipeline"
+	"github.com/bililive-go/bililive-go/src/pkg/recordmeta"
+	"github.com/bililive-go/bililive-go/src/pkg/utils"
+	"github.com/bililive-go/bililive-go/src/tools"
+)
+
+// storyboardMaxFrames 单张雪碧图最多的帧数，录制较长时加大间隔，避免图片过大
+const storyboardMaxFrames = 400
+
+// StoryboardStage 故事板阶段
+// 每隔固定时间截取一帧缩略图拼成 <文件名>.storyboard.jpg，
+// 并生成 <文件名>.storyboard.vtt 缩略图轨道，写入 .meta.json 供 WebUI 显示拖动预览。
+type StoryboardStage struct {
+	config   pipeline.StageConfig
+	interval int
+	columns  int
+	width    int
+	commands []string
+	logs     string
+}
+
+// NewStoryboardStage 创建故事板阶段工厂
+func NewStoryboardStage(config pipeline.StageConfig) (pipeline.Stage, error) {
+	interval := config.GetIntOption(pipeline.OptionStoryboardInterval, 10)
+	if interval <= 0 {
+		interval = 10
+	}
+	columns := config.GetIntOption(pipeline.OptionStoryboardColumns, 10)
+	if columns <= 0 {
+		columns = 10
+	}
+	width := config.GetIntOption(pipeline.OptionStoryboardWidth, 160)
+	if width < 16 {
+		width = 160
+	}
+	return &StoryboardStage{
+		config:   config,
+		interval: interval,
+		columns:  columns,
+		// 宽度取偶数，与 scale=W:-2 得到的偶数高度一致
+		width: width &^ 1,
+	}, nil
+}
+
+func (s *StoryboardStage) Name() string {
+	return pipeline.StageNameStoryboard
+}
+
+func (s *StoryboardStage) Execute(ctx *pipeline.PipelineContext, input []pipeline.FileInfo) ([]pipeline.FileInfo, error) {
+	if len(input) == 0 {
+		s.logs = "没有输入文件"
+		return input, nil
+	}
+
+	ffmpegPath := ctx.FFmpegPath
+	if ffmpegPath == "" {
+		if waitErr := tools.WaitFFmpegAsyncInitDone(ctx.Ctx, nil); waitErr != nil {
+			s.logs = fmt.Sprintf("等待 FFmpeg 就绪被中断: %s", waitErr.Error())
+			return nil, waitErr
+		}
+		var err error
+		ffmpegPath, err = utils.GetFFmpegPath(ctx.Ctx)
+		if err != nil {
+			s.logs = fmt.Sprintf("ffmpeg 不可用: %s", err.Error())
+			return nil, fmt.Errorf("ffmpeg not available: %w", err)
+		}
+	}
+
+	output := append([]pipeline.FileInfo{}, input...)
+	// 转换 MP4 保留源文件时同一录制会有多个视频，只生成一次
+	done := make(map[string]bool)
+
+	for _, file := range input {
+		if file.Type != pipeline.FileTypeVideo {
+			continue
+		}
+		base := strings.TrimSuffix(file.Path, filepath.Ext(file.Path))
+		if done[base] {
+			continue
+		}
+		if _, err := os.Stat(file.Path); os.IsNotExist(err) {
+			s.logs += fmt.Sprintf("文件不存在: %s\n", file.Path)
+			continue
+		}
+		done[base] = true
+
+		board, err := s.generate(ctx, ffmpegPath, file.Path)
+		if err != nil {
+			if ctx.Ctx.Err() != nil {
+				return nil, ctx.Ctx.Err()
+			}
+			s.logs += fmt.Sprintf("生成故事板失败: %s - %s\n", filepath.Base(file.Path), err.Error())
+			ctx.Logger.Warnf("生成故事板失败: %s - %s", file.Path, err)
+			continue
+		}
+		if err := recordmeta.Update(file.Path, func(m *recordmeta.Meta) { m.Storyboard = board }); err != nil {
+			s.logs += fmt.Sprintf("写入录制元数据失败: %s - %s\n", filepath.Base(file.Path), err.Error())
+		}
+
+		dir := filepath.Dir(file.Path)
+		for _, name := range []string{board.Sprite, board.VTT} {
+			output = append(output, pipeline.FileInfo{
+				Path:       filepath.Join(dir, name),
+				Type:       pipeline.FileTypeOther,
+				SourcePath: file.Path,
+			})
+		}
+		s.logs += fmt.Sprintf("故事板已生成: %s（%d 帧，间隔 %.0f 秒）\n", board.Sprite, board.Count, board.Interval)
+		ctx.Logger.Infof("故事板已生成: %s", filepath.Join(dir, board.Sprite))
+	}
+
+	return output, nil
+}
+
+// generate 截取缩略图拼成雪碧图并写出 WebVTT 轨道
+func (s *StoryboardStage) generate(ctx *pipeline.PipelineContext, ffmpegPath, videoPath string) (*recordmeta.Storyboard, error) {
+	duration := getVideoDuration(ctx.Ctx, ffmpegPath, videoPath)
+	if duration <= 0 {
+		return nil, fmt.Errorf("无法获取视频时长")
+	}
+	interval := float64(s.interval)
+	if duration/interval > storyboardMaxFrames {
+		interval = math.Ceil(duration / storyboardMaxFrames)
+	}
+	count := int(math.Ceil(duration / interval))
+	columns := min(s.columns, count)
+	rows := (count + columns - 1) / columns
+
+	base := strings.TrimSuffix(videoPath, filepath.Ext(videoPath))
+	spritePath := base + recordmeta.StoryboardSpriteSuffix
+	vttPath := base + recordmeta.StoryboardVTTSuffix
+
+	// 只解码关键帧，长录制也能很快完成；fps 滤镜按固定间隔取最接近的帧
+	args := []string{
+		"-hide_banner",
+		"-skip_frame", "nokey",
+		"-i", videoPath,
+		"-an", "-sn",
+		"-vf", fmt.Sprintf("fps=1/%s,scale=%d:-2,tile=%dx%d", strconv.FormatFloat(interval, 'f', -1, 64), s.width, columns, rows),
+		"-frames:v", "1",
+		"-q:v", "5",
+		"-y",
+		spritePath,
+	}
+	s.commands = append(s.commands, fmt.Sprintf("%s %s", ffmpegPath, strings.Join(args, " ")))
+	ctx.Logger.Infof("生成故事板: %s（%d 帧，间隔 %.0f 秒）", filepath.Base(videoPath), count, interval)
+
+	cmd := exec.CommandContext(ctx.Ctx, ffmpegPath, args...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		os.Remove(spritePath)
+		msg := strings.TrimSpace(string(out))
+		if i := strings.LastIndex(msg, "\n"); i >= 0 {
+			msg = msg[i+1:]
+		}
+		return nil, fmt.Errorf("%w: %s", err, msg)
+	}
+
+	// 单帧高度取决于视频宽高比，从生成的雪碧图尺寸反推
+	f, err := os.Open(spritePath)
+	if err != nil {
+		return nil, err
+	}
+	cfg, _, err := image.DecodeConfig(f)
+	f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("读取雪碧图失败: %w", err)
+	}
+
+	board := &recordmeta.Storyboard{
+		Sprite:   filepath.Base(spritePath),
+		VTT:      filepath.Base(vttPath),
+		Interval: interval,
+		Count:    count,
+		Columns:  columns,
+		Width:    cfg.Width / columns,
+		Height:   cfg.Height / rows,
+	}
+	if err := os.WriteFile(vttPath, []byte(storyboardVTT(board, duration)), 0644); err != nil {
+		return nil, err
+	}
+	return board, nil
+}
+
+// storyboardVTT 生成 WebVTT 缩略图轨道，每个条目指向雪碧图中的一帧（媒体片段 #xywh）
+func storyboardVTT(board *recordmeta.Storyboard, duration float64) string {
+	var sb strings.Builder
+	sb.WriteString("WEBVTT\n\n")
+	for i := 0; i < board.Count; i++ {
+		start := float64(i) * board.Interval
+		end := min(start+board.Interval, duration)
+		x := i % board.Columns * board.Width
+		y := i / board.Columns * board.Height
+		fmt.Fprintf(&sb, "%s --> %s\n%s#xywh=%d,%d,%d,%d\n\n",
+			vttTimestamp(start), vttTimestamp(end), board.Sprite, x, y, board.Width, board.Height)
+	}
+	return sb.String()
+}
+
+// vttTimestamp 格式化为 WebVTT 时间戳 HH:MM:SS.mmm
+func vttTimestamp(seconds float64) string {
+	ms := int64(math.Round(seconds * 1000))
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
@@ -0,0 +1,67 @@
+package stages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bililive-go/bililive-go/src/pkg/recordmeta"
+)
+
+func TestVttTimestamp(t *testing.T) {
+	tests := []struct {
+		seconds float64
+		want    string
+	}{
+		{0, "00:00:00.000"},
+		{1.2345, "00:00:01.235"},
+		{59.9996, "00:01:00.000"},
+		{3600, "01:00:00.000"},
+		{3*3600 + 25*60 + 7.5, "03:25:07.500"},
+		{100 * 3600, "100:00:00.000"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, vttTimestamp(tt.seconds), tt.seconds)
+	}
+}
+
+func TestStoryboardVTT(t *testing.T) {
+	tests := []struct {
+		name     string
+		board    recordmeta.Storyboard
+		duration float64
+		want     string
+	}{
+		{
+			name:     "最后一帧截止到视频时长",
+			board:    recordmeta.Storyboard{Sprite: "a.storyboard.jpg", Interval: 10, Count: 3, Columns: 2, Width: 160, Height: 90},
+			duration: 25,
+			want: "WEBVTT\n\n" +
+				"00:00:00.000 --> 00:00:10.000\na.storyboard.jpg#xywh=0,0,160,90\n\n" +
+				"00:00:10.000 --> 00:00:20.000\na.storyboard.jpg#xywh=160,0,160,90\n\n" +
+				"00:00:20.000 --> 00:00:25.000\na.storyboard.jpg#xywh=0,90,160,90\n\n",
+		},
+		{
+			name:     "超过一小时的时间戳",
+			board:    recordmeta.Storyboard{Sprite: "b.jpg", Interval: 1800, Count: 3, Columns: 3, Width: 100, Height: 50},
+			duration: 5400,
+			want: "WEBVTT\n\n" +
+				"00:00:00.000 --> 00:30:00.000\nb.jpg#xywh=0,0,100,50\n\n" +
+				"00:30:00.000 --> 01:00:00.000\nb.jpg#xywh=100,0,100,50\n\n" +
+				"01:00:00.000 --> 01:30:00.000\nb.jpg#xywh=200,0,100,50\n\n",
+		},
+		{
+			name:     "单列雪碧图按行排列",
+			board:    recordmeta.Storyboard{Sprite: "c.jpg", Interval: 2.5, Count: 2, Columns: 1, Width: 64, Height: 36},
+			duration: 10,
+			want: "WEBVTT\n\n" +
+				"00:00:00.000 --> 00:00:02.500\nc.jpg#xywh=0,0,64,36\n\n" +
+				"00:00:02.500 --> 00:00:05.000\nc.jpg#xywh=0,36,64,36\n\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, storyboardVTT(&tt.board, tt.duration))
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// Package apiclient 是 bililive-go HTTP API 的客户端，供命令行工具等程序控制正在运行的实例
+package apiclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout 默认的请求超时时间
+const DefaultTimeout = 30 * time.Second
+
+// Client 访问 bililive-go API 的客户端
+type Client struct {
+	// BaseURL 实例地址，如 http://127.0.0.1:8080
+	BaseURL string
+	// Token 对应服务端 rpc.token，为空时不携带
+	Token string
+	// HTTPClient 为空时使用带默认超时的客户端
+	HTTPClient *http.Client
+}
+
+// New 创建客户端，baseURL 缺少协议时默认使用 http
+func New(baseURL, token string) *Client {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
+		baseURL = "http://" + baseURL
+	}
+	return &Client{
+		BaseURL:    baseURL,
+		Token:      token,
+		HTTPClient: &http.Client{Timeout: DefaultTimeout},
+	}
+}
+
+// APIError 服务端返回的错误
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("请求失败: HTTP %d", e.StatusCode)
+	}
+	return fmt.Sprintf("请求失败: HTTP %d: %s", e.StatusCode, e.Message)
+}
+
+// errorBody 服务端通用响应中的错误字段
+type errorBody struct {
+	ErrNo  int    `json:"err_no"`
+	ErrMsg string `json:"err_msg"`
+}
+
+// Do 发送请求并把 JSON 响应解码到 out，供本包未封装的接口使用
+func (c *Client) Do(ctx context.Context, method, path string, body, out any) error {
+	return c.do(ctx, method, path, nil, body, out)
+}
+
+// do 发送请求并把 JSON 响应解码到 out（out 为空时丢弃响应）。
+// 非 2xx 状态码，以及状态码为 200 但 err_no 非零的通用响应都视为错误
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	u := c.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: DefaultTimeout}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var eb errorBody
+	isCommonResp := json.Unmarshal(b, &eb) == nil
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg := eb.ErrMsg
+		if !isCommonResp || msg == "" {
+			msg = strings.TrimSpace(string(b))
+		}
+		return &APIError{StatusCode: resp.StatusCode, Message: msg}
+	}
+	if isCommonResp && eb.ErrNo != 0 {
+		return &APIError{StatusCode: resp.StatusCode, Message: eb.ErrMsg}
+	}
+	if out == nil || len(b) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(b, out); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package apiclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientSendsTokenAndDecodesErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"err_no":401,"err_msg":"未授权","data":null}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/api/lives":
+			w.Write([]byte(`[{"id":"abc","live_url":"https://live.bilibili.com/1"}]`))
+		case "/api/lives/abc/stop":
+			// 状态码为 200 但 err_no 非零
+			w.Write([]byte(`{"err_no":400,"err_msg":"this live has not a listener","data":null}`))
+		case "/api/pipeline/tasks/7/retry":
+			http.Error(w, "task not found", http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	_, err := New(srv.URL, "").ListLives(ctx)
+	var apiErr *APIError
+	if assert.ErrorAs(t, err, &apiErr) {
+		assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+		assert.Equal(t, "未授权", apiErr.Message)
+	}
+
+	c := New(srv.URL, "s3cret")
+	id, err := c.ResolveLiveID(ctx, "live.bilibili.com/1/")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", id)
+
+	err = c.StopLive(ctx, "abc")
+	if assert.ErrorAs(t, err, &apiErr) {
+		assert.Equal(t, "this live has not a listener", apiErr.Message)
+	}
+
+	err = c.RetryTask(ctx, 7)
+	if assert.ErrorAs(t, err, &apiErr) {
+		assert.Equal(t, http.StatusInternalServerError, apiErr.StatusCode)
+		assert.Equal(t, "task not found", apiErr.Message)
+	}
+}
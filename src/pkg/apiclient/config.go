@@ -0,0 +1,46 @@
+package apiclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// PatchResult 是部分更新配置的结果，键以点号分隔的路径表示，如 "rpc.token"
+type PatchResult struct {
+	Applied []string `json:"applied"`
+	Ignored []string `json:"ignored"`
+}
+
+// GetConfig 获取当前配置（敏感字段已脱敏）
+func (c *Client) GetConfig(ctx context.Context) (map[string]any, error) {
+	var cfg map[string]any
+	if err := c.do(ctx, http.MethodGet, "/api/config", nil, nil, &cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// GetRawConfig 获取 YAML 格式的配置原文（敏感字段已脱敏）
+func (c *Client) GetRawConfig(ctx context.Context) (string, error) {
+	var raw struct {
+		Config string `json:"config"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/raw-config", nil, nil, &raw); err != nil {
+		return "", err
+	}
+	return raw.Config, nil
+}
+
+// PatchConfig 部分更新配置，patch 的结构与配置文件一致，如 {"rpc": {"token": "..."}}。
+// 以严格模式提交：patch 中包含服务端无法识别的键时整体拒绝，不做任何修改
+func (c *Client) PatchConfig(ctx context.Context, patch map[string]any) (*PatchResult, error) {
+	var resp struct {
+		Data PatchResult `json:"data"`
+	}
+	query := url.Values{"strict": {"true"}}
+	if err := c.do(ctx, http.MethodPatch, "/api/config", query, patch, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
@@ -0,0 +1,37 @@
+package apiclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// FileSearchResult 文件搜索结果，Path 为相对输出目录的路径
+type FileSearchResult struct {
+	Path         string `json:"path"`
+	Name         string `json:"name"`
+	IsFolder     bool   `json:"is_folder"`
+	Size         int64  `json:"size"`
+	LastModified int64  `json:"last_modified"`
+}
+
+// FileSearchResponse 文件搜索响应，Truncated 表示结果数量达到上限被截断
+type FileSearchResponse struct {
+	Query     string             `json:"query"`
+	Files     []FileSearchResult `json:"files"`
+	Truncated bool               `json:"truncated"`
+}
+
+// SearchFiles 按文件名搜索输出目录，limit 为 0 时使用服务端默认值
+func (c *Client) SearchFiles(ctx context.Context, q string, limit int) (*FileSearchResponse, error) {
+	query := url.Values{"q": {q}}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	var resp FileSearchResponse
+	if err := c.do(ctx, http.MethodGet, "/api/files/search", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,71 @@
+package apiclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bililive-go/bililive-go/src/live"
+)
+
+// ListLives 列出所有直播间
+func (c *Client) ListLives(ctx context.Context) ([]live.InfoJSON, error) {
+	var lives []live.InfoJSON
+	if err := c.do(ctx, http.MethodGet, "/api/lives", nil, nil, &lives); err != nil {
+		return nil, err
+	}
+	return lives, nil
+}
+
+// AddLive 添加直播间，listen 为 true 时立即开始监控
+func (c *Client) AddLive(ctx context.Context, roomURL string, listen bool) (*live.InfoJSON, error) {
+	reqs := []map[string]any{{"url": roomURL, "listen": listen}}
+	var lives []live.InfoJSON
+	if err := c.do(ctx, http.MethodPost, "/api/lives", nil, reqs, &lives); err != nil {
+		return nil, err
+	}
+	if len(lives) == 0 {
+		// 服务端只返回添加成功的直播间，失败原因记录在服务端日志中
+		return nil, fmt.Errorf("添加 %s 失败：直播间已存在或链接不受支持", roomURL)
+	}
+	return &lives[0], nil
+}
+
+// RemoveLive 删除直播间
+func (c *Client) RemoveLive(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/lives/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// StartLive 开始监控直播间
+func (c *Client) StartLive(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/api/lives/"+url.PathEscape(id)+"/start", nil, nil, nil)
+}
+
+// StopLive 停止监控直播间
+func (c *Client) StopLive(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodGet, "/api/lives/"+url.PathEscape(id)+"/stop", nil, nil, nil)
+}
+
+// ResolveLiveID 把直播间 ID 或直播间链接解析为直播间 ID
+func (c *Client) ResolveLiveID(ctx context.Context, idOrURL string) (string, error) {
+	lives, err := c.ListLives(ctx)
+	if err != nil {
+		return "", err
+	}
+	target := normalizeRoomURL(idOrURL)
+	for _, l := range lives {
+		if string(l.Id) == idOrURL || normalizeRoomURL(l.LiveUrl) == target {
+			return string(l.Id), nil
+		}
+	}
+	return "", fmt.Errorf("找不到直播间: %s", idOrURL)
+}
+
+func normalizeRoomURL(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "https://")
+	s = strings.TrimPrefix(s, "http://")
+	return strings.TrimRight(s, "/")
+}
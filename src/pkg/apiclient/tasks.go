@@ -0,0 +1,41 @@
+package apiclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bililive-go/bililive-go/src/pipeline"
+)
+
+// TaskFilter 后处理任务查询条件，零值字段不参与过滤
+type TaskFilter struct {
+	Status string
+	LiveID string
+	Limit  int
+}
+
+// ListTasks 列出后处理任务
+func (c *Client) ListTasks(ctx context.Context, filter TaskFilter) ([]*pipeline.PipelineTask, error) {
+	query := url.Values{}
+	if filter.Status != "" {
+		query.Set("status", filter.Status)
+	}
+	if filter.LiveID != "" {
+		query.Set("live_id", filter.LiveID)
+	}
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	var tasks []*pipeline.PipelineTask
+	if err := c.do(ctx, http.MethodGet, "/api/pipeline/tasks", query, nil, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// RetryTask 重试失败的后处理任务
+func (c *Client) RetryTask(ctx context.Context, id int64) error {
+	return c.do(ctx, http.MethodPost, "/api/pipeline/tasks/"+strconv.FormatInt(id, 10)+"/retry", nil, nil, nil)
+}
@@ -0,0 +1,281 @@
+// Package cookiekeeper 定期校验已保存的 Cookie，并在哔哩哔哩 Cookie 即将过期时
+// 使用 refresh_token 自动刷新，避免登录失效后录制画质悄悄降级。
+package cookiekeeper
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/live/bilibili"
+	applog "github.com/bililive-go/bililive-go/src/log"
+	"github.com/bililive-go/bililive-go/src/notify"
+	"github.com/bililive-go/bililive-go/src/pkg/cookiepool"
+	"github.com/bililive-go/bililive-go/src/pkg/livelogger"
+	"github.com/bililive-go/bililive-go/src/pkg/metadata"
+)
+
+const (
+	// BilibiliHost 哔哩哔哩直播 Cookie 在配置中使用的 host
+	BilibiliHost = "live.bilibili.com"
+
+	initialDelay = time.Minute
+)
+
+// 便于测试替换
+var (
+	verifyCookie  = bilibili.VerifyCookie
+	needsRefresh  = bilibili.NeedsRefresh
+	refreshCookie = bilibili.RefreshCookie
+	sendNotify    = func(title, body string) {
+		notify.SendSystemNotification(livelogger.New(0, logrus.Fields{"module": "cookie_keeper"}), title, body)
+	}
+)
+
+// SaveRefreshToken 保存扫码登录返回的 refresh_token，uid 为 Cookie 中的 DedeUserID
+func SaveRefreshToken(ctx context.Context, uid, refreshToken string) error {
+	store := metadata.GetStore()
+	if store == nil {
+		return fmt.Errorf("元数据存储未初始化")
+	}
+	if uid == "" || refreshToken == "" {
+		return nil
+	}
+	return store.Set(ctx, metadata.NamespaceBilibiliAuth, uid, refreshToken)
+}
+
+// LoadRefreshToken 读取 uid 对应的 refresh_token，不存在时返回空字符串
+func LoadRefreshToken(ctx context.Context, uid string) (string, error) {
+	store := metadata.GetStore()
+	if store == nil || uid == "" {
+		return "", nil
+	}
+	return store.Get(ctx, metadata.NamespaceBilibiliAuth, uid)
+}
+
+// cookieTarget 配置中的一个待校验 Cookie
+type cookieTarget struct {
+	// label 用于日志和通知，如 "cookies" 或 "cookie_pools/账号名"
+	label string
+	// raw 配置中的原始值（可能是密钥库引用），cookie 为解析后的明文
+	raw     string
+	cookie  string
+	account string // 为空表示 cookies 中的单个 Cookie
+}
+
+// Keeper Cookie 保活任务
+type Keeper struct {
+	getConfig func() *configs.Config
+	// onCookieUpdated Cookie 刷新并写入配置后调用，用于把新 Cookie 应用到直播间
+	onCookieUpdated func(host string)
+	now             func() time.Time
+
+	mu sync.Mutex
+	// notified 已发送过重新登录提醒的 Cookie（label+指纹），Cookie 变化后会重新提醒
+	notified map[string]struct{}
+}
+
+// New 创建 Cookie 保活任务
+func New(getConfig func() *configs.Config, onCookieUpdated func(host string)) *Keeper {
+	return &Keeper{
+		getConfig:       getConfig,
+		onCookieUpdated: onCookieUpdated,
+		now:             time.Now,
+		notified:        make(map[string]struct{}),
+	}
+}
+
+// Run 运行校验循环，直到 ctx 取消
+func (k *Keeper) Run(ctx context.Context) {
+	select {
+	case <-time.After(initialDelay):
+	case <-ctx.Done():
+		return
+	}
+
+	for {
+		interval := 6 * time.Hour
+		if cfg := k.getConfig(); cfg != nil {
+			if cfg.CookieKeeper.Enable {
+				k.CheckOnce(ctx)
+			}
+			if cfg.CookieKeeper.CheckIntervalHours > 0 {
+				interval = time.Duration(cfg.CookieKeeper.CheckIntervalHours) * time.Hour
+			}
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// CheckOnce 校验一遍配置中的哔哩哔哩 Cookie
+func (k *Keeper) CheckOnce(ctx context.Context) {
+	cfg := k.getConfig()
+	if cfg == nil {
+		return
+	}
+	refreshBefore := time.Duration(cfg.CookieKeeper.RefreshBeforeDays) * 24 * time.Hour
+	if refreshBefore <= 0 {
+		refreshBefore = 7 * 24 * time.Hour
+	}
+
+	for _, target := range collectTargets(cfg) {
+		if ctx.Err() != nil {
+			return
+		}
+		k.check(ctx, target, refreshBefore)
+	}
+}
+
+func collectTargets(cfg *configs.Config) []cookieTarget {
+	var targets []cookieTarget
+	if raw := cfg.Cookies[BilibiliHost]; strings.TrimSpace(raw) != "" {
+		if cookie := strings.TrimSpace(configs.ResolveSecret(raw)); cookie != "" {
+			targets = append(targets, cookieTarget{label: "cookies", raw: raw, cookie: cookie})
+		}
+	}
+	for _, account := range cfg.CookiePools[BilibiliHost].Accounts {
+		cookie := strings.TrimSpace(configs.ResolveSecret(account.Cookie))
+		if account.Disabled || cookie == "" {
+			continue
+		}
+		targets = append(targets, cookieTarget{
+			label:   "cookie_pools/" + account.Name,
+			raw:     account.Cookie,
+			cookie:  cookie,
+			account: account.Name,
+		})
+	}
+	return targets
+}
+
+func (k *Keeper) check(ctx context.Context, target cookieTarget, refreshBefore time.Duration) {
+	logger := applog.GetLogger().WithField("cookie", target.label)
+
+	status, err := verifyCookie(target.cookie)
+	if err != nil {
+		// 网络问题不代表 Cookie 失效，等待下次校验
+		logger.WithError(err).Warn("校验哔哩哔哩 Cookie 失败")
+		return
+	}
+	if !status.IsLogin {
+		k.notifyOnce(target, "哔哩哔哩 Cookie 已失效",
+			fmt.Sprintf("%s 中的哔哩哔哩 Cookie 已失效，高画质录制可能降级，请在 Web 界面重新扫码登录。", target.label))
+		return
+	}
+
+	need := false
+	if expiresAt := cookiepool.AccountExpiry(configs.CookieAccount{Cookie: target.cookie}); expiresAt != nil &&
+		expiresAt.Sub(k.now()) < refreshBefore {
+		need = true
+	}
+	if !need {
+		if need, err = needsRefresh(target.cookie); err != nil {
+			logger.WithError(err).Warn("查询哔哩哔哩 Cookie 刷新状态失败")
+			return
+		}
+	}
+	if !need {
+		logger.Debugf("哔哩哔哩 Cookie 有效（%s），无需刷新", status.Uname)
+		return
+	}
+
+	uid := bilibili.CookieValue(target.cookie, "DedeUserID")
+	refreshToken, err := LoadRefreshToken(ctx, uid)
+	if err != nil {
+		logger.WithError(err).Warn("读取 refresh_token 失败")
+	}
+	if refreshToken == "" {
+		k.notifyOnce(target, "哔哩哔哩 Cookie 即将过期",
+			fmt.Sprintf("%s 中的哔哩哔哩 Cookie（%s）即将过期，但没有可用的 refresh_token（仅扫码登录会保存），请在 Web 界面重新扫码登录。",
+				target.label, status.Uname))
+		return
+	}
+
+	result, err := refreshCookie(target.cookie, refreshToken)
+	if result == nil {
+		logger.WithError(err).Warn("刷新哔哩哔哩 Cookie 失败")
+		k.notifyOnce(target, "哔哩哔哩 Cookie 刷新失败",
+			fmt.Sprintf("%s 中的哔哩哔哩 Cookie（%s）自动刷新失败：%v\n请在 Web 界面重新扫码登录。", target.label, status.Uname, err))
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Warn("哔哩哔哩 Cookie 已刷新，但确认刷新失败")
+	}
+
+	// 新的 refresh_token 必须先保存，旧的已经失效
+	if err := SaveRefreshToken(ctx, uid, result.RefreshToken); err != nil {
+		logger.WithError(err).Error("保存新的 refresh_token 失败")
+	}
+	if err := k.applyCookie(target, result.Cookie); err != nil {
+		logger.WithError(err).Error("保存刷新后的哔哩哔哩 Cookie 失败")
+		return
+	}
+	logger.Infof("哔哩哔哩 Cookie（%s）已自动刷新", status.Uname)
+	if k.onCookieUpdated != nil {
+		k.onCookieUpdated(BilibiliHost)
+	}
+}
+
+// applyCookie 将刷新后的 Cookie 写回配置（引用密钥库时写回密钥库）；
+// 配置中的 Cookie 已被用户修改时不覆盖
+func (k *Keeper) applyCookie(target cookieTarget, newCookie string) error {
+	_, err := configs.UpdateWithRetry(func(c *configs.Config) error {
+		if target.account == "" {
+			cookie := c.Cookies[BilibiliHost]
+			if cookie != target.raw {
+				return fmt.Errorf("cookie 已被修改，跳过写回")
+			}
+			if err := c.AssignSecret(&cookie, newCookie); err != nil {
+				return err
+			}
+			c.Cookies[BilibiliHost] = cookie
+			return nil
+		}
+		pool, ok := c.CookiePools[BilibiliHost]
+		if !ok {
+			return fmt.Errorf("账号池已被删除，跳过写回")
+		}
+		for i, account := range pool.Accounts {
+			if account.Name == target.account && account.Cookie == target.raw {
+				if err := c.AssignSecret(&pool.Accounts[i].Cookie, newCookie); err != nil {
+					return err
+				}
+				c.CookiePools[BilibiliHost] = pool
+				return nil
+			}
+		}
+		return fmt.Errorf("账号 %s 已被修改，跳过写回", target.account)
+	}, 3, 10*time.Millisecond)
+	return err
+}
+
+func (k *Keeper) notifyOnce(target cookieTarget, title, body string) {
+	key := target.label + "\x00" + fingerprint(target.cookie)
+	k.mu.Lock()
+	if _, ok := k.notified[key]; ok {
+		k.mu.Unlock()
+		return
+	}
+	k.notified[key] = struct{}{}
+	k.mu.Unlock()
+
+	applog.GetLogger().Warn(title + ": " + body)
+	sendNotify(title, body)
+}
+
+func fingerprint(cookie string) string {
+	sum := md5.Sum([]byte(cookie))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,62 @@
+package cookiekeeper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/live/bilibili"
+)
+
+func stubKeeper(t *testing.T, status *bilibili.CookieStatus, refresh bool) *[]string {
+	t.Helper()
+	oldVerify, oldNeeds, oldRefresh, oldNotify := verifyCookie, needsRefresh, refreshCookie, sendNotify
+	t.Cleanup(func() {
+		verifyCookie, needsRefresh, refreshCookie, sendNotify = oldVerify, oldNeeds, oldRefresh, oldNotify
+	})
+
+	var titles []string
+	verifyCookie = func(string) (*bilibili.CookieStatus, error) { return status, nil }
+	needsRefresh = func(string) (bool, error) { return refresh, nil }
+	refreshCookie = func(string, string) (*bilibili.RefreshResult, error) {
+		t.Fatal("没有 refresh_token 时不应尝试刷新")
+		return nil, nil
+	}
+	sendNotify = func(title, _ string) { titles = append(titles, title) }
+	return &titles
+}
+
+func newTestKeeper() *Keeper {
+	cfg := &configs.Config{
+		Cookies:      map[string]string{BilibiliHost: "SESSDATA=a; bili_jct=b; DedeUserID=1"},
+		CookieKeeper: configs.CookieKeeperConfig{Enable: true, RefreshBeforeDays: 7},
+	}
+	k := New(func() *configs.Config { return cfg }, nil)
+	k.now = func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) }
+	return k
+}
+
+func TestCheckOnceNotifiesLoginInvalidOnce(t *testing.T) {
+	titles := stubKeeper(t, &bilibili.CookieStatus{IsLogin: false}, false)
+	k := newTestKeeper()
+
+	k.CheckOnce(context.Background())
+	k.CheckOnce(context.Background())
+	assert.Equal(t, []string{"哔哩哔哩 Cookie 已失效"}, *titles)
+}
+
+func TestCheckOnceValidCookieNoRefresh(t *testing.T) {
+	titles := stubKeeper(t, &bilibili.CookieStatus{IsLogin: true, Uname: "tester"}, false)
+	newTestKeeper().CheckOnce(context.Background())
+	assert.Empty(t, *titles)
+}
+
+func TestCheckOnceRefreshWithoutToken(t *testing.T) {
+	// 元数据存储未初始化时读取不到 refresh_token，应提醒重新登录
+	titles := stubKeeper(t, &bilibili.CookieStatus{IsLogin: true, Uname: "tester"}, true)
+	newTestKeeper().CheckOnce(context.Background())
+	assert.Equal(t, []string{"哔哩哔哩 Cookie 即将过期"}, *titles)
+}
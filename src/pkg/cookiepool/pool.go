@@ -0,0 +1,429 @@
+// Package cookiepool 管理多账号 Cookie 池：为直播间分配账号、跟踪账号健康状态，
+// 并在账号触发风控或登录失效时切换到其他账号。
+//
+// 账号池配置来自 configs.Config.CookiePools，运行时状态（冷却、失效、分配关系）仅保存在内存中，
+// 账号的 Cookie 内容变化后对应状态会自动重置。
+package cookiepool
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"hash/fnv"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/live"
+	applog "github.com/bililive-go/bililive-go/src/log"
+	"github.com/bililive-go/bililive-go/src/types"
+)
+
+// AccountStatus 账号健康状态
+type AccountStatus string
+
+const (
+	// StatusHealthy 可正常分配
+	StatusHealthy AccountStatus = "healthy"
+	// StatusCooling 触发风控，冷却中
+	StatusCooling AccountStatus = "cooling"
+	// StatusExpired Cookie 已过期或登录失效，需要重新登录
+	StatusExpired AccountStatus = "expired"
+	// StatusDisabled 在配置中被禁用
+	StatusDisabled AccountStatus = "disabled"
+)
+
+const (
+	// baseCooldown 首次触发风控的冷却时长，连续触发时按指数增长
+	baseCooldown = 10 * time.Minute
+	// maxCooldown 冷却时长上限
+	maxCooldown = 6 * time.Hour
+	// expiringSoon 距离过期不足该时长时在健康状态中提示
+	expiringSoon = 72 * time.Hour
+)
+
+// FailureKind 请求失败的类型
+type FailureKind int
+
+const (
+	// FailureOther 与 Cookie 无关的失败（网络错误、房间不存在等）
+	FailureOther FailureKind = iota
+	// FailureRiskControl 触发平台风控
+	FailureRiskControl
+	// FailureAuth Cookie 失效或需要登录
+	FailureAuth
+)
+
+// ClassifyError 判断请求失败的类型，平台实现以 live.ErrRiskControl / live.ErrLoginRequired 包装相应错误
+func ClassifyError(err error) FailureKind {
+	switch {
+	case errors.Is(err, live.ErrRiskControl):
+		return FailureRiskControl
+	case errors.Is(err, live.ErrLoginRequired):
+		return FailureAuth
+	}
+	return FailureOther
+}
+
+// AccountHealth 账号健康状态（不包含 Cookie 内容）
+type AccountHealth struct {
+	Name                string        `json:"name"`
+	Status              AccountStatus `json:"status"`
+	AssignedRooms       int           `json:"assigned_rooms"`
+	ConsecutiveFailures int           `json:"consecutive_failures"`
+	TotalSuccess        int64         `json:"total_success"`
+	TotalFailures       int64         `json:"total_failures"`
+	LastError           string        `json:"last_error,omitempty"`
+	LastSuccessAt       *time.Time    `json:"last_success_at,omitempty"`
+	LastFailureAt       *time.Time    `json:"last_failure_at,omitempty"`
+	CooldownUntil       *time.Time    `json:"cooldown_until,omitempty"`
+	ExpiresAt           *time.Time    `json:"expires_at,omitempty"`
+	ExpiringSoon        bool          `json:"expiring_soon,omitempty"`
+}
+
+// PoolHealth 单个 host 账号池的健康状态
+type PoolHealth struct {
+	Host     string                     `json:"host"`
+	Strategy configs.CookiePoolStrategy `json:"strategy"`
+	Accounts []AccountHealth            `json:"accounts"`
+}
+
+// accountState 账号的运行时状态
+type accountState struct {
+	fingerprint   string // Cookie 内容摘要，Cookie 变化后状态重置
+	failures      int    // 连续风控次数
+	totalSuccess  int64
+	totalFailures int64
+	lastError     string
+	lastSuccessAt time.Time
+	lastFailureAt time.Time
+	coolUntil     time.Time
+	loginInvalid  bool
+}
+
+type assignment struct {
+	host    string
+	account string
+}
+
+// Manager 账号池管理器
+type Manager struct {
+	getConfig func() *configs.Config
+	now       func() time.Time
+
+	mu          sync.Mutex
+	states      map[string]*accountState // key: host + "\x00" + 账号名
+	assignments map[types.LiveID]assignment
+	cursors     map[string]int // round_robin 游标
+}
+
+// NewManager 创建账号池管理器，getConfig 用于读取最新配置
+func NewManager(getConfig func() *configs.Config) *Manager {
+	return &Manager{
+		getConfig:   getConfig,
+		now:         time.Now,
+		states:      make(map[string]*accountState),
+		assignments: make(map[types.LiveID]assignment),
+		cursors:     make(map[string]int),
+	}
+}
+
+func stateKey(host, name string) string {
+	return host + "\x00" + name
+}
+
+func fingerprint(cookie string) string {
+	sum := md5.Sum([]byte(cookie))
+	return hex.EncodeToString(sum[:])
+}
+
+// state 获取账号状态，Cookie 内容变化时重置。调用方需持有 m.mu
+func (m *Manager) state(host string, account configs.CookieAccount) *accountState {
+	key := stateKey(host, account.Name)
+	fp := fingerprint(configs.ResolveSecret(account.Cookie))
+	st, ok := m.states[key]
+	if !ok || st.fingerprint != fp {
+		st = &accountState{fingerprint: fp}
+		m.states[key] = st
+	}
+	return st
+}
+
+// status 计算账号当前状态。调用方需持有 m.mu
+func (m *Manager) status(host string, account configs.CookieAccount, now time.Time) AccountStatus {
+	if account.Disabled || strings.TrimSpace(account.Cookie) == "" {
+		return StatusDisabled
+	}
+	if expiresAt := AccountExpiry(account); expiresAt != nil && !now.Before(*expiresAt) {
+		return StatusExpired
+	}
+	st := m.state(host, account)
+	if st.loginInvalid {
+		return StatusExpired
+	}
+	if now.Before(st.coolUntil) {
+		return StatusCooling
+	}
+	return StatusHealthy
+}
+
+// CookieFor 返回分配给直播间的 Cookie。
+// host 未配置账号池或账号全部过期/禁用时 ok 为 false，调用方应回退到 cookies 配置。
+func (m *Manager) CookieFor(host string, liveID types.LiveID) (account, cookie string, ok bool) {
+	cfg := m.getConfig()
+	if cfg == nil {
+		return "", "", false
+	}
+	pool, exists := cfg.CookiePools[host]
+	if !exists || len(pool.Accounts) == 0 {
+		return "", "", false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+
+	healthy := make([]configs.CookieAccount, 0, len(pool.Accounts))
+	var cooling []configs.CookieAccount
+	for _, a := range pool.Accounts {
+		switch m.status(host, a, now) {
+		case StatusHealthy:
+			healthy = append(healthy, a)
+		case StatusCooling:
+			cooling = append(cooling, a)
+		}
+	}
+
+	// 已有分配且账号仍健康时保持不变
+	if current, ok := m.assignments[liveID]; ok && current.host == host {
+		for _, a := range healthy {
+			if a.Name == current.account {
+				return a.Name, configs.ResolveSecret(a.Cookie), true
+			}
+		}
+	}
+
+	var chosen configs.CookieAccount
+	switch {
+	case len(healthy) > 0:
+		if pool.Strategy == configs.CookiePoolSticky {
+			chosen = pickSticky(healthy, liveID)
+		} else {
+			chosen = healthy[m.cursors[host]%len(healthy)]
+			m.cursors[host]++
+		}
+	case len(cooling) > 0:
+		// 全部账号都在冷却：选择最早结束冷却的账号，避免直接退回无登录状态
+		sort.Slice(cooling, func(i, j int) bool {
+			return m.state(host, cooling[i]).coolUntil.Before(m.state(host, cooling[j]).coolUntil)
+		})
+		chosen = cooling[0]
+	default:
+		delete(m.assignments, liveID)
+		return "", "", false
+	}
+
+	m.assignments[liveID] = assignment{host: host, account: chosen.Name}
+	return chosen.Name, configs.ResolveSecret(chosen.Cookie), true
+}
+
+// pickSticky 使用最高随机权重（rendezvous hashing）为直播间选择账号，
+// 账号增减时只有受影响的直播间会被重新分配
+func pickSticky(accounts []configs.CookieAccount, liveID types.LiveID) configs.CookieAccount {
+	var (
+		best      configs.CookieAccount
+		bestScore uint64
+	)
+	for i, a := range accounts {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(liveID))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(a.Name))
+		if score := h.Sum64(); i == 0 || score > bestScore {
+			best, bestScore = a, score
+		}
+	}
+	return best
+}
+
+// ReportResult 记录直播间请求结果，err 为 nil 表示成功。
+// 若失败是由账号引起的（风控 / 登录失效），将该账号置为冷却或失效，
+// 并返回所有原本分配到该账号的直播间，调用方需要为它们重新应用 Cookie。
+func (m *Manager) ReportResult(liveID types.LiveID, err error) []types.LiveID {
+	cfg := m.getConfig()
+	if cfg == nil {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.assignments[liveID]
+	if !ok {
+		return nil
+	}
+	account, ok := findAccount(cfg.CookiePools[current.host], current.account)
+	if !ok {
+		// 账号已从配置中移除
+		delete(m.assignments, liveID)
+		return []types.LiveID{liveID}
+	}
+	st := m.state(current.host, account)
+	now := m.now()
+
+	if err == nil {
+		st.failures = 0
+		st.totalSuccess++
+		st.lastSuccessAt = now
+		return nil
+	}
+
+	kind := ClassifyError(err)
+	if kind == FailureOther {
+		return nil
+	}
+	st.totalFailures++
+	st.lastError = err.Error()
+	st.lastFailureAt = now
+	switch kind {
+	case FailureRiskControl:
+		st.failures++
+		cooldown := baseCooldown << (st.failures - 1)
+		if cooldown > maxCooldown || cooldown <= 0 {
+			cooldown = maxCooldown
+		}
+		st.coolUntil = now.Add(cooldown)
+		applog.GetLogger().Warnf("账号池 %s 的账号 %s 触发风控，冷却 %s 后再使用", current.host, current.account, cooldown)
+	case FailureAuth:
+		st.loginInvalid = true
+		applog.GetLogger().Warnf("账号池 %s 的账号 %s 登录已失效，需要更新 Cookie", current.host, current.account)
+	}
+
+	affected := make([]types.LiveID, 0, 1)
+	for id, a := range m.assignments {
+		if a == current {
+			affected = append(affected, id)
+			delete(m.assignments, id)
+		}
+	}
+	return affected
+}
+
+// Release 释放直播间的账号分配（直播间被移除时调用）
+func (m *Manager) Release(liveID types.LiveID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.assignments, liveID)
+}
+
+// Health 返回所有账号池的健康状态，按 host 排序
+func (m *Manager) Health() []PoolHealth {
+	cfg := m.getConfig()
+	if cfg == nil {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+
+	assigned := make(map[string]int)
+	for _, a := range m.assignments {
+		assigned[stateKey(a.host, a.account)]++
+	}
+
+	hosts := make([]string, 0, len(cfg.CookiePools))
+	for host := range cfg.CookiePools {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	result := make([]PoolHealth, 0, len(hosts))
+	for _, host := range hosts {
+		pool := cfg.CookiePools[host]
+		strategy := pool.Strategy
+		if strategy == "" {
+			strategy = configs.CookiePoolRoundRobin
+		}
+		ph := PoolHealth{Host: host, Strategy: strategy, Accounts: make([]AccountHealth, 0, len(pool.Accounts))}
+		for _, a := range pool.Accounts {
+			st := m.state(host, a)
+			ah := AccountHealth{
+				Name:                a.Name,
+				Status:              m.status(host, a, now),
+				AssignedRooms:       assigned[stateKey(host, a.Name)],
+				ConsecutiveFailures: st.failures,
+				TotalSuccess:        st.totalSuccess,
+				TotalFailures:       st.totalFailures,
+				LastError:           st.lastError,
+				LastSuccessAt:       timePtr(st.lastSuccessAt),
+				LastFailureAt:       timePtr(st.lastFailureAt),
+				ExpiresAt:           AccountExpiry(a),
+			}
+			if ah.Status == StatusCooling {
+				ah.CooldownUntil = timePtr(st.coolUntil)
+			}
+			if ah.ExpiresAt != nil && ah.Status != StatusExpired && ah.ExpiresAt.Sub(now) < expiringSoon {
+				ah.ExpiringSoon = true
+			}
+			ph.Accounts = append(ph.Accounts, ah)
+		}
+		result = append(result, ph)
+	}
+	return result
+}
+
+func findAccount(pool configs.CookiePool, name string) (configs.CookieAccount, bool) {
+	for _, a := range pool.Accounts {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return configs.CookieAccount{}, false
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// AccountExpiry 返回账号的过期时间：优先使用配置的 expires_at，
+// 否则尝试从哔哩哔哩 SESSDATA（格式：token,过期时间戳,校验）中解析
+func AccountExpiry(account configs.CookieAccount) *time.Time {
+	if account.ExpiresAt != nil {
+		return account.ExpiresAt
+	}
+	for _, part := range strings.Split(configs.ResolveSecret(account.Cookie), ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || key != "SESSDATA" {
+			continue
+		}
+		if unescaped, err := url.QueryUnescape(value); err == nil {
+			value = unescaped
+		}
+		fields := strings.Split(value, ",")
+		if len(fields) < 2 {
+			return nil
+		}
+		ts, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil || ts <= 0 {
+			return nil
+		}
+		t := time.Unix(ts, 0)
+		return &t
+	}
+	return nil
+}
+
+var defaultManager = NewManager(configs.GetCurrentConfig)
+
+// Default 返回全局账号池管理器
+func Default() *Manager {
+	return defaultManager
+}
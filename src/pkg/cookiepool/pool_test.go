@@ -0,0 +1,125 @@
+package cookiepool
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/live"
+	"github.com/bililive-go/bililive-go/src/types"
+)
+
+const testHost = "live.bilibili.com"
+
+func newTestManager(strategy configs.CookiePoolStrategy, accounts ...configs.CookieAccount) (*Manager, *time.Time) {
+	cfg := &configs.Config{
+		CookiePools: map[string]configs.CookiePool{
+			testHost: {Strategy: strategy, Accounts: accounts},
+		},
+	}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := NewManager(func() *configs.Config { return cfg })
+	m.now = func() time.Time { return now }
+	return m, &now
+}
+
+func TestCookieForRoundRobin(t *testing.T) {
+	m, _ := newTestManager("",
+		configs.CookieAccount{Name: "a", Cookie: "k=a"},
+		configs.CookieAccount{Name: "b", Cookie: "k=b"},
+	)
+
+	name1, _, ok := m.CookieFor(testHost, "room1")
+	assert.True(t, ok)
+	name2, _, _ := m.CookieFor(testHost, "room2")
+	assert.NotEqual(t, name1, name2)
+
+	// 已分配的直播间保持不变
+	again, _, _ := m.CookieFor(testHost, "room1")
+	assert.Equal(t, name1, again)
+}
+
+func TestCookieForSticky(t *testing.T) {
+	m, _ := newTestManager(configs.CookiePoolSticky,
+		configs.CookieAccount{Name: "a", Cookie: "k=a"},
+		configs.CookieAccount{Name: "b", Cookie: "k=b"},
+		configs.CookieAccount{Name: "c", Cookie: "k=c"},
+	)
+	first, _, _ := m.CookieFor(testHost, "room1")
+	m.Release("room1")
+	second, _, _ := m.CookieFor(testHost, "room1")
+	assert.Equal(t, first, second)
+}
+
+func TestCookieForUnknownHost(t *testing.T) {
+	m, _ := newTestManager("", configs.CookieAccount{Name: "a", Cookie: "k=a"})
+	_, _, ok := m.CookieFor("www.douyu.com", "room1")
+	assert.False(t, ok)
+}
+
+func TestReportRiskControlRotatesAccount(t *testing.T) {
+	m, now := newTestManager(configs.CookiePoolSticky,
+		configs.CookieAccount{Name: "a", Cookie: "k=a"},
+		configs.CookieAccount{Name: "b", Cookie: "k=b"},
+	)
+	first, _, _ := m.CookieFor(testHost, "room1")
+
+	assert.Empty(t, m.ReportResult("room1", errors.New("dial tcp: i/o timeout")))
+	affected := m.ReportResult("room1", fmt.Errorf("%w: status 412, code 0", live.ErrRiskControl))
+	assert.Equal(t, []types.LiveID{"room1"}, affected)
+
+	second, _, ok := m.CookieFor(testHost, "room1")
+	assert.True(t, ok)
+	assert.NotEqual(t, first, second)
+
+	health := m.Health()
+	if assert.Len(t, health, 1) {
+		for _, a := range health[0].Accounts {
+			if a.Name == first {
+				assert.Equal(t, StatusCooling, a.Status)
+				assert.NotNil(t, a.CooldownUntil)
+			}
+		}
+	}
+
+	// 冷却结束后恢复可用
+	*now = now.Add(baseCooldown + time.Second)
+	for _, a := range m.Health()[0].Accounts {
+		assert.Equal(t, StatusHealthy, a.Status)
+	}
+}
+
+func TestReportLoginRequiredMarksExpired(t *testing.T) {
+	m, _ := newTestManager("", configs.CookieAccount{Name: "a", Cookie: "k=a"})
+	_, _, ok := m.CookieFor(testHost, "room1")
+	assert.True(t, ok)
+
+	m.ReportResult("room1", live.StatusError(http.StatusUnauthorized))
+	_, _, ok = m.CookieFor(testHost, "room1")
+	assert.False(t, ok, "唯一账号失效后应回退到 cookies 配置")
+	assert.Equal(t, StatusExpired, m.Health()[0].Accounts[0].Status)
+}
+
+func TestSessdataExpiry(t *testing.T) {
+	expiry := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	sessdata := url.QueryEscape(fmt.Sprintf("abc,%d,def*11", expiry.Unix()))
+	m, now := newTestManager("", configs.CookieAccount{Name: "a", Cookie: "SESSDATA=" + sessdata + "; bili_jct=x"})
+
+	health := m.Health()[0].Accounts[0]
+	assert.Equal(t, StatusHealthy, health.Status)
+	assert.True(t, health.ExpiringSoon)
+	if assert.NotNil(t, health.ExpiresAt) {
+		assert.True(t, health.ExpiresAt.Equal(expiry))
+	}
+
+	*now = expiry.Add(time.Second)
+	assert.Equal(t, StatusExpired, m.Health()[0].Accounts[0].Status)
+	_, _, ok := m.CookieFor(testHost, "room1")
+	assert.False(t, ok)
+}
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	blog "github.com/bililive-go/bililive-go/src/log"
+	"github.com/bililive-go/bililive-go/src/pkg/proxy"
 	bilisentry "github.com/bililive-go/bililive-go/src/pkg/sentry"
 )
 
@@ -32,6 +33,8 @@ type FLVProxy struct {
 	localURL    string
 	upstreamURL string
 	headers     map[string]string
+	// upstreamProxy 连接上游使用的代理地址（为空时使用环境变量）
+	upstreamProxy string
 
 	// 分段检测
 	avcHeaderCount int
@@ -72,6 +75,11 @@ func NewFLVProxy(upstreamURL string, headers map[string]string) (*FLVProxy, erro
 	return proxy, nil
 }
 
+// SetUpstreamProxy 设置连接上游使用的代理地址，需在 Serve 之前调用
+func (p *FLVProxy) SetUpstreamProxy(proxyURL string) {
+	p.upstreamProxy = proxyURL
+}
+
 // RequestSegment 请求在下一个关键帧处分段
 // 返回 true 表示请求已接受，false 表示距离上次分段时间过短
 func (p *FLVProxy) RequestSegment() bool {
@@ -166,7 +174,8 @@ func (p *FLVProxy) handleStream(w http.ResponseWriter, r *http.Request) {
 	}
 
 	client := &http.Client{
-		Timeout: 0, // 流式传输，不设置超时
+		Transport: proxy.WrapDownloadTransport(http.DefaultTransport.(*http.Transport).Clone(), p.upstreamProxy),
+		Timeout:   0, // 流式传输，不设置超时
 	}
 	resp, err := client.Do(req)
 	if err != nil {
@@ -0,0 +1,256 @@
+package flvproxy
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrTooManyViewers 预览人数已达上限
+	ErrTooManyViewers = errors.New("too many preview viewers")
+	// ErrRelayClosed 录制已结束
+	ErrRelayClosed = errors.New("relay closed")
+)
+
+const (
+	// viewerQueueSize 每个观众最多积压的 tag 数，超出说明观众网络跟不上，直接断开
+	viewerQueueSize = 512
+	// maxGOPBytes 缓存的最近一个 GOP 的大小上限，超出时放弃缓存直到下一个关键帧
+	maxGOPBytes = 16 << 20
+)
+
+// Viewer 一个预览观众，从 C 中依次读取 FLV 数据写给客户端。
+// C 被关闭表示录制结束或观众读取过慢被断开
+type Viewer struct {
+	C chan []byte
+	// waiting 订阅时还没有收到 FLV 头，收到后再发送开头部分
+	waiting bool
+	// tap 内部读者（如转推），不计入预览人数
+	tap bool
+}
+
+// Relay 将录制器正在接收的 FLV 流转发给本地预览观众。
+// 通过 Write 接收原始字节流，按 tag 切分后广播；新观众加入时先收到 FLV 头、
+// 元数据、音视频序列头和最近一个 GOP，可以立即开始播放，不会再次请求 CDN。
+type Relay struct {
+	mu      sync.Mutex
+	closed  bool
+	invalid bool // 收到的不是 FLV 数据，停止解析
+
+	buf      []byte
+	header   []byte // FLV 头（9 字节）+ PreviousTagSize0
+	metadata []byte
+	videoSeq []byte
+	audioSeq []byte
+	gop      [][]byte
+	gopBytes int
+	viewers  map[*Viewer]struct{}
+}
+
+// NewRelay 创建预览转发
+func NewRelay() *Relay {
+	return &Relay{viewers: make(map[*Viewer]struct{})}
+}
+
+// Write 接收下载器读取到的 FLV 数据，不会阻塞，也不会返回错误
+func (r *Relay) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed || r.invalid {
+		return len(p), nil
+	}
+	r.buf = append(r.buf, p...)
+	r.parse()
+	return len(p), nil
+}
+
+// parse 从缓冲区中切出完整的 FLV 头和 tag，调用方需持有 r.mu
+func (r *Relay) parse() {
+	if r.header == nil {
+		if len(r.buf) < 13 {
+			return
+		}
+		if !bytes.HasPrefix(r.buf, []byte("FLV")) {
+			r.invalid = true
+			r.buf = nil
+			return
+		}
+		r.header = append([]byte(nil), r.buf[:13]...)
+		r.buf = r.buf[13:]
+		for v := range r.viewers {
+			if v.waiting {
+				v.waiting = false
+				r.send(v, r.header)
+			}
+		}
+	}
+	for len(r.buf) >= 11 {
+		dataSize := int(r.buf[1])<<16 | int(r.buf[2])<<8 | int(r.buf[3])
+		tagSize := 11 + dataSize + 4
+		if len(r.buf) < tagSize {
+			break
+		}
+		// 复制出来，r.buf 的底层数组会被后续数据覆盖
+		tag := append([]byte(nil), r.buf[:tagSize]...)
+		r.buf = r.buf[tagSize:]
+		r.handleTag(tag)
+	}
+	// 消费完后缩小缓冲区，避免底层数组无限增长
+	if len(r.buf) == 0 {
+		r.buf = nil
+	}
+}
+
+// handleTag 缓存播放器起播所需的 tag 并广播，tag 包含末尾的 PreviousTagSize
+func (r *Relay) handleTag(tag []byte) {
+	data := tag[11 : len(tag)-4]
+	switch tag[0] & 0x1f {
+	case 18: // script
+		r.metadata = tag
+	case 9: // video
+		if len(data) < 2 {
+			break
+		}
+		var keyframe, seqHeader bool
+		if data[0]&0x80 != 0 {
+			// Enhanced RTMP（HEVC/AV1）：低 4 位为 PacketType，0 为序列头
+			keyframe = (data[0]>>4)&0x07 == 1
+			seqHeader = data[0]&0x0f == 0
+		} else {
+			codecID := data[0] & 0x0f
+			keyframe = data[0]>>4 == 1
+			seqHeader = (codecID == 7 || codecID == 12) && data[1] == 0
+		}
+		switch {
+		case seqHeader:
+			r.videoSeq = tag
+		case keyframe:
+			r.gop = append(r.gop[:0], tag)
+			r.gopBytes = len(tag)
+		default:
+			r.appendGOP(tag)
+		}
+	case 8: // audio
+		if len(data) >= 2 && data[0]>>4 == 10 && data[1] == 0 {
+			r.audioSeq = tag
+		} else {
+			r.appendGOP(tag)
+		}
+	}
+	for v := range r.viewers {
+		if !v.waiting {
+			r.send(v, tag)
+		}
+	}
+}
+
+// appendGOP 在已经遇到关键帧时追加到 GOP 缓存
+func (r *Relay) appendGOP(tag []byte) {
+	if len(r.gop) == 0 {
+		return
+	}
+	if r.gopBytes+len(tag) > maxGOPBytes {
+		r.gop = nil
+		r.gopBytes = 0
+		return
+	}
+	r.gop = append(r.gop, tag)
+	r.gopBytes += len(tag)
+}
+
+// send 非阻塞地发给观众，队列已满时断开，调用方需持有 r.mu
+func (r *Relay) send(v *Viewer, data []byte) {
+	select {
+	case v.C <- data:
+	default:
+		delete(r.viewers, v)
+		close(v.C)
+	}
+}
+
+// Subscribe 加入一个观众，maxViewers 大于 0 时限制同时观看的人数
+func (r *Relay) Subscribe(maxViewers int) (*Viewer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil, ErrRelayClosed
+	}
+	if maxViewers > 0 && r.viewerCount() >= maxViewers {
+		return nil, ErrTooManyViewers
+	}
+	return r.subscribe(false), nil
+}
+
+// Tap 加入一个不计入预览人数的内部读者，如转推
+func (r *Relay) Tap() (*Viewer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil, ErrRelayClosed
+	}
+	return r.subscribe(true), nil
+}
+
+// subscribe 调用方需持有 r.mu
+func (r *Relay) subscribe(tap bool) *Viewer {
+	v := &Viewer{C: make(chan []byte, viewerQueueSize+len(r.gop)+4), tap: tap}
+	r.viewers[v] = struct{}{}
+	if r.header == nil {
+		v.waiting = true
+		return v
+	}
+	v.C <- r.header
+	for _, tag := range [][]byte{r.metadata, r.videoSeq, r.audioSeq} {
+		if tag != nil {
+			v.C <- tag
+		}
+	}
+	for _, tag := range r.gop {
+		v.C <- tag
+	}
+	return v
+}
+
+// Unsubscribe 移除观众
+func (r *Relay) Unsubscribe(v *Viewer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.viewers[v]; ok {
+		delete(r.viewers, v)
+		close(v.C)
+	}
+}
+
+// ViewerCount 当前预览人数
+func (r *Relay) ViewerCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.viewerCount()
+}
+
+func (r *Relay) viewerCount() int {
+	n := 0
+	for v := range r.viewers {
+		if !v.tap {
+			n++
+		}
+	}
+	return n
+}
+
+// Close 录制结束时断开所有观众
+func (r *Relay) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	r.closed = true
+	for v := range r.viewers {
+		close(v.C)
+	}
+	r.viewers = nil
+	r.buf = nil
+	r.gop = nil
+}
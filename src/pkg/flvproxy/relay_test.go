@@ -0,0 +1,90 @@
+package flvproxy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var flvHeader = []byte{'F', 'L', 'V', 1, 5, 0, 0, 0, 9, 0, 0, 0, 0}
+
+// makeTag 构造一个包含末尾 PreviousTagSize 的 tag
+func makeTag(tagType byte, data ...byte) []byte {
+	size := len(data)
+	tag := []byte{tagType, byte(size >> 16), byte(size >> 8), byte(size), 0, 0, 0, 0, 0, 0, 0}
+	tag = append(tag, data...)
+	total := 11 + size
+	return append(tag, byte(total>>24), byte(total>>16), byte(total>>8), byte(total))
+}
+
+func drain(v *Viewer) [][]byte {
+	var got [][]byte
+	for {
+		select {
+		case data, ok := <-v.C:
+			if !ok {
+				return got
+			}
+			got = append(got, data)
+		default:
+			return got
+		}
+	}
+}
+
+func TestRelayLateViewerStartsAtKeyframe(t *testing.T) {
+	r := NewRelay()
+	early, err := r.Subscribe(2)
+	assert.NoError(t, err)
+
+	meta := makeTag(18, 2, 0, 10)
+	videoSeq := makeTag(9, 0x17, 0, 1)
+	audioSeq := makeTag(8, 0xaf, 0, 2)
+	key1 := makeTag(9, 0x17, 1, 3)
+	inter := makeTag(9, 0x27, 1, 4)
+	audio := makeTag(8, 0xaf, 1, 5)
+	key2 := makeTag(9, 0x17, 1, 6)
+
+	var stream []byte
+	for _, b := range [][]byte{flvHeader, meta, videoSeq, audioSeq, key1, inter, audio, key2, inter} {
+		stream = append(stream, b...)
+	}
+	// 分成零散的小块写入，验证跨块切分
+	for i := 0; i < len(stream); i += 7 {
+		end := min(i+7, len(stream))
+		r.Write(stream[i:end])
+	}
+
+	// 从头开始观看的观众收到完整的流
+	assert.Equal(t, [][]byte{flvHeader, meta, videoSeq, audioSeq, key1, inter, audio, key2, inter}, drain(early))
+
+	// 中途加入的观众从最近的关键帧开始
+	late, err := r.Subscribe(2)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{flvHeader, meta, videoSeq, audioSeq, key2, inter}, drain(late))
+
+	_, err = r.Subscribe(2)
+	assert.ErrorIs(t, err, ErrTooManyViewers)
+	// 内部读者不占用预览名额
+	tap, err := r.Tap()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{flvHeader, meta, videoSeq, audioSeq, key2, inter}, drain(tap))
+	assert.Equal(t, 2, r.ViewerCount())
+	r.Unsubscribe(tap)
+	r.Unsubscribe(late)
+	assert.Equal(t, 1, r.ViewerCount())
+
+	r.Close()
+	_, ok := <-early.C
+	assert.False(t, ok)
+	_, err = r.Subscribe(2)
+	assert.ErrorIs(t, err, ErrRelayClosed)
+}
+
+func TestRelayIgnoresNonFLV(t *testing.T) {
+	r := NewRelay()
+	v, err := r.Subscribe(0)
+	assert.NoError(t, err)
+	r.Write([]byte("#EXTM3U\n#EXT-X-VERSION:3\n"))
+	assert.Empty(t, drain(v))
+}
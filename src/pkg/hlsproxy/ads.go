@@ -0,0 +1,320 @@
+package hlsproxy
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	applog "github.com/bililive-go/bililive-go/src/log"
+)
+
+// AdMode 广告分段的处理方式
+type AdMode string
+
+const (
+	// AdModeOff 不识别广告，播放列表原样透传
+	AdModeOff AdMode = "off"
+	// AdModeDrop 识别并从播放列表中剔除广告分段（默认）
+	AdModeDrop AdMode = "drop"
+	// AdModeMark 识别广告但保留分段，仅记录广告位置，方便后期剪辑
+	AdModeMark AdMode = "mark"
+)
+
+// ParseAdMode 将配置字符串解析为 AdMode，未知值回退为 AdModeDrop
+func ParseAdMode(s string) AdMode {
+	switch AdMode(strings.ToLower(strings.TrimSpace(s))) {
+	case AdModeOff:
+		return AdModeOff
+	case AdModeMark:
+		return AdModeMark
+	default:
+		return AdModeDrop
+	}
+}
+
+// AdBreak 一次广告插播的记录
+type AdBreak struct {
+	// Offset 广告在录制文件中出现的位置（秒）。
+	// 由播放列表中分段时长累加得到，是近似值：下载器从直播边缘开始拉流时，
+	// 首个播放列表中较早的分段可能并未写入文件。
+	Offset float64 `json:"offset"`
+	// Duration 广告总时长（秒）
+	Duration float64 `json:"duration"`
+	// Segments 广告分段数量
+	Segments int `json:"segments"`
+	// Removed 为 true 表示广告已从录制中剔除，false 表示仅做了标记
+	Removed bool `json:"removed"`
+	// DetectedAt 首次发现该广告分段的本地时间
+	DetectedAt time.Time `json:"detected_at"`
+	// ProgramDateTime 广告首个分段的 EXT-X-PROGRAM-DATE-TIME（如有）
+	ProgramDateTime *time.Time `json:"program_date_time,omitempty"`
+	// Reason 识别依据：daterange / cue / title
+	Reason string `json:"reason"`
+}
+
+// stitchedAdClass Twitch 等平台拼接广告时在 EXT-X-DATERANGE 中使用的 CLASS / ID 前缀
+const (
+	stitchedAdClass    = "twitch-stitched-ad"
+	stitchedAdIDPrefix = "stitched-ad-"
+)
+
+// adDateRange 播放列表中声明的广告时间窗
+type adDateRange struct {
+	start    time.Time
+	duration time.Duration
+}
+
+func (d adDateRange) contains(t time.Time) bool {
+	if d.duration <= 0 {
+		return !t.Before(d.start)
+	}
+	return !t.Before(d.start) && t.Before(d.start.Add(d.duration))
+}
+
+// segmentMeta 从分段伴随标签中提取的信息
+type segmentMeta struct {
+	duration        float64
+	title           string
+	programDateTime *time.Time
+	variant         bool // 伴随 EXT-X-STREAM-INF，说明下一行是子播放列表而非媒体分段
+}
+
+func parseSegmentMeta(tags []string) segmentMeta {
+	var meta segmentMeta
+	for _, tag := range tags {
+		switch {
+		case strings.HasPrefix(tag, "#EXTINF:"):
+			value := strings.TrimPrefix(tag, "#EXTINF:")
+			durationStr, title, _ := strings.Cut(value, ",")
+			if d, err := strconv.ParseFloat(strings.TrimSpace(durationStr), 64); err == nil {
+				meta.duration = d
+			}
+			meta.title = strings.TrimSpace(title)
+		case strings.HasPrefix(tag, "#EXT-X-PROGRAM-DATE-TIME:"):
+			if t, err := parseProgramDateTime(strings.TrimPrefix(tag, "#EXT-X-PROGRAM-DATE-TIME:")); err == nil {
+				meta.programDateTime = &t
+			}
+		case strings.HasPrefix(tag, "#EXT-X-STREAM-INF"):
+			meta.variant = true
+		}
+	}
+	return meta
+}
+
+func parseProgramDateTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	// 部分 CDN 输出的时区不带冒号，例如 2024-01-01T00:00:00.000+0000
+	return time.Parse("2006-01-02T15:04:05.000Z0700", value)
+}
+
+// parseAdDateRanges 收集播放列表中所有广告类型的 EXT-X-DATERANGE。
+// DATERANGE 标签可能出现在它所覆盖的分段之前或之后，因此需要先整体扫描一遍。
+func parseAdDateRanges(lines []string) []adDateRange {
+	var ranges []adDateRange
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "#EXT-X-DATERANGE:") {
+			continue
+		}
+		attrs := parseAttributeList(strings.TrimPrefix(line, "#EXT-X-DATERANGE:"))
+		if attrs["CLASS"] != stitchedAdClass && !strings.HasPrefix(attrs["ID"], stitchedAdIDPrefix) {
+			continue
+		}
+		start, err := parseProgramDateTime(attrs["START-DATE"])
+		if err != nil {
+			continue
+		}
+		r := adDateRange{start: start}
+		durationStr := attrs["DURATION"]
+		if durationStr == "" {
+			durationStr = attrs["PLANNED-DURATION"]
+		}
+		if d, err := strconv.ParseFloat(durationStr, 64); err == nil {
+			r.duration = time.Duration(d * float64(time.Second))
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+// parseAttributeList 解析 m3u8 属性列表（KEY=VALUE,KEY="VALUE"），值中的逗号需在引号内。
+func parseAttributeList(s string) map[string]string {
+	attrs := make(map[string]string)
+	for len(s) > 0 {
+		key, rest, ok := strings.Cut(s, "=")
+		if !ok {
+			break
+		}
+		key = strings.TrimSpace(key)
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				value = rest[1:]
+				rest = ""
+			} else {
+				value = rest[1 : end+1]
+				rest = rest[end+2:]
+			}
+			rest = strings.TrimPrefix(rest, ",")
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		attrs[key] = value
+		s = rest
+	}
+	return attrs
+}
+
+// classifyAdSegment 判断分段是否为广告，并返回识别依据
+func classifyAdSegment(meta segmentMeta, inCue bool, dateRanges []adDateRange) (bool, string) {
+	if meta.programDateTime != nil {
+		for _, r := range dateRanges {
+			if r.contains(*meta.programDateTime) {
+				return true, "daterange"
+			}
+		}
+	}
+	if inCue {
+		return true, "cue"
+	}
+	if isAdTitle(meta.title) {
+		return true, "title"
+	}
+	return false, ""
+}
+
+// isAdTitle 判断 EXTINF 标题是否为广告。Twitch 直播内容的标题固定为 "live"，
+// 而拼接广告的标题以广告来源命名（例如 "Amazon|..."）。
+func isAdTitle(title string) bool {
+	return strings.Contains(title, "Amazon")
+}
+
+// trackedSegment 已经出现过的分段
+type trackedSegment struct {
+	seq int64 // 剔除广告后重新分配的本地媒体序号
+	ad  bool
+}
+
+// playlistState 单个媒体播放列表的跟踪状态。
+// 下载器会周期性刷新播放列表，同一分段会多次出现，需要按 URL 去重。
+type playlistState struct {
+	segments      map[string]trackedSegment
+	nextSeq       int64
+	contentOffset float64 // 已写入录制的内容时长（秒）
+	observed      int     // 累计观察到的新分段数
+	breaks        []AdBreak
+	openBreak     *AdBreak
+}
+
+// adTracker 在多次播放列表刷新之间识别广告并记录广告插播位置
+type adTracker struct {
+	mode AdMode
+
+	mu        sync.Mutex
+	playlists map[string]*playlistState
+}
+
+func newAdTracker(mode AdMode) *adTracker {
+	return &adTracker{
+		mode:      mode,
+		playlists: make(map[string]*playlistState),
+	}
+}
+
+// playlistKey 播放列表的跟踪键。忽略查询参数，避免签名刷新后被当作新的播放列表。
+func playlistKey(u *url.URL) string {
+	return u.Scheme + "://" + u.Host + u.Path
+}
+
+// state 获取播放列表的跟踪状态，调用方需持有 t.mu
+func (t *adTracker) state(key string) *playlistState {
+	st, ok := t.playlists[key]
+	if !ok {
+		st = &playlistState{segments: make(map[string]trackedSegment)}
+		t.playlists[key] = st
+	}
+	return st
+}
+
+// observe 记录一个分段，返回其跟踪信息。
+// 已出现过的分段沿用首次的判定结果，保证同一分段在多次刷新中处理一致。
+func (t *adTracker) observe(st *playlistState, segmentURL string, meta segmentMeta, ad bool, reason string) trackedSegment {
+	if seg, ok := st.segments[segmentURL]; ok {
+		return seg
+	}
+
+	seg := trackedSegment{ad: ad, seq: -1}
+	if !ad || t.mode != AdModeDrop {
+		seg.seq = st.nextSeq
+		st.nextSeq++
+	}
+	st.segments[segmentURL] = seg
+	st.observed++
+
+	if ad {
+		if st.openBreak == nil {
+			st.openBreak = &AdBreak{
+				Offset:          st.contentOffset,
+				Removed:         t.mode == AdModeDrop,
+				DetectedAt:      time.Now(),
+				ProgramDateTime: meta.programDateTime,
+				Reason:          reason,
+			}
+			applog.GetLogger().Infof("检测到 HLS 广告插播: offset=%.1fs reason=%s mode=%s", st.contentOffset, reason, t.mode)
+		}
+		st.openBreak.Duration += meta.duration
+		st.openBreak.Segments++
+		if t.mode != AdModeDrop {
+			st.contentOffset += meta.duration
+		}
+		return seg
+	}
+
+	if st.openBreak != nil {
+		applog.GetLogger().Infof("HLS 广告插播结束: offset=%.1fs duration=%.1fs segments=%d",
+			st.openBreak.Offset, st.openBreak.Duration, st.openBreak.Segments)
+		st.breaks = append(st.breaks, *st.openBreak)
+		st.openBreak = nil
+	}
+	st.contentOffset += meta.duration
+	return seg
+}
+
+// prune 丢弃已经滚出播放列表窗口的分段，避免长时间录制时内存持续增长
+func (st *playlistState) prune(current map[string]struct{}) {
+	for u := range st.segments {
+		if _, ok := current[u]; !ok {
+			delete(st.segments, u)
+		}
+	}
+}
+
+// AdBreaks 返回主播放列表中记录到的广告插播。
+// 下载器在读取主列表时可能短暂打开多个子播放列表，只有持续刷新的那一个
+// （观察到分段最多）才对应真正写入录制文件的内容。
+func (t *adTracker) AdBreaks() []AdBreak {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var primary *playlistState
+	for _, st := range t.playlists {
+		if primary == nil || st.observed > primary.observed {
+			primary = st
+		}
+	}
+	if primary == nil {
+		return nil
+	}
+	breaks := make([]AdBreak, 0, len(primary.breaks)+1)
+	breaks = append(breaks, primary.breaks...)
+	if primary.openBreak != nil {
+		breaks = append(breaks, *primary.openBreak)
+	}
+	return breaks
+}
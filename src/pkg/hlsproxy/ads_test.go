@@ -0,0 +1,169 @@
+package hlsproxy
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const stitchedAdPlaylist = `#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-TARGETDURATION:2
+#EXT-X-MEDIA-SEQUENCE:100
+#EXT-X-DATERANGE:ID="stitched-ad-1",CLASS="twitch-stitched-ad",START-DATE="2024-01-01T00:00:04.000Z",DURATION=4.000
+#EXT-X-PROGRAM-DATE-TIME:2024-01-01T00:00:00.000Z
+#EXTINF:2.000,live
+content1.ts
+#EXT-X-PROGRAM-DATE-TIME:2024-01-01T00:00:02.000Z
+#EXTINF:2.000,live
+content2.ts
+#EXT-X-DISCONTINUITY
+#EXT-X-PROGRAM-DATE-TIME:2024-01-01T00:00:04.000Z
+#EXTINF:2.000,
+ad1.ts
+#EXT-X-PROGRAM-DATE-TIME:2024-01-01T00:00:06.000Z
+#EXTINF:2.000,
+ad2.ts
+#EXT-X-DISCONTINUITY
+#EXT-X-PROGRAM-DATE-TIME:2024-01-01T00:00:08.000Z
+#EXTINF:2.000,live
+content3.ts
+`
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	assert.NoError(t, err)
+	return u
+}
+
+func TestRewritePlaylistDropsStitchedAds(t *testing.T) {
+	upstreamURL := mustParseURL(t, "https://video.example.com/v1/playlist/abc.m3u8")
+	localURL := mustParseURL(t, "http://127.0.0.1:18080/stream.m3u8")
+	tracker := newAdTracker(AdModeDrop)
+
+	rewritten, err := rewritePlaylistWithAds(stitchedAdPlaylist, upstreamURL, localURL, false, tracker)
+	assert.NoError(t, err)
+	assert.NotContains(t, rewritten, "ad1.ts")
+	assert.NotContains(t, rewritten, "ad2.ts")
+	assert.Contains(t, rewritten, "content3.ts")
+	// 进入广告的 DISCONTINUITY 随广告分段丢弃，结束处的保留
+	assert.Equal(t, 1, strings.Count(rewritten, "#EXT-X-DISCONTINUITY"))
+	assert.Contains(t, rewritten, "#EXT-X-MEDIA-SEQUENCE:0\n")
+
+	breaks := tracker.AdBreaks()
+	if assert.Len(t, breaks, 1) {
+		assert.Equal(t, 4.0, breaks[0].Offset)
+		assert.Equal(t, 4.0, breaks[0].Duration)
+		assert.Equal(t, 2, breaks[0].Segments)
+		assert.True(t, breaks[0].Removed)
+		assert.Equal(t, "daterange", breaks[0].Reason)
+	}
+}
+
+func TestRewritePlaylistKeepsMediaSequenceConsistentAcrossRefreshes(t *testing.T) {
+	upstreamURL := mustParseURL(t, "https://video.example.com/v1/playlist/abc.m3u8")
+	localURL := mustParseURL(t, "http://127.0.0.1:18080/stream.m3u8")
+	tracker := newAdTracker(AdModeDrop)
+
+	_, err := rewritePlaylistWithAds(stitchedAdPlaylist, upstreamURL, localURL, false, tracker)
+	assert.NoError(t, err)
+
+	// 窗口滑动：content1、content2 滚出，新增 content4
+	refreshed := `#EXTM3U
+#EXT-X-MEDIA-SEQUENCE:102
+#EXT-X-DATERANGE:ID="stitched-ad-1",CLASS="twitch-stitched-ad",START-DATE="2024-01-01T00:00:04.000Z",DURATION=4.000
+#EXT-X-PROGRAM-DATE-TIME:2024-01-01T00:00:04.000Z
+#EXTINF:2.000,
+ad1.ts
+#EXT-X-PROGRAM-DATE-TIME:2024-01-01T00:00:06.000Z
+#EXTINF:2.000,
+ad2.ts
+#EXT-X-DISCONTINUITY
+#EXT-X-PROGRAM-DATE-TIME:2024-01-01T00:00:08.000Z
+#EXTINF:2.000,live
+content3.ts
+#EXT-X-PROGRAM-DATE-TIME:2024-01-01T00:00:10.000Z
+#EXTINF:2.000,live
+content4.ts
+`
+	rewritten, err := rewritePlaylistWithAds(refreshed, upstreamURL, localURL, false, tracker)
+	assert.NoError(t, err)
+	// content3 首次出现时被分配为本地序号 2
+	assert.Contains(t, rewritten, "#EXT-X-MEDIA-SEQUENCE:2\n")
+	assert.Contains(t, rewritten, "content4.ts")
+	assert.Len(t, tracker.AdBreaks(), 1)
+}
+
+func TestRewritePlaylistMarksAdsWithoutDropping(t *testing.T) {
+	upstreamURL := mustParseURL(t, "https://video.example.com/v1/playlist/abc.m3u8")
+	localURL := mustParseURL(t, "http://127.0.0.1:18080/stream.m3u8")
+	tracker := newAdTracker(AdModeMark)
+
+	rewritten, err := rewritePlaylistWithAds(stitchedAdPlaylist, upstreamURL, localURL, false, tracker)
+	assert.NoError(t, err)
+	assert.Contains(t, rewritten, "ad1.ts")
+	assert.Contains(t, rewritten, "#EXT-X-MEDIA-SEQUENCE:100\n")
+
+	breaks := tracker.AdBreaks()
+	if assert.Len(t, breaks, 1) {
+		assert.False(t, breaks[0].Removed)
+		assert.Equal(t, 4.0, breaks[0].Offset)
+	}
+}
+
+func TestRewritePlaylistDetectsCueAndTitleAds(t *testing.T) {
+	upstreamURL := mustParseURL(t, "https://video.example.com/v1/playlist/abc.m3u8")
+	localURL := mustParseURL(t, "http://127.0.0.1:18080/stream.m3u8")
+	tracker := newAdTracker(AdModeDrop)
+
+	content := `#EXTM3U
+#EXTINF:2.000,live
+content1.ts
+#EXT-X-CUE-OUT:DURATION=2
+#EXTINF:2.000,live
+cue1.ts
+#EXT-X-CUE-IN
+#EXTINF:2.000,live
+content2.ts
+#EXTINF:2.000,Amazon|123456
+amazon1.ts
+#EXTINF:2.000,live
+content3.ts
+`
+	rewritten, err := rewritePlaylistWithAds(content, upstreamURL, localURL, false, tracker)
+	assert.NoError(t, err)
+	assert.NotContains(t, rewritten, "cue1.ts")
+	assert.NotContains(t, rewritten, "amazon1.ts")
+
+	breaks := tracker.AdBreaks()
+	if assert.Len(t, breaks, 2) {
+		assert.Equal(t, "cue", breaks[0].Reason)
+		assert.Equal(t, 2.0, breaks[0].Offset)
+		assert.Equal(t, "title", breaks[1].Reason)
+		assert.Equal(t, 4.0, breaks[1].Offset)
+	}
+}
+
+func TestRewritePlaylistDoesNotTrackVariantPlaylists(t *testing.T) {
+	upstreamURL := mustParseURL(t, "https://usher.example.com/api/channel/hls/abc.m3u8")
+	localURL := mustParseURL(t, "http://127.0.0.1:18080/stream.m3u8")
+	tracker := newAdTracker(AdModeDrop)
+
+	content := `#EXTM3U
+#EXT-X-STREAM-INF:BANDWIDTH=6000000,RESOLUTION=1920x1080
+https://video.example.com/v1/playlist/source.m3u8
+`
+	rewritten, err := rewritePlaylistWithAds(content, upstreamURL, localURL, false, tracker)
+	assert.NoError(t, err)
+	assert.Contains(t, rewritten, "source.m3u8")
+	assert.Empty(t, tracker.AdBreaks())
+}
+
+func TestParseAdMode(t *testing.T) {
+	assert.Equal(t, AdModeDrop, ParseAdMode(""))
+	assert.Equal(t, AdModeMark, ParseAdMode("Mark"))
+	assert.Equal(t, AdModeOff, ParseAdMode("off"))
+}
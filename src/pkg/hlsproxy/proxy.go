@@ -23,15 +23,37 @@ type Proxy struct {
 	upstreamURL      *url.URL
 	headers          map[string]string
 	filterPreloading bool
+	ads              *adTracker
+	proxyURL         string
+	preview          *Relay
 	client           *http.Client
 	clientOnce       sync.Once
 }
 
+// Options HLS 代理的可选行为
+type Options struct {
+	// FilterPreloading 过滤名称中包含 preloading 的分段（Soop）
+	FilterPreloading bool
+	// AdMode 拼接广告的处理方式，空值等同于 AdModeOff
+	AdMode AdMode
+	// ProxyURL 访问上游使用的代理（按直播间解析，为空时使用全局下载代理）
+	ProxyURL string
+	// Preview 不为 nil 时，把转发给下载器的媒体分段同步交给本地预览
+	Preview *Relay
+}
+
 // New 创建一个 HLS 本地代理。
 // 当前主要用于 Soop 的 m3u8 兼容处理：
 // - 重写媒体分段 URL 到本地代理；
 // - 可选过滤名称中包含 preloading 的分段。
 func New(upstreamURL *url.URL, headers map[string]string, filterPreloading bool) (*Proxy, error) {
+	return NewWithOptions(upstreamURL, headers, Options{FilterPreloading: filterPreloading})
+}
+
+// NewWithOptions 按选项创建 HLS 本地代理。
+// AdMode 不为 off 时，代理会识别 Twitch 等平台拼接进直播流的广告分段，
+// 按模式剔除或仅标记，并通过 AdBreaks 提供广告位置供写入录制元数据。
+func NewWithOptions(upstreamURL *url.URL, headers map[string]string, opts Options) (*Proxy, error) {
 	listener, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
 		return nil, fmt.Errorf("failed to create hls proxy listener: %w", err)
@@ -48,12 +70,25 @@ func New(upstreamURL *url.URL, headers map[string]string, filterPreloading bool)
 		localURL:         localURL,
 		upstreamURL:      upstreamURL,
 		headers:          headers,
-		filterPreloading: filterPreloading,
+		filterPreloading: opts.FilterPreloading,
+		proxyURL:         opts.ProxyURL,
+		preview:          opts.Preview,
 	}
-	applog.GetLogger().Debugf("HLS 代理已创建: upstream=%s local=%s filterPreloading=%v", upstreamURL.String(), localURL.String(), filterPreloading)
+	if opts.AdMode != "" && opts.AdMode != AdModeOff {
+		proxy.ads = newAdTracker(opts.AdMode)
+	}
+	applog.GetLogger().Debugf("HLS 代理已创建: upstream=%s local=%s filterPreloading=%v adMode=%s", upstreamURL.String(), localURL.String(), opts.FilterPreloading, opts.AdMode)
 	return proxy, nil
 }
 
+// AdBreaks 返回本次代理期间识别到的广告插播，未开启广告识别时返回 nil。
+func (p *Proxy) AdBreaks() []AdBreak {
+	if p.ads == nil {
+		return nil
+	}
+	return p.ads.AdBreaks()
+}
+
 // LocalURL 返回本地代理入口地址，供下载器直接消费。
 func (p *Proxy) LocalURL() *url.URL {
 	return p.localURL
@@ -138,7 +173,7 @@ func (p *Proxy) serveTargetPlaylist(w http.ResponseWriter, r *http.Request, targ
 	}
 
 	content := string(body)
-	rewritten, err := rewritePlaylist(content, targetURL, p.localURL, p.filterPreloading)
+	rewritten, err := rewritePlaylistWithAds(content, targetURL, p.localURL, p.filterPreloading, p.ads)
 	if err != nil {
 		http.Error(w, "重写 HLS 播放列表失败", http.StatusInternalServerError)
 		return
@@ -163,9 +198,40 @@ func (p *Proxy) proxyRaw(w http.ResponseWriter, r *http.Request, targetURL *url.
 
 	copyResponseHeaders(w.Header(), resp.Header)
 	w.WriteHeader(resp.StatusCode)
-	if _, err := io.Copy(w, resp.Body); err != nil && r.Context().Err() == nil {
-		applog.GetLogger().Warnf("HLS 原始资源透传失败: target=%s err=%v", targetURL.String(), err)
+	var dst io.Writer = w
+	var segment *segmentBuffer
+	if p.preview != nil && resp.StatusCode == http.StatusOK {
+		segment = &segmentBuffer{}
+		dst = io.MultiWriter(w, segment)
+	}
+	if _, err := io.Copy(dst, resp.Body); err != nil {
+		if r.Context().Err() == nil {
+			applog.GetLogger().Warnf("HLS 原始资源透传失败: target=%s err=%v", targetURL.String(), err)
+		}
+		return
+	}
+	// 只转发完整下载的分段
+	if segment != nil && !segment.overflow {
+		p.preview.WriteSegment(segment.data)
+	}
+}
+
+// segmentBuffer 缓存转发中的分段，超过 maxSegmentBytes 后放弃缓存但不影响转发
+type segmentBuffer struct {
+	data     []byte
+	overflow bool
+}
+
+func (b *segmentBuffer) Write(p []byte) (int, error) {
+	if !b.overflow {
+		if len(b.data)+len(p) > maxSegmentBytes {
+			b.overflow = true
+			b.data = nil
+		} else {
+			b.data = append(b.data, p...)
+		}
 	}
+	return len(p), nil
 }
 
 // fetchTarget 透传请求到上游 m3u8 / ts / m4s / init 段。
@@ -204,7 +270,11 @@ func (p *Proxy) doUpstreamRequest(ctx context.Context, targetURL *url.URL) (*htt
 
 func (p *Proxy) getHTTPClient() *http.Client {
 	p.clientOnce.Do(func() {
-		p.client = utils.CreateDownloadClient()
+		if p.proxyURL != "" {
+			p.client = utils.CreateDownloadClientWithProxy(p.proxyURL)
+		} else {
+			p.client = utils.CreateDownloadClient()
+		}
 	})
 	return p.client
 }
@@ -223,10 +293,40 @@ func copyResponseHeaders(dst, src http.Header) {
 // 2. 把 EXT-X-MAP 中的 URI 也重写到本地代理；
 // 3. 在需要时过滤掉 Soop 的 preloading 分片，并同步丢弃其前置标签。
 func rewritePlaylist(content string, upstreamURL, localBaseURL *url.URL, filterPreloading bool) (string, error) {
+	return rewritePlaylistWithAds(content, upstreamURL, localBaseURL, filterPreloading, nil)
+}
+
+// rewritePlaylistWithAds 在 rewritePlaylist 的基础上识别拼接广告。
+// 广告分段的判定依据（任一满足即可）：
+// - 分段的 PROGRAM-DATE-TIME 落在广告类 EXT-X-DATERANGE 的时间窗内；
+// - 分段位于 EXT-X-CUE-OUT 与 EXT-X-CUE-IN 之间；
+// - EXTINF 标题为广告来源。
+// drop 模式下广告分段连同其前置标签（包括进入广告时的 EXT-X-DISCONTINUITY）一起丢弃，
+// 广告结束处的 DISCONTINUITY 保留，用于提示下载器时间戳发生跳变；
+// 同时按剔除后的分段重新编号 EXT-X-MEDIA-SEQUENCE，避免下载器按序号追踪分段时错位。
+func rewritePlaylistWithAds(content string, upstreamURL, localBaseURL *url.URL, filterPreloading bool, ads *adTracker) (string, error) {
 	lines := strings.Split(content, "\n")
 	output := make([]string, 0, len(lines))
 	pending := make([]string, 0, 4)
 	filteredSegments := 0
+	droppedAds := 0
+
+	trackAds := ads != nil && ads.mode != AdModeOff
+	var (
+		st           *playlistState
+		dateRanges   []adDateRange
+		current      map[string]struct{}
+		inCue        bool
+		mediaSeqLine       = -1
+		firstSeq     int64 = -1
+	)
+	if trackAds {
+		ads.mu.Lock()
+		defer ads.mu.Unlock()
+		st = ads.state(playlistKey(upstreamURL))
+		dateRanges = parseAdDateRanges(lines)
+		current = make(map[string]struct{})
+	}
 
 	flushPending := func() {
 		if len(pending) == 0 {
@@ -243,11 +343,22 @@ func rewritePlaylist(content string, upstreamURL, localBaseURL *url.URL, filterP
 		}
 
 		if strings.HasPrefix(trimmed, "#") {
+			if trackAds {
+				switch {
+				case strings.HasPrefix(trimmed, "#EXT-X-CUE-OUT"):
+					inCue = true
+				case strings.HasPrefix(trimmed, "#EXT-X-CUE-IN"):
+					inCue = false
+				}
+			}
 			rewrittenTag := rewriteTagURI(trimmed, upstreamURL, localBaseURL)
 			if isURIRelatedTag(trimmed) {
 				pending = append(pending, rewrittenTag)
 			} else {
 				flushPending()
+				if trackAds && ads.mode == AdModeDrop && strings.HasPrefix(trimmed, "#EXT-X-MEDIA-SEQUENCE:") {
+					mediaSeqLine = len(output)
+				}
 				output = append(output, rewrittenTag)
 			}
 			continue
@@ -264,12 +375,41 @@ func rewritePlaylist(content string, upstreamURL, localBaseURL *url.URL, filterP
 			continue
 		}
 
+		if trackAds {
+			meta := parseSegmentMeta(pending)
+			if !meta.variant && !looksLikePlaylist(absURL.String()) {
+				isAd, reason := classifyAdSegment(meta, inCue, dateRanges)
+				current[absURL.String()] = struct{}{}
+				seg := ads.observe(st, absURL.String(), meta, isAd, reason)
+				if seg.ad && ads.mode == AdModeDrop {
+					pending = pending[:0]
+					droppedAds++
+					continue
+				}
+				if firstSeq < 0 {
+					firstSeq = seg.seq
+				}
+			}
+		}
+
 		flushPending()
 		output = append(output, buildLocalMediaURL(localBaseURL, absURL.String()))
 	}
 
 	flushPending()
-	applog.GetLogger().Debugf("HLS 播放列表重写完成: upstream=%s filteredPreloading=%d outputLines=%d", upstreamURL.String(), filteredSegments, len(output))
+	if trackAds {
+		if len(current) > 0 {
+			st.prune(current)
+		}
+		if mediaSeqLine >= 0 {
+			if firstSeq < 0 {
+				// 当前窗口全是广告：序号指向下一个内容分段，下载器会继续等待
+				firstSeq = st.nextSeq
+			}
+			output[mediaSeqLine] = fmt.Sprintf("#EXT-X-MEDIA-SEQUENCE:%d", firstSeq)
+		}
+	}
+	applog.GetLogger().Debugf("HLS 播放列表重写完成: upstream=%s filteredPreloading=%d droppedAds=%d outputLines=%d", upstreamURL.String(), filteredSegments, droppedAds, len(output))
 	return strings.Join(output, "\n") + "\n", nil
 }
 
@@ -82,6 +82,27 @@ func TestProxyRawStreamsUpstreamResponse(t *testing.T) {
 	assert.Equal(t, "segment-data", recorder.Body.String())
 }
 
+func TestProxyRawTeesSegmentsToPreview(t *testing.T) {
+	segment := makeSegment(3, 1)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(segment)
+	}))
+	defer upstream.Close()
+
+	targetURL, err := url.Parse(upstream.URL)
+	assert.NoError(t, err)
+	relay := NewRelay()
+	proxy := &Proxy{upstreamURL: targetURL, preview: relay}
+	viewer, err := relay.Subscribe(0)
+	assert.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	proxy.proxyRaw(recorder, httptest.NewRequest(http.MethodGet, "http://127.0.0.1/media", nil), targetURL)
+
+	assert.Equal(t, segment, recorder.Body.Bytes())
+	assert.Equal(t, segment, <-viewer.C)
+}
+
 func TestProxyReusesDownloadClient(t *testing.T) {
 	proxy := &Proxy{}
 
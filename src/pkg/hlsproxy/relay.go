@@ -0,0 +1,135 @@
+package hlsproxy
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrTooManyViewers 预览人数已达上限
+	ErrTooManyViewers = errors.New("too many preview viewers")
+	// ErrRelayClosed 录制已结束
+	ErrRelayClosed = errors.New("relay closed")
+	// ErrUnsupportedSegment 分段不是 MPEG-TS（如 fMP4），无法拼接成连续的流
+	ErrUnsupportedSegment = errors.New("unsupported hls segment format")
+)
+
+const (
+	// viewerQueueSize 每个观众最多积压的分段数，超出说明观众网络跟不上，直接断开
+	viewerQueueSize = 8
+	// maxSegmentBytes 单个分段的大小上限，超出的分段不转发
+	maxSegmentBytes = 32 << 20
+	// tsPacketSize MPEG-TS 包大小
+	tsPacketSize = 188
+)
+
+// Viewer 一个预览观众，从 C 中依次读取 MPEG-TS 分段写给客户端。
+// C 被关闭表示录制结束或观众读取过慢被断开
+type Viewer struct {
+	C chan []byte
+}
+
+// Relay 将 HLS 代理转发给下载器的媒体分段拼接成连续的 MPEG-TS 流，供本地预览。
+// 新观众先收到最近一个完整分段，分段通常以关键帧开始，可以立即开始播放，不会再次请求 CDN。
+type Relay struct {
+	mu          sync.Mutex
+	closed      bool
+	unsupported bool
+
+	last    []byte
+	viewers map[*Viewer]struct{}
+}
+
+// NewRelay 创建预览转发
+func NewRelay() *Relay {
+	return &Relay{viewers: make(map[*Viewer]struct{})}
+}
+
+// WriteSegment 接收一个下载完成的媒体分段并广播。
+// 收到非 MPEG-TS 分段时停止转发并断开所有观众
+func (r *Relay) WriteSegment(data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed || r.unsupported || len(data) == 0 || len(data) > maxSegmentBytes {
+		return
+	}
+	if !isTS(data) {
+		r.unsupported = true
+		r.last = nil
+		for v := range r.viewers {
+			close(v.C)
+		}
+		r.viewers = nil
+		return
+	}
+	r.last = data
+	for v := range r.viewers {
+		select {
+		case v.C <- data:
+		default:
+			delete(r.viewers, v)
+			close(v.C)
+		}
+	}
+}
+
+// isTS 检查数据是否以 MPEG-TS 同步字节开始
+func isTS(data []byte) bool {
+	if data[0] != 0x47 {
+		return false
+	}
+	return len(data) <= tsPacketSize || data[tsPacketSize] == 0x47
+}
+
+// Subscribe 加入一个观众，maxViewers 大于 0 时限制同时观看的人数
+func (r *Relay) Subscribe(maxViewers int) (*Viewer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil, ErrRelayClosed
+	}
+	if r.unsupported {
+		return nil, ErrUnsupportedSegment
+	}
+	if maxViewers > 0 && len(r.viewers) >= maxViewers {
+		return nil, ErrTooManyViewers
+	}
+	v := &Viewer{C: make(chan []byte, viewerQueueSize+1)}
+	if r.last != nil {
+		v.C <- r.last
+	}
+	r.viewers[v] = struct{}{}
+	return v, nil
+}
+
+// Unsubscribe 移除观众
+func (r *Relay) Unsubscribe(v *Viewer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.viewers[v]; ok {
+		delete(r.viewers, v)
+		close(v.C)
+	}
+}
+
+// ViewerCount 当前预览人数
+func (r *Relay) ViewerCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.viewers)
+}
+
+// Close 录制结束时断开所有观众
+func (r *Relay) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	r.closed = true
+	for v := range r.viewers {
+		close(v.C)
+	}
+	r.viewers = nil
+	r.last = nil
+}
@@ -0,0 +1,70 @@
+package hlsproxy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// makeSegment 构造由 n 个 MPEG-TS 包组成的分段
+func makeSegment(n int, fill byte) []byte {
+	seg := make([]byte, n*tsPacketSize)
+	for i := 0; i < n; i++ {
+		seg[i*tsPacketSize] = 0x47
+		seg[i*tsPacketSize+1] = fill
+	}
+	return seg
+}
+
+func TestRelayLateViewerStartsAtLastSegment(t *testing.T) {
+	r := NewRelay()
+	early, err := r.Subscribe(2)
+	assert.NoError(t, err)
+
+	seg1, seg2 := makeSegment(2, 1), makeSegment(2, 2)
+	r.WriteSegment(seg1)
+	r.WriteSegment(seg2)
+	assert.Equal(t, seg1, <-early.C)
+	assert.Equal(t, seg2, <-early.C)
+
+	late, err := r.Subscribe(2)
+	assert.NoError(t, err)
+	assert.Equal(t, seg2, <-late.C)
+
+	_, err = r.Subscribe(2)
+	assert.ErrorIs(t, err, ErrTooManyViewers)
+
+	r.Close()
+	_, ok := <-early.C
+	assert.False(t, ok)
+	_, err = r.Subscribe(0)
+	assert.ErrorIs(t, err, ErrRelayClosed)
+}
+
+func TestRelayRejectsNonTSSegments(t *testing.T) {
+	r := NewRelay()
+	v, err := r.Subscribe(0)
+	assert.NoError(t, err)
+
+	// fMP4 初始化分段
+	r.WriteSegment([]byte("\x00\x00\x00\x18ftypiso6"))
+	_, ok := <-v.C
+	assert.False(t, ok)
+	_, err = r.Subscribe(0)
+	assert.ErrorIs(t, err, ErrUnsupportedSegment)
+}
+
+func TestRelayDropsSlowViewer(t *testing.T) {
+	r := NewRelay()
+	v, err := r.Subscribe(0)
+	assert.NoError(t, err)
+	for i := 0; i <= viewerQueueSize+1; i++ {
+		r.WriteSegment(makeSegment(1, byte(i)))
+	}
+	assert.Equal(t, 0, r.ViewerCount())
+	n := 0
+	for range v.C {
+		n++
+	}
+	assert.Equal(t, viewerQueueSize+1, n)
+}
@@ -206,6 +206,8 @@ const (
 	NamespaceUpdate = "update"
 	// NamespaceMigration 数据库迁移状态
 	NamespaceMigration = "migration"
+	// NamespaceBilibiliAuth 哔哩哔哩登录凭据（按 DedeUserID 保存 refresh_token）
+	NamespaceBilibiliAuth = "bilibili_auth"
 )
 
 // 预定义的键常量
@@ -0,0 +1,292 @@
+// Package mqtt 实现发布事件所需的最小 MQTT 3.1.1 客户端：
+// 只支持 QoS 0 发布、遗嘱消息与心跳，不支持订阅。
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// 控制报文类型（固定报头高 4 位）
+const (
+	packetConnect    byte = 0x10
+	packetConnack    byte = 0x20
+	packetPublish    byte = 0x30
+	packetPingreq    byte = 0xC0
+	packetPingresp   byte = 0xD0
+	packetDisconnect byte = 0xE0
+)
+
+// Options 连接参数
+type Options struct {
+	// Broker 服务器地址：tcp://host:1883、ssl://host:8883（也接受 mqtt://、mqtts://、tls://）
+	Broker    string
+	ClientID  string
+	Username  string
+	Password  string
+	KeepAlive time.Duration // 心跳间隔，默认 60 秒
+	// Will 遗嘱消息，连接异常断开时由服务器发布
+	WillTopic   string
+	WillPayload []byte
+	WillRetain  bool
+}
+
+// Client MQTT 连接。Publish 可并发调用；连接断开后需重新 Dial
+type Client struct {
+	conn      net.Conn
+	reader    *bufio.Reader
+	writeMu   sync.Mutex
+	keepAlive time.Duration
+	done      chan struct{}
+	closeOnce sync.Once
+	err       error
+}
+
+// Dial 连接服务器并完成 CONNECT 握手
+func Dial(ctx context.Context, opts Options) (*Client, error) {
+	u, err := url.Parse(opts.Broker)
+	if err != nil {
+		return nil, fmt.Errorf("无效的 MQTT 地址: %w", err)
+	}
+	useTLS := false
+	defaultPort := "1883"
+	switch u.Scheme {
+	case "tcp", "mqtt", "":
+	case "ssl", "tls", "mqtts":
+		useTLS = true
+		defaultPort = "8883"
+	default:
+		return nil, fmt.Errorf("不支持的 MQTT 协议: %s", u.Scheme)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), defaultPort)
+	}
+
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	var conn net.Conn
+	if useTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: u.Hostname()}}).DialContext(ctx, "tcp", host)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", host)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	keepAlive := opts.KeepAlive
+	if keepAlive <= 0 {
+		keepAlive = 60 * time.Second
+	}
+	c := &Client{conn: conn, reader: bufio.NewReader(conn), keepAlive: keepAlive, done: make(chan struct{})}
+	if err := c.handshake(opts); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	go c.readLoop()
+	go c.pingLoop()
+	return c, nil
+}
+
+func (c *Client) handshake(opts Options) error {
+	var flags byte = 0x02 // clean session
+	payload := appendString(nil, opts.ClientID)
+	if opts.WillTopic != "" {
+		flags |= 0x04
+		if opts.WillRetain {
+			flags |= 0x20
+		}
+		payload = appendString(payload, opts.WillTopic)
+		payload = appendBytes(payload, opts.WillPayload)
+	}
+	if opts.Username != "" {
+		flags |= 0x80
+		payload = appendString(payload, opts.Username)
+		if opts.Password != "" {
+			flags |= 0x40
+			payload = appendString(payload, opts.Password)
+		}
+	}
+	body := appendString(nil, "MQTT")
+	body = append(body, 4, flags, byte(c.keepAlive/time.Second>>8), byte(c.keepAlive/time.Second))
+	body = append(body, payload...)
+
+	c.conn.SetDeadline(time.Now().Add(10 * time.Second))
+	defer c.conn.SetDeadline(time.Time{})
+	if err := c.writePacket(packetConnect, body); err != nil {
+		return err
+	}
+	typ, resp, err := readPacket(c.reader)
+	if err != nil {
+		return err
+	}
+	if typ&0xF0 != packetConnack || len(resp) < 2 {
+		return errors.New("MQTT 服务器未返回 CONNACK")
+	}
+	if code := resp[1]; code != 0 {
+		return fmt.Errorf("MQTT 连接被拒绝: %s", connackReason(code))
+	}
+	return nil
+}
+
+// Publish 以 QoS 0 发布消息
+func (c *Client) Publish(topic string, payload []byte, retain bool) error {
+	select {
+	case <-c.done:
+		return c.Err()
+	default:
+	}
+	header := packetPublish
+	if retain {
+		header |= 0x01
+	}
+	body := appendString(nil, topic)
+	body = append(body, payload...)
+	return c.writePacket(header, body)
+}
+
+// Done 连接断开时关闭
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err 返回连接断开的原因
+func (c *Client) Err() error {
+	select {
+	case <-c.done:
+		return c.err
+	default:
+		return nil
+	}
+}
+
+// Close 发送 DISCONNECT 并关闭连接（正常断开不会触发遗嘱消息）
+func (c *Client) Close() error {
+	c.writePacket(packetDisconnect, nil)
+	c.shutdown(errors.New("连接已关闭"))
+	return nil
+}
+
+func (c *Client) shutdown(err error) {
+	c.closeOnce.Do(func() {
+		c.err = err
+		c.conn.Close()
+		close(c.done)
+	})
+}
+
+func (c *Client) writePacket(header byte, body []byte) error {
+	buf := make([]byte, 0, len(body)+5)
+	buf = append(buf, header)
+	buf = appendRemainingLength(buf, len(body))
+	buf = append(buf, body...)
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if _, err := c.conn.Write(buf); err != nil {
+		c.shutdown(err)
+		return err
+	}
+	return nil
+}
+
+// readLoop 读取并丢弃服务器发来的报文（PINGRESP 等），用于及时发现断线
+func (c *Client) readLoop() {
+	for {
+		c.conn.SetReadDeadline(time.Now().Add(c.keepAlive * 3 / 2))
+		if _, _, err := readPacket(c.reader); err != nil {
+			c.shutdown(err)
+			return
+		}
+	}
+}
+
+func (c *Client) pingLoop() {
+	ticker := time.NewTicker(c.keepAlive / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			if err := c.writePacket(packetPingreq, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func readPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		if i >= 4 {
+			return 0, nil, errors.New("MQTT 报文长度无效")
+		}
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(b&0x7F) * multiplier
+		if b&0x80 == 0 {
+			break
+		}
+		multiplier *= 128
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header, body, nil
+}
+
+func appendRemainingLength(buf []byte, n int) []byte {
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+		buf = append(buf, b)
+		if n == 0 {
+			return buf
+		}
+	}
+}
+
+func appendString(buf []byte, s string) []byte {
+	return appendBytes(buf, []byte(s))
+}
+
+func appendBytes(buf []byte, b []byte) []byte {
+	buf = append(buf, byte(len(b)>>8), byte(len(b)))
+	return append(buf, b...)
+}
+
+func connackReason(code byte) string {
+	switch code {
+	case 1:
+		return "不支持的协议版本"
+	case 2:
+		return "客户端 ID 不合法"
+	case 3:
+		return "服务不可用"
+	case 4:
+		return "用户名或密码错误"
+	case 5:
+		return "未授权"
+	}
+	return fmt.Sprintf("返回码 %d", code)
+}
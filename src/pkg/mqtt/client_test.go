@@ -0,0 +1,77 @@
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialAndPublish(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ln.Close()
+
+	type packet struct {
+		header byte
+		body   []byte
+	}
+	received := make(chan packet, 4)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			header, body, err := readPacket(r)
+			if err != nil {
+				close(received)
+				return
+			}
+			if header&0xF0 == packetConnect {
+				conn.Write([]byte{packetConnack, 2, 0, 0})
+			}
+			received <- packet{header, body}
+		}
+	}()
+
+	c, err := Dial(context.Background(), Options{
+		Broker:      "tcp://" + ln.Addr().String(),
+		ClientID:    "test",
+		Username:    "user",
+		Password:    "pass",
+		WillTopic:   "p/status",
+		WillPayload: []byte("offline"),
+		WillRetain:  true,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	connect := <-received
+	assert.Equal(t, packetConnect, connect.header)
+	assert.Equal(t, []byte{0, 4, 'M', 'Q', 'T', 'T', 4}, connect.body[:7])
+	assert.Equal(t, byte(0x02|0x04|0x20|0x40|0x80), connect.body[7], "clean session、遗嘱保留、用户名密码")
+
+	assert.NoError(t, c.Publish("p/events/live_start", []byte(`{}`), true))
+	publish := <-received
+	assert.Equal(t, packetPublish|0x01, publish.header)
+	assert.Equal(t, append(appendString(nil, "p/events/live_start"), '{', '}'), publish.body)
+
+	c.Close()
+	assert.Equal(t, packetDisconnect, (<-received).header)
+	assert.Error(t, c.Publish("p/x", nil, false))
+}
+
+func TestRemainingLength(t *testing.T) {
+	assert.Equal(t, []byte{0x00}, appendRemainingLength(nil, 0))
+	assert.Equal(t, []byte{0x7F}, appendRemainingLength(nil, 127))
+	assert.Equal(t, []byte{0x80, 0x01}, appendRemainingLength(nil, 128))
+	assert.Equal(t, []byte{0xFF, 0xFF, 0x7F}, appendRemainingLength(nil, 2097151))
+}
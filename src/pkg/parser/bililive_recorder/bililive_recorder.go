@@ -16,6 +16,7 @@ import (
 	"github.com/bililive-go/bililive-go/src/live"
 	"github.com/bililive-go/bililive-go/src/pkg/livelogger"
 	"github.com/bililive-go/bililive-go/src/pkg/parser"
+	"github.com/bililive-go/bililive-go/src/pkg/proxy"
 	bilisentry "github.com/bililive-go/bililive-go/src/pkg/sentry"
 	"github.com/bililive-go/bililive-go/src/pkg/utils"
 
@@ -168,6 +169,10 @@ func (p *Parser) ParseLiveStream(ctx context.Context, streamUrlInfo *live.Stream
 
 	p.cmdLock.Lock()
 	p.cmd = exec.Command(dotnetPath, args...)
+	// 按直播间解析出的下载代理访问上游（本机的探测代理地址不受影响）
+	if proxyEnv := proxy.GetProxyEnvVarsForURL(p.cfg["proxy_url"]); len(proxyEnv) > 0 {
+		p.cmd.Env = append(os.Environ(), proxyEnv...)
+	}
 
 	var cmdErr error
 	if p.cmdStdIn, cmdErr = p.cmd.StdinPipe(); cmdErr != nil {
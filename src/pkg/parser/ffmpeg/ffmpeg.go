@@ -4,14 +4,17 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
 	"os"
 	"os/exec"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bililive-go/bililive-go/src/configs"
@@ -19,6 +22,7 @@ import (
 	"github.com/bililive-go/bililive-go/src/pkg/flvproxy"
 	"github.com/bililive-go/bililive-go/src/pkg/livelogger"
 	"github.com/bililive-go/bililive-go/src/pkg/parser"
+	netproxy "github.com/bililive-go/bililive-go/src/pkg/proxy"
 	bilisentry "github.com/bililive-go/bililive-go/src/pkg/sentry"
 	"github.com/bililive-go/bililive-go/src/pkg/utils"
 	"github.com/bililive-go/bililive-go/src/tools"
@@ -29,6 +33,9 @@ const (
 	userAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_12_6) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/59.0.3071.115 Safari/537.36"
 )
 
+// upstreamStatusRegexp FFmpeg 请求上游失败时输出的 HTTP 状态码
+var upstreamStatusRegexp = regexp.MustCompile(`(?:HTTP error|Server returned) (\d{3})`)
+
 func init() {
 	parser.Register(Name, new(builder))
 }
@@ -46,6 +53,7 @@ func (b *builder) Build(cfg map[string]string, logger *livelogger.LiveLogger) (p
 		timeoutInUs: cfg["timeout_in_us"],
 		audioOnly:   audioOnly,
 		useFlvProxy: useFlvProxy,
+		proxyURL:    cfg["proxy_url"],
 		logger:      logger,
 	}, nil
 }
@@ -59,6 +67,8 @@ type Parser struct {
 	timeoutInUs string
 	audioOnly   bool
 	useFlvProxy bool // 是否使用 FLV 代理分段
+	// proxyURL 按直播间解析出的下载代理（为空时使用环境变量）
+	proxyURL string
 
 	statusReq  chan struct{}
 	statusResp chan map[string]interface{}
@@ -70,6 +80,26 @@ type Parser struct {
 	flvProxyMu   sync.Mutex
 	flvProxyCtx  context.Context
 	flvProxyStop context.CancelFunc
+
+	// upstreamStatus FFmpeg 输出中最近一次上游返回的错误状态码，用于识别 Cookie 失效或风控
+	upstreamStatus atomic.Int32
+}
+
+// proxyEnv 返回 FFmpeg 进程的环境变量，使其经由下载代理访问上游。
+// FFmpeg 只支持 HTTP 代理，SOCKS5 代理由录制器通过本地探测/HLS 代理转发；
+// 输入为本机代理地址时无需设置。返回 nil 表示继承当前进程环境变量。
+// 本地代理不可用、FFmpeg 需要直接经 SOCKS5 代理访问上游时返回错误，不绕过代理直连
+func (p *Parser) proxyEnv(inputURL string) ([]string, error) {
+	if p.proxyURL == "" {
+		return nil, nil
+	}
+	if u, err := url.Parse(inputURL); err == nil && netproxy.IsLoopbackHost(u.Host) {
+		return nil, nil
+	}
+	if netproxy.IsSocks5URL(p.proxyURL) {
+		return nil, errors.New("FFmpeg 不支持 SOCKS5 代理且本地转发代理不可用，请改用 HTTP 代理或其他下载器")
+	}
+	return append(os.Environ(), netproxy.GetProxyEnvVarsForURL(p.proxyURL)...), nil
 }
 
 func (p *Parser) scanFFmpegStatus() <-chan []byte {
@@ -209,6 +239,7 @@ func (p *Parser) ParseLiveStream(ctx context.Context, streamUrlInfo *live.Stream
 			p.logger.Warnf("无法创建 FLV 代理，将直接连接上游: %v", proxyErr)
 			useProxy = false
 		} else {
+			proxy.SetUpstreamProxy(p.proxyURL)
 			p.flvProxyMu.Lock()
 			p.flvProxy = proxy
 			p.flvProxyCtx, p.flvProxyStop = context.WithCancel(ctx)
@@ -299,6 +330,9 @@ func (p *Parser) ParseLiveStream(ctx context.Context, streamUrlInfo *live.Stream
 		default:
 		}
 		p.cmd = exec.Command(ffmpegPath, args...)
+		if p.cmd.Env, err = p.proxyEnv(inputURL); err != nil {
+			return
+		}
 		if p.cmdStdIn, err = p.cmd.StdinPipe(); err != nil {
 			return
 		}
@@ -309,6 +343,12 @@ func (p *Parser) ParseLiveStream(ctx context.Context, streamUrlInfo *live.Stream
 		p.cmd.Stderr = io.MultiWriter(
 			utils.NewLogFilterWriter(os.Stderr),
 			utils.NewLoggerWriter(p.logger),
+			utils.NewFilteredLineWriter(func(line string, _ bool) {
+				if m := upstreamStatusRegexp.FindStringSubmatch(line); m != nil {
+					code, _ := strconv.Atoi(m[1])
+					p.upstreamStatus.Store(int32(code))
+				}
+			}),
 		)
 		if err = p.cmd.Start(); err != nil {
 			if p.cmd.Process != nil {
@@ -331,11 +371,20 @@ func (p *Parser) ParseLiveStream(ctx context.Context, streamUrlInfo *live.Stream
 	p.stopFlvProxy()
 
 	if err != nil {
-		return err
+		return p.wrapUpstreamError(err)
 	}
 	return nil
 }
 
+// wrapUpstreamError 上游返回 401/412/429 时将 FFmpeg 的退出错误包装为登录失效或风控错误，
+// 账号池据此切换 Cookie
+func (p *Parser) wrapUpstreamError(err error) error {
+	if statusErr := live.StatusError(int(p.upstreamStatus.Load())); statusErr != nil {
+		return fmt.Errorf("%w: %w", statusErr, err)
+	}
+	return err
+}
+
 // isFlvStream 判断 URL 是否指向 FLV 流
 func (p *Parser) isFlvStream(u *url.URL) bool {
 	path := strings.ToLower(u.Path)
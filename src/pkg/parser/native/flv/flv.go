@@ -48,7 +48,7 @@ func (b *builder) Build(cfg map[string]string, logger *livelogger.LiveLogger) (p
 	audioOnly := cfg["audio_only"] == "true"
 	return &Parser{
 		Metadata:  Metadata{},
-		hc:        &http.Client{},
+		hc:        utils.CreateDownloadClientWithProxy(cfg["proxy_url"]),
 		stopCh:    make(chan struct{}),
 		closeOnce: new(sync.Once),
 		audioOnly: audioOnly,
@@ -75,7 +75,7 @@ type Parser struct {
 	logger    *livelogger.LiveLogger
 }
 
-func (p *Parser) ParseLiveStream(ctx context.Context, streamUrlInfo *live.StreamUrlInfo, live live.Live, file string) error {
+func (p *Parser) ParseLiveStream(ctx context.Context, streamUrlInfo *live.StreamUrlInfo, _ live.Live, file string) error {
 	// 检查是否配置了分段策略，原生 FLV 解析器不支持
 	cfg := configs.GetCurrentConfig()
 	if cfg != nil {
@@ -100,6 +100,13 @@ func (p *Parser) ParseLiveStream(ctx context.Context, streamUrlInfo *live.Stream
 		return err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		// 登录失效、风控类状态码包装为对应错误，账号池据此切换 Cookie
+		if err := live.StatusError(resp.StatusCode); err != nil {
+			return err
+		}
+		return fmt.Errorf("上游返回 HTTP %d", resp.StatusCode)
+	}
 	p.i = reader.New(resp.Body)
 	defer p.i.Free()
 
@@ -0,0 +1,222 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+	applog "github.com/bililive-go/bililive-go/src/log"
+)
+
+const (
+	defaultHealthCheckURL      = "https://www.gstatic.com/generate_204"
+	defaultHealthCheckInterval = 60 * time.Second
+	healthCheckTimeout         = 10 * time.Second
+	// failureThreshold 连续请求失败多少次后将代理标记为不可用
+	failureThreshold = 3
+	// healthCheckTick 健康检查调度的最小粒度
+	healthCheckTick = 5 * time.Second
+)
+
+// proxyHealth 单个代理地址的健康状态
+type proxyHealth struct {
+	unhealthy bool
+	failures  int
+}
+
+// poolManager 维护代理池中各代理地址的健康状态
+type poolManager struct {
+	mu     sync.Mutex
+	health map[string]*proxyHealth // 代理地址 -> 健康状态（同一地址在多个池中共享状态）
+	// lastCheck 各代理池上次健康检查的时间
+	lastCheck map[string]time.Time
+}
+
+func newPoolManager() *poolManager {
+	return &poolManager{
+		health:    make(map[string]*proxyHealth),
+		lastCheck: make(map[string]time.Time),
+	}
+}
+
+var pools = newPoolManager()
+
+// pick 返回池中第一个健康的代理地址；全部不可用时返回第一个，避免完全无法请求
+func (m *poolManager) pick(pool configs.ProxyPool) string {
+	if len(pool.URLs) == 0 {
+		return ""
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, u := range pool.URLs {
+		if h, ok := m.health[u]; !ok || !h.unhealthy {
+			return u
+		}
+	}
+	return pool.URLs[0]
+}
+
+func (m *poolManager) get(proxyURL string) *proxyHealth {
+	h, ok := m.health[proxyURL]
+	if !ok {
+		h = &proxyHealth{}
+		m.health[proxyURL] = h
+	}
+	return h
+}
+
+// reportFailure 记录一次请求失败，连续失败达到阈值时标记为不可用
+func (m *poolManager) reportFailure(proxyURL string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h := m.get(proxyURL)
+	h.failures++
+	if h.failures >= failureThreshold && !h.unhealthy {
+		h.unhealthy = true
+		applog.GetLogger().Warnf("代理 %s 连续 %d 次请求失败，已切换到代理池中的下一个代理", redactProxyURL(proxyURL), h.failures)
+	}
+}
+
+// reportSuccess 记录一次请求成功
+func (m *poolManager) reportSuccess(proxyURL string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if h, ok := m.health[proxyURL]; ok {
+		h.failures = 0
+	}
+}
+
+// setHealthy 根据健康检查结果更新状态
+func (m *poolManager) setHealthy(proxyURL string, healthy bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h := m.get(proxyURL)
+	if healthy {
+		if h.unhealthy {
+			applog.GetLogger().Infof("代理 %s 已恢复可用", redactProxyURL(proxyURL))
+		}
+		h.unhealthy = false
+		h.failures = 0
+		return
+	}
+	if !h.unhealthy {
+		applog.GetLogger().Warnf("代理 %s 健康检查失败，已标记为不可用", redactProxyURL(proxyURL))
+	}
+	h.unhealthy = true
+}
+
+// isPoolMember 判断代理地址是否属于某个代理池（仅代理池中的地址参与故障切换）
+func isPoolMember(cfg *configs.Config, proxyURL string) bool {
+	if cfg == nil {
+		return false
+	}
+	for _, pool := range cfg.ProxyPools {
+		for _, u := range pool.URLs {
+			if u == proxyURL {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ReportFailure 报告经由 proxyURL 的请求失败（连接错误等），用于代理池故障切换
+func ReportFailure(proxyURL string) {
+	if proxyURL == "" || !isPoolMember(configs.GetCurrentConfig(), proxyURL) {
+		return
+	}
+	pools.reportFailure(proxyURL)
+}
+
+// ReportSuccess 报告经由 proxyURL 的请求成功
+func ReportSuccess(proxyURL string) {
+	if proxyURL == "" {
+		return
+	}
+	pools.reportSuccess(proxyURL)
+}
+
+// StartHealthChecks 按各代理池配置的间隔定期检查池中代理的可用性，直到 ctx 取消
+func StartHealthChecks(ctx context.Context) {
+	ticker := time.NewTicker(healthCheckTick)
+	defer ticker.Stop()
+	for {
+		pools.checkDue(ctx, time.Now())
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// checkDue 检查所有到期的代理池
+func (m *poolManager) checkDue(ctx context.Context, now time.Time) {
+	cfg := configs.GetCurrentConfig()
+	if cfg == nil {
+		return
+	}
+	for name, pool := range cfg.ProxyPools {
+		interval := defaultHealthCheckInterval
+		if pool.HealthCheckIntervalSec > 0 {
+			interval = time.Duration(pool.HealthCheckIntervalSec) * time.Second
+		}
+		m.mu.Lock()
+		last := m.lastCheck[name]
+		due := now.Sub(last) >= interval
+		if due {
+			m.lastCheck[name] = now
+		}
+		m.mu.Unlock()
+		if !due {
+			continue
+		}
+		checkURL := pool.HealthCheckURL
+		if checkURL == "" {
+			checkURL = defaultHealthCheckURL
+		}
+		var wg sync.WaitGroup
+		for _, u := range pool.URLs {
+			wg.Add(1)
+			go func(proxyURL string) {
+				defer wg.Done()
+				m.setHealthy(proxyURL, checkProxy(ctx, proxyURL, checkURL) == nil)
+			}(u)
+		}
+		wg.Wait()
+	}
+}
+
+// checkProxy 通过 proxyURL 请求 checkURL，服务端返回 5xx 以外的状态码即视为可用
+var checkProxy = func(ctx context.Context, proxyURL, checkURL string) error {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	transport := &http.Transport{Proxy: fixedProxyFunc(proxyURL)}
+	defer transport.CloseIdleConnections()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, checkURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("健康检查返回状态码 %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// redactProxyURL 去掉代理地址中的认证信息，用于日志
+func redactProxyURL(proxyURL string) string {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return proxyURL
+	}
+	return u.Redacted()
+}
@@ -0,0 +1,94 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+)
+
+const testRoomURL = "https://live.bilibili.com/1"
+
+func setupPoolConfig(t *testing.T) *configs.Config {
+	t.Helper()
+	cfg := configs.NewConfig()
+	cfg.ProxyPools = map[string]configs.ProxyPool{
+		"jp": {URLs: []string{"http://10.0.0.1:8080", "socks5://10.0.0.2:1080"}},
+	}
+	cfg.Proxy = configs.Proxy{Enable: true, URL: "http://global:8080"}
+	cfg.PlatformConfigs = map[string]configs.PlatformConfig{
+		"bilibili": {OverridableConfig: configs.OverridableConfig{
+			Proxy: &configs.Proxy{Enable: true, Pool: "jp"},
+		}},
+	}
+	cfg.LiveRooms = []configs.LiveRoom{
+		{Url: "https://live.bilibili.com/2", OverridableConfig: configs.OverridableConfig{
+			Proxy: &configs.Proxy{Enable: true, URL: configs.ProxyDirect},
+		}},
+	}
+	configs.SetCurrentConfig(cfg)
+	old := pools
+	pools = newPoolManager()
+	t.Cleanup(func() {
+		configs.SetCurrentConfig(nil)
+		pools = old
+	})
+	return cfg
+}
+
+func TestProxyResolutionForRoom(t *testing.T) {
+	cfg := setupPoolConfig(t)
+	assert.NoError(t, cfg.ValidateProxies())
+
+	assert.Equal(t, "http://10.0.0.1:8080", GetDownloadProxyURLForRoom(testRoomURL), "平台级代理池覆盖全局代理")
+	assert.Equal(t, configs.ProxyDirect, GetInfoProxyURLForRoom("https://live.bilibili.com/2"), "房间级覆盖为直连")
+	assert.Equal(t, "http://global:8080", GetInfoProxyURLForRoom("https://www.douyu.com/1"), "其他平台使用全局代理")
+
+	cfg.Proxy.Pool = "missing"
+	assert.Error(t, cfg.ValidateProxies())
+}
+
+func TestPoolFailover(t *testing.T) {
+	setupPoolConfig(t)
+
+	for i := 0; i < failureThreshold; i++ {
+		ReportFailure("http://10.0.0.1:8080")
+	}
+	assert.Equal(t, "socks5://10.0.0.2:1080", GetDownloadProxyURLForRoom(testRoomURL), "连续失败后切换到下一个代理")
+
+	// 全部不可用时仍返回第一个
+	pools.setHealthy("socks5://10.0.0.2:1080", false)
+	assert.Equal(t, "http://10.0.0.1:8080", GetDownloadProxyURLForRoom(testRoomURL))
+
+	// 健康检查恢复
+	old := checkProxy
+	checkProxy = func(ctx context.Context, proxyURL, checkURL string) error {
+		if proxyURL == "http://10.0.0.1:8080" {
+			return errors.New("unreachable")
+		}
+		return nil
+	}
+	defer func() { checkProxy = old }()
+	pools.checkDue(context.Background(), pools.lastCheck["jp"].Add(defaultHealthCheckInterval))
+	assert.Equal(t, "socks5://10.0.0.2:1080", GetDownloadProxyURLForRoom(testRoomURL))
+}
+
+func TestWrapTransportBypassesLoopback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	// 代理地址不可达，但本机地址不走代理
+	client := &http.Client{Transport: WrapDownloadTransport(&http.Transport{}, "http://127.0.0.1:1")}
+	resp, err := client.Get(srv.URL)
+	if assert.NoError(t, err) {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	}
+}
@@ -32,34 +32,61 @@ const (
 func getProxyURLForScope(scope proxyScope) string {
 	cfg := configs.GetCurrentConfig()
 	if cfg != nil && configs.EnableProxyConfig {
-		// 先检查专用代理
-		switch scope {
-		case scopeInfo:
-			if cfg.Proxy.InfoProxy != nil && cfg.Proxy.InfoProxy.Enable && cfg.Proxy.InfoProxy.URL != "" {
-				return cfg.Proxy.InfoProxy.URL
-			}
-		case scopeDownload:
-			if cfg.Proxy.DownloadProxy != nil && cfg.Proxy.DownloadProxy.Enable && cfg.Proxy.DownloadProxy.URL != "" {
-				return cfg.Proxy.DownloadProxy.URL
-			}
-		}
-
-		// 回退到通用代理
-		if cfg.Proxy.Enable && cfg.Proxy.URL != "" {
-			return cfg.Proxy.URL
+		if proxyURL, ok := selectProxyURL(cfg, &cfg.Proxy, scope); ok {
+			return proxyURL
 		}
 	}
+	return getEnvProxyURL()
+}
 
-	// 按优先级检查环境变量
+// getEnvProxyURL 按优先级读取代理环境变量
+func getEnvProxyURL() string {
 	for _, envVar := range []string{"ALL_PROXY", "all_proxy", "HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy"} {
 		if proxyURL := os.Getenv(envVar); proxyURL != "" {
 			return proxyURL
 		}
 	}
-
 	return ""
 }
 
+// selectProxyURL 从一组代理配置中选出指定用途的代理地址（代理池按健康状态选取），
+// 未配置时返回 false，调用方应回退到环境变量。返回 configs.ProxyDirect 表示直连。
+func selectProxyURL(cfg *configs.Config, p *configs.Proxy, scope proxyScope) (string, bool) {
+	// 先检查专用代理
+	var entry *configs.ProxyEntry
+	switch scope {
+	case scopeInfo:
+		entry = p.InfoProxy
+	case scopeDownload:
+		entry = p.DownloadProxy
+	}
+	if entry != nil && entry.Enable {
+		if proxyURL := resolveEntry(cfg, entry.URL, entry.Pool); proxyURL != "" {
+			return proxyURL, true
+		}
+	}
+
+	// 回退到通用代理
+	if p.Enable {
+		if proxyURL := resolveEntry(cfg, p.URL, p.Pool); proxyURL != "" {
+			return proxyURL, true
+		}
+	}
+	return "", false
+}
+
+// resolveEntry 代理池优先于固定地址
+func resolveEntry(cfg *configs.Config, proxyURL, poolName string) string {
+	if poolName != "" {
+		if pool, ok := cfg.ProxyPools[poolName]; ok {
+			if picked := pools.pick(pool); picked != "" {
+				return picked
+			}
+		}
+	}
+	return proxyURL
+}
+
 // GetProxyURL 获取通用代理 URL
 // 优先级：配置文件 > 环境变量 (ALL_PROXY > HTTPS_PROXY > HTTP_PROXY)
 func GetProxyURL() string {
@@ -181,6 +208,10 @@ func getProxyFuncForURL(proxyURL string) func(*http.Request) (*url.URL, error) {
 
 // applyProxyURLToTransport 将指定的代理 URL 应用到 http.Transport
 func applyProxyURLToTransport(transport *http.Transport, proxyURL string) {
+	if proxyURL == configs.ProxyDirect {
+		transport.Proxy = nil
+		return
+	}
 	if proxyURL == "" {
 		// 使用系统默认代理（从环境变量）
 		transport.Proxy = http.ProxyFromEnvironment
@@ -201,6 +232,14 @@ func getProxyEnvVarsForURL(proxyURL string) []string {
 	if proxyURL == "" {
 		return nil
 	}
+	if proxyURL == configs.ProxyDirect {
+		// 覆盖继承自父进程的代理环境变量
+		return []string{
+			"HTTP_PROXY=", "HTTPS_PROXY=", "ALL_PROXY=",
+			"http_proxy=", "https_proxy=", "all_proxy=",
+			"NO_PROXY=*", "no_proxy=*",
+		}
+	}
 
 	return []string{
 		"HTTP_PROXY=" + proxyURL,
@@ -0,0 +1,163 @@
+package proxy
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+)
+
+// getProxyURLForRoom 获取直播间指定用途的代理 URL
+// 直播间的代理配置按 全局 -> 平台 -> 房间 整体覆盖，未配置时回退到环境变量
+func getProxyURLForRoom(roomURL string, scope proxyScope) string {
+	cfg := configs.GetCurrentConfig()
+	if cfg != nil && configs.EnableProxyConfig {
+		p := resolveRoomProxy(cfg, roomURL)
+		if proxyURL, ok := selectProxyURL(cfg, &p, scope); ok {
+			return proxyURL
+		}
+	}
+	return getEnvProxyURL()
+}
+
+// resolveRoomProxy 解析直播间最终生效的代理配置。
+// 每次请求都会调用，因此只读遍历房间列表，不使用会写入索引缓存的 GetLiveRoomByUrl
+func resolveRoomProxy(cfg *configs.Config, roomURL string) configs.Proxy {
+	room := &configs.LiveRoom{Url: roomURL}
+	for i := range cfg.LiveRooms {
+		if cfg.LiveRooms[i].Url == roomURL {
+			room = &cfg.LiveRooms[i]
+			break
+		}
+	}
+	return cfg.ResolveConfigForRoom(room, configs.GetPlatformKeyFromUrl(roomURL)).Proxy
+}
+
+// GetInfoProxyURLForRoom 获取直播间的信息获取代理 URL，"direct" 表示直连
+func GetInfoProxyURLForRoom(roomURL string) string {
+	return getProxyURLForRoom(roomURL, scopeInfo)
+}
+
+// GetDownloadProxyURLForRoom 获取直播间的下载代理 URL，"direct" 表示直连
+func GetDownloadProxyURLForRoom(roomURL string) string {
+	return getProxyURLForRoom(roomURL, scopeDownload)
+}
+
+// GetProxyEnvVarsForURL 返回用于子进程的代理环境变量，本机地址不走代理
+func GetProxyEnvVarsForURL(proxyURL string) []string {
+	envs := getProxyEnvVarsForURL(proxyURL)
+	if proxyURL != "" && proxyURL != configs.ProxyDirect {
+		envs = append(envs,
+			"ALL_PROXY="+proxyURL, "all_proxy="+proxyURL,
+			"NO_PROXY=localhost,127.0.0.1,::1", "no_proxy=localhost,127.0.0.1,::1",
+		)
+	}
+	return envs
+}
+
+// IsSocks5URL 检查代理 URL 是否为 SOCKS5 代理
+func IsSocks5URL(proxyURL string) bool {
+	return isSocks5(proxyURL)
+}
+
+// IsLoopbackHost 判断 host（可带端口）是否为本机地址。
+// 本地的 FLV/HLS 代理服务器不应再经过代理访问
+func IsLoopbackHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// parseProxyURL 解析代理地址，"" 和 "direct" 返回 nil。
+// socks5h 按 socks5 处理（标准库的 SOCKS5 实现本就由代理端解析域名）
+func parseProxyURL(proxyURL string) (*url.URL, error) {
+	if proxyURL == "" || proxyURL == configs.ProxyDirect {
+		return nil, nil
+	}
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "socks5h" {
+		cp := *u
+		cp.Scheme = "socks5"
+		u = &cp
+	}
+	return u, nil
+}
+
+// fixedProxyFunc 返回使用固定代理地址的 http.Transport.Proxy 函数。
+// 与 applyProxyURLToTransport 不同，SOCKS5 也通过 Proxy 字段处理，
+// 因此不会被自定义的 DialTLSContext 绕过
+func fixedProxyFunc(proxyURL string) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		if IsLoopbackHost(req.URL.Host) {
+			return nil, nil
+		}
+		if proxyURL == "" {
+			return http.ProxyFromEnvironment(req)
+		}
+		return parseProxyURL(proxyURL)
+	}
+}
+
+type proxyURLKey struct{}
+
+// proxyFromContext 从请求上下文中读取 dynamicTransport 选定的代理地址
+func proxyFromContext(req *http.Request) (*url.URL, error) {
+	proxyURL, _ := req.Context().Value(proxyURLKey{}).(string)
+	return fixedProxyFunc(proxyURL)(req)
+}
+
+// dynamicTransport 每次请求时重新解析代理地址，使配置热更新和代理池故障切换
+// 对长期存在的 HTTP 客户端同样生效，并把请求结果反馈给代理池
+type dynamicTransport struct {
+	base    *http.Transport
+	resolve func() string
+}
+
+func (t *dynamicTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	proxyURL := t.resolve()
+	ctx := context.WithValue(req.Context(), proxyURLKey{}, proxyURL)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if IsLoopbackHost(req.URL.Host) {
+		return resp, err
+	}
+	if err != nil {
+		if req.Context().Err() == nil {
+			ReportFailure(proxyURL)
+		}
+	} else {
+		ReportSuccess(proxyURL)
+	}
+	return resp, err
+}
+
+// CloseIdleConnections 关闭底层 Transport 的空闲连接
+func (t *dynamicTransport) CloseIdleConnections() {
+	t.base.CloseIdleConnections()
+}
+
+// WrapTransport 让 base 按 resolve 返回的代理地址发送请求（每次请求重新解析），
+// resolve 返回 "" 表示使用环境变量，"direct" 表示直连
+func WrapTransport(base *http.Transport, resolve func() string) http.RoundTripper {
+	base.Proxy = proxyFromContext
+	return &dynamicTransport{base: base, resolve: resolve}
+}
+
+// WrapInfoTransportForRoom 让 base 使用直播间的信息获取代理
+func WrapInfoTransportForRoom(base *http.Transport, roomURL string) http.RoundTripper {
+	return WrapTransport(base, func() string { return GetInfoProxyURLForRoom(roomURL) })
+}
+
+// WrapDownloadTransport 让 base 使用固定的下载代理地址（通常由录制器在开始录制时解析）
+func WrapDownloadTransport(base *http.Transport, proxyURL string) http.RoundTripper {
+	return WrapTransport(base, func() string { return proxyURL })
+}
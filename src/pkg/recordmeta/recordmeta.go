@@ -0,0 +1,185 @@
+// Package recordmeta 管理录制文件旁的元数据文件（{视频文件名去扩展名}.meta.json）。
+// 元数据与视频同目录存放，按去掉扩展名后的文件名关联，
+// 因此后处理将 .ts/.flv 转封装为 .mp4 后仍能对应到同一份元数据。
+package recordmeta
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/bililive-go/bililive-go/src/pkg/hlsproxy"
+)
+
+// currentVersion 元数据文件格式版本
+const currentVersion = 1
+
+// fileSuffix 元数据文件后缀
+const fileSuffix = ".meta.json"
+
+// 故事板文件与视频同目录，文件名为视频去扩展名加以下后缀
+const (
+	StoryboardSpriteSuffix = ".storyboard.jpg"
+	StoryboardVTTSuffix    = ".storyboard.vtt"
+)
+
+// Meta 单个录制文件的元数据
+type Meta struct {
+	Version int `json:"version"`
+	// VideoFile 首次写入元数据时对应的视频文件名（不含目录）
+	VideoFile string `json:"video_file"`
+	// AdBreaks HLS 拼接广告的位置（剔除或仅标记）
+	AdBreaks []hlsproxy.AdBreak `json:"ad_breaks,omitempty"`
+	// Highlights 根据弹幕密度和礼物活跃度识别的高光片段，按得分从高到低排列
+	Highlights []Highlight `json:"highlights,omitempty"`
+	// Storyboard 拖动预览用的缩略图雪碧图，由后处理生成
+	Storyboard *Storyboard `json:"storyboard,omitempty"`
+}
+
+// Storyboard 缩略图雪碧图，第 i 帧（从 0 开始）位于第 i/Columns 行、第 i%Columns 列，
+// 对应视频的 [i*Interval, (i+1)*Interval) 秒
+type Storyboard struct {
+	Sprite   string  `json:"sprite"` // 雪碧图文件名（不含目录）
+	VTT      string  `json:"vtt"`    // WebVTT 缩略图轨道文件名（不含目录）
+	Interval float64 `json:"interval"`
+	Count    int     `json:"count"`
+	Columns  int     `json:"columns"`
+	Width    int     `json:"width"`  // 单帧宽度（像素）
+	Height   int     `json:"height"` // 单帧高度（像素）
+}
+
+// Highlight 一个高光片段，时间为相对视频开头的秒数
+type Highlight struct {
+	Start     float64 `json:"start"`
+	End       float64 `json:"end"`
+	Score     float64 `json:"score"`
+	Danmaku   int     `json:"danmaku"`    // 片段内的弹幕数
+	Gifts     int     `json:"gifts"`      // 片段内的礼物、SC、上舰消息数
+	GiftValue float64 `json:"gift_value"` // 片段内可折算的付费金额（元）
+}
+
+// mu 串行化同一进程内对元数据文件的读改写
+var mu sync.Mutex
+
+// PathFor 返回视频文件对应的元数据文件路径
+func PathFor(videoFile string) string {
+	ext := filepath.Ext(videoFile)
+	return strings.TrimSuffix(videoFile, ext) + fileSuffix
+}
+
+// Load 读取视频文件对应的元数据，文件不存在时返回 nil, nil
+func Load(videoFile string) (*Meta, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	return load(PathFor(videoFile))
+}
+
+// Update 读取（不存在则新建）视频文件对应的元数据，经 fn 修改后写回
+func Update(videoFile string, fn func(m *Meta)) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	path := PathFor(videoFile)
+	m, err := load(path)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		m = &Meta{VideoFile: filepath.Base(videoFile)}
+	}
+	fn(m)
+	return save(path, m)
+}
+
+// Rename 视频文件重命名后，同步重命名元数据文件与故事板文件，
+// 并更新元数据和缩略图轨道中引用的雪碧图文件名。没有元数据时不做任何事
+func Rename(oldVideo, newVideo string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	oldPath, newPath := PathFor(oldVideo), PathFor(newVideo)
+	m, err := load(oldPath)
+	if err != nil || m == nil {
+		return err
+	}
+	if board := m.Storyboard; board != nil {
+		oldDir, newDir := filepath.Dir(oldVideo), filepath.Dir(newVideo)
+		newBase := strings.TrimSuffix(filepath.Base(newVideo), filepath.Ext(newVideo))
+		sprite, vtt := newBase+StoryboardSpriteSuffix, newBase+StoryboardVTTSuffix
+		if err := os.Rename(filepath.Join(oldDir, board.Sprite), filepath.Join(newDir, sprite)); err == nil {
+			// 缩略图轨道的每个条目以 <雪碧图文件名>#xywh= 引用雪碧图
+			vttPath := filepath.Join(newDir, vtt)
+			if err := os.Rename(filepath.Join(oldDir, board.VTT), vttPath); err == nil {
+				if data, err := os.ReadFile(vttPath); err == nil {
+					data = []byte(strings.ReplaceAll(string(data), board.Sprite+"#", sprite+"#"))
+					_ = os.WriteFile(vttPath, data, 0644)
+				}
+			}
+			board.Sprite, board.VTT = sprite, vtt
+		}
+	}
+	if err := save(newPath, m); err != nil {
+		return err
+	}
+	if oldPath != newPath {
+		_ = os.Remove(oldPath)
+	}
+	return nil
+}
+
+// Remove 删除视频文件对应的元数据文件及其引用的故事板文件
+func Remove(videoFile string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	path := PathFor(videoFile)
+	m, err := load(path)
+	if err != nil || m == nil {
+		return err
+	}
+	if m.Storyboard != nil {
+		dir := filepath.Dir(videoFile)
+		_ = os.Remove(filepath.Join(dir, m.Storyboard.Sprite))
+		_ = os.Remove(filepath.Join(dir, m.Storyboard.VTT))
+	}
+	return os.Remove(path)
+}
+
+// save 写入元数据文件，调用方需持有 mu
+func save(path string, m *Meta) error {
+	m.Version = currentVersion
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化录制元数据失败: %w", err)
+	}
+	// 先写临时文件再重命名，避免进程中断留下半截 JSON
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("写入录制元数据失败: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("写入录制元数据失败: %w", err)
+	}
+	return nil
+}
+
+func load(path string) (*Meta, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取录制元数据失败: %w", err)
+	}
+	m := &Meta{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("解析录制元数据失败: %w", err)
+	}
+	return m, nil
+}
@@ -0,0 +1,279 @@
+// Package secrets 提供加密存储的密钥库，用于保存 Cookie、密码、令牌等敏感信息。
+//
+// 密钥库文件中的每个条目使用 AES-256-GCM 单独加密，条目名作为附加认证数据，
+// 主密钥来源（按优先级）：
+//  1. 环境变量 BILILIVE_SECRETS_KEY（任意字符串，经 SHA-256 派生）；
+//  2. 环境变量 BILILIVE_SECRETS_KEY_FILE 指向的文件；
+//  3. 数据目录下的 secrets.key（不存在时自动生成，权限 0600）。
+//
+// 配置中通过 "secret://名称" 引用条目，见 configs.SecretRefPrefix。
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+)
+
+const (
+	// EnvMasterKey 主密钥环境变量
+	EnvMasterKey = "BILILIVE_SECRETS_KEY"
+	// EnvMasterKeyFile 主密钥文件路径环境变量
+	EnvMasterKeyFile = "BILILIVE_SECRETS_KEY_FILE"
+
+	storeFileName = "secrets.json"
+	keyFileName   = "secrets.key"
+	fileVersion   = 1
+)
+
+var (
+	// ErrNotFound 条目不存在
+	ErrNotFound = errors.New("secret not found")
+
+	nameRegexp = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,128}$`)
+)
+
+type storeFile struct {
+	Version int               `json:"version"`
+	Secrets map[string]string `json:"secrets"`
+}
+
+// Store 加密密钥库
+type Store struct {
+	mu      sync.RWMutex
+	path    string
+	aead    cipher.AEAD
+	entries map[string]string // 名称 -> base64(nonce+密文)
+}
+
+// ValidateName 校验条目名称
+func ValidateName(name string) error {
+	if !nameRegexp.MatchString(name) {
+		return fmt.Errorf("无效的密钥名称 %q：仅支持字母、数字、下划线、点和连字符", name)
+	}
+	return nil
+}
+
+// Open 打开（或创建）密钥库文件，key 为任意长度的主密钥材料
+func Open(path string, key []byte) (*Store, error) {
+	if len(key) == 0 {
+		return nil, fmt.Errorf("主密钥为空")
+	}
+	sum := sha256.Sum256(key)
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{path: path, aead: aead, entries: make(map[string]string)}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("读取密钥库失败: %w", err)
+	}
+	var f storeFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("解析密钥库失败: %w", err)
+	}
+	if f.Secrets != nil {
+		s.entries = f.Secrets
+	}
+	// 用任意一个条目验证主密钥，避免主密钥错误时静默返回空值
+	for name := range s.entries {
+		if _, err := s.decrypt(name); err != nil {
+			return nil, fmt.Errorf("无法解密密钥库，主密钥可能不匹配: %w", err)
+		}
+		break
+	}
+	return s, nil
+}
+
+// Get 读取条目明文
+func (s *Store) Get(name string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.decrypt(name)
+}
+
+func (s *Store) decrypt(name string) (string, error) {
+	encoded, ok := s.entries[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("密钥 %s 数据损坏: %w", name, err)
+	}
+	nonceSize := s.aead.NonceSize()
+	if len(raw) < nonceSize {
+		return "", fmt.Errorf("密钥 %s 数据损坏", name)
+	}
+	plain, err := s.aead.Open(nil, raw[:nonceSize], raw[nonceSize:], []byte(name))
+	if err != nil {
+		return "", fmt.Errorf("解密密钥 %s 失败: %w", name, err)
+	}
+	return string(plain), nil
+}
+
+// Set 写入条目并持久化
+func (s *Store) Set(name, value string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	sealed := s.aead.Seal(nonce, nonce, []byte(value), []byte(name))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, existed := s.entries[name]
+	s.entries[name] = base64.StdEncoding.EncodeToString(sealed)
+	if err := s.save(); err != nil {
+		if existed {
+			s.entries[name] = old
+		} else {
+			delete(s.entries, name)
+		}
+		return err
+	}
+	return nil
+}
+
+// Delete 删除条目并持久化
+func (s *Store) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.entries[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	delete(s.entries, name)
+	if err := s.save(); err != nil {
+		s.entries[name] = old
+		return err
+	}
+	return nil
+}
+
+// Names 返回所有条目名称（已排序）
+func (s *Store) Names() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]string, 0, len(s.entries))
+	for name := range s.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// save 原子写入密钥库文件。调用方需持有写锁
+func (s *Store) save() error {
+	b, err := json.MarshalIndent(storeFile{Version: fileVersion, Secrets: s.entries}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("创建密钥库目录失败: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return fmt.Errorf("写入密钥库失败: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("写入密钥库失败: %w", err)
+	}
+	return nil
+}
+
+// LoadMasterKey 按优先级读取主密钥，均未配置时在 dataDir 下生成 secrets.key。
+// 返回值 source 描述密钥来源，用于日志。
+func LoadMasterKey(dataDir string) (key []byte, source string, err error) {
+	if v := strings.TrimSpace(os.Getenv(EnvMasterKey)); v != "" {
+		return []byte(v), "环境变量 " + EnvMasterKey, nil
+	}
+	if p := strings.TrimSpace(os.Getenv(EnvMasterKeyFile)); p != "" {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return nil, "", fmt.Errorf("读取主密钥文件失败: %w", err)
+		}
+		if v := strings.TrimSpace(string(b)); v != "" {
+			return []byte(v), p, nil
+		}
+		return nil, "", fmt.Errorf("主密钥文件 %s 为空", p)
+	}
+
+	p := filepath.Join(dataDir, keyFileName)
+	if b, err := os.ReadFile(p); err == nil {
+		if v := strings.TrimSpace(string(b)); v != "" {
+			return []byte(v), p, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, "", fmt.Errorf("读取主密钥文件失败: %w", err)
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", err
+	}
+	v := hex.EncodeToString(raw)
+	if err := os.MkdirAll(dataDir, 0o700); err != nil {
+		return nil, "", fmt.Errorf("创建数据目录失败: %w", err)
+	}
+	if err := os.WriteFile(p, []byte(v+"\n"), 0o600); err != nil {
+		return nil, "", fmt.Errorf("生成主密钥文件失败: %w", err)
+	}
+	return []byte(v), p + "（自动生成）", nil
+}
+
+var (
+	defaultMu    sync.RWMutex
+	defaultStore *Store
+)
+
+// Init 初始化全局密钥库并注册到 configs，使配置中的 "secret://" 引用可以被解析
+func Init(dataDir string) error {
+	key, _, err := LoadMasterKey(dataDir)
+	if err != nil {
+		return err
+	}
+	s, err := Open(filepath.Join(dataDir, storeFileName), key)
+	if err != nil {
+		return err
+	}
+	defaultMu.Lock()
+	defaultStore = s
+	defaultMu.Unlock()
+	configs.SetSecretBackend(s)
+	return nil
+}
+
+// Default 返回全局密钥库，未初始化时返回 nil
+func Default() *Store {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultStore
+}
@@ -0,0 +1,73 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.json")
+	s, err := Open(path, []byte("master"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.Set("bili_cookie", "SESSDATA=abc"))
+	v, err := s.Get("bili_cookie")
+	assert.NoError(t, err)
+	assert.Equal(t, "SESSDATA=abc", v)
+
+	// 文件中不应出现明文
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.False(t, strings.Contains(string(b), "SESSDATA=abc"))
+
+	// 重新打开后可读取
+	s2, err := Open(path, []byte("master"))
+	assert.NoError(t, err)
+	v, err = s2.Get("bili_cookie")
+	assert.NoError(t, err)
+	assert.Equal(t, "SESSDATA=abc", v)
+	assert.Equal(t, []string{"bili_cookie"}, s2.Names())
+
+	assert.NoError(t, s2.Delete("bili_cookie"))
+	_, err = s2.Get("bili_cookie")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestOpenWithWrongKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.json")
+	s, err := Open(path, []byte("master"))
+	assert.NoError(t, err)
+	assert.NoError(t, s.Set("token", "x"))
+
+	_, err = Open(path, []byte("other"))
+	assert.Error(t, err)
+}
+
+func TestInvalidName(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "secrets.json"), []byte("master"))
+	assert.NoError(t, err)
+	assert.Error(t, s.Set("bad name", "x"))
+}
+
+func TestLoadMasterKey(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvMasterKey, "")
+	t.Setenv(EnvMasterKeyFile, "")
+
+	key1, _, err := LoadMasterKey(dir)
+	assert.NoError(t, err)
+	key2, _, err := LoadMasterKey(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, key1, key2, "自动生成的主密钥应被复用")
+
+	t.Setenv(EnvMasterKey, "from-env")
+	key, source, err := LoadMasterKey(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("from-env"), key)
+	assert.Contains(t, source, EnvMasterKey)
+}
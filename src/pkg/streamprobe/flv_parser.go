@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"time"
 
 	"github.com/bluenviron/mediacommon/v2/pkg/codecs/h264"
 	"github.com/bluenviron/mediacommon/v2/pkg/codecs/h265"
@@ -136,6 +137,12 @@ func parseFLVStreamInfo(r io.Reader, maxTags int) (*StreamHeaderInfo, []byte, er
 		}
 		buffered = append(buffered, tagData...)
 
+		// 记录首个音视频 tag 的到达时间，下载器输出的文件以此为时间零点
+		if info.FirstMediaAt.IsZero() && (tagHeader.TagType == flvTagVideo || tagHeader.TagType == flvTagAudio) {
+			info.FirstMediaAt = time.Now()
+			info.FirstMediaTimestamp = tagHeader.Timestamp
+		}
+
 		// 根据 tag 类型解析
 		switch tagHeader.TagType {
 		case flvTagScript:
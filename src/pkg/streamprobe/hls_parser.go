@@ -7,7 +7,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"net"
 	"net/http"
 	"net/url"
 	"strings"
@@ -17,7 +16,6 @@ import (
 	"github.com/bluenviron/mediacommon/v2/pkg/codecs/h265"
 
 	"github.com/bililive-go/bililive-go/src/pkg/livelogger"
-	"github.com/bililive-go/bililive-go/src/pkg/proxy"
 )
 
 // TS (MPEG-2 Transport Stream) 常量
@@ -53,6 +51,7 @@ func probeHLSStreamInfo(
 	m3u8Body io.Reader,
 	m3u8URL *url.URL,
 	headers map[string]string,
+	proxyURL string,
 	logger *livelogger.LiveLogger,
 ) (*StreamHeaderInfo, error) {
 	// 1. 读取 m3u8 内容
@@ -68,7 +67,7 @@ func probeHLSStreamInfo(
 	initURL, err := parseEXTXMap(content, m3u8URL)
 	if err == nil && initURL != "" {
 		// fMP4 格式：下载 init 段并解析 moov box
-		return probeFMP4Init(ctx, initURL, headers, logger)
+		return probeFMP4Init(ctx, initURL, headers, proxyURL, logger)
 	}
 
 	// TS 格式：走原有逻辑
@@ -82,7 +81,7 @@ func probeHLSStreamInfo(
 	}
 
 	// 下载 TS 分段头部
-	tsData, err := downloadSegmentHeader(ctx, segmentURL, headers, tsMaxReadBytes)
+	tsData, err := downloadSegmentHeader(ctx, segmentURL, headers, proxyURL, tsMaxReadBytes)
 	if err != nil {
 		return nil, fmt.Errorf("下载 TS 分段头部失败: %w", err)
 	}
@@ -138,13 +137,13 @@ func parseEXTXMap(content string, baseURL *url.URL) (string, error) {
 }
 
 // probeFMP4Init 下载 fMP4 init 段并解析编解码器信息
-func probeFMP4Init(ctx context.Context, initURL string, headers map[string]string, logger *livelogger.LiveLogger) (*StreamHeaderInfo, error) {
+func probeFMP4Init(ctx context.Context, initURL string, headers map[string]string, proxyURL string, logger *livelogger.LiveLogger) (*StreamHeaderInfo, error) {
 	if logger != nil {
 		logger.Infof("HLS 探测 (fMP4): 下载 init 段: %s", initURL)
 	}
 
 	// init 段通常很小（几百字节到几 KB），完整下载即可
-	initData, err := downloadSegmentHeader(ctx, initURL, headers, 128*1024)
+	initData, err := downloadSegmentHeader(ctx, initURL, headers, proxyURL, 128*1024)
 	if err != nil {
 		return nil, fmt.Errorf("下载 fMP4 init 段失败: %w", err)
 	}
@@ -430,17 +429,9 @@ func parseFirstSegmentURL(content string, baseURL *url.URL) (string, error) {
 }
 
 // downloadSegmentHeader 下载 TS 分段的头部数据
-func downloadSegmentHeader(ctx context.Context, segmentURL string, headers map[string]string, maxBytes int) ([]byte, error) {
-	transport := &http.Transport{
-		DialContext: (&net.Dialer{
-			Timeout:   10 * time.Second,
-			KeepAlive: 30 * time.Second,
-		}).DialContext,
-	}
-	proxy.ApplyDownloadProxyToTransport(transport)
-
+func downloadSegmentHeader(ctx context.Context, segmentURL string, headers map[string]string, proxyURL string, maxBytes int) ([]byte, error) {
 	client := &http.Client{
-		Transport: transport,
+		Transport: newUpstreamTransport(proxyURL),
 		Timeout:   15 * time.Second,
 	}
 
@@ -808,6 +799,11 @@ func extractNALUnits(data []byte) [][]byte {
 // ProbeHLS 独立的 HLS 流探测函数（不经过 StreamProbe 代理）
 // 流程：下载 m3u8 → 解析第一个 TS 分段 URL → 下载 TS 头部 → 解析 SPS
 func ProbeHLS(ctx context.Context, m3u8URL *url.URL, headers map[string]string, logger *livelogger.LiveLogger) (*StreamHeaderInfo, error) {
+	return ProbeHLSWithProxy(ctx, m3u8URL, headers, "", logger)
+}
+
+// ProbeHLSWithProxy 同 ProbeHLS，使用指定的下载代理（为空时使用全局下载代理）
+func ProbeHLSWithProxy(ctx context.Context, m3u8URL *url.URL, headers map[string]string, proxyURL string, logger *livelogger.LiveLogger) (*StreamHeaderInfo, error) {
 	if logger != nil {
 		// 只打印 header 键名列表，避免泄露 Cookie/Authorization 等敏感信息到日志
 		headerKeys := make([]string, 0, len(headers))
@@ -817,16 +813,8 @@ func ProbeHLS(ctx context.Context, m3u8URL *url.URL, headers map[string]string,
 		logger.Debugf("HLS 探测开始: URL=%s, HeaderKeys=%v", m3u8URL.String(), headerKeys)
 	}
 	// 1. 下载 m3u8 内容
-	transport := &http.Transport{
-		DialContext: (&net.Dialer{
-			Timeout:   10 * time.Second,
-			KeepAlive: 30 * time.Second,
-		}).DialContext,
-	}
-	proxy.ApplyDownloadProxyToTransport(transport)
-
 	client := &http.Client{
-		Transport: transport,
+		Transport: newUpstreamTransport(proxyURL),
 		Timeout:   15 * time.Second,
 	}
 
@@ -855,5 +843,5 @@ func ProbeHLS(ctx context.Context, m3u8URL *url.URL, headers map[string]string,
 	}
 
 	// 2. 解析并探测
-	return probeHLSStreamInfo(ctxTimeout, resp.Body, m3u8URL, headers, logger)
+	return probeHLSStreamInfo(ctxTimeout, resp.Body, m3u8URL, headers, proxyURL, logger)
 }
@@ -3,7 +3,10 @@
 // 替代旧的 flvproxy 包，同时支持 SPS/PPS 变化分段检测
 package streamprobe
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // StreamHeaderInfo 包含从直播流头部解析出的实际信息
 type StreamHeaderInfo struct {
@@ -18,6 +21,10 @@ type StreamHeaderInfo struct {
 	AudioCodec   string `json:"audio_codec"`   // "aac", "mp3", "opus", "unknown"
 	AudioBitrate int    `json:"audio_bitrate"` // 音频码率 (kbps)
 
+	// 时间轴信息：录制文件从首个音视频 tag 开始，弹幕等外部时间轴据此对齐
+	FirstMediaTimestamp uint32    `json:"first_media_timestamp"` // 首个音视频 tag 的流内时间戳（毫秒）
+	FirstMediaAt        time.Time `json:"-"`                     // 收到首个音视频 tag 的本地时间，零值表示未知
+
 	// 解析来源和状态
 	ParsedFromSPS  bool `json:"parsed_from_sps"`  // 分辨率是否从 SPS 解析（最可靠）
 	ParsedFromMeta bool `json:"parsed_from_meta"` // 分辨率是否从 onMetaData 解析
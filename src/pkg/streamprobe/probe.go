@@ -30,6 +30,9 @@ type Config struct {
 	// Headers 下载用 HTTP headers（如 Cookie、Referer 等）
 	Headers map[string]string
 
+	// ProxyURL 连接上游使用的代理（按直播间解析，为空时使用全局下载代理）
+	ProxyURL string
+
 	// OnProbed 探测完成回调
 	// 在成功解析到流信息后调用
 	OnProbed func(info *StreamHeaderInfo)
@@ -38,6 +41,10 @@ type Config struct {
 	// 仅用于日志记录，不应阻止录制
 	OnProbeError func(err error, msg string)
 
+	// Tee 同步接收转发给下载器的全部数据（如本地预览转发），可为 nil。
+	// 在转发路径上调用，写入不应阻塞
+	Tee io.Writer
+
 	// Logger 日志记录器
 	Logger *livelogger.LiveLogger
 }
@@ -127,19 +134,26 @@ func (p *StreamProbe) GetHeaderInfo() *StreamHeaderInfo {
 	return p.headerInfo.Load()
 }
 
-// connectUpstream 连接上游直播流
-func (p *StreamProbe) connectUpstream() error {
-	// 创建带下载代理的 HTTP 客户端
+// newUpstreamTransport 创建连接上游用的 Transport，proxyURL 为空时使用全局下载代理
+func newUpstreamTransport(proxyURL string) http.RoundTripper {
 	transport := &http.Transport{
 		DialContext: (&net.Dialer{
 			Timeout:   10 * time.Second,
 			KeepAlive: 30 * time.Second,
 		}).DialContext,
 	}
+	if proxyURL != "" {
+		return proxy.WrapDownloadTransport(transport, proxyURL)
+	}
 	proxy.ApplyDownloadProxyToTransport(transport)
+	return transport
+}
 
+// connectUpstream 连接上游直播流
+func (p *StreamProbe) connectUpstream() error {
+	// 创建带下载代理的 HTTP 客户端
 	client := &http.Client{
-		Transport: transport,
+		Transport: newUpstreamTransport(p.config.ProxyURL),
 		Timeout:   0, // 不设超时，直播流是持久连接
 	}
 
@@ -318,6 +332,7 @@ func (p *StreamProbe) handleStreamRequest(w http.ResponseWriter, r *http.Request
 		if _, err := w.Write(buffered); err != nil {
 			return
 		}
+		p.tee(buffered)
 		if hasFlusher {
 			flusher.Flush()
 		}
@@ -343,6 +358,7 @@ func (p *StreamProbe) handleStreamRequest(w http.ResponseWriter, r *http.Request
 			if _, writeErr := w.Write(buf[:n]); writeErr != nil {
 				return
 			}
+			p.tee(buf[:n])
 			if hasFlusher {
 				flusher.Flush()
 			}
@@ -356,6 +372,13 @@ func (p *StreamProbe) handleStreamRequest(w http.ResponseWriter, r *http.Request
 	}
 }
 
+// tee 将转发的数据写入 Config.Tee
+func (p *StreamProbe) tee(data []byte) {
+	if p.config.Tee != nil {
+		p.config.Tee.Write(data)
+	}
+}
+
 // cleanup 清理资源
 func (p *StreamProbe) cleanup() {
 	if p.server != nil {
@@ -252,13 +252,7 @@ func newProductionTransport() *http.Transport {
 }
 
 func CreateDefaultClient() *http.Client {
-	dialer := &net.Dialer{
-		Timeout: 10 * time.Second,
-	}
-
-	transport := newProductionTransport()
-	transport.DialContext = dialer.DialContext
-	transport.DialTLSContext = createTLSDialer(dialer, false, "")
+	transport := newDefaultTransport()
 
 	// 应用信息获取代理（这些客户端主要用于获取直播间信息等 API 请求）
 	proxy.ApplyInfoProxyToTransport(transport)
@@ -266,7 +260,13 @@ func CreateDefaultClient() *http.Client {
 	return &http.Client{Transport: transport}
 }
 
-func CreateDownloadClient() *http.Client {
+// CreateDefaultClientForRoom 创建使用直播间代理配置的信息获取客户端，
+// 每次请求时重新解析代理，配置变更与代理池故障切换无需重建客户端
+func CreateDefaultClientForRoom(roomURL string) *http.Client {
+	return &http.Client{Transport: proxy.WrapInfoTransportForRoom(newDefaultTransport(), roomURL)}
+}
+
+func newDefaultTransport() *http.Transport {
 	dialer := &net.Dialer{
 		Timeout: 10 * time.Second,
 	}
@@ -274,13 +274,23 @@ func CreateDownloadClient() *http.Client {
 	transport := newProductionTransport()
 	transport.DialContext = dialer.DialContext
 	transport.DialTLSContext = createTLSDialer(dialer, false, "")
+	return transport
+}
+
+func CreateDownloadClient() *http.Client {
+	transport := newDefaultTransport()
 
 	proxy.ApplyDownloadProxyToTransport(transport)
 
 	return &http.Client{Transport: transport}
 }
 
-func CreateConnCounterClient() (*http.Client, error) {
+// CreateDownloadClientWithProxy 创建使用指定下载代理的客户端，proxyURL 为空时使用环境变量
+func CreateDownloadClientWithProxy(proxyURL string) *http.Client {
+	return &http.Client{Transport: proxy.WrapDownloadTransport(newDefaultTransport(), proxyURL)}
+}
+
+func newConnCounterTransport() *http.Transport {
 	dialer := &net.Dialer{
 		Timeout: 10 * time.Second,
 	}
@@ -303,9 +313,19 @@ func CreateConnCounterClient() (*http.Client, error) {
 	transport.DialContext = dialPlain
 	// Use "tls:" prefix to distinguish from plain connections
 	transport.DialTLSContext = createTLSDialer(dialer, true, "tls:")
+	return transport
+}
+
+func CreateConnCounterClient() (*http.Client, error) {
+	transport := newConnCounterTransport()
 
 	// 应用信息获取代理（这些客户端主要用于获取直播间信息等 API 请求）
 	proxy.ApplyInfoProxyToTransport(transport)
 
 	return &http.Client{Transport: transport}, nil
 }
+
+// CreateConnCounterClientForRoom 同 CreateConnCounterClient，使用直播间的代理配置
+func CreateConnCounterClientForRoom(roomURL string) *http.Client {
+	return &http.Client{Transport: proxy.WrapInfoTransportForRoom(newConnCounterTransport(), roomURL)}
+}
@@ -0,0 +1,383 @@
+// Package acfun 实现 AcFun 直播弹幕客户端。
+// 以游客身份登录后通过 kwaizt 长连接进房，评论通过 ZtLiveScActionSignal 推送
+package acfun
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/internal/pbutil"
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/wsclient"
+)
+
+const (
+	visitorLoginURL = "https://id.app.acfun.cn/rest/app/visitor/login"
+	startPlayURL    = "https://api.kuaishouzt.com/rest/zt/live/web/startPlay"
+	wsURL           = "wss://klink-newproduct-ws3.kwaizt.com/"
+
+	heartbeatInterval = 10 * time.Second
+	// 每 5 次心跳附带一次 KeepAlive
+	keepAliveEvery = 5
+
+	appID                = 13
+	appName              = "link-sdk"
+	sdkVersion           = "1.2.1"
+	kpn                  = "ACFUN_APP"
+	kpf                  = "PC_WEB"
+	subBiz               = "mainApp"
+	clientLiveSdkVersion = "kwai-acfun-live-link"
+
+	compressionGzip = 2
+)
+
+// UpstreamPayload/DownstreamPayload.command
+const (
+	cmdRegister    = "Basic.Register"
+	cmdKeepAlive   = "Basic.KeepAlive"
+	cmdCsCmd       = "Global.ZtLiveInteractive.CsCmd"
+	cmdPushMessage = "Push.ZtLiveInteractive.Message"
+)
+
+// session 一次连接的登录状态
+type session struct {
+	uid             uint64
+	serviceToken    string
+	securityKey     []byte
+	sessionKey      []byte
+	instanceID      uint64
+	liveID          string
+	ticket          string
+	enterRoomAttach string
+	seqID           uint64
+	heartbeatSeq    uint64
+}
+
+type protocol struct {
+	authorID  string
+	cookies   string
+	onDanmaku func(username, content string, color int)
+	logger    *logrus.Entry
+
+	mu sync.Mutex
+	s  *session
+	// ticketIndex 当前使用的进房票据序号，票据失效时切换到下一个
+	ticketIndex int
+}
+
+// NewAcfunClient 创建 AcFun 弹幕客户端，authorID 为主播 UID（直播间地址 /live/ 之后的部分）
+func NewAcfunClient(authorID, cookies string, onDanmaku func(username, content string, color int), logger *logrus.Entry) *wsclient.Client {
+	return wsclient.New(&protocol{
+		authorID:  authorID,
+		cookies:   cookies,
+		onDanmaku: onDanmaku,
+		logger:    logger,
+	}, logger)
+}
+
+func (p *protocol) Name() string { return "AcFun" }
+
+func (p *protocol) HeartbeatInterval() time.Duration { return heartbeatInterval }
+
+func (p *protocol) MessageType() int { return websocket.BinaryMessage }
+
+func (p *protocol) Heartbeat() ([][]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := p.s
+	if s == nil {
+		return nil, fmt.Errorf("尚未登录")
+	}
+	s.heartbeatSeq++
+	heartbeat := encodeCsCmd("ZtLiveCsHeartbeat", encodeHeartbeat(uint64(time.Now().UnixMilli()), s.heartbeatSeq), s.ticket, s.liveID)
+	frame, err := s.frame(cmdCsCmd, heartbeat)
+	if err != nil {
+		return nil, err
+	}
+	frames := [][]byte{frame}
+	if s.heartbeatSeq%keepAliveEvery == 0 {
+		keepAlive, err := s.frame(cmdKeepAlive, encodeKeepAlive())
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, keepAlive)
+	}
+	return frames, nil
+}
+
+// frame 使用会话密钥编码一条上行消息
+func (s *session) frame(command string, data []byte) ([]byte, error) {
+	s.seqID++
+	header := &packetHeader{
+		appID:          appID,
+		uid:            s.uid,
+		instanceID:     s.instanceID,
+		encryptionMode: encryptionSessionKey,
+		seqID:          s.seqID,
+		kpn:            kpn,
+	}
+	return encodeFrame(header, encodeUpstream(command, s.seqID, data), s.sessionKey)
+}
+
+func (s *session) keyFor(mode uint64) []byte {
+	if mode == encryptionServiceToken {
+		return s.securityKey
+	}
+	return s.sessionKey
+}
+
+func (p *protocol) Connect(ctx context.Context) (*websocket.Conn, error) {
+	s, err := p.login(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.logger.Infof("连接 AcFun 弹幕服务器: authorID=%s liveID=%s", p.authorID, s.liveID)
+
+	conn, err := wsclient.Dial(ctx, wsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := register(conn, s); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	keepAlive, err := s.frame(cmdKeepAlive, encodeKeepAlive())
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	enterRoom, err := s.frame(cmdCsCmd, encodeCsCmd("ZtLiveCsEnterRoom", encodeEnterRoom(s.enterRoomAttach), s.ticket, s.liveID))
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	for _, frame := range [][]byte{keepAlive, enterRoom} {
+		if err := conn.WriteMessage(websocket.BinaryMessage, frame); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("进房失败: %w", err)
+		}
+	}
+
+	p.mu.Lock()
+	p.s = s
+	p.mu.Unlock()
+	return conn, nil
+}
+
+// register 发送 Basic.Register 并等待响应，获取会话密钥
+func register(conn *websocket.Conn, s *session) error {
+	s.seqID++
+	header := &packetHeader{
+		appID:          appID,
+		uid:            s.uid,
+		encryptionMode: encryptionServiceToken,
+		serviceToken:   s.serviceToken,
+		seqID:          s.seqID,
+		kpn:            kpn,
+	}
+	frame, err := encodeFrame(header, encodeUpstream(cmdRegister, s.seqID, encodeRegister(s.uid)), s.securityKey)
+	if err != nil {
+		return err
+	}
+	if err := conn.WriteMessage(websocket.BinaryMessage, frame); err != nil {
+		return fmt.Errorf("发送注册消息失败: %w", err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	defer conn.SetReadDeadline(time.Time{})
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("读取注册响应失败: %w", err)
+		}
+		_, payload, err := decodeFrame(data, s.keyFor)
+		if err != nil {
+			return fmt.Errorf("解析注册响应失败: %w", err)
+		}
+		downstream, err := pbutil.Decode(payload)
+		if err != nil {
+			return fmt.Errorf("解析注册响应失败: %w", err)
+		}
+		if downstream.String(1) != cmdRegister {
+			continue
+		}
+		// DownstreamPayload: command(1) errorCode(3) payloadData(4) errorMsg(5)
+		if code := downstream.Uint(3); code != 0 {
+			return fmt.Errorf("注册失败: code=%d msg=%s", code, downstream.String(5))
+		}
+		// RegisterResponse: sessKey(2) instanceId(3)
+		resp, err := downstream.Message(4)
+		if err != nil {
+			return fmt.Errorf("解析注册响应失败: %w", err)
+		}
+		s.sessionKey = resp.Bytes(2)
+		s.instanceID = resp.Uint(3)
+		if len(s.sessionKey) == 0 {
+			return fmt.Errorf("注册响应中没有会话密钥")
+		}
+		return nil
+	}
+}
+
+// login 游客登录并获取直播 ID 与进房票据
+func (p *protocol) login(ctx context.Context) (*session, error) {
+	did := cookieValue(p.cookies, "_did")
+	if did == "" {
+		buf := make([]byte, 8)
+		rand.Read(buf)
+		did = "web_" + strings.ToUpper(hex.EncodeToString(buf))
+	}
+
+	visitor, err := p.post(ctx, visitorLoginURL, url.Values{"sid": {"acfun.api.visitor"}}, did)
+	if err != nil {
+		return nil, fmt.Errorf("游客登录失败: %w", err)
+	}
+	if code := visitor.Get("result").Int(); code != 0 {
+		return nil, fmt.Errorf("游客登录失败: result=%d", code)
+	}
+	s := &session{
+		uid:          uint64(visitor.Get("userId").Int()),
+		serviceToken: visitor.Get(`acfun\.api\.visitor_st`).String(),
+	}
+	if s.securityKey, err = base64.StdEncoding.DecodeString(visitor.Get("acSecurity").String()); err != nil || len(s.securityKey) == 0 {
+		return nil, fmt.Errorf("游客登录未返回有效的 acSecurity")
+	}
+
+	query := url.Values{
+		"subBiz":               {subBiz},
+		"kpn":                  {kpn},
+		"kpf":                  {kpf},
+		"userId":               {fmt.Sprint(s.uid)},
+		"did":                  {did},
+		"acfun.api.visitor_st": {s.serviceToken},
+	}
+	play, err := p.post(ctx, startPlayURL+"?"+query.Encode(), url.Values{"authorId": {p.authorID}, "pullStreamType": {"FLV"}}, did)
+	if err != nil {
+		return nil, fmt.Errorf("获取直播信息失败: %w", err)
+	}
+	if code := play.Get("result").Int(); code != 1 {
+		return nil, fmt.Errorf("获取直播信息失败，主播可能未开播: result=%d %s", code, play.Get("error_msg").String())
+	}
+	s.liveID = play.Get("data.liveId").String()
+	s.enterRoomAttach = play.Get("data.enterRoomAttach").String()
+	tickets := play.Get("data.availableTickets").Array()
+	if s.liveID == "" || len(tickets) == 0 {
+		return nil, fmt.Errorf("接口未返回进房票据")
+	}
+	p.mu.Lock()
+	s.ticket = tickets[p.ticketIndex%len(tickets)].String()
+	p.mu.Unlock()
+	return s, nil
+}
+
+func (p *protocol) post(ctx context.Context, rawURL string, form url.Values, did string) (*gjson.Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", wsclient.UserAgent)
+	req.Header.Set("Referer", "https://live.acfun.cn/")
+	req.Header.Set("Cookie", "_did="+did)
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	result := gjson.ParseBytes(body)
+	return &result, nil
+}
+
+func cookieValue(cookies, name string) string {
+	for _, part := range strings.Split(cookies, ";") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(part), "="); ok && k == name {
+			return v
+		}
+	}
+	return ""
+}
+
+func (p *protocol) Handle(data []byte, send func([]byte) error) error {
+	p.mu.Lock()
+	s := p.s
+	p.mu.Unlock()
+	if s == nil {
+		return nil
+	}
+	header, payload, err := decodeFrame(data, s.keyFor)
+	if err != nil {
+		p.logger.WithError(err).Debug("解析 AcFun 消息失败")
+		return nil
+	}
+	downstream, err := pbutil.Decode(payload)
+	if err != nil {
+		p.logger.WithError(err).Debug("解析 AcFun 消息失败")
+		return nil
+	}
+
+	switch downstream.String(1) {
+	case cmdCsCmd:
+		// ZtLiveCsCmdAck: cmdAckType(1) errorCode(2) errorMsg(3)
+		ack, err := downstream.Message(4)
+		if err == nil && ack.Uint(2) != 0 {
+			return fmt.Errorf("%s 失败: code=%d msg=%s", ack.String(1), ack.Uint(2), ack.String(3))
+		}
+	case cmdPushMessage:
+		// 推送消息需要按原 seqId 回复确认
+		p.mu.Lock()
+		ackFrame, err := encodeFrame(&packetHeader{
+			appID:          appID,
+			uid:            s.uid,
+			instanceID:     s.instanceID,
+			encryptionMode: encryptionSessionKey,
+			seqID:          header.Uint(10),
+			kpn:            kpn,
+		}, encodeUpstream(cmdPushMessage, header.Uint(10), nil), s.sessionKey)
+		p.mu.Unlock()
+		if err == nil {
+			if err := send(ackFrame); err != nil {
+				return err
+			}
+		}
+
+		messageType, comments, err := decodeScMessage(downstream.Bytes(4))
+		if err != nil {
+			p.logger.WithError(err).Debug("解析 AcFun 弹幕失败")
+			return nil
+		}
+		switch messageType {
+		case "ZtLiveScTicketInvalid":
+			p.mu.Lock()
+			p.ticketIndex++
+			p.mu.Unlock()
+			return fmt.Errorf("进房票据失效")
+		case "ZtLiveScStatusChanged":
+			p.logger.Debug("AcFun 直播状态变化")
+		}
+		if p.onDanmaku != nil {
+			for _, c := range comments {
+				p.onDanmaku(c.username, c.content, wsclient.DefaultColor)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package acfun
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/internal/danmakutest"
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/internal/pbutil"
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/wsclient"
+)
+
+// 用测试会话密钥加密构造的 Push.ZtLiveInteractive.Message 帧，
+// 负载为 gzip 压缩的 ZtLiveScActionSignal，包含两条评论与一条点赞
+const (
+	testSessionKey = "acfun-session-k!"
+	pushFrame      = "abcd00010000001900000100080d10e807180738ef014002502a6209414346554e5f415050032d71d08c4b13bc26a85a0634689ee7baa0a24c704c372a236fd5c563851bb193d3c519ca5ea4aec467564ebe012db588bc2e1924944d61e4c573e715d8e19b45d2fb7f7aba79fd615864b1434f4b663cf3b6d5e3b79a9796cd8e109742d8e03fe29f237cf0dbb62be2898fd8434db080cc0f4969651d085368d5cf7224ca77b75d7a15eeaf3dde6747dccb6cdb07d70b6a8e86f9aa3fcd2ecdd34d323ffd80e04241ad9ec39a6a4464f04bc7ce012e3e00ad4747ba1465eadcb393608b66ed3e57d2aa7329cc583f421e658774a5645877ccfe0531a548a6e107ae9ebf44ee3dd282af0739113844dabfb82020c4a4763c95e58b5f3df97e3b671dc5eaeec8"
+)
+
+func TestHandlePush(t *testing.T) {
+	var c danmakutest.Collector
+	var acks [][]byte
+	s := &session{uid: 1000, instanceID: 7, sessionKey: []byte(testSessionKey)}
+	p := &protocol{onDanmaku: c.OnDanmaku, logger: danmakutest.Logger(), s: s}
+	data := danmakutest.Hex(t, pushFrame)
+	assert.NoError(t, p.Handle(data, func(b []byte) error {
+		acks = append(acks, b)
+		return nil
+	}))
+	assert.Equal(t, []danmakutest.Chat{
+		{User: "AC娘", Content: "主播晚上好", Color: wsclient.DefaultColor},
+		{User: "香蕉君", Content: "233333", Color: wsclient.DefaultColor},
+	}, c.Chats)
+
+	// 推送消息按原 seqId 回复确认
+	if assert.Len(t, acks, 1) {
+		header, payload, err := decodeFrame(acks[0], s.keyFor)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(42), header.Uint(10))
+		upstream, err := pbutil.Decode(payload)
+		assert.NoError(t, err)
+		assert.Equal(t, cmdPushMessage, upstream.String(1))
+		assert.Equal(t, uint64(42), upstream.Uint(2))
+	}
+
+	// 使用错误的密钥时忽略该帧
+	p.s = &session{sessionKey: []byte("0123456789abcdef")}
+	c.Chats = nil
+	assert.NoError(t, p.Handle(data, func([]byte) error { return nil }))
+	assert.Empty(t, c.Chats)
+}
+
+func TestFrameRoundTrip(t *testing.T) {
+	s := &session{uid: 1, securityKey: []byte("security-key-16b"), sessionKey: []byte("session-key-16b!")}
+	frame, err := s.frame(cmdKeepAlive, encodeKeepAlive())
+	assert.NoError(t, err)
+	header, payload, err := decodeFrame(frame, s.keyFor)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(encryptionSessionKey), header.Uint(8))
+	assert.Equal(t, uint64(len(payload)), header.Uint(7))
+	upstream, err := pbutil.Decode(payload)
+	assert.NoError(t, err)
+	assert.Equal(t, cmdKeepAlive, upstream.String(1))
+	assert.Equal(t, subBiz, upstream.String(10))
+}
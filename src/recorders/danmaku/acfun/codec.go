@@ -0,0 +1,243 @@
+package acfun
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/internal/pbutil"
+)
+
+// 帧格式：magic(4) + 头部长度(4) + 负载长度(4) + PacketHeader + 负载。
+// 负载为 AES-CBC 加密的 UpstreamPayload/DownstreamPayload，前 16 字节为 IV
+const (
+	frameMagic     = 0xABCD0001
+	frameHeaderLen = 12
+)
+
+// PacketHeader.encryptionMode
+const (
+	encryptionNone         = 0
+	encryptionServiceToken = 1
+	encryptionSessionKey   = 2
+)
+
+// packetHeader PacketHeader 中用到的字段
+type packetHeader struct {
+	appID             uint64 // 1
+	uid               uint64 // 2
+	instanceID        uint64 // 3
+	decodedPayloadLen uint64 // 7
+	encryptionMode    uint64 // 8
+	serviceToken      string // 9: TokenInfo{tokenType(1)=kServiceToken, token(2)}
+	seqID             uint64 // 10
+	kpn               string // 12
+}
+
+func (h *packetHeader) marshal() []byte {
+	b := pbutil.AppendVarint(nil, 1, h.appID)
+	b = pbutil.AppendVarint(b, 2, h.uid)
+	b = pbutil.AppendVarint(b, 3, h.instanceID)
+	b = pbutil.AppendVarint(b, 7, h.decodedPayloadLen)
+	b = pbutil.AppendVarint(b, 8, h.encryptionMode)
+	if h.serviceToken != "" {
+		token := pbutil.AppendVarint(nil, 1, 1)
+		token = pbutil.AppendString(token, 2, h.serviceToken)
+		b = pbutil.AppendBytes(b, 9, token)
+	}
+	b = pbutil.AppendVarint(b, 10, h.seqID)
+	return pbutil.AppendString(b, 12, h.kpn)
+}
+
+// encodeFrame 加密负载并组装成帧
+func encodeFrame(header *packetHeader, payload, key []byte) ([]byte, error) {
+	header.decodedPayloadLen = uint64(len(payload))
+	body := payload
+	if header.encryptionMode != encryptionNone {
+		var err error
+		if body, err = encrypt(key, payload); err != nil {
+			return nil, err
+		}
+	}
+	h := header.marshal()
+	frame := make([]byte, frameHeaderLen, frameHeaderLen+len(h)+len(body))
+	binary.BigEndian.PutUint32(frame[0:4], frameMagic)
+	binary.BigEndian.PutUint32(frame[4:8], uint32(len(h)))
+	binary.BigEndian.PutUint32(frame[8:12], uint32(len(body)))
+	frame = append(frame, h...)
+	return append(frame, body...), nil
+}
+
+// decodeFrame 解析帧并解密负载，keyFor 根据加密方式返回密钥
+func decodeFrame(frame []byte, keyFor func(mode uint64) []byte) (header pbutil.Message, payload []byte, err error) {
+	if len(frame) < frameHeaderLen || binary.BigEndian.Uint32(frame[0:4]) != frameMagic {
+		return nil, nil, fmt.Errorf("无效的帧")
+	}
+	headerLen := int(binary.BigEndian.Uint32(frame[4:8]))
+	payloadLen := int(binary.BigEndian.Uint32(frame[8:12]))
+	if frameHeaderLen+headerLen+payloadLen > len(frame) {
+		return nil, nil, fmt.Errorf("帧长度不足")
+	}
+	header, err = pbutil.Decode(frame[frameHeaderLen : frameHeaderLen+headerLen])
+	if err != nil {
+		return nil, nil, err
+	}
+	payload = frame[frameHeaderLen+headerLen : frameHeaderLen+headerLen+payloadLen]
+	if mode := header.Uint(8); mode != encryptionNone {
+		if payload, err = decrypt(keyFor(mode), payload); err != nil {
+			return nil, nil, err
+		}
+	}
+	return header, payload, nil
+}
+
+func encrypt(key, plain []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	padding := aes.BlockSize - len(plain)%aes.BlockSize
+	padded := append(append([]byte(nil), plain...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	out := make([]byte, aes.BlockSize+len(padded))
+	iv := out[:aes.BlockSize]
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, err
+	}
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(out[aes.BlockSize:], padded)
+	return out, nil
+}
+
+func decrypt(key, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 2*aes.BlockSize || len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("密文长度无效")
+	}
+	plain := make([]byte, len(data)-aes.BlockSize)
+	cipher.NewCBCDecrypter(block, data[:aes.BlockSize]).CryptBlocks(plain, data[aes.BlockSize:])
+	padding := int(plain[len(plain)-1])
+	if padding == 0 || padding > aes.BlockSize {
+		return nil, fmt.Errorf("填充无效")
+	}
+	return plain[:len(plain)-padding], nil
+}
+
+// encodeUpstream 编码 UpstreamPayload: command(1) seqId(2) retryCount(3) payloadData(4) subBiz(10)
+func encodeUpstream(command string, seqID uint64, data []byte) []byte {
+	b := pbutil.AppendString(nil, 1, command)
+	b = pbutil.AppendVarint(b, 2, seqID)
+	b = pbutil.AppendVarint(b, 3, 1)
+	b = pbutil.AppendBytes(b, 4, data)
+	return pbutil.AppendString(b, 10, subBiz)
+}
+
+// encodeRegister 编码 RegisterRequest
+func encodeRegister(uid uint64) []byte {
+	// AppInfo: appName(1) sdkVersion(4)
+	appInfo := pbutil.AppendString(nil, 1, appName)
+	appInfo = pbutil.AppendString(appInfo, 4, sdkVersion)
+	// DeviceInfo: platformType(1)=H5_WINDOWS deviceModel(3)
+	deviceInfo := pbutil.AppendVarint(nil, 1, 6)
+	deviceInfo = pbutil.AppendString(deviceInfo, 3, "h5")
+	// ZtCommonInfo: kpn(1) kpf(2) uid(8)
+	common := pbutil.AppendString(nil, 1, kpn)
+	common = pbutil.AppendString(common, 2, kpf)
+	common = pbutil.AppendVarint(common, 8, uid)
+
+	b := pbutil.AppendBytes(nil, 1, appInfo)
+	b = pbutil.AppendBytes(b, 2, deviceInfo)
+	b = pbutil.AppendVarint(b, 4, 1) // presenceStatus = kPresenceOnline
+	b = pbutil.AppendVarint(b, 5, 1) // appActiveStatus = kAppInForeground
+	return pbutil.AppendBytes(b, 11, common)
+}
+
+// encodeKeepAlive 编码 KeepAliveRequest: presenceStatus(1) appActiveStatus(2)
+func encodeKeepAlive() []byte {
+	b := pbutil.AppendVarint(nil, 1, 1)
+	return pbutil.AppendVarint(b, 2, 1)
+}
+
+// encodeCsCmd 编码 ZtLiveCsCmd: cmdType(1) payload(2) ticket(3) liveId(4)
+func encodeCsCmd(cmdType string, payload []byte, ticket, liveID string) []byte {
+	b := pbutil.AppendString(nil, 1, cmdType)
+	b = pbutil.AppendBytes(b, 2, payload)
+	b = pbutil.AppendString(b, 3, ticket)
+	return pbutil.AppendString(b, 4, liveID)
+}
+
+// encodeEnterRoom 编码 ZtLiveCsEnterRoom: enterRoomAttach(4) clientLiveSdkVersion(5)
+func encodeEnterRoom(enterRoomAttach string) []byte {
+	b := pbutil.AppendString(nil, 4, enterRoomAttach)
+	return pbutil.AppendString(b, 5, clientLiveSdkVersion)
+}
+
+// encodeHeartbeat 编码 ZtLiveCsHeartbeat: clientTimestampMs(1) sequence(2)
+func encodeHeartbeat(timestampMs, sequence uint64) []byte {
+	b := pbutil.AppendVarint(nil, 1, timestampMs)
+	return pbutil.AppendVarint(b, 2, sequence)
+}
+
+type comment struct {
+	username string
+	content  string
+}
+
+// decodeScMessage 解析 ZtLiveScMessage: messageType(1) compressionType(2) payload(3)，
+// 返回其中 ZtLiveScActionSignal 携带的评论
+func decodeScMessage(data []byte) (messageType string, comments []comment, err error) {
+	msg, err := pbutil.Decode(data)
+	if err != nil {
+		return "", nil, err
+	}
+	messageType = msg.String(1)
+	if messageType != "ZtLiveScActionSignal" {
+		return messageType, nil, nil
+	}
+	payload := msg.Bytes(3)
+	if msg.Uint(2) == compressionGzip {
+		r, err := gzip.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			return messageType, nil, err
+		}
+		defer r.Close()
+		if payload, err = io.ReadAll(r); err != nil {
+			return messageType, nil, err
+		}
+	}
+	// ZtLiveScActionSignal: item(1)，ZtLiveScActionSignalItem: signalType(1) payload(2 repeated)
+	signal, err := pbutil.Decode(payload)
+	if err != nil {
+		return messageType, nil, err
+	}
+	for _, rawItem := range signal.Repeated(1) {
+		item, err := pbutil.Decode(rawItem)
+		if err != nil {
+			return messageType, nil, err
+		}
+		if item.String(1) != "CommonActionSignalComment" {
+			continue
+		}
+		for _, raw := range item.Repeated(2) {
+			// CommonActionSignalComment: content(1) sendTimeMs(2) userInfo(3).nickname(2)
+			c, err := pbutil.Decode(raw)
+			if err != nil {
+				return messageType, nil, err
+			}
+			user, err := c.Message(3)
+			if err != nil {
+				return messageType, nil, err
+			}
+			if name, content := user.String(2), c.String(1); name != "" && content != "" {
+				comments = append(comments, comment{username: name, content: content})
+			}
+		}
+	}
+	return messageType, comments, nil
+}
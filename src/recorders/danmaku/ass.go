@@ -1,6 +1,7 @@
 package danmaku
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
@@ -11,6 +12,9 @@ import (
 	"github.com/bililive-go/bililive-go/src/configs"
 )
 
+// ErrCompanionRotate 附属字幕（SRT/WebVTT）切换文件失败，此时 ASS 文件已正常切换
+var ErrCompanionRotate = errors.New("failed to rotate companion subtitle")
+
 // AssWriter writes danmaku entries to an ASS subtitle file.
 type AssWriter struct {
 	mu           sync.Mutex
@@ -28,7 +32,8 @@ type AssWriter struct {
 	laneEnd      int // last usable lane index (exclusive)
 	laneNum      int // total lanes in the usable range
 	nextLane     int
-	laneLast     []int64 // last tail-clear time (centiseconds) per lane
+	laneLast     []int64               // last tail-clear time (centiseconds) per lane
+	companions   []*TextSubtitleWriter // 按 subtitle_formats 同步写入的 SRT/WebVTT 字幕
 }
 
 func parseResolution(res string) (int, int) {
@@ -102,9 +107,29 @@ func NewAssWriter(filePath string, startAt time.Time, cfg configs.DanmakuConfig,
 		f.Close()
 		return nil, err
 	}
+	for _, format := range cfg.SubtitleFormats {
+		c, err := NewTextSubtitleWriter(companionPath(filePath, format), startAt, format)
+		if err != nil {
+			w.Close()
+			return nil, err
+		}
+		w.companions = append(w.companions, c)
+	}
 	return w, nil
 }
 
+// companionPath 返回与 ASS 文件同名的附加字幕文件路径
+func companionPath(assPath, format string) string {
+	return strings.TrimSuffix(assPath, ".ass") + "." + format
+}
+
+// addChatLine 将消息同步写入附加字幕，调用方需持有 w.mu
+func (w *AssWriter) addChatLine(recvAt time.Time, text string, hold time.Duration) {
+	for _, c := range w.companions {
+		c.AddLine(recvAt, text, hold)
+	}
+}
+
 // scTierColor 返回 SC 价位对应的 ASS 背景色 (B站原始配色)
 func scTierColor(price int) string {
 	switch {
@@ -246,6 +271,7 @@ func (w *AssWriter) AddDanmaku(recvAt time.Time, username, text string, color in
 	}
 
 	fullText := username + ": " + text
+	w.addChatLine(recvAt, fullText, chatHoldDanmaku)
 	textWidth := w.estimateTextWidth(fullText)
 	totalDistance := w.resX + textWidth
 	// 使用 scrollTimeMs 精确计算，避免 bannerSpeed 整数截断
@@ -299,6 +325,7 @@ func (w *AssWriter) AddGift(recvAt time.Time, username, giftName string, num int
 	} else {
 		fullText = fmt.Sprintf("%s 赠送 %s x%d", username, giftName, num)
 	}
+	w.addChatLine(recvAt, fullText, chatHoldDanmaku)
 	textWidth := w.estimateTextWidth(fullText)
 	totalDistance := w.resX + textWidth
 	durationCS := int64(w.scrollTimeMs) * int64(totalDistance) / int64(w.resX) / 10
@@ -354,6 +381,7 @@ func (w *AssWriter) AddGuard(recvAt time.Time, username, giftName string, price
 	endCS := startCS + 500 // 5 seconds
 
 	fullText := fmt.Sprintf("[%s ¥%d] %s 开通了%s", giftName, price/1000, username, giftName)
+	w.addChatLine(recvAt, fullText, chatHoldPaid)
 	alignment, marginV := positionToAlignment(w.cfg.GuardPosition, 60)
 	line := fmt.Sprintf("Dialogue: 1,%s,%s,Guard,,0,0,%d,,{\\an%d}{\\q0}%s\n",
 		formatTime(startCS), formatTime(endCS), marginV, alignment, escapeText(fullText))
@@ -378,6 +406,7 @@ func (w *AssWriter) AddSuperChat(recvAt time.Time, username, text string, price
 	endCS := startCS + 500 // 5 seconds
 
 	fullText := fmt.Sprintf("[SC ¥%d] %s: %s", price, username, text)
+	w.addChatLine(recvAt, fullText, chatHoldPaid)
 	alignment, marginV := positionToAlignment(w.cfg.ScPosition, 100)
 	styleName := scTierStyle(price)
 	line := fmt.Sprintf("Dialogue: 1,%s,%s,%s,,0,0,%d,,{\\an%d}{\\q0}%s\n",
@@ -420,13 +449,62 @@ func (w *AssWriter) assignLane(startCS int64, textWidth int) (int, int64) {
 }
 
 func (w *AssWriter) OutputPath() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
 	return w.file.Name()
 }
 
+// SetTimelineStart 重设时间轴零点，之后写入的条目都相对新的零点计时。
+// 用于在视频实际开始的时间确定后校准弹幕时间轴。
+func (w *AssWriter) SetTimelineStart(startAt time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.startAt = startAt
+	for _, c := range w.companions {
+		c.SetTimelineStart(startAt)
+	}
+}
+
+// Rotate 关闭当前文件，改为写入 filePath，并以 startAt 作为新文件的时间轴零点。
+// 用于视频分段时同步切分弹幕文件；新文件创建失败时继续写入原文件。
+func (w *AssWriter) Rotate(filePath string, startAt time.Time) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return fmt.Errorf("ass writer closed")
+	}
+	f, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create ass file: %w", err)
+	}
+	old := w.file
+	w.file = f
+	w.startAt = startAt
+	w.writeErr = false
+	w.nextLane = 0
+	w.laneLast = make([]int64, w.laneNum)
+	old.Close()
+	// 先写入 ASS 文件头，附属字幕轮换失败不影响 ASS 文件继续写入
+	if err := w.writeHeader(); err != nil {
+		w.writeErr = true
+		return err
+	}
+	var errs []error
+	for _, c := range w.companions {
+		if err := c.Rotate(companionPath(filePath, c.format), startAt); err != nil {
+			errs = append(errs, fmt.Errorf("%w: %w", ErrCompanionRotate, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func (w *AssWriter) Close() error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	w.closed = true
+	for _, c := range w.companions {
+		c.Close()
+	}
 	if w.file != nil {
 		return w.file.Close()
 	}
@@ -0,0 +1,67 @@
+package danmaku
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+)
+
+func dialogueLines(t *testing.T, path string) []string {
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, "Dialogue:") {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func TestCalibrateAndSplitTimeline(t *testing.T) {
+	dir := t.TempDir()
+	cfg := configs.GetDefaultDanmakuConfig()
+	cfg.StreamDelayMs = configs.IntPtr(2000)
+
+	recorderStart := time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC)
+	first := filepath.Join(dir, "video.ass")
+	w, err := NewAssWriter(first, recorderStart, cfg, "test")
+	assert.NoError(t, err)
+	r := &baseRecorder{
+		running:    true,
+		assWriter:  w,
+		outputFile: first,
+		cfg:        cfg,
+		logger:     logrus.NewEntry(logrus.New()),
+		startAt:    recorderStart,
+	}
+
+	// 视频首帧比录制器启动早 3 秒，再加 2 秒直播流延迟：时间轴零点为启动前 5 秒
+	r.CalibrateTimeline(recorderStart.Add(-3 * time.Second))
+	r.addDanmaku(recorderStart.Add(time.Second), "u", "hello", 0)
+
+	// 分段后新文件从分段开始重新计时
+	splitAt := recorderStart.Add(time.Minute)
+	second := filepath.Join(dir, "video_PART001.ass")
+	assert.NoError(t, r.SplitTimeline(second, splitAt))
+	r.addDanmaku(splitAt.Add(4*time.Second), "u", "world", 0)
+	w.Close()
+
+	assert.Equal(t, []string{first, second}, r.OutputFiles())
+	lines := dialogueLines(t, first)
+	if assert.Len(t, lines, 1) {
+		assert.True(t, strings.HasPrefix(lines[0], "Dialogue: 0,0:00:06.00,"), lines[0])
+	}
+	lines = dialogueLines(t, second)
+	if assert.Len(t, lines, 1) {
+		assert.True(t, strings.HasPrefix(lines[0], "Dialogue: 0,0:00:06.00,"), lines[0])
+		assert.Contains(t, lines[0], "world")
+	}
+}
@@ -1,19 +1,21 @@
 package danmaku
 
 import (
+	"errors"
 	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/pkg/recordmeta"
 )
 
 // DanmakuBroadcastCallback 弹幕实时广播回调函数类型
 // 当设置时，每条弹幕/礼物/SC/舰长消息都会同时通过此回调广播
 type DanmakuBroadcastCallback func(msgType, username, content string, extra map[string]interface{})
 
-// baseRecorder 提供三个平台弹幕录制器的公共字段和方法。
+// baseRecorder 提供各平台弹幕录制器的公共字段和方法。
 type baseRecorder struct {
 	mu         sync.Mutex
 	running    bool
@@ -24,12 +26,72 @@ type baseRecorder struct {
 	logger     *logrus.Entry
 	startAt    time.Time
 	broadcastCb DanmakuBroadcastCallback
+	statsCb     DanmakuBroadcastCallback
+	splitFiles []string // 视频分段后切换到的 ASS 文件，按分段顺序排列
+	filter     *danmakuFilter
+	highlight  *highlightTracker
 }
 
 func (b *baseRecorder) OutputFile() string {
 	return b.outputFile
 }
 
+// OutputFiles 返回本次录制写入的全部 ASS 文件：初始文件及各分段文件
+func (b *baseRecorder) OutputFiles() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string{b.outputFile}, b.splitFiles...)
+}
+
+// Highlights 返回各 ASS 文件对应视频的高光片段，与 OutputFiles 一一对应，
+// 秒数相对各自视频分段的开头。
+func (b *baseRecorder) Highlights() [][]recordmeta.Highlight {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.highlight == nil {
+		return make([][]recordmeta.Highlight, len(b.splitFiles)+1)
+	}
+	return b.highlight.results()
+}
+
+// CalibrateTimeline 以视频的实际开始时间校准弹幕时间轴。
+// videoStart 为录制文件首帧到达的本地时间，再减去配置的直播流延迟作为时间轴零点，
+// 使弹幕与画面对齐，而不是以弹幕录制器启动的时刻为零点。
+func (b *baseRecorder) CalibrateTimeline(videoStart time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.running || b.assWriter == nil || videoStart.IsZero() {
+		return
+	}
+	origin := videoStart.Add(-b.cfg.StreamDelay())
+	b.assWriter.SetTimelineStart(origin)
+	b.highlightTracker().setOrigin(origin)
+	b.logger.Infof("弹幕时间轴已校准: 零点相对弹幕录制启动偏移 %s（直播流延迟 %s）",
+		origin.Sub(b.startAt).Round(time.Millisecond), b.cfg.StreamDelay())
+}
+
+// SplitTimeline 视频分段时切换到新的 ASS 文件，时间轴从新分段的开始时间重新计时。
+func (b *baseRecorder) SplitTimeline(outputFile string, videoStart time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.running || b.assWriter == nil {
+		return nil
+	}
+	// 合并中的弹幕属于上一个分段
+	b.flushPending()
+	origin := videoStart.Add(-b.cfg.StreamDelay())
+	if err := b.assWriter.Rotate(outputFile, origin); err != nil {
+		if !errors.Is(err, ErrCompanionRotate) {
+			return err
+		}
+		b.logger.WithError(err).Warn("附属字幕切换文件失败，ASS 弹幕继续写入新文件")
+	}
+	b.highlightTracker().rotate(origin)
+	b.splitFiles = append(b.splitFiles, outputFile)
+	b.logger.Infof("视频已分段，弹幕切换到新文件: %s", outputFile)
+	return nil
+}
+
 func (b *baseRecorder) GetCount() int {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -49,14 +111,59 @@ func (b *baseRecorder) GetStatus() map[string]interface{} {
 	status := map[string]interface{}{
 		"danmaku_running": b.running,
 		"danmaku_count":   b.count,
-		"danmaku_output":  b.outputFile,
+		"danmaku_output":  b.currentOutputFile(),
 	}
 	if b.running {
 		status["danmaku_start_time"] = b.startAt.Format(time.RFC3339)
 	}
+	if b.filter != nil {
+		status["danmaku_filtered"] = b.filter.dropped
+	}
 	return status
 }
 
+// danmakuFilter 返回按配置创建的弹幕过滤器，调用方需持有 b.mu
+func (b *baseRecorder) danmakuFilter() *danmakuFilter {
+	if b.filter == nil {
+		b.filter = newDanmakuFilter(b.cfg, b.logger)
+	}
+	return b.filter
+}
+
+// highlightTracker 返回高光统计，零点默认为弹幕录制启动时刻，调用方需持有 b.mu
+func (b *baseRecorder) highlightTracker() *highlightTracker {
+	if b.highlight == nil {
+		b.highlight = newHighlightTracker(b.startAt)
+	}
+	return b.highlight
+}
+
+// writeDanmaku 将通过过滤的弹幕写入 ASS，受每秒密度上限约束，调用方需持有 b.mu
+func (b *baseRecorder) writeDanmaku(p *pendingDanmaku) {
+	if b.assWriter == nil || !b.danmakuFilter().admit(p.recvAt) {
+		return
+	}
+	b.assWriter.AddDanmaku(p.recvAt, p.username, p.text(), p.color)
+}
+
+// flushPending 写入合并窗口中尚未写入的弹幕，调用方需持有 b.mu
+func (b *baseRecorder) flushPending() {
+	if b.filter == nil {
+		return
+	}
+	for _, p := range b.filter.flush() {
+		b.writeDanmaku(p)
+	}
+}
+
+// currentOutputFile 返回当前正在写入的 ASS 文件，调用方需持有 b.mu
+func (b *baseRecorder) currentOutputFile() string {
+	if n := len(b.splitFiles); n > 0 {
+		return b.splitFiles[n-1]
+	}
+	return b.outputFile
+}
+
 // stopBase 通用停止逻辑：标记停止、清空 writer、返回旧引用供调用方关闭。
 func (b *baseRecorder) stopBase() (*AssWriter) {
 	b.mu.Lock()
@@ -64,6 +171,7 @@ func (b *baseRecorder) stopBase() (*AssWriter) {
 		b.mu.Unlock()
 		return nil
 	}
+	b.flushPending()
 	b.running = false
 	w := b.assWriter
 	b.assWriter = nil
@@ -78,20 +186,56 @@ func (b *baseRecorder) SetBroadcastCallback(cb DanmakuBroadcastCallback) {
 	b.broadcastCb = cb
 }
 
-// addDanmaku 弹幕回调的通用处理：加锁、检查运行状态、写入 ASS、计数。
+// SetStatsCallback 设置弹幕统计回调。与广播不同，屏蔽规则过滤掉的弹幕同样会计入统计
+func (b *baseRecorder) SetStatsCallback(cb DanmakuBroadcastCallback) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.statsCb = cb
+}
+
+// emit 把消息交给统计和广播回调，调用方需持有 b.mu
+func (b *baseRecorder) emit(msgType, username, content string, extra map[string]interface{}) {
+	if b.statsCb != nil {
+		b.statsCb(msgType, username, content, extra)
+	}
+	if b.broadcastCb != nil {
+		b.broadcastCb(msgType, username, content, extra)
+	}
+}
+
+// addDanmaku 弹幕回调的通用处理，用于不提供用户等级的平台。
 func (b *baseRecorder) addDanmaku(recvAt time.Time, username, content string, color int) {
+	b.addDanmakuWithLevel(recvAt, username, content, color, levelUnknown)
+}
+
+// addDanmakuWithLevel 弹幕回调的通用处理：加锁、检查运行状态、过滤、写入 ASS、计数。
+// 重复弹幕合并时写入会推迟到合并窗口结束，实时广播不受合并和密度限制影响。
+func (b *baseRecorder) addDanmakuWithLevel(recvAt time.Time, username, content string, color, level int) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 	if !b.running || b.assWriter == nil {
 		return
 	}
-	b.assWriter.AddDanmaku(recvAt, username, content, color)
+	extra := map[string]interface{}{
+		"color":     color,
+		"timestamp": recvAt.Unix(),
+	}
+	// 会话统计反映直播间实际收到的弹幕，在屏蔽规则之前计入
+	if b.statsCb != nil {
+		b.statsCb("danmaku", username, content, extra)
+	}
+	f := b.danmakuFilter()
+	if !f.allow(username, content, level) {
+		return
+	}
+	// 高光按实际弹幕密度统计，不受合并和密度限制影响
+	b.highlightTracker().addDanmaku(recvAt)
+	for _, p := range f.push(recvAt, username, content, color) {
+		b.writeDanmaku(p)
+	}
 	b.count++
 	if b.broadcastCb != nil {
-		b.broadcastCb("danmaku", username, content, map[string]interface{}{
-			"color":     color,
-			"timestamp": recvAt.Unix(),
-		})
+		b.broadcastCb("danmaku", username, content, extra)
 	}
 }
 
@@ -102,17 +246,24 @@ func (b *baseRecorder) addGift(recvAt time.Time, username, giftName string, num
 	if !b.running || b.assWriter == nil {
 		return
 	}
-	b.assWriter.AddGift(recvAt, username, giftName, num, price, coinType)
+	if b.danmakuFilter().admit(recvAt) {
+		b.assWriter.AddGift(recvAt, username, giftName, num, price, coinType)
+	}
+	value := giftValueYuan(num, price, coinType)
+	b.highlightTracker().addPaid(recvAt, scoreGift, scorePerYuan, value)
 	b.count++
-	if b.broadcastCb != nil {
-		b.broadcastCb("gift", username, giftName, map[string]interface{}{
-			"gift_name": giftName,
-			"num":       num,
-			"price":     price,
-			"coin_type": coinType,
-			"timestamp": recvAt.Unix(),
-		})
+	extra := map[string]interface{}{
+		"gift_name": giftName,
+		"num":       num,
+		"price":     price,
+		"coin_type": coinType,
+		"timestamp": recvAt.Unix(),
+	}
+	// 免费礼物和未提供价格的平台不带 value_cny
+	if coinType != "" {
+		extra["value_cny"] = value
 	}
+	b.emit("gift", username, giftName, extra)
 }
 
 // addSuperChat SC 回调的通用处理。
@@ -123,13 +274,13 @@ func (b *baseRecorder) addSuperChat(recvAt time.Time, username, message string,
 		return
 	}
 	b.assWriter.AddSuperChat(recvAt, username, message, price)
+	b.highlightTracker().addPaid(recvAt, scoreSuperChat, scoreSuperPerYuan, float64(price))
 	b.count++
-	if b.broadcastCb != nil {
-		b.broadcastCb("super_chat", username, message, map[string]interface{}{
-			"price":     price,
-			"timestamp": recvAt.Unix(),
-		})
-	}
+	b.emit("super_chat", username, message, map[string]interface{}{
+		"price":     price,
+		"value_cny": float64(price),
+		"timestamp": recvAt.Unix(),
+	})
 }
 
 // addGuard 舰长回调的通用处理。
@@ -140,12 +291,12 @@ func (b *baseRecorder) addGuard(recvAt time.Time, username, giftName string, pri
 		return
 	}
 	b.assWriter.AddGuard(recvAt, username, giftName, price)
+	b.highlightTracker().addPaid(recvAt, scoreGuard, scoreSuperPerYuan, float64(price)/1000.0)
 	b.count++
-	if b.broadcastCb != nil {
-		b.broadcastCb("guard", username, giftName, map[string]interface{}{
-			"gift_name": giftName,
-			"price":     price,
-			"timestamp": recvAt.Unix(),
-		})
-	}
+	b.emit("guard", username, giftName, map[string]interface{}{
+		"gift_name": giftName,
+		"price":     price,
+		"value_cny": float64(price) / 1000.0,
+		"timestamp": recvAt.Unix(),
+	})
 }
@@ -40,6 +40,10 @@ type Client struct {
 	onGift      func(GiftMsg)
 	onSuperChat func(SuperChatMsg)
 	onGuardBuy  func(GuardBuyMsg)
+	// onLiveStatus 收到开播(LIVE)/下播(PREPARING)推送时调用
+	onLiveStatus func(living bool)
+	// onConnection 认证成功(true)或连接断开(false)时调用
+	onConnection func(connected bool)
 }
 
 // NewClient 创建新的 B站弹幕客户端
@@ -73,6 +77,22 @@ func (c *Client) OnGuardBuy(fn func(GuardBuyMsg)) {
 	c.onGuardBuy = fn
 }
 
+// OnLiveStatus 注册开播/下播推送回调
+func (c *Client) OnLiveStatus(fn func(living bool)) {
+	c.onLiveStatus = fn
+}
+
+// OnConnectionChange 注册连接状态回调：认证成功时为 true，连接断开（等待重连）时为 false
+func (c *Client) OnConnectionChange(fn func(connected bool)) {
+	c.onConnection = fn
+}
+
+func (c *Client) notifyConnection(connected bool) {
+	if c.onConnection != nil {
+		c.onConnection(connected)
+	}
+}
+
 // Start 启动连接
 func (c *Client) Start() error {
 	// 1. 解析真实 room_id
@@ -232,6 +252,7 @@ func (c *Client) readLoopWithReconnect(realRoomID, uid int, buvid string, danmuI
 		if err == nil {
 			return // 正常关闭
 		}
+		c.notifyConnection(false)
 
 		select {
 		case <-c.done:
@@ -367,6 +388,7 @@ func (c *Client) readLoop(stableCh chan struct{}) error {
 				case c.heartbeatCh <- struct{}{}:
 				default:
 				}
+				c.notifyConnection(true)
 			case OpHeartBeatReply:
 				// 提取人气值（在线人数）
 				if len(pkt.Body) >= 4 {
@@ -30,6 +30,14 @@ func (c *Client) handleMessage(data []byte) {
 		if msg, ok := parseGuardBuy(data); ok && c.onGuardBuy != nil {
 			c.onGuardBuy(msg)
 		}
+	case "LIVE":
+		if c.onLiveStatus != nil {
+			c.onLiveStatus(true)
+		}
+	case "PREPARING":
+		if c.onLiveStatus != nil {
+			c.onLiveStatus(false)
+		}
 	}
 }
 
@@ -67,6 +75,7 @@ func parseDanmaku(data []byte) (DanmakuMsg, bool) {
 	}
 
 	msg.GuardLevel = int(info.Get("7").Int())
+	msg.UserLevel = int(info.Get("4.0").Int())
 	msg.Timestamp = info.Get("0.4").Int()
 
 	// 勋章信息
@@ -6,6 +6,7 @@ type DanmakuMsg struct {
 	UID         int64
 	Uname       string
 	GuardLevel  int // 0=无, 1=总督, 2=提督, 3=舰长
+	UserLevel   int // 用户等级 (UL)
 	Color       int
 	Timestamp   int64
 	MedalLevel  int
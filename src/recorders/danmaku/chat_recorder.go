@@ -0,0 +1,126 @@
+package danmaku
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/acfun"
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/huya"
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/kuaishou"
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/soop"
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/twitch"
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/wsclient"
+)
+
+// chatClientFactory 创建平台弹幕客户端
+type chatClientFactory func(onDanmaku func(username, content string, color int)) *wsclient.Client
+
+// ChatDanmakuRecorder 只录制聊天弹幕的录制器，用于虎牙、Twitch、快手、AcFun、SOOP 等平台
+type ChatDanmakuRecorder struct {
+	baseRecorder
+	platform  string
+	title     string
+	newClient chatClientFactory
+	client    *wsclient.Client
+}
+
+func newChatDanmakuRecorder(platform, title, outputFile string, cfg configs.DanmakuConfig, logger *logrus.Entry, newClient chatClientFactory) *ChatDanmakuRecorder {
+	return &ChatDanmakuRecorder{
+		baseRecorder: baseRecorder{
+			outputFile: outputFile,
+			cfg:        cfg,
+			logger:     logger,
+		},
+		platform:  platform,
+		title:     title,
+		newClient: newClient,
+	}
+}
+
+// NewHuyaDanmakuRecorder 创建虎牙弹幕录制器
+func NewHuyaDanmakuRecorder(roomID, cookies, outputFile string, cfg configs.DanmakuConfig, logger *logrus.Entry) *ChatDanmakuRecorder {
+	return newChatDanmakuRecorder("虎牙", "Huya Danmaku", outputFile, cfg, logger, func(onDanmaku func(username, content string, color int)) *wsclient.Client {
+		return huya.NewHuyaClient(roomID, cookies, onDanmaku, logger)
+	})
+}
+
+// NewTwitchDanmakuRecorder 创建 Twitch 弹幕录制器
+func NewTwitchDanmakuRecorder(channel, outputFile string, cfg configs.DanmakuConfig, logger *logrus.Entry) *ChatDanmakuRecorder {
+	return newChatDanmakuRecorder("Twitch", "Twitch Chat", outputFile, cfg, logger, func(onDanmaku func(username, content string, color int)) *wsclient.Client {
+		return twitch.NewTwitchClient(channel, onDanmaku, logger)
+	})
+}
+
+// NewKuaishouDanmakuRecorder 创建快手弹幕录制器
+func NewKuaishouDanmakuRecorder(principalID, cookies, outputFile string, cfg configs.DanmakuConfig, logger *logrus.Entry) *ChatDanmakuRecorder {
+	return newChatDanmakuRecorder("快手", "Kuaishou Danmaku", outputFile, cfg, logger, func(onDanmaku func(username, content string, color int)) *wsclient.Client {
+		return kuaishou.NewKuaishouClient(principalID, cookies, onDanmaku, logger)
+	})
+}
+
+// NewAcfunDanmakuRecorder 创建 AcFun 弹幕录制器
+func NewAcfunDanmakuRecorder(authorID, cookies, outputFile string, cfg configs.DanmakuConfig, logger *logrus.Entry) *ChatDanmakuRecorder {
+	return newChatDanmakuRecorder("AcFun", "AcFun Danmaku", outputFile, cfg, logger, func(onDanmaku func(username, content string, color int)) *wsclient.Client {
+		return acfun.NewAcfunClient(authorID, cookies, onDanmaku, logger)
+	})
+}
+
+// NewSoopDanmakuRecorder 创建 SOOP 弹幕录制器
+func NewSoopDanmakuRecorder(bjID, cookies, outputFile string, cfg configs.DanmakuConfig, logger *logrus.Entry) *ChatDanmakuRecorder {
+	return newChatDanmakuRecorder("SOOP", "SOOP Chat", outputFile, cfg, logger, func(onDanmaku func(username, content string, color int)) *wsclient.Client {
+		return soop.NewSoopClient(bjID, cookies, onDanmaku, logger)
+	})
+}
+
+// Start 开始弹幕录制
+func (r *ChatDanmakuRecorder) Start(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.running {
+		return nil
+	}
+
+	startAt := time.Now()
+
+	assWriter, err := NewAssWriter(r.outputFile, startAt, r.cfg, r.title)
+	if err != nil {
+		return err
+	}
+	r.assWriter = assWriter
+	r.startAt = startAt
+
+	r.client = r.newClient(r.onDanmaku)
+
+	if err := r.client.Start(ctx); err != nil {
+		assWriter.Close()
+		return err
+	}
+
+	r.running = true
+	r.logger.Infof("%s弹幕录制已启动", r.platform)
+
+	return nil
+}
+
+// Stop 停止弹幕录制
+func (r *ChatDanmakuRecorder) Stop() {
+	w := r.stopBase()
+	c := r.client
+	r.client = nil
+	if c != nil {
+		c.Stop()
+	}
+	if w != nil {
+		w.Close()
+	}
+	r.logger.Infof("%s弹幕录制已停止，共录制 %d 条弹幕", r.platform, r.GetCount())
+}
+
+// onDanmaku 弹幕回调
+func (r *ChatDanmakuRecorder) onDanmaku(username, content string, color int) {
+	r.addDanmaku(time.Now(), username, content, color)
+}
@@ -52,7 +52,7 @@ func (d *DanmakuRecorder) Start(ctx context.Context) error {
 	c := bilibili.NewClient(d.roomID, d.cookies, d.logger)
 
 	c.OnDanmaku(func(msg bilibili.DanmakuMsg) {
-		d.addDanmaku(time.Now(), msg.Uname, msg.Content, msg.Color)
+		d.addDanmakuWithLevel(time.Now(), msg.Uname, msg.Content, msg.Color, msg.UserLevel)
 	})
 
 	if d.cfg.RecordGift != nil && *d.cfg.RecordGift {
@@ -100,6 +100,7 @@ func (d *DanmakuRecorder) Stop() {
 		d.mu.Unlock()
 		return
 	}
+	d.flushPending()
 	d.running = false
 	w := d.assWriter
 	d.assWriter = nil
@@ -30,7 +30,7 @@ type DouyuClient struct {
 	roomID    string
 	cookies   string
 	conn      net.Conn
-	onDanmaku func(username, content string, color int)
+	onDanmaku func(username, content string, color, level int)
 	onGift    func(username, giftName string, num int)
 	done      chan struct{}
 	closeOnce sync.Once
@@ -40,7 +40,7 @@ type DouyuClient struct {
 	cachedAddr string
 }
 
-func NewDouyuClient(roomID, cookies string, onDanmaku func(username, content string, color int), onGift func(username, giftName string, num int), logger *logrus.Entry) *DouyuClient {
+func NewDouyuClient(roomID, cookies string, onDanmaku func(username, content string, color, level int), onGift func(username, giftName string, num int), logger *logrus.Entry) *DouyuClient {
 	return &DouyuClient{
 		roomID:    roomID,
 		cookies:   cookies,
@@ -345,7 +345,11 @@ func (c *DouyuClient) readLoop(ctx context.Context) error {
 			txt := fields["txt"]
 			if nn != "" && txt != "" && c.onDanmaku != nil {
 				col := parseDouyuColor(fields["col"])
-				c.handleDanmakuSafe(nn, txt, col)
+				level, err := strconv.Atoi(fields["level"])
+				if err != nil {
+					level = -1
+				}
+				c.handleDanmakuSafe(nn, txt, col, level)
 			}
 		case "dgb":
 			if c.onGift != nil {
@@ -542,13 +546,13 @@ func parseDouyuColor(col string) int {
 }
 
 // handleDanmakuSafe 带 panic 恢复的弹幕处理
-func (c *DouyuClient) handleDanmakuSafe(username, content string, color int) {
+func (c *DouyuClient) handleDanmakuSafe(username, content string, color, level int) {
 	defer func() {
 		if r := recover(); r != nil {
 			c.logger.Errorf("弹幕处理 panic: %v", r)
 		}
 	}()
-	c.onDanmaku(username, content, color)
+	c.onDanmaku(username, content, color, level)
 }
 
 // handleGiftSafe 带 panic 恢复的礼物处理
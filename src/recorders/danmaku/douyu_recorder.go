@@ -83,7 +83,7 @@ func (r *DouyuDanmakuRecorder) Stop() {
 	r.logger.Infof("斗鱼弹幕录制已停止，共录制 %d 条弹幕", r.GetCount())
 }
 
-// onDanmaku 弹幕回调
-func (r *DouyuDanmakuRecorder) onDanmaku(username, content string, color int) {
-	r.addDanmaku(time.Now(), username, content, color)
+// onDanmaku 弹幕回调，level 为用户等级，未知时为 -1
+func (r *DouyuDanmakuRecorder) onDanmaku(username, content string, color, level int) {
+	r.addDanmakuWithLevel(time.Now(), username, content, color, level)
 }
@@ -0,0 +1,186 @@
+package danmaku
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+)
+
+// levelUnknown 平台未提供用户等级时使用，不参与最低等级过滤
+const levelUnknown = -1
+
+// pendingDanmaku 等待写入的弹幕，count > 1 表示合并了重复内容
+type pendingDanmaku struct {
+	recvAt   time.Time
+	username string
+	content  string
+	color    int
+	count    int
+	seq      int // 到达顺序，同一时刻的弹幕按到达先后写入
+}
+
+// text 返回写入 ASS 的文本，合并过的弹幕追加次数，如 "233 x23"
+func (p *pendingDanmaku) text() string {
+	if p.count > 1 {
+		return fmt.Sprintf("%s x%d", p.content, p.count)
+	}
+	return p.content
+}
+
+// danmakuFilter 写入 ASS 前的弹幕过滤：屏蔽词/正则、屏蔽用户、最低用户等级、
+// 重复弹幕合并和每秒密度限制。不是并发安全的，由 baseRecorder 持锁调用。
+type danmakuFilter struct {
+	keywords     []string
+	patterns     []*regexp.Regexp
+	users        map[string]struct{}
+	minLevel     int
+	foldWindow   time.Duration
+	maxPerSecond int
+
+	pending map[string]*pendingDanmaku // 合并窗口内的弹幕，按内容索引
+	written map[int64]int              // 每秒已写入的条数，按 Unix 秒索引
+	dropped int                        // 被屏蔽或因密度限制丢弃的条数
+	seq     int
+}
+
+func newDanmakuFilter(cfg configs.DanmakuConfig, logger *logrus.Entry) *danmakuFilter {
+	f := &danmakuFilter{
+		users:   make(map[string]struct{}, len(cfg.BlockUsers)),
+		pending: make(map[string]*pendingDanmaku),
+		written: make(map[int64]int),
+	}
+	for _, kw := range cfg.BlockKeywords {
+		if kw = strings.TrimSpace(kw); kw != "" {
+			f.keywords = append(f.keywords, kw)
+		}
+	}
+	for _, pattern := range cfg.BlockPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			// 配置校验时已拒绝无效表达式，这里仅防御性跳过
+			logger.WithError(err).Warnf("忽略无效的屏蔽正则表达式: %s", pattern)
+			continue
+		}
+		f.patterns = append(f.patterns, re)
+	}
+	for _, user := range cfg.BlockUsers {
+		if user = strings.TrimSpace(user); user != "" {
+			f.users[user] = struct{}{}
+		}
+	}
+	if cfg.MinUserLevel != nil {
+		f.minLevel = *cfg.MinUserLevel
+	}
+	if cfg.FoldWindow != nil {
+		f.foldWindow = time.Duration(*cfg.FoldWindow) * time.Second
+	}
+	if cfg.MaxPerSecond != nil {
+		f.maxPerSecond = *cfg.MaxPerSecond
+	}
+	return f
+}
+
+// allow 判断弹幕是否通过屏蔽规则，level 为 levelUnknown 时不检查等级
+func (f *danmakuFilter) allow(username, content string, level int) bool {
+	ok := f.check(username, content, level)
+	if !ok {
+		f.dropped++
+	}
+	return ok
+}
+
+func (f *danmakuFilter) check(username, content string, level int) bool {
+	if _, blocked := f.users[username]; blocked {
+		return false
+	}
+	if f.minLevel > 0 && level != levelUnknown && level < f.minLevel {
+		return false
+	}
+	for _, kw := range f.keywords {
+		if strings.Contains(content, kw) {
+			return false
+		}
+	}
+	for _, re := range f.patterns {
+		if re.MatchString(content) {
+			return false
+		}
+	}
+	return true
+}
+
+// push 将弹幕放入合并窗口，返回窗口已结束、需要写入的弹幕（按首次出现时间排序）。
+// 未启用合并时直接返回该弹幕。
+func (f *danmakuFilter) push(recvAt time.Time, username, content string, color int) []*pendingDanmaku {
+	due := f.expire(recvAt)
+	f.seq++
+	if f.foldWindow <= 0 {
+		return append(due, &pendingDanmaku{recvAt: recvAt, username: username, content: content, color: color, count: 1, seq: f.seq})
+	}
+	key := strings.TrimSpace(content)
+	if p, ok := f.pending[key]; ok {
+		p.count++
+		return due
+	}
+	f.pending[key] = &pendingDanmaku{recvAt: recvAt, username: username, content: content, color: color, count: 1, seq: f.seq}
+	return due
+}
+
+// expire 取出合并窗口在 now 之前结束的弹幕
+func (f *danmakuFilter) expire(now time.Time) []*pendingDanmaku {
+	var due []*pendingDanmaku
+	for key, p := range f.pending {
+		if now.Sub(p.recvAt) >= f.foldWindow {
+			due = append(due, p)
+			delete(f.pending, key)
+		}
+	}
+	sortPending(due)
+	return due
+}
+
+// flush 取出全部等待合并的弹幕，用于停止录制或切换文件前写入
+func (f *danmakuFilter) flush() []*pendingDanmaku {
+	due := make([]*pendingDanmaku, 0, len(f.pending))
+	for key, p := range f.pending {
+		due = append(due, p)
+		delete(f.pending, key)
+	}
+	sortPending(due)
+	return due
+}
+
+// admit 按每秒上限决定 recvAt 所在秒是否还能写入一条普通消息，SC 与上舰消息不经过此检查
+func (f *danmakuFilter) admit(recvAt time.Time) bool {
+	if f.maxPerSecond <= 0 {
+		return true
+	}
+	sec := recvAt.Unix()
+	if f.written[sec] >= f.maxPerSecond {
+		f.dropped++
+		return false
+	}
+	f.written[sec]++
+	// 合并窗口最长 60 秒，更早的计数不会再被用到
+	for s := range f.written {
+		if s < sec-120 {
+			delete(f.written, s)
+		}
+	}
+	return true
+}
+
+func sortPending(list []*pendingDanmaku) {
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].recvAt.Equal(list[j].recvAt) {
+			return list[i].recvAt.Before(list[j].recvAt)
+		}
+		return list[i].seq < list[j].seq
+	})
+}
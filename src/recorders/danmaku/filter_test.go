@@ -0,0 +1,90 @@
+package danmaku
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+)
+
+func TestDanmakuFilterAllow(t *testing.T) {
+	cfg := configs.GetDefaultDanmakuConfig()
+	cfg.BlockKeywords = []string{"加群"}
+	cfg.BlockPatterns = []string{`^\d{6,}$`}
+	cfg.BlockUsers = []string{"spammer"}
+	cfg.MinUserLevel = configs.IntPtr(3)
+	f := newDanmakuFilter(cfg, logrus.NewEntry(logrus.New()))
+
+	assert.True(t, f.allow("u", "主播好", 5))
+	assert.True(t, f.allow("u", "主播好", levelUnknown))
+	assert.False(t, f.allow("u", "快来加群", 5))
+	assert.False(t, f.allow("u", "12345678", 5))
+	assert.False(t, f.allow("spammer", "主播好", 5))
+	assert.False(t, f.allow("u", "主播好", 1))
+	assert.Equal(t, 4, f.dropped)
+}
+
+func TestFoldAndDensityCap(t *testing.T) {
+	cfg := configs.GetDefaultDanmakuConfig()
+	cfg.FoldWindow = configs.IntPtr(5)
+	cfg.MaxPerSecond = configs.IntPtr(2)
+
+	start := time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "video.ass")
+	w, err := NewAssWriter(path, start, cfg, "test")
+	assert.NoError(t, err)
+	r := &baseRecorder{running: true, assWriter: w, outputFile: path, cfg: cfg, logger: logrus.NewEntry(logrus.New()), startAt: start}
+
+	// 23 条相同弹幕在窗口内合并为一条
+	for i := 0; i < 23; i++ {
+		r.addDanmaku(start.Add(time.Duration(i)*100*time.Millisecond), "u", "233", 0)
+	}
+	// 同一秒内的 3 条不同弹幕只保留 2 条，SC 不受限制
+	at := start.Add(10 * time.Second)
+	r.addDanmaku(at, "a", "one", 0)
+	r.addDanmaku(at, "b", "two", 0)
+	r.addDanmaku(at, "c", "three", 0)
+	r.addSuperChat(at, "d", "sc", 30)
+	w = r.stopBase()
+	w.Close()
+
+	var texts []string
+	for _, line := range dialogueLines(t, path) {
+		texts = append(texts, line[strings.LastIndex(line, "}")+1:])
+	}
+	// 合并的弹幕在窗口结束后写入，时间仍为首次出现的时间
+	assert.Equal(t, []string{"u: 233 x23", "[SC ¥30] d: sc", "a: one", "b: two"}, texts)
+}
+
+func TestBlockedDanmakuCountedInStatsButNotBroadcast(t *testing.T) {
+	cfg := configs.GetDefaultDanmakuConfig()
+	cfg.BlockKeywords = []string{"加群"}
+
+	start := time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "video.ass")
+	w, err := NewAssWriter(path, start, cfg, "test")
+	assert.NoError(t, err)
+	r := &baseRecorder{running: true, assWriter: w, outputFile: path, cfg: cfg, logger: logrus.NewEntry(logrus.New()), startAt: start}
+
+	var stats, broadcast []string
+	r.SetStatsCallback(func(msgType, username, content string, extra map[string]interface{}) {
+		stats = append(stats, content)
+	})
+	r.SetBroadcastCallback(func(msgType, username, content string, extra map[string]interface{}) {
+		broadcast = append(broadcast, content)
+	})
+
+	r.addDanmaku(start, "a", "主播好", 0)
+	r.addDanmaku(start.Add(time.Second), "b", "加群领福利", 0)
+	r.addSuperChat(start.Add(2*time.Second), "c", "sc", 30)
+	w = r.stopBase()
+	w.Close()
+
+	assert.Equal(t, []string{"主播好", "加群领福利", "sc"}, stats)
+	assert.Equal(t, []string{"主播好", "sc"}, broadcast)
+}
@@ -0,0 +1,191 @@
+package danmaku
+
+import (
+	"sort"
+	"time"
+
+	"github.com/bililive-go/bililive-go/src/pkg/recordmeta"
+)
+
+const (
+	// highlightWindow 计算活跃度的滑动窗口长度（秒）
+	highlightWindow = 30
+	// highlightMinScore 窗口得分的最低门槛，避免冷清直播间把零星弹幕当作高光
+	highlightMinScore = 20
+	// highlightMaxCount 每个文件最多保存的高光片段数
+	highlightMaxCount = 10
+
+	scoreDanmaku      = 1.0  // 每条弹幕
+	scoreGift         = 2.0  // 每次送礼，另按金额加分
+	scoreSuperChat    = 10.0 // 每条 SC，另按金额加分
+	scoreGuard        = 30.0 // 每次上舰，另按金额加分
+	scorePerYuan      = 0.5  // 礼物每元加分
+	scoreSuperPerYuan = 1.0  // SC、上舰每元加分
+)
+
+// activityBucket 一秒内的弹幕与付费活动
+type activityBucket struct {
+	score   float64
+	danmaku int
+	gifts   int
+	value   float64
+}
+
+// highlightTracker 按秒统计弹幕密度和礼物活跃度，录制结束或分段时找出得分最高的窗口。
+// 秒数相对弹幕时间轴零点计算，与 ASS 文件和视频的时间对齐。
+// 不是并发安全的，由 baseRecorder 持锁调用。
+type highlightTracker struct {
+	origin   time.Time
+	buckets  map[int]*activityBucket
+	segments [][]recordmeta.Highlight // 已结束分段的检测结果
+}
+
+func newHighlightTracker(origin time.Time) *highlightTracker {
+	return &highlightTracker{origin: origin, buckets: make(map[int]*activityBucket)}
+}
+
+func (h *highlightTracker) bucket(at time.Time) *activityBucket {
+	sec := int(at.Sub(h.origin) / time.Second)
+	if sec < 0 {
+		sec = 0
+	}
+	b, ok := h.buckets[sec]
+	if !ok {
+		b = &activityBucket{}
+		h.buckets[sec] = b
+	}
+	return b
+}
+
+func (h *highlightTracker) addDanmaku(at time.Time) {
+	b := h.bucket(at)
+	b.score += scoreDanmaku
+	b.danmaku++
+}
+
+// addPaid 记录一次礼物/SC/上舰，yuan 为折算金额，无法折算时为 0
+func (h *highlightTracker) addPaid(at time.Time, base, perYuan, yuan float64) {
+	b := h.bucket(at)
+	b.score += base + yuan*perYuan
+	b.gifts++
+	b.value += yuan
+}
+
+// setOrigin 校准时间轴零点，已统计的数据按新零点平移
+func (h *highlightTracker) setOrigin(origin time.Time) {
+	shift := int(origin.Sub(h.origin) / time.Second)
+	h.origin = origin
+	if shift == 0 {
+		return
+	}
+	old := h.buckets
+	h.buckets = make(map[int]*activityBucket, len(old))
+	for sec, b := range old {
+		sec -= shift
+		if sec < 0 {
+			sec = 0
+		}
+		if cur, ok := h.buckets[sec]; ok {
+			cur.score += b.score
+			cur.danmaku += b.danmaku
+			cur.gifts += b.gifts
+			cur.value += b.value
+			continue
+		}
+		h.buckets[sec] = b
+	}
+}
+
+// rotate 视频分段时结束当前分段的统计，新分段从 origin 重新计时
+func (h *highlightTracker) rotate(origin time.Time) {
+	h.segments = append(h.segments, h.detect())
+	h.origin = origin
+	h.buckets = make(map[int]*activityBucket)
+}
+
+// results 返回各分段的高光片段，顺序与 OutputFiles 一致
+func (h *highlightTracker) results() [][]recordmeta.Highlight {
+	out := make([][]recordmeta.Highlight, 0, len(h.segments)+1)
+	out = append(out, h.segments...)
+	return append(out, h.detect())
+}
+
+// detect 以滑动窗口累计得分，取得分不低于中位数两倍（且不低于最低门槛）、
+// 互不重叠的窗口作为高光片段，按得分从高到低返回。
+func (h *highlightTracker) detect() []recordmeta.Highlight {
+	last := -1
+	for sec := range h.buckets {
+		if sec > last {
+			last = sec
+		}
+	}
+	if last < 0 {
+		return nil
+	}
+
+	// 前缀和，windows[s] 为 [s, s+highlightWindow) 的累计
+	prefix := make([]activityBucket, last+2)
+	for sec := 0; sec <= last; sec++ {
+		prefix[sec+1] = prefix[sec]
+		if b, ok := h.buckets[sec]; ok {
+			prefix[sec+1].score += b.score
+			prefix[sec+1].danmaku += b.danmaku
+			prefix[sec+1].gifts += b.gifts
+			prefix[sec+1].value += b.value
+		}
+	}
+	windowAt := func(start int) recordmeta.Highlight {
+		end := start + highlightWindow
+		if end > last+1 {
+			end = last + 1
+		}
+		return recordmeta.Highlight{
+			Start:     float64(start),
+			End:       float64(end),
+			Score:     prefix[end].score - prefix[start].score,
+			Danmaku:   prefix[end].danmaku - prefix[start].danmaku,
+			Gifts:     prefix[end].gifts - prefix[start].gifts,
+			GiftValue: prefix[end].value - prefix[start].value,
+		}
+	}
+
+	windows := make([]recordmeta.Highlight, 0, last+1)
+	scores := make([]float64, 0, last+1)
+	for start := 0; start <= last; start++ {
+		w := windowAt(start)
+		windows = append(windows, w)
+		scores = append(scores, w.Score)
+	}
+	sort.Float64s(scores)
+	threshold := 2 * scores[len(scores)/2]
+	if threshold < highlightMinScore {
+		threshold = highlightMinScore
+	}
+
+	sort.SliceStable(windows, func(i, j int) bool { return windows[i].Score > windows[j].Score })
+	var picked []recordmeta.Highlight
+	for _, w := range windows {
+		if w.Score < threshold || len(picked) >= highlightMaxCount {
+			break
+		}
+		overlap := false
+		for _, p := range picked {
+			if w.Start < p.End && p.Start < w.End {
+				overlap = true
+				break
+			}
+		}
+		if !overlap {
+			picked = append(picked, w)
+		}
+	}
+	return picked
+}
+
+// giftValueYuan 将礼物折算为人民币，仅 B 站付费礼物（金瓜子）可折算
+func giftValueYuan(num, price int, coinType string) float64 {
+	if coinType == "gold" && price > 0 {
+		return float64(price) * float64(num) / 1000.0
+	}
+	return 0
+}
@@ -0,0 +1,70 @@
+package danmaku
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+)
+
+func TestHighlightDetect(t *testing.T) {
+	start := time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC)
+	h := newHighlightTracker(start)
+
+	// 10 分钟内每 5 秒一条弹幕作为平时的热度
+	for sec := 0; sec < 600; sec += 5 {
+		h.addDanmaku(start.Add(time.Duration(sec) * time.Second))
+	}
+	// 第 120 秒起 10 秒内 40 条弹幕；第 400 秒一条 100 元 SC
+	for i := 0; i < 40; i++ {
+		h.addDanmaku(start.Add(120*time.Second + time.Duration(i)*250*time.Millisecond))
+	}
+	h.addPaid(start.Add(400*time.Second), scoreSuperChat, scoreSuperPerYuan, 100)
+
+	got := h.detect()
+	if assert.Len(t, got, 2) {
+		assert.True(t, got[0].Start <= 400 && got[0].End > 400, "SC 得分最高: %v", got[0])
+		assert.Equal(t, 1, got[0].Gifts)
+		assert.Equal(t, 100.0, got[0].GiftValue)
+		assert.True(t, got[1].Start <= 120 && got[1].End >= 130, "%v", got[1])
+		assert.Equal(t, 46, got[1].Danmaku)
+	}
+}
+
+func TestHighlightsFollowSplits(t *testing.T) {
+	cfg := configs.GetDefaultDanmakuConfig()
+	start := time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	first := filepath.Join(dir, "video.ass")
+	w, err := NewAssWriter(first, start, cfg, "test")
+	assert.NoError(t, err)
+	r := &baseRecorder{running: true, assWriter: w, outputFile: first, cfg: cfg, logger: logrus.NewEntry(logrus.New()), startAt: start}
+
+	// 每个分段 5 分钟，每 10 秒一条弹幕，中间有一次 30 条的刷屏
+	segment := func(from time.Time, burstAt time.Duration) {
+		for sec := 0; sec < 300; sec += 10 {
+			r.addDanmaku(from.Add(time.Duration(sec)*time.Second), "u", fmt.Sprint(sec), 0)
+		}
+		for i := 0; i < 30; i++ {
+			r.addDanmaku(from.Add(burstAt+time.Duration(i)*100*time.Millisecond), "u", "哈哈", 0)
+		}
+	}
+	segment(start, 95*time.Second)
+	splitAt := start.Add(5 * time.Minute)
+	assert.NoError(t, r.SplitTimeline(filepath.Join(dir, "video_PART001.ass"), splitAt))
+	segment(splitAt, 205*time.Second)
+	w = r.stopBase()
+	w.Close()
+
+	// 每个分段各一个高光，时间相对各自分段的开头
+	got := r.Highlights()
+	if assert.Len(t, got, 2) && assert.Len(t, got[0], 1) && assert.Len(t, got[1], 1) {
+		assert.True(t, got[0][0].Start <= 95 && got[0][0].End >= 98, "%v", got[0][0])
+		assert.True(t, got[1][0].Start <= 205 && got[1][0].End >= 208, "%v", got[1][0])
+	}
+}
@@ -0,0 +1,194 @@
+// Package huya 实现虎牙弹幕客户端：通过 WebSocket 注册直播间的消息组，
+// 消息使用 Tars 编码
+package huya
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/wsclient"
+)
+
+const (
+	wsURL = "wss://cdnws.api.huya.com"
+
+	heartbeatInterval = 60 * time.Second
+
+	mobileUserAgent = "Mozilla/5.0 (iPhone; CPU iPhone OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.6 Mobile/15E148 Safari/604.1"
+)
+
+// WebSocketCommand.iCmdType
+const (
+	cmdHeartBeat        = 5
+	cmdMsgPushReq       = 7
+	cmdRegisterGroupReq = 16
+	cmdRegisterGroupRsp = 17
+	cmdMsgPushReqV2     = 22
+	uriMessageNotice    = 1400
+)
+
+var reYyid = []*regexp.Regexp{
+	regexp.MustCompile(`"lYyid"\s*:\s*"?(\d+)`),
+	regexp.MustCompile(`"yyid"\s*:\s*"?(\d+)`),
+}
+
+type protocol struct {
+	roomID    string
+	cookies   string
+	yyid      string
+	onDanmaku func(username, content string, color int)
+	logger    *logrus.Entry
+}
+
+// NewHuyaClient 创建虎牙弹幕客户端，roomID 为直播间地址中的房间号或别名
+func NewHuyaClient(roomID, cookies string, onDanmaku func(username, content string, color int), logger *logrus.Entry) *wsclient.Client {
+	return wsclient.New(&protocol{
+		roomID:    roomID,
+		cookies:   cookies,
+		onDanmaku: onDanmaku,
+		logger:    logger,
+	}, logger)
+}
+
+func (p *protocol) Name() string { return "虎牙" }
+
+func (p *protocol) HeartbeatInterval() time.Duration { return heartbeatInterval }
+
+func (p *protocol) MessageType() int { return websocket.BinaryMessage }
+
+func (p *protocol) Heartbeat() ([][]byte, error) {
+	var cmd tarsWriter
+	cmd.writeInt(0, cmdHeartBeat)
+	return [][]byte{cmd.Bytes()}, nil
+}
+
+func (p *protocol) Connect(ctx context.Context) (*websocket.Conn, error) {
+	if p.yyid == "" {
+		yyid, err := p.fetchYyid(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("获取主播 yyid 失败: %w", err)
+		}
+		p.yyid = yyid
+	}
+	p.logger.Infof("连接虎牙弹幕服务器: roomID=%s yyid=%s", p.roomID, p.yyid)
+
+	conn, err := wsclient.Dial(ctx, wsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.WriteMessage(websocket.BinaryMessage, encodeRegisterGroup(p.yyid)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("注册消息组失败: %w", err)
+	}
+	return conn, nil
+}
+
+// fetchYyid 从移动端直播间页面中解析主播的 yyid，弹幕消息组以它命名
+func (p *protocol) fetchYyid(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://m.huya.com/"+p.roomID, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", mobileUserAgent)
+	if p.cookies != "" {
+		req.Header.Set("Cookie", p.cookies)
+	}
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	for _, re := range reYyid {
+		if m := re.FindSubmatch(body); m != nil && string(m[1]) != "0" {
+			return string(m[1]), nil
+		}
+	}
+	return "", fmt.Errorf("页面中未找到 yyid")
+}
+
+// encodeRegisterGroup 编码 WSRegisterGroupReq，订阅直播间的 live 与 chat 消息组
+func encodeRegisterGroup(yyid string) []byte {
+	var req tarsWriter
+	req.writeStringList(0, []string{"live:" + yyid, "chat:" + yyid})
+	req.writeString(1, "")
+
+	var cmd tarsWriter
+	cmd.writeInt(0, cmdRegisterGroupReq)
+	cmd.writeBytes(1, req.Bytes())
+	return cmd.Bytes()
+}
+
+func (p *protocol) Handle(data []byte, _ func([]byte) error) error {
+	cmd, err := decodeTars(data)
+	if err != nil {
+		p.logger.WithError(err).Debug("解析虎牙消息失败")
+		return nil
+	}
+	switch cmd.Int(0) {
+	case cmdRegisterGroupRsp:
+		rsp, err := decodeTars(cmd.Bytes(1))
+		if err == nil && rsp.Int(0) != 0 {
+			return fmt.Errorf("注册消息组失败: code=%d", rsp.Int(0))
+		}
+	case cmdMsgPushReq:
+		// WSPushMessage: ePushType(0) iUri(1) sMsg(2)
+		push, err := decodeTars(cmd.Bytes(1))
+		if err != nil {
+			return nil
+		}
+		p.handlePush(push.Int(1), push.Bytes(2))
+	case cmdMsgPushReqV2:
+		// WSPushMessage_V2: sGroupId(0) vMsgItem(1)，WSMsgItem: iUri(0) sMsg(1)
+		push, err := decodeTars(cmd.Bytes(1))
+		if err != nil {
+			return nil
+		}
+		for _, item := range push.List(1) {
+			if msg, ok := item.(tarsStruct); ok {
+				p.handlePush(msg.Int(0), msg.Bytes(1))
+			}
+		}
+	}
+	return nil
+}
+
+func (p *protocol) handlePush(uri int64, msg []byte) {
+	if uri != uriMessageNotice || p.onDanmaku == nil {
+		return
+	}
+	username, content, color, err := decodeMessageNotice(msg)
+	if err != nil {
+		p.logger.WithError(err).Debug("解析虎牙弹幕失败")
+		return
+	}
+	if username != "" && content != "" {
+		p.onDanmaku(username, content, color)
+	}
+}
+
+// decodeMessageNotice 解析弹幕消息 MessageNotice：
+// tUserInfo(0).sNickName(2)、sContent(3)、tBulletFormat(6).iFontColor(0)
+func decodeMessageNotice(msg []byte) (username, content string, color int, err error) {
+	notice, err := decodeTars(msg)
+	if err != nil {
+		return "", "", 0, err
+	}
+	color = wsclient.DefaultColor
+	if format := notice.Struct(6); format != nil {
+		if c := format.Int(0); c > 0 {
+			color = int(c)
+		}
+	}
+	return notice.Struct(0).String(2), notice.String(3), color, nil
+}
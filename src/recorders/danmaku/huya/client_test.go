@@ -0,0 +1,38 @@
+package huya
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/internal/danmakutest"
+)
+
+// 按 Tars 协议构造的弹幕推送：EWSCmdS2C_MsgPushReq 与 EWSCmdS2C_MsgPushReq_V2 各一帧
+const (
+	pushV1 = "00071d00004f00051105782d0000450a03000001174881dc4e1c260ce8998ee78999e794a8e688b730010b125043a233225043a233360ce4b8bbe692ade5a5bdefbc814c5a00ff10040b6a0200ff5c5c10042c0b3c2c"
+	pushV2 = "00161d0000650612636861743a313139393531323334353637381900010a0105781d0000450a03000001174881dc4e1c260ce8998ee78999e794a8e688b730010b125043a233225043a233360ce4b8bbe692ade5a5bdefbc814c5a00ff10040b6a0200ff5c5c10042c0b0b"
+)
+
+func TestHandlePushFrames(t *testing.T) {
+	var c danmakutest.Collector
+	p := &protocol{onDanmaku: c.OnDanmaku, logger: danmakutest.Logger()}
+	for _, frame := range []string{pushV1, pushV2} {
+		assert.NoError(t, p.Handle(danmakutest.Hex(t, frame), nil))
+	}
+	want := danmakutest.Chat{User: "虎牙用户", Content: "主播好！", Color: 0xFF5C5C}
+	assert.Equal(t, []danmakutest.Chat{want, want}, c.Chats)
+
+	// 截断的帧不应导致 panic 或断开连接
+	assert.NoError(t, p.Handle(danmakutest.Hex(t, pushV1)[:20], nil))
+	assert.Len(t, c.Chats, 2)
+}
+
+func TestEncodeRegisterGroup(t *testing.T) {
+	cmd, err := decodeTars(encodeRegisterGroup("1199512345678"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(cmdRegisterGroupReq), cmd.Int(0))
+	req, err := decodeTars(cmd.Bytes(1))
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"live:1199512345678", "chat:1199512345678"}, req.List(0))
+}
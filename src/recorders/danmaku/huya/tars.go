@@ -0,0 +1,318 @@
+package huya
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Tars 编码类型
+const (
+	tarsInt8 byte = iota
+	tarsInt16
+	tarsInt32
+	tarsInt64
+	tarsFloat
+	tarsDouble
+	tarsString1
+	tarsString4
+	tarsMap
+	tarsList
+	tarsStructBegin
+	tarsStructEnd
+	tarsZero
+	tarsSimpleList
+)
+
+// tarsWriter 最小的 Tars 编码器，只实现发送注册与心跳所需的类型
+type tarsWriter struct {
+	buf bytes.Buffer
+}
+
+func (w *tarsWriter) head(tag, typ byte) {
+	if tag < 15 {
+		w.buf.WriteByte(tag<<4 | typ)
+		return
+	}
+	w.buf.WriteByte(0xF0 | typ)
+	w.buf.WriteByte(tag)
+}
+
+func (w *tarsWriter) writeInt(tag byte, v int64) {
+	switch {
+	case v == 0:
+		w.head(tag, tarsZero)
+	case v >= math.MinInt8 && v <= math.MaxInt8:
+		w.head(tag, tarsInt8)
+		w.buf.WriteByte(byte(int8(v)))
+	case v >= math.MinInt16 && v <= math.MaxInt16:
+		w.head(tag, tarsInt16)
+		binary.Write(&w.buf, binary.BigEndian, int16(v))
+	case v >= math.MinInt32 && v <= math.MaxInt32:
+		w.head(tag, tarsInt32)
+		binary.Write(&w.buf, binary.BigEndian, int32(v))
+	default:
+		w.head(tag, tarsInt64)
+		binary.Write(&w.buf, binary.BigEndian, v)
+	}
+}
+
+func (w *tarsWriter) writeString(tag byte, s string) {
+	if len(s) <= math.MaxUint8 {
+		w.head(tag, tarsString1)
+		w.buf.WriteByte(byte(len(s)))
+	} else {
+		w.head(tag, tarsString4)
+		binary.Write(&w.buf, binary.BigEndian, uint32(len(s)))
+	}
+	w.buf.WriteString(s)
+}
+
+func (w *tarsWriter) writeBytes(tag byte, b []byte) {
+	w.head(tag, tarsSimpleList)
+	w.head(0, tarsInt8)
+	w.writeInt(0, int64(len(b)))
+	w.buf.Write(b)
+}
+
+func (w *tarsWriter) writeStringList(tag byte, list []string) {
+	w.head(tag, tarsList)
+	w.writeInt(0, int64(len(list)))
+	for _, s := range list {
+		w.writeString(0, s)
+	}
+}
+
+func (w *tarsWriter) writeStruct(tag byte, inner []byte) {
+	w.head(tag, tarsStructBegin)
+	w.buf.Write(inner)
+	w.head(0, tarsStructEnd)
+}
+
+func (w *tarsWriter) Bytes() []byte {
+	return w.buf.Bytes()
+}
+
+// tarsStruct 解码后的结构体，按 tag 索引字段值。
+// 字段值为 int64、float64、string、[]byte、[]interface{} 或 tarsStruct
+type tarsStruct map[byte]interface{}
+
+func (s tarsStruct) Int(tag byte) int64 {
+	v, _ := s[tag].(int64)
+	return v
+}
+
+func (s tarsStruct) String(tag byte) string {
+	switch v := s[tag].(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	return ""
+}
+
+func (s tarsStruct) Bytes(tag byte) []byte {
+	switch v := s[tag].(type) {
+	case []byte:
+		return v
+	case string:
+		return []byte(v)
+	}
+	return nil
+}
+
+func (s tarsStruct) Struct(tag byte) tarsStruct {
+	v, _ := s[tag].(tarsStruct)
+	return v
+}
+
+func (s tarsStruct) List(tag byte) []interface{} {
+	v, _ := s[tag].([]interface{})
+	return v
+}
+
+// decodeTars 将一段 Tars 编码的数据解码为结构体
+func decodeTars(data []byte) (tarsStruct, error) {
+	r := &tarsReader{data: data}
+	return r.readFields(false)
+}
+
+type tarsReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *tarsReader) next(n int) ([]byte, error) {
+	if n < 0 || r.pos+n > len(r.data) {
+		return nil, fmt.Errorf("tars: 数据长度不足")
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+func (r *tarsReader) readHead() (tag, typ byte, err error) {
+	b, err := r.next(1)
+	if err != nil {
+		return 0, 0, err
+	}
+	tag, typ = b[0]>>4, b[0]&0x0F
+	if tag == 15 {
+		b, err = r.next(1)
+		if err != nil {
+			return 0, 0, err
+		}
+		tag = b[0]
+	}
+	return tag, typ, nil
+}
+
+// readFields 读取结构体字段，nested 为 true 时读到 StructEnd 为止，否则读到数据结尾
+func (r *tarsReader) readFields(nested bool) (tarsStruct, error) {
+	s := make(tarsStruct)
+	for r.pos < len(r.data) {
+		tag, typ, err := r.readHead()
+		if err != nil {
+			return nil, err
+		}
+		if typ == tarsStructEnd {
+			if nested {
+				return s, nil
+			}
+			continue
+		}
+		v, err := r.readValue(typ)
+		if err != nil {
+			return nil, err
+		}
+		s[tag] = v
+	}
+	if nested {
+		return nil, fmt.Errorf("tars: 结构体未结束")
+	}
+	return s, nil
+}
+
+func (r *tarsReader) readLength() (int, error) {
+	_, typ, err := r.readHead()
+	if err != nil {
+		return 0, err
+	}
+	v, err := r.readValue(typ)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := v.(int64)
+	if !ok || n < 0 || n > int64(len(r.data)) {
+		return 0, fmt.Errorf("tars: 无效的长度")
+	}
+	return int(n), nil
+}
+
+func (r *tarsReader) readValue(typ byte) (interface{}, error) {
+	switch typ {
+	case tarsZero:
+		return int64(0), nil
+	case tarsInt8:
+		b, err := r.next(1)
+		if err != nil {
+			return nil, err
+		}
+		return int64(int8(b[0])), nil
+	case tarsInt16:
+		b, err := r.next(2)
+		if err != nil {
+			return nil, err
+		}
+		return int64(int16(binary.BigEndian.Uint16(b))), nil
+	case tarsInt32:
+		b, err := r.next(4)
+		if err != nil {
+			return nil, err
+		}
+		return int64(int32(binary.BigEndian.Uint32(b))), nil
+	case tarsInt64:
+		b, err := r.next(8)
+		if err != nil {
+			return nil, err
+		}
+		return int64(binary.BigEndian.Uint64(b)), nil
+	case tarsFloat:
+		b, err := r.next(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+	case tarsDouble:
+		b, err := r.next(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	case tarsString1:
+		b, err := r.next(1)
+		if err != nil {
+			return nil, err
+		}
+		s, err := r.next(int(b[0]))
+		return string(s), err
+	case tarsString4:
+		b, err := r.next(4)
+		if err != nil {
+			return nil, err
+		}
+		s, err := r.next(int(binary.BigEndian.Uint32(b)))
+		return string(s), err
+	case tarsMap:
+		n, err := r.readLength()
+		if err != nil {
+			return nil, err
+		}
+		// 键值交替存放
+		items := make([]interface{}, 0, 2*n)
+		for i := 0; i < 2*n; i++ {
+			_, t, err := r.readHead()
+			if err != nil {
+				return nil, err
+			}
+			v, err := r.readValue(t)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		return items, nil
+	case tarsList:
+		n, err := r.readLength()
+		if err != nil {
+			return nil, err
+		}
+		items := make([]interface{}, 0, n)
+		for i := 0; i < n; i++ {
+			_, t, err := r.readHead()
+			if err != nil {
+				return nil, err
+			}
+			v, err := r.readValue(t)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		return items, nil
+	case tarsStructBegin:
+		return r.readFields(true)
+	case tarsSimpleList:
+		if _, _, err := r.readHead(); err != nil {
+			return nil, err
+		}
+		n, err := r.readLength()
+		if err != nil {
+			return nil, err
+		}
+		return r.next(n)
+	}
+	return nil, fmt.Errorf("tars: 未知类型 %d", typ)
+}
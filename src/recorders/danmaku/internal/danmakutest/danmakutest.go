@@ -0,0 +1,46 @@
+// Package danmakutest 弹幕客户端协议测试共用的辅助函数。
+// 虎牙、快手、AcFun、SOOP、Twitch 测试中的帧均按协议格式构造，不是真实抓包数据，
+// 只能验证解析逻辑与实现者理解的协议一致，不能发现真实服务器返回的字段差异，
+// 这些平台的弹幕录制因此标记为实验性。取得真实抓包后应替换对应测试中的帧。
+package danmakutest
+
+import (
+	"encoding/hex"
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Chat 一条解析出的弹幕
+type Chat struct {
+	User    string
+	Content string
+	Color   int
+}
+
+// Collector 收集协议解析出的弹幕，OnDanmaku 可直接作为客户端的弹幕回调
+type Collector struct {
+	Chats []Chat
+}
+
+func (c *Collector) OnDanmaku(username, content string, color int) {
+	c.Chats = append(c.Chats, Chat{username, content, color})
+}
+
+// Logger 返回丢弃输出的日志记录器
+func Logger() *logrus.Entry {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logrus.NewEntry(logger)
+}
+
+// Hex 解码十六进制表示的二进制帧
+func Hex(t testing.TB, s string) []byte {
+	t.Helper()
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("无效的十六进制帧: %v", err)
+	}
+	return data
+}
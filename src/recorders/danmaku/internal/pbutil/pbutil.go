@@ -0,0 +1,111 @@
+// Package pbutil 提供不依赖生成代码的 protobuf 编解码辅助，
+// 用于只需要少量字段的弹幕协议（快手、AcFun）
+package pbutil
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Message 解码后的消息，按字段号保存原始值：varint 与定长类型为 uint64，length-delimited 为 []byte
+type Message map[protowire.Number][]interface{}
+
+// Decode 解码一条 protobuf 消息
+func Decode(data []byte) (Message, error) {
+	m := make(Message)
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, fmt.Errorf("protobuf: %w", protowire.ParseError(n))
+		}
+		data = data[n:]
+		var v interface{}
+		switch typ {
+		case protowire.VarintType:
+			v, n = protowire.ConsumeVarint(data)
+		case protowire.Fixed32Type:
+			var x uint32
+			x, n = protowire.ConsumeFixed32(data)
+			v = uint64(x)
+		case protowire.Fixed64Type:
+			v, n = protowire.ConsumeFixed64(data)
+		case protowire.BytesType:
+			v, n = protowire.ConsumeBytes(data)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return nil, fmt.Errorf("protobuf: %w", protowire.ParseError(n))
+		}
+		data = data[n:]
+		if v != nil {
+			m[num] = append(m[num], v)
+		}
+	}
+	return m, nil
+}
+
+// Uint 返回字段的最后一个整数值
+func (m Message) Uint(num protowire.Number) uint64 {
+	values := m[num]
+	if len(values) == 0 {
+		return 0
+	}
+	v, _ := values[len(values)-1].(uint64)
+	return v
+}
+
+// Bytes 返回字段的最后一个 length-delimited 值
+func (m Message) Bytes(num protowire.Number) []byte {
+	values := m[num]
+	if len(values) == 0 {
+		return nil
+	}
+	v, _ := values[len(values)-1].([]byte)
+	return v
+}
+
+// String 返回字符串字段
+func (m Message) String(num protowire.Number) string {
+	return string(m.Bytes(num))
+}
+
+// Message 解码嵌套消息字段，字段不存在时返回空消息
+func (m Message) Message(num protowire.Number) (Message, error) {
+	return Decode(m.Bytes(num))
+}
+
+// Repeated 返回 repeated length-delimited 字段的所有值
+func (m Message) Repeated(num protowire.Number) [][]byte {
+	var result [][]byte
+	for _, v := range m[num] {
+		if b, ok := v.([]byte); ok {
+			result = append(result, b)
+		}
+	}
+	return result
+}
+
+// AppendVarint 追加整数字段，零值不编码
+func AppendVarint(b []byte, num protowire.Number, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+// AppendBytes 追加 length-delimited 字段，空值不编码
+func AppendBytes(b []byte, num protowire.Number, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+// AppendString 追加字符串字段，空字符串不编码
+func AppendString(b []byte, num protowire.Number, v string) []byte {
+	return AppendBytes(b, num, []byte(v))
+}
@@ -0,0 +1,235 @@
+// Package kuaishou 实现快手直播弹幕客户端。
+// 进房令牌与服务器地址来自网页端接口，消息为 protobuf 编码的 SocketMessage
+package kuaishou
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/internal/pbutil"
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/wsclient"
+)
+
+const (
+	liveDetailAPI    = "https://live.kuaishou.com/live_api/liveroom/livedetail"
+	websocketInfoAPI = "https://live.kuaishou.com/live_api/liveroom/websocketinfo"
+
+	heartbeatInterval = 20 * time.Second
+)
+
+// SocketMessage.payloadType
+const (
+	payloadCSHeartbeat = 1
+	payloadSCError     = 103
+	payloadCSEnterRoom = 200
+	payloadSCFeedPush  = 310
+)
+
+// SocketMessage.compressionType
+const compressionGzip = 2
+
+type protocol struct {
+	principalID string
+	cookies     string
+	onDanmaku   func(username, content string, color int)
+	logger      *logrus.Entry
+}
+
+// NewKuaishouClient 创建快手弹幕客户端，principalID 为主播 ID（直播间地址 /u/ 之后的部分）
+func NewKuaishouClient(principalID, cookies string, onDanmaku func(username, content string, color int), logger *logrus.Entry) *wsclient.Client {
+	return wsclient.New(&protocol{
+		principalID: principalID,
+		cookies:     cookies,
+		onDanmaku:   onDanmaku,
+		logger:      logger,
+	}, logger)
+}
+
+func (p *protocol) Name() string { return "快手" }
+
+func (p *protocol) HeartbeatInterval() time.Duration { return heartbeatInterval }
+
+func (p *protocol) MessageType() int { return websocket.BinaryMessage }
+
+func (p *protocol) Heartbeat() ([][]byte, error) {
+	heartbeat := pbutil.AppendVarint(nil, 1, uint64(time.Now().UnixMilli()))
+	return [][]byte{encodeSocketMessage(payloadCSHeartbeat, heartbeat)}, nil
+}
+
+func (p *protocol) Connect(ctx context.Context) (*websocket.Conn, error) {
+	detail, err := p.getJSON(ctx, liveDetailAPI+"?principalId="+url.QueryEscape(p.principalID))
+	if err != nil {
+		return nil, fmt.Errorf("获取直播间信息失败: %w", err)
+	}
+	liveStreamID := detail.Get("data.liveStream.id").String()
+	if liveStreamID == "" {
+		return nil, fmt.Errorf("未获取到 liveStreamId，主播可能未开播")
+	}
+
+	info, err := p.getJSON(ctx, websocketInfoAPI+"?liveStreamId="+url.QueryEscape(liveStreamID))
+	if err != nil {
+		return nil, fmt.Errorf("获取弹幕服务器信息失败: %w", err)
+	}
+	token := info.Get("data.token").String()
+	wsURL := info.Get("data.websocketUrls.0").String()
+	if token == "" || wsURL == "" {
+		return nil, fmt.Errorf("接口未返回进房令牌")
+	}
+	p.logger.Infof("连接快手弹幕服务器: principalID=%s liveStreamId=%s addr=%s", p.principalID, liveStreamID, wsURL)
+
+	conn, err := wsclient.Dial(ctx, wsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.WriteMessage(websocket.BinaryMessage, encodeEnterRoom(token, liveStreamID, newPageID())); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("发送进房消息失败: %w", err)
+	}
+	return conn, nil
+}
+
+func (p *protocol) getJSON(ctx context.Context, rawURL string) (*gjson.Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", wsclient.UserAgent)
+	req.Header.Set("Referer", "https://live.kuaishou.com/u/"+p.principalID)
+	if p.cookies != "" {
+		req.Header.Set("Cookie", p.cookies)
+	}
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	result := gjson.ParseBytes(body)
+	return &result, nil
+}
+
+// newPageID 生成网页端格式的 pageId：16 位随机字符 + 毫秒时间戳
+func newPageID() string {
+	const chars = "-_zyxwvutsrqponmlkjihgfedcbaZYXWVUTSRQPONMLKJIHGFEDCBA9876543210"
+	var sb strings.Builder
+	for i := 0; i < 16; i++ {
+		sb.WriteByte(chars[rand.Intn(len(chars))])
+	}
+	sb.WriteString("_")
+	sb.WriteString(strconv.FormatInt(time.Now().UnixMilli(), 10))
+	return sb.String()
+}
+
+// encodeSocketMessage 编码 SocketMessage: payloadType(1) compressionType(2) payload(3)
+func encodeSocketMessage(payloadType uint64, payload []byte) []byte {
+	b := pbutil.AppendVarint(nil, 1, payloadType)
+	return pbutil.AppendBytes(b, 3, payload)
+}
+
+// encodeEnterRoom 编码 CSWebEnterRoom: token(1) liveStreamId(2) pageId(7)
+func encodeEnterRoom(token, liveStreamID, pageID string) []byte {
+	b := pbutil.AppendString(nil, 1, token)
+	b = pbutil.AppendString(b, 2, liveStreamID)
+	b = pbutil.AppendString(b, 7, pageID)
+	return encodeSocketMessage(payloadCSEnterRoom, b)
+}
+
+func (p *protocol) Handle(data []byte, _ func([]byte) error) error {
+	msg, err := pbutil.Decode(data)
+	if err != nil {
+		p.logger.WithError(err).Debug("解析快手消息失败")
+		return nil
+	}
+	payload := msg.Bytes(3)
+	if msg.Uint(2) == compressionGzip {
+		if payload, err = gunzip(payload); err != nil {
+			p.logger.WithError(err).Debug("解压快手消息失败")
+			return nil
+		}
+	}
+	switch msg.Uint(1) {
+	case payloadSCError:
+		// SCError: code(1) msg(2)
+		e, _ := pbutil.Decode(payload)
+		return fmt.Errorf("服务器返回错误: code=%d msg=%s", e.Uint(1), e.String(2))
+	case payloadSCFeedPush:
+		comments, err := decodeFeedPush(payload)
+		if err != nil {
+			p.logger.WithError(err).Debug("解析快手弹幕失败")
+			return nil
+		}
+		if p.onDanmaku == nil {
+			return nil
+		}
+		for _, c := range comments {
+			p.onDanmaku(c.username, c.content, c.color)
+		}
+	}
+	return nil
+}
+
+type comment struct {
+	username string
+	content  string
+	color    int
+}
+
+// decodeFeedPush 解析 SCWebFeedPush 中的 commentFeeds(5)。
+// WebCommentFeed: user(2).userName(2)、content(3)、color(6)
+func decodeFeedPush(payload []byte) ([]comment, error) {
+	push, err := pbutil.Decode(payload)
+	if err != nil {
+		return nil, err
+	}
+	var comments []comment
+	for _, raw := range push.Repeated(5) {
+		feed, err := pbutil.Decode(raw)
+		if err != nil {
+			return nil, err
+		}
+		user, err := feed.Message(2)
+		if err != nil {
+			return nil, err
+		}
+		c := comment{
+			username: user.String(2),
+			content:  feed.String(3),
+			color:    wsclient.DefaultColor,
+		}
+		if v, err := strconv.ParseInt(strings.TrimPrefix(feed.String(6), "#"), 16, 32); err == nil {
+			c.color = int(v)
+		}
+		if c.username != "" && c.content != "" {
+			comments = append(comments, c)
+		}
+	}
+	return comments, nil
+}
+
+func gunzip(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
@@ -0,0 +1,44 @@
+package kuaishou
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/internal/danmakutest"
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/internal/pbutil"
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/wsclient"
+)
+
+// 按 protobuf 格式构造的 SC_FEED_PUSH（未压缩与 gzip 压缩各一帧）以及 SC_ERROR
+const (
+	feedPush     = "08b60210011aaa010a06312e32e4b8871206332e34e4b88720e8072a5c0a10313730303030303030303030305f6331121f0a0f33786b377a71326d66793463656b65120ce5bfabe6898be794a8e688b71a12e4b8bbe692ade594b1e5be97e79c9fe5a5bd2208643431643863643928013207234646384330302a370a10313730303030303030303030315f6332121c0a0f33786876626e3865367577736277671209e8b7afe4babae794b21a033636362802"
+	feedPushGzip = "08b60210021aae011f8b08000000000000ffe26233d4337ab2a35d88cd58cfe4c98e768517ec5a315c0286e60608109f6c2824cfc56f5c916d5e5568949b5669929c9a9d2ac4f374ffea679dddcfa7ac78d6b15d4ae8c98eddcf26ad7d3a65e3d37dd39fcf99ff74e95e258e1413c3148be4144b0d4623766537370b6703032d7354d30de3938d846440a6679425e559a49a9596172795a70b71bed8befec9ae5dcfa76c9262363333d360020c000e9ed3f8aa000000"
+	scError      = "086710011a1208e907120d746f6b656e2065787069726564"
+)
+
+func TestHandleFeedPush(t *testing.T) {
+	var c danmakutest.Collector
+	p := &protocol{onDanmaku: c.OnDanmaku, logger: danmakutest.Logger()}
+	for _, frame := range []string{feedPush, feedPushGzip} {
+		assert.NoError(t, p.Handle(danmakutest.Hex(t, frame), nil))
+	}
+	want := []danmakutest.Chat{
+		{User: "快手用户", Content: "主播唱得真好", Color: 0xFF8C00},
+		{User: "路人甲", Content: "666", Color: wsclient.DefaultColor},
+	}
+	assert.Equal(t, append(want, want...), c.Chats)
+
+	assert.ErrorContains(t, p.Handle(danmakutest.Hex(t, scError), nil), "token expired")
+}
+
+func TestEncodeEnterRoom(t *testing.T) {
+	msg, err := pbutil.Decode(encodeEnterRoom("tok", "ls-1", "page"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(payloadCSEnterRoom), msg.Uint(1))
+	enter, err := msg.Message(3)
+	assert.NoError(t, err)
+	assert.Equal(t, "tok", enter.String(1))
+	assert.Equal(t, "ls-1", enter.String(2))
+	assert.Equal(t, "page", enter.String(7))
+}
@@ -0,0 +1,193 @@
+// Package soop 实现 SOOP（原 AfreecaTV）聊天室弹幕客户端。
+// 聊天协议包格式：ESC TAB + 4 位服务号 + 6 位正文长度 + 2 位返回码 + 以 \x0c 分隔的正文
+package soop
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/wsclient"
+)
+
+const (
+	channelAPIURL = "https://live.sooplive.com/afreeca/player_live_api.php"
+
+	heartbeatInterval = 60 * time.Second
+
+	headerLen = 14
+	separator = "\x0c"
+)
+
+// 服务号
+const (
+	svcKeepAlive = 0
+	svcLogin     = 1
+	svcJoinCh    = 2
+	svcChatMsg   = 5
+)
+
+type protocol struct {
+	bjID      string
+	cookies   string
+	onDanmaku func(username, content string, color int)
+	logger    *logrus.Entry
+}
+
+// NewSoopClient 创建 SOOP 弹幕客户端，bjID 为主播 ID
+func NewSoopClient(bjID, cookies string, onDanmaku func(username, content string, color int), logger *logrus.Entry) *wsclient.Client {
+	return wsclient.New(&protocol{
+		bjID:      bjID,
+		cookies:   cookies,
+		onDanmaku: onDanmaku,
+		logger:    logger,
+	}, logger)
+}
+
+func (p *protocol) Name() string { return "SOOP" }
+
+func (p *protocol) HeartbeatInterval() time.Duration { return heartbeatInterval }
+
+func (p *protocol) Heartbeat() ([][]byte, error) {
+	return [][]byte{encodePacket(svcKeepAlive, separator)}, nil
+}
+
+func (p *protocol) MessageType() int { return websocket.BinaryMessage }
+
+// chatInfo 聊天服务器信息
+type chatInfo struct {
+	domain string
+	port   int64
+	chatNo string
+}
+
+func (p *protocol) Connect(ctx context.Context) (*websocket.Conn, error) {
+	info, err := p.fetchChatInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取聊天服务器信息失败: %w", err)
+	}
+	// 网页端使用 CHPT+1 端口的 WebSocket 服务
+	wsURL := fmt.Sprintf("wss://%s:%d/Websocket/%s", info.domain, info.port+1, url.PathEscape(p.bjID))
+	p.logger.Infof("连接 SOOP 聊天服务器: bjID=%s chatNo=%s addr=%s", p.bjID, info.chatNo, wsURL)
+
+	conn, err := wsclient.Dial(ctx, wsURL, nil, "chat")
+	if err != nil {
+		return nil, err
+	}
+	// 匿名登录后加入聊天室
+	packets := [][]byte{
+		encodePacket(svcLogin, strings.Repeat(separator, 3)+"16"+separator),
+		encodePacket(svcJoinCh, separator+info.chatNo+strings.Repeat(separator, 5)),
+	}
+	for _, packet := range packets {
+		if err := conn.WriteMessage(websocket.BinaryMessage, packet); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("加入聊天室失败: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+func (p *protocol) fetchChatInfo(ctx context.Context) (*chatInfo, error) {
+	form := url.Values{
+		"bid":         {p.bjID},
+		"type":        {"live"},
+		"player_type": {"html5"},
+		"mode":        {"landing"},
+		"from_api":    {"0"},
+		"stream_type": {"common"},
+		"pwd":         {""},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, channelAPIURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", wsclient.UserAgent)
+	req.Header.Set("Referer", "https://play.sooplive.com/"+p.bjID)
+	if p.cookies != "" {
+		req.Header.Set("Cookie", p.cookies)
+	}
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	channel := gjson.GetBytes(body, "CHANNEL")
+	if result := channel.Get("RESULT").Int(); result != 1 {
+		return nil, fmt.Errorf("接口返回 RESULT=%d", result)
+	}
+	info := &chatInfo{
+		domain: strings.ToLower(channel.Get("CHDOMAIN").String()),
+		port:   channel.Get("CHPT").Int(),
+		chatNo: channel.Get("CHATNO").String(),
+	}
+	if info.domain == "" || info.port == 0 || info.chatNo == "" {
+		return nil, fmt.Errorf("接口未返回聊天服务器信息")
+	}
+	return info, nil
+}
+
+// encodePacket 编码一个聊天协议包
+func encodePacket(svc int, body string) []byte {
+	return []byte(fmt.Sprintf("\x1b\t%04d%06d00%s", svc, len(body), body))
+}
+
+// decodePacket 解析聊天协议包，返回服务号与按 \x0c 分隔的正文字段
+func decodePacket(data []byte) (svc int, fields []string, err error) {
+	if len(data) < headerLen || data[0] != 0x1b || data[1] != '\t' {
+		return 0, nil, fmt.Errorf("无效的聊天协议包")
+	}
+	svc, err = strconv.Atoi(string(data[2:6]))
+	if err != nil {
+		return 0, nil, fmt.Errorf("无效的服务号: %w", err)
+	}
+	return svc, strings.Split(string(data[headerLen:]), separator), nil
+}
+
+func (p *protocol) Handle(data []byte, _ func([]byte) error) error {
+	svc, fields, err := decodePacket(data)
+	if err != nil {
+		p.logger.WithError(err).Debug("解析 SOOP 消息失败")
+		return nil
+	}
+	if svc != svcChatMsg || p.onDanmaku == nil {
+		return nil
+	}
+	username, content := parseChat(fields)
+	if username != "" && content != "" {
+		p.onDanmaku(username, content, wsclient.DefaultColor)
+	}
+	return nil
+}
+
+// parseChat 解析聊天消息字段：[1] 内容、[2] 用户 ID、[6] 昵称
+func parseChat(fields []string) (username, content string) {
+	if len(fields) < 7 {
+		return "", ""
+	}
+	content = fields[1]
+	// 内容为 -1、1 或包含 | 的是系统消息
+	if content == "-1" || content == "1" || strings.Contains(content, "|") {
+		return "", ""
+	}
+	username = fields[6]
+	if username == "" {
+		// 用户 ID 可能带有 "(2)" 之类的多开后缀
+		username, _, _ = strings.Cut(fields[2], "(")
+	}
+	return username, content
+}
@@ -0,0 +1,36 @@
+package soop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/internal/danmakutest"
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/wsclient"
+)
+
+// 按 SOOP 聊天协议构造的帧：一条普通聊天、一条系统消息与一次心跳
+var chatFrames = []string{
+	"\x1b\t000500006600\x0c안녕하세요 ㅋㅋ\x0cviewer123(2)\x0c0\x0c1\x0c0\x0c시청자\x0c524288\x0c-1\x0c-1\x0c",
+	"\x1b\t000500001600\x0c-1\x0cviewer123\x0c0\x0c",
+	"\x1b\t000000000100\x0c",
+}
+
+func TestHandleChatFrames(t *testing.T) {
+	var c danmakutest.Collector
+	p := &protocol{onDanmaku: c.OnDanmaku, logger: danmakutest.Logger()}
+	for _, frame := range chatFrames {
+		assert.NoError(t, p.Handle([]byte(frame), nil))
+	}
+	assert.Equal(t, []danmakutest.Chat{{User: "시청자", Content: "안녕하세요 ㅋㅋ", Color: wsclient.DefaultColor}}, c.Chats)
+}
+
+func TestEncodePacket(t *testing.T) {
+	assert.Equal(t, "\x1b\t000000000100\x0c", string(encodePacket(svcKeepAlive, "\x0c")))
+	assert.Equal(t, "\x1b\t000100000600\x0c\x0c\x0c16\x0c", string(encodePacket(svcLogin, "\x0c\x0c\x0c16\x0c")))
+
+	svc, fields, err := decodePacket(encodePacket(svcJoinCh, "\x0c123456\x0c\x0c\x0c\x0c\x0c"))
+	assert.NoError(t, err)
+	assert.Equal(t, svcJoinCh, svc)
+	assert.Equal(t, "123456", fields[1])
+}
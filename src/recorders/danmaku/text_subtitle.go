@@ -0,0 +1,226 @@
+package danmaku
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 文本字幕格式，作为 ASS 之外的附加输出
+const (
+	FormatSRT = "srt"
+	FormatVTT = "vtt"
+)
+
+// TextSubtitleExts 附加字幕文件的扩展名，与 ASS 文件同名，随视频一起重命名、删除
+var TextSubtitleExts = []string{".srt", ".vtt"}
+
+const (
+	// chatBoxLines 聊天框最多同时显示的行数
+	chatBoxLines = 5
+	// chatHoldDanmaku 弹幕和礼物在聊天框中停留的时间
+	chatHoldDanmaku = 5 * time.Second
+	// chatHoldPaid SC 和上舰消息停留的时间
+	chatHoldPaid = 10 * time.Second
+)
+
+// chatLine 聊天框中的一行
+type chatLine struct {
+	text     string
+	expireAt time.Time
+}
+
+// TextSubtitleWriter 将弹幕写为 SRT 或 WebVTT 字幕。
+// 这两种格式不支持滚动，采用静态布局：画面左下角的聊天框显示最近几条消息，
+// 新消息从底部加入，超过行数或停留时间的消息移出。每次内容变化生成一个新的字幕条目，
+// 条目之间互不重叠，浏览器和移动端播放器都能正常显示。
+type TextSubtitleWriter struct {
+	mu       sync.Mutex
+	file     *os.File
+	format   string
+	closed   bool
+	writeErr bool
+	startAt  time.Time
+	index    int        // SRT 条目序号
+	lines    []chatLine // 当前聊天框内容，旧消息在前
+	cueStart time.Time  // 当前聊天框内容开始显示的时间
+}
+
+// NewTextSubtitleWriter 创建 format 格式（FormatSRT 或 FormatVTT）的字幕文件
+func NewTextSubtitleWriter(filePath string, startAt time.Time, format string) (*TextSubtitleWriter, error) {
+	if format != FormatSRT && format != FormatVTT {
+		return nil, fmt.Errorf("unsupported subtitle format: %s", format)
+	}
+	f, err := os.Create(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s file: %w", format, err)
+	}
+	w := &TextSubtitleWriter{file: f, format: format, startAt: startAt}
+	if err := w.writeHeader(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *TextSubtitleWriter) writeHeader() error {
+	if w.format != FormatVTT {
+		return nil
+	}
+	_, err := w.file.WriteString("WEBVTT\n\n")
+	return err
+}
+
+// AddLine 向聊天框加入一行，hold 为停留时间
+func (w *TextSubtitleWriter) AddLine(recvAt time.Time, text string, hold time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed || w.writeErr {
+		return
+	}
+	// 合并弹幕会以首次出现的时间延后写入，字幕条目只能向后追加
+	if recvAt.Before(w.cueStart) {
+		recvAt = w.cueStart
+	}
+	w.flush(recvAt)
+	w.lines = append(w.lines, chatLine{
+		text:     strings.Join(strings.Fields(text), " "),
+		expireAt: recvAt.Add(hold),
+	})
+	if len(w.lines) > chatBoxLines {
+		w.lines = w.lines[len(w.lines)-chatBoxLines:]
+	}
+	w.cueStart = recvAt
+}
+
+// flush 输出从 cueStart 到 until 之间的条目，期间有消息到期时在到期处切分
+func (w *TextSubtitleWriter) flush(until time.Time) {
+	for len(w.lines) > 0 && w.cueStart.Before(until) {
+		end := until
+		for _, l := range w.lines {
+			if l.expireAt.Before(end) {
+				end = l.expireAt
+			}
+		}
+		if end.After(w.cueStart) {
+			w.writeCue(w.cueStart, end)
+		}
+		w.cueStart = end
+		kept := w.lines[:0]
+		for _, l := range w.lines {
+			if l.expireAt.After(end) {
+				kept = append(kept, l)
+			}
+		}
+		w.lines = kept
+	}
+}
+
+func (w *TextSubtitleWriter) writeCue(start, end time.Time) {
+	texts := make([]string, len(w.lines))
+	for i, l := range w.lines {
+		texts[i] = l.text
+		if w.format == FormatVTT {
+			texts[i] = escapeVTT(l.text)
+		}
+	}
+	var cue string
+	from, to := w.offset(start), w.offset(end)
+	if w.format == FormatVTT {
+		cue = fmt.Sprintf("%s --> %s line:-1 position:2%% align:left\n%s\n\n",
+			formatVTTTime(from), formatVTTTime(to), strings.Join(texts, "\n"))
+	} else {
+		w.index++
+		cue = fmt.Sprintf("%d\n%s --> %s\n%s\n\n",
+			w.index, formatSRTTime(from), formatSRTTime(to), strings.Join(texts, "\n"))
+	}
+	if _, err := w.file.WriteString(cue); err != nil {
+		w.writeErr = true
+	}
+}
+
+func (w *TextSubtitleWriter) offset(t time.Time) time.Duration {
+	d := t.Sub(w.startAt)
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// drain 输出聊天框中剩余的全部内容
+func (w *TextSubtitleWriter) drain() {
+	var last time.Time
+	for _, l := range w.lines {
+		if l.expireAt.After(last) {
+			last = l.expireAt
+		}
+	}
+	w.flush(last)
+	w.lines = nil
+}
+
+// SetTimelineStart 重设时间轴零点，与 AssWriter.SetTimelineStart 相同
+func (w *TextSubtitleWriter) SetTimelineStart(startAt time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.startAt = startAt
+}
+
+// Rotate 写完当前聊天框后改为写入 filePath，时间轴从 startAt 重新计时
+func (w *TextSubtitleWriter) Rotate(filePath string, startAt time.Time) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return fmt.Errorf("%s writer closed", w.format)
+	}
+	f, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s file: %w", w.format, err)
+	}
+	w.drain()
+	old := w.file
+	w.file = f
+	w.startAt = startAt
+	w.cueStart = time.Time{}
+	w.index = 0
+	w.writeErr = false
+	old.Close()
+	if err := w.writeHeader(); err != nil {
+		w.writeErr = true
+		return err
+	}
+	return nil
+}
+
+func (w *TextSubtitleWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	if !w.writeErr {
+		w.drain()
+	}
+	w.closed = true
+	return w.file.Close()
+}
+
+// formatSRTTime 格式化为 SRT 时间 00:00:00,000
+func formatSRTTime(d time.Duration) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+// formatVTTTime 格式化为 WebVTT 时间 00:00:00.000
+func formatVTTTime(d time.Duration) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+var vttEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func escapeVTT(s string) string {
+	return vttEscaper.Replace(s)
+}
@@ -0,0 +1,79 @@
+package danmaku
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+)
+
+func TestTextSubtitleCues(t *testing.T) {
+	start := time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "video.srt")
+	w, err := NewTextSubtitleWriter(path, start, FormatSRT)
+	if !assert.NoError(t, err) {
+		return
+	}
+	w.AddLine(start.Add(1*time.Second), "a: 你好", chatHoldDanmaku)
+	w.AddLine(start.Add(3*time.Second), "b: <b>", chatHoldDanmaku)
+	w.AddLine(start.Add(20*time.Second), "c: 再见", chatHoldDanmaku)
+	assert.NoError(t, w.Close())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	// 每次聊天框变化生成一个条目，条目首尾相接；a 在第 6 秒到期移出
+	assert.Equal(t, "1\n00:00:01,000 --> 00:00:03,000\na: 你好\n\n"+
+		"2\n00:00:03,000 --> 00:00:06,000\na: 你好\nb: <b>\n\n"+
+		"3\n00:00:06,000 --> 00:00:08,000\nb: <b>\n\n"+
+		"4\n00:00:20,000 --> 00:00:25,000\nc: 再见\n\n", string(data))
+}
+
+func TestAssWriterCompanionVTT(t *testing.T) {
+	cfg := configs.GetDefaultDanmakuConfig()
+	cfg.SubtitleFormats = []string{FormatVTT}
+	start := time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	w, err := NewAssWriter(filepath.Join(dir, "video.ass"), start, cfg, "test")
+	if !assert.NoError(t, err) {
+		return
+	}
+	w.AddDanmaku(start.Add(2*time.Second), "a", "x<y", 0)
+	assert.NoError(t, w.Rotate(filepath.Join(dir, "video_PART001.ass"), start.Add(time.Minute)))
+	w.AddSuperChat(start.Add(61*time.Second), "b", "谢谢", 30)
+	assert.NoError(t, w.Close())
+
+	first, err := os.ReadFile(filepath.Join(dir, "video.vtt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "WEBVTT\n\n00:00:02.000 --> 00:00:07.000 line:-1 position:2% align:left\na: x&lt;y\n\n", string(first))
+	second, err := os.ReadFile(filepath.Join(dir, "video_PART001.vtt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "WEBVTT\n\n00:00:01.000 --> 00:00:11.000 line:-1 position:2% align:left\n[SC ¥30] b: 谢谢\n\n", string(second))
+}
+
+func TestAssWriterRotateCompanionFailure(t *testing.T) {
+	cfg := configs.GetDefaultDanmakuConfig()
+	cfg.SubtitleFormats = []string{FormatVTT}
+	start := time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	w, err := NewAssWriter(filepath.Join(dir, "video.ass"), start, cfg, "test")
+	if !assert.NoError(t, err) {
+		return
+	}
+	// 附属字幕文件路径被目录占用，无法创建
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "video_PART001.vtt"), 0755))
+	err = w.Rotate(filepath.Join(dir, "video_PART001.ass"), start.Add(time.Minute))
+	assert.ErrorIs(t, err, ErrCompanionRotate)
+	w.AddDanmaku(start.Add(62*time.Second), "a", "hi", 0)
+	assert.NoError(t, w.Close())
+
+	// ASS 文件已切换并写入文件头，之后的弹幕正常写入
+	data, err := os.ReadFile(filepath.Join(dir, "video_PART001.ass"))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "[Script Info]"))
+	assert.Contains(t, string(data), "a: hi")
+}
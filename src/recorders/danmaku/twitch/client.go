@@ -0,0 +1,150 @@
+// Package twitch 实现 Twitch 聊天室（IRC over WebSocket）的弹幕客户端，
+// 以匿名 justinfan 身份只读加入频道
+package twitch
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/wsclient"
+)
+
+const (
+	ircURL = "wss://irc-ws.chat.twitch.tv:443"
+
+	// 服务器约 5 分钟才发送一次 PING，主动 PING 保证读超时内有数据返回
+	heartbeatInterval = 60 * time.Second
+)
+
+type protocol struct {
+	channel   string
+	onDanmaku func(username, content string, color int)
+	logger    *logrus.Entry
+}
+
+// NewTwitchClient 创建 Twitch 弹幕客户端，channel 为频道登录名
+func NewTwitchClient(channel string, onDanmaku func(username, content string, color int), logger *logrus.Entry) *wsclient.Client {
+	return wsclient.New(&protocol{
+		channel:   strings.ToLower(channel),
+		onDanmaku: onDanmaku,
+		logger:    logger,
+	}, logger)
+}
+
+func (p *protocol) Name() string { return "Twitch" }
+
+func (p *protocol) HeartbeatInterval() time.Duration { return heartbeatInterval }
+
+func (p *protocol) Heartbeat() ([][]byte, error) { return [][]byte{[]byte("PING :tmi.twitch.tv")}, nil }
+
+func (p *protocol) MessageType() int { return websocket.TextMessage }
+
+func (p *protocol) Connect(ctx context.Context) (*websocket.Conn, error) {
+	p.logger.Infof("连接 Twitch 聊天室: channel=%s", p.channel)
+	conn, err := wsclient.Dial(ctx, ircURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	nick := fmt.Sprintf("justinfan%d", 10000+rand.Intn(90000))
+	for _, line := range []string{
+		"CAP REQ :twitch.tv/tags twitch.tv/commands",
+		"PASS SCHMOOPIIE",
+		"NICK " + nick,
+		"JOIN #" + p.channel,
+	} {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(line)); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("加入频道失败: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+func (p *protocol) Handle(data []byte, send func([]byte) error) error {
+	for _, line := range strings.Split(string(data), "\r\n") {
+		if line == "" {
+			continue
+		}
+		msg := parseMessage(line)
+		switch msg.command {
+		case "PING":
+			if err := send([]byte("PONG :" + msg.trailing)); err != nil {
+				return err
+			}
+		case "PRIVMSG":
+			username, content, color := msg.chat()
+			if username != "" && content != "" && p.onDanmaku != nil {
+				p.onDanmaku(username, content, color)
+			}
+		case "RECONNECT":
+			return fmt.Errorf("服务器要求重连")
+		case "NOTICE":
+			p.logger.Debugf("Twitch 聊天室通知: %s", msg.trailing)
+		}
+	}
+	return nil
+}
+
+// message 一行 IRC 消息
+type message struct {
+	tags     map[string]string
+	prefix   string
+	command  string
+	params   []string
+	trailing string
+}
+
+// parseMessage 解析一行 IRCv3 消息：[@tags] [:prefix] COMMAND [params] [:trailing]
+func parseMessage(line string) message {
+	var msg message
+	if strings.HasPrefix(line, "@") {
+		rawTags, rest, _ := strings.Cut(line[1:], " ")
+		msg.tags = make(map[string]string)
+		for _, tag := range strings.Split(rawTags, ";") {
+			key, value, _ := strings.Cut(tag, "=")
+			msg.tags[key] = unescapeTagValue(value)
+		}
+		line = rest
+	}
+	if strings.HasPrefix(line, ":") {
+		msg.prefix, line, _ = strings.Cut(line[1:], " ")
+	}
+	line, msg.trailing, _ = strings.Cut(line, " :")
+	fields := strings.Fields(line)
+	if len(fields) > 0 {
+		msg.command = fields[0]
+		msg.params = fields[1:]
+	}
+	return msg
+}
+
+// chat 从 PRIVMSG 中取出发送者、内容与颜色
+func (m message) chat() (username, content string, color int) {
+	username = m.tags["display-name"]
+	if username == "" {
+		username, _, _ = strings.Cut(m.prefix, "!")
+	}
+	content = m.trailing
+	// /me 消息以 CTCP ACTION 形式发送
+	if strings.HasPrefix(content, "\x01ACTION ") {
+		content = strings.TrimSuffix(strings.TrimPrefix(content, "\x01ACTION "), "\x01")
+	}
+	color = wsclient.DefaultColor
+	if c, err := strconv.ParseInt(strings.TrimPrefix(m.tags["color"], "#"), 16, 32); err == nil {
+		color = int(c)
+	}
+	return username, content, color
+}
+
+var tagValueReplacer = strings.NewReplacer(`\s`, " ", `\:`, ";", `\\`, `\`, `\r`, "\r", `\n`, "\n")
+
+func unescapeTagValue(value string) string {
+	return tagValueReplacer.Replace(value)
+}
@@ -0,0 +1,33 @@
+package twitch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/internal/danmakutest"
+	"github.com/bililive-go/bililive-go/src/recorders/danmaku/wsclient"
+)
+
+// 按 Twitch IRC 格式构造的一帧消息，包含两条 PRIVMSG 与一条 PING
+const ircFrame = "@badge-info=;badges=;client-nonce=8c2f;color=#1E90FF;display-name=Chat\\sUser;emotes=;first-msg=0;flags=;id=0d1f;mod=0;returning-chatter=0;room-id=12826;subscriber=0;tmi-sent-ts=1700000000000;turbo=0;user-id=1234;user-type= :chatuser!chatuser@chatuser.tmi.twitch.tv PRIVMSG #twitch :hello :) world\r\n" +
+	"@badge-info=;badges=;color=;display-name=;emotes=;id=0d20;room-id=12826;user-id=5678 :lurker!lurker@lurker.tmi.twitch.tv PRIVMSG #twitch :\x01ACTION waves\x01\r\n" +
+	"PING :tmi.twitch.tv\r\n"
+
+func TestHandleIRCFrame(t *testing.T) {
+	var c danmakutest.Collector
+	var sent []string
+	p := &protocol{channel: "twitch", onDanmaku: c.OnDanmaku, logger: danmakutest.Logger()}
+	err := p.Handle([]byte(ircFrame), func(b []byte) error {
+		sent = append(sent, string(b))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []danmakutest.Chat{
+		{User: "Chat User", Content: "hello :) world", Color: 0x1E90FF},
+		{User: "lurker", Content: "waves", Color: wsclient.DefaultColor},
+	}, c.Chats)
+	assert.Equal(t, []string{"PONG :tmi.twitch.tv"}, sent)
+
+	assert.Error(t, p.Handle([]byte(":tmi.twitch.tv RECONNECT\r\n"), nil))
+}
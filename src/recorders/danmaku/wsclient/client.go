@@ -0,0 +1,247 @@
+// Package wsclient 提供基于 WebSocket 的弹幕客户端的公共连接管理：
+// 建立连接、定时心跳、读取消息以及断线后的线性退避重连。
+// 各平台只需实现 Protocol 描述握手、心跳与消息解析。
+package wsclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultColor 平台未提供颜色时使用的弹幕颜色（白色）
+const DefaultColor = 16777215
+
+const (
+	readTimeout  = 90 * time.Second
+	writeTimeout = 10 * time.Second
+)
+
+// Protocol 平台弹幕协议
+type Protocol interface {
+	// Name 平台名称，用于日志
+	Name() string
+	// Connect 获取弹幕服务器地址、建立连接并完成登录/进房，每次重连都会重新调用
+	Connect(ctx context.Context) (*websocket.Conn, error)
+	// HeartbeatInterval 心跳间隔，返回 0 表示不需要主动发送心跳
+	HeartbeatInterval() time.Duration
+	// Heartbeat 返回每次心跳需要发送的消息
+	Heartbeat() ([][]byte, error)
+	// MessageType 发送消息使用的 WebSocket 帧类型
+	MessageType() int
+	// Handle 处理服务器下发的一帧消息，send 用于回复（如 PONG、ACK）。
+	// 返回错误时断开连接并重连
+	Handle(data []byte, send func([]byte) error) error
+}
+
+// Client 通用 WebSocket 弹幕客户端
+type Client struct {
+	proto     Protocol
+	logger    *logrus.Entry
+	conn      *websocket.Conn
+	done      chan struct{}
+	closeOnce sync.Once
+	mu        sync.Mutex
+	writeMu   sync.Mutex
+	running   bool
+}
+
+// New 创建弹幕客户端
+func New(proto Protocol, logger *logrus.Entry) *Client {
+	return &Client{
+		proto:  proto,
+		logger: logger,
+		done:   make(chan struct{}),
+	}
+}
+
+// Start 建立首次连接，成功后在后台读取消息并维持心跳
+func (c *Client) Start(ctx context.Context) error {
+	conn, err := c.proto.Connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.conn = conn
+	c.running = true
+	c.mu.Unlock()
+
+	go c.readLoopWithReconnect(ctx)
+	if c.proto.HeartbeatInterval() > 0 {
+		go c.heartbeatLoop(ctx)
+	}
+
+	c.logger.Infof("%s弹幕连接成功", c.proto.Name())
+	return nil
+}
+
+// Stop 停止客户端并关闭连接
+func (c *Client) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.running {
+		return
+	}
+	c.running = false
+	c.closeOnce.Do(func() { close(c.done) })
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
+
+// getConn 安全获取当前连接引用，客户端已停止时返回 nil
+func (c *Client) getConn() *websocket.Conn {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.running {
+		return nil
+	}
+	return c.conn
+}
+
+func (c *Client) send(data []byte) error {
+	conn := c.getConn()
+	if conn == nil {
+		return fmt.Errorf("连接不可用")
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return conn.WriteMessage(c.proto.MessageType(), data)
+}
+
+func (c *Client) readLoopWithReconnect(ctx context.Context) {
+	defer func() {
+		c.mu.Lock()
+		c.running = false
+		c.closeOnce.Do(func() { close(c.done) })
+		if c.conn != nil {
+			c.conn.Close()
+		}
+		c.mu.Unlock()
+	}()
+
+	reconnectCount := 0
+
+	for {
+		err := c.readLoop(ctx)
+		if err == nil || c.stopped(ctx) {
+			return
+		}
+		c.logger.WithError(err).Debugf("%s弹幕连接断开", c.proto.Name())
+
+		for {
+			reconnectCount++
+
+			// 线性退避，上限 60 秒
+			delay := 3 * reconnectCount
+			if delay > 60 {
+				delay = 60
+			}
+			c.logger.Warnf("连接断开，%d秒后第 %d 次重连...", delay, reconnectCount)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-c.done:
+				return
+			case <-time.After(time.Duration(delay) * time.Second):
+			}
+
+			conn, dialErr := c.proto.Connect(ctx)
+			if dialErr != nil {
+				c.logger.WithError(dialErr).Warn("重连失败")
+				continue
+			}
+
+			c.mu.Lock()
+			if !c.running {
+				c.mu.Unlock()
+				conn.Close()
+				return
+			}
+			if c.conn != nil {
+				c.conn.Close()
+			}
+			c.conn = conn
+			c.mu.Unlock()
+			break
+		}
+
+		reconnectCount = 0
+		c.logger.Info("重连成功")
+	}
+}
+
+func (c *Client) stopped(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *Client) readLoop(ctx context.Context) error {
+	for {
+		if c.stopped(ctx) {
+			return nil
+		}
+
+		conn := c.getConn()
+		if conn == nil {
+			return fmt.Errorf("连接不可用")
+		}
+		conn.SetReadDeadline(time.Now().Add(readTimeout))
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("读取消息失败: %w", err)
+		}
+		if err := c.handleSafe(data); err != nil {
+			return err
+		}
+	}
+}
+
+// handleSafe 带 panic 恢复的消息处理
+func (c *Client) handleSafe(data []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.logger.Errorf("弹幕处理 panic: %v", r)
+		}
+	}()
+	return c.proto.Handle(data, c.send)
+}
+
+func (c *Client) heartbeatLoop(ctx context.Context) {
+	ticker := time.NewTicker(c.proto.HeartbeatInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.done:
+			return
+		case <-ticker.C:
+			frames, err := c.proto.Heartbeat()
+			if err != nil {
+				c.logger.WithError(err).Debug("生成心跳包失败")
+				continue
+			}
+			for _, data := range frames {
+				if err := c.send(data); err != nil {
+					c.logger.WithError(err).Debug("发送心跳包失败")
+					break
+				}
+			}
+		}
+	}
+}
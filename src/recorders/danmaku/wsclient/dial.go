@@ -0,0 +1,36 @@
+package wsclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// UserAgent 请求平台接口与建立 WebSocket 连接时使用的 UA
+const UserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
+// Dial 建立 WebSocket 连接，header 可为 nil
+func Dial(ctx context.Context, rawURL string, header http.Header, subprotocols ...string) (*websocket.Conn, error) {
+	if header == nil {
+		header = http.Header{}
+	}
+	if header.Get("User-Agent") == "" {
+		header.Set("User-Agent", UserAgent)
+	}
+	dialer := websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: 10 * time.Second,
+		Subprotocols:     subprotocols,
+	}
+	conn, resp, err := dialer.DialContext(ctx, rawURL, header)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("WebSocket 连接失败 (HTTP %d): %w", resp.StatusCode, err)
+		}
+		return nil, fmt.Errorf("WebSocket 连接失败: %w", err)
+	}
+	return conn, nil
+}
@@ -1,9 +1,21 @@
 package recorders
 
-import "github.com/bililive-go/bililive-go/src/pkg/events"
+import (
+	"github.com/bililive-go/bililive-go/src/live"
+	"github.com/bililive-go/bililive-go/src/pkg/events"
+)
 
 const (
 	RecorderStart   events.EventType = "RecorderStart"
 	RecorderStop    events.EventType = "RecorderStop"
 	RecorderRestart events.EventType = "RecorderRestart"
+	// RecorderError 录制过程中出错，事件对象为 *ErrorInfo
+	RecorderError events.EventType = "RecorderError"
 )
+
+// ErrorInfo RecorderError 事件的数据
+type ErrorInfo struct {
+	Live  live.Live
+	Stage string // 出错环节：mkdir、parser_init、parse、pipeline_enqueue
+	Err   error
+}
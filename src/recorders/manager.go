@@ -2,6 +2,7 @@ package recorders
 
 import (
 	"context"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -32,6 +33,8 @@ var (
 	onRecordingEndFunc OnRecordingEndFunc
 	// broadcastDanmakuFunc 全局弹幕广播函数，由 servers 包设置
 	broadcastDanmakuFunc BroadcastDanmakuFunc
+	// danmakuStatsFunc 弹幕统计函数，由 livestate 包设置
+	danmakuStatsFunc BroadcastDanmakuFunc
 )
 
 // SetBroadcastRecorderStatusFunc 设置录制器状态广播函数
@@ -49,6 +52,12 @@ func SetBroadcastDanmakuFunc(fn BroadcastDanmakuFunc) {
 	broadcastDanmakuFunc = fn
 }
 
+// SetDanmakuStatsFunc 设置弹幕统计函数，参数与弹幕广播相同，付费消息的 extra 中
+// value_cny 为折算的人民币金额，缺失表示无法折算
+func SetDanmakuStatsFunc(fn BroadcastDanmakuFunc) {
+	danmakuStatsFunc = fn
+}
+
 func NewManager(ctx context.Context) Manager {
 	rm := &manager{
 		savers:       make(map[types.LiveID]Recorder),
@@ -73,6 +82,8 @@ type Manager interface {
 	GetRecorderStatus(ctx context.Context, liveId types.LiveID) (map[string]interface{}, error)
 	// GetActiveRecordingsCount 获取当前活跃的录制数量
 	GetActiveRecordingsCount() int
+	// IsRecordingFile 判断 path 是否为某个录制器正在写入的文件
+	IsRecordingFile(path string) bool
 }
 
 // for test
@@ -388,3 +399,21 @@ func (m *manager) GetActiveRecordingsCount() int {
 	defer m.lock.RUnlock()
 	return len(m.savers) + int(m.restartingCount.Load())
 }
+
+// IsRecordingFile 判断 path 是否为某个录制器正在写入的文件
+func (m *manager) IsRecordingFile(path string) bool {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	for _, recorder := range m.savers {
+		if file := recorder.CurrentFile(); file != "" {
+			if abs, err := filepath.Abs(file); err == nil && abs == path {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -16,6 +16,8 @@ import (
 
 	live "github.com/bililive-go/bililive-go/src/live"
 	notify "github.com/bililive-go/bililive-go/src/notify"
+	flvproxy "github.com/bililive-go/bililive-go/src/pkg/flvproxy"
+	hlsproxy "github.com/bililive-go/bililive-go/src/pkg/hlsproxy"
 	types "github.com/bililive-go/bililive-go/src/types"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -70,6 +72,20 @@ func (mr *MockRecorderMockRecorder) CloseForRestart() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseForRestart", reflect.TypeOf((*MockRecorder)(nil).CloseForRestart))
 }
 
+// CurrentFile mocks base method.
+func (m *MockRecorder) CurrentFile() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CurrentFile")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CurrentFile indicates an expected call of CurrentFile.
+func (mr *MockRecorderMockRecorder) CurrentFile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CurrentFile", reflect.TypeOf((*MockRecorder)(nil).CurrentFile))
+}
+
 // GetParserPID mocks base method.
 func (m *MockRecorder) GetParserPID() int {
 	m.ctrl.T.Helper()
@@ -99,6 +115,20 @@ func (mr *MockRecorderMockRecorder) GetStatus() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatus", reflect.TypeOf((*MockRecorder)(nil).GetStatus))
 }
 
+// HLSPreview mocks base method.
+func (m *MockRecorder) HLSPreview() *hlsproxy.Relay {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HLSPreview")
+	ret0, _ := ret[0].(*hlsproxy.Relay)
+	return ret0
+}
+
+// HLSPreview indicates an expected call of HLSPreview.
+func (mr *MockRecorderMockRecorder) HLSPreview() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HLSPreview", reflect.TypeOf((*MockRecorder)(nil).HLSPreview))
+}
+
 // HasFlvProxy mocks base method.
 func (m *MockRecorder) HasFlvProxy() bool {
 	m.ctrl.T.Helper()
@@ -127,6 +157,20 @@ func (mr *MockRecorderMockRecorder) IsRecording() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRecording", reflect.TypeOf((*MockRecorder)(nil).IsRecording))
 }
 
+// Preview mocks base method.
+func (m *MockRecorder) Preview() *flvproxy.Relay {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Preview")
+	ret0, _ := ret[0].(*flvproxy.Relay)
+	return ret0
+}
+
+// Preview indicates an expected call of Preview.
+func (mr *MockRecorderMockRecorder) Preview() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Preview", reflect.TypeOf((*MockRecorder)(nil).Preview))
+}
+
 // RequestSegment mocks base method.
 func (m *MockRecorder) RequestSegment() bool {
 	m.ctrl.T.Helper()
@@ -303,6 +347,20 @@ func (mr *MockManagerMockRecorder) HasRecorder(ctx, liveId any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasRecorder", reflect.TypeOf((*MockManager)(nil).HasRecorder), ctx, liveId)
 }
 
+// IsRecordingFile mocks base method.
+func (m *MockManager) IsRecordingFile(path string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsRecordingFile", path)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsRecordingFile indicates an expected call of IsRecordingFile.
+func (mr *MockManagerMockRecorder) IsRecordingFile(path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRecordingFile", reflect.TypeOf((*MockManager)(nil).IsRecordingFile), path)
+}
+
 // RemoveRecorder mocks base method.
 func (m *MockManager) RemoveRecorder(ctx context.Context, liveId types.LiveID) error {
 	m.ctrl.T.Helper()
@@ -29,12 +29,15 @@ import (
 	"github.com/bililive-go/bililive-go/src/notify"
 	"github.com/bililive-go/bililive-go/src/pipeline"
 	"github.com/bililive-go/bililive-go/src/pkg/events"
+	"github.com/bililive-go/bililive-go/src/pkg/flvproxy"
 	"github.com/bililive-go/bililive-go/src/pkg/hlsproxy"
 	"github.com/bililive-go/bililive-go/src/pkg/livelogger"
 	"github.com/bililive-go/bililive-go/src/pkg/parser"
 	"github.com/bililive-go/bililive-go/src/pkg/parser/bililive_recorder"
 	"github.com/bililive-go/bililive-go/src/pkg/parser/ffmpeg"
 	"github.com/bililive-go/bililive-go/src/pkg/parser/native/flv"
+	"github.com/bililive-go/bililive-go/src/pkg/proxy"
+	"github.com/bililive-go/bililive-go/src/pkg/recordmeta"
 	bilisentry "github.com/bililive-go/bililive-go/src/pkg/sentry"
 	"github.com/bililive-go/bililive-go/src/pkg/streamprobe"
 	"github.com/bililive-go/bililive-go/src/pkg/utils"
@@ -79,7 +82,7 @@ var (
 // videoExtensions 用于匹配弹幕文件对应的视频文件
 var videoExtensions = []string{".flv", ".mkv", ".ts", ".mp4"}
 
-// cleanupOrphanedDanmakuFiles 清理没有对应视频文件的 ASS 弹幕文件。
+// cleanupOrphanedDanmakuFiles 清理没有对应视频文件的 ASS 弹幕文件及其 SRT/WebVTT 附加字幕。
 // 视频流快速失败时（如 404），弹幕录制器可能已创建 .ass 文件但视频未生成，
 // 遗留的孤立 .ass 文件会在前端显示为无效录制，需要清理。
 func cleanupOrphanedDanmakuFiles(assFile string) {
@@ -114,6 +117,9 @@ func cleanupOrphanedDanmakuFiles(assFile string) {
 			}
 			if !hasVideo {
 				os.Remove(filepath.Join(dir, name))
+				for _, sext := range danmaku.TextSubtitleExts {
+					os.Remove(filepath.Join(dir, assBase+sext))
+				}
 			}
 		}
 	}
@@ -222,6 +228,42 @@ func getDefaultFileNameTmpl() *template.Template {
 		Parse(`{{ .Live.GetPlatformCNName }}/{{ with .Live.GetOptions.NickName }}{{ . | filenameFilter }}{{ else }}{{ .HostName | filenameFilter }}{{ end }}/[{{ now | date "2006-01-02 15-04-05"}}][{{ .HostName | filenameFilter }}][{{ .RoomName | filenameFilter }}].flv`))
 }
 
+// RenderOutputFileName 按层级配置的输出模板（未配置或无效时使用默认模板）与输出目录生成录制文件路径
+func RenderOutputFileName(cfg *configs.Config, resolvedConfig configs.ResolvedConfig, info *live.Info) (string, error) {
+	tmpl := getDefaultFileNameTmpl()
+	// 使用层级配置的 OutputTmpl
+	if resolvedConfig.OutputTmpl != "" {
+		_tmpl, errTmpl := template.New("user_filename").Funcs(utils.GetFuncMap(cfg)).Parse(resolvedConfig.OutputTmpl)
+		if errTmpl == nil {
+			tmpl = _tmpl
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, info); err != nil {
+		return "", err
+	}
+	// 使用层级配置的 OutPutPath
+	return filepath.Join(resolvedConfig.OutPutPath, buf.String()), nil
+}
+
+// FileNameForStream 按流格式调整文件扩展名：HLS 流保存为 .ts，仅音频保存为 .aac
+func FileNameForStream(fileName string, u *url.URL, audioOnly bool) string {
+	if strings.Contains(u.Path, "m3u8") {
+		fileName = fileName[:len(fileName)-4] + ".ts"
+	}
+
+	if audioOnly {
+		fileName = fileName[:strings.LastIndex(fileName, ".")] + ".aac"
+	}
+	return fileName
+}
+
+// NewParser 按下载器类型创建 parser，回退逻辑与录制器一致，供单次录制等场景使用
+func NewParser(u *url.URL, downloaderType configs.DownloaderType, cfg map[string]string, logger *livelogger.LiveLogger) (parser.Parser, error) {
+	return newParser(u, downloaderType, cfg, logger)
+}
+
 type Recorder interface {
 	Start(ctx context.Context) error
 	StartTime() time.Time
@@ -240,6 +282,12 @@ type Recorder interface {
 	RequestSegment() bool
 	// HasFlvProxy 检查当前是否使用 FLV 代理
 	HasFlvProxy() bool
+	// Preview 返回当前录制的本地预览转发，不可用时返回 nil
+	Preview() *flvproxy.Relay
+	// HLSPreview 返回当前 HLS 录制的本地预览转发，不可用时返回 nil
+	HLSPreview() *hlsproxy.Relay
+	// CurrentFile 返回正在写入的录制文件路径，未在录制时返回空字符串
+	CurrentFile() string
 	// CloseForRestart 用于分段重启场景：关闭 recorder 但不推送摘要，
 	// 等待 run() 完全退出后返回已累积的录制文件列表
 	CloseForRestart() []notify.RecordingFileDetail
@@ -256,6 +304,12 @@ type danmakuRecorder interface {
 	IsRunning() bool
 	GetStatus() map[string]interface{}
 	SetBroadcastCallback(cb danmaku.DanmakuBroadcastCallback)
+	// SetStatsCallback 设置会话统计回调，屏蔽规则过滤掉的弹幕也会计入
+	SetStatsCallback(cb danmaku.DanmakuBroadcastCallback)
+	OutputFiles() []string
+	CalibrateTimeline(videoStart time.Time)
+	SplitTimeline(outputFile string, videoStart time.Time) error
+	Highlights() [][]recordmeta.Highlight
 }
 
 // 编译期接口断言
@@ -263,6 +317,7 @@ var (
 	_ danmakuRecorder = (*danmaku.DanmakuRecorder)(nil)
 	_ danmakuRecorder = (*danmaku.DouyinDanmakuRecorder)(nil)
 	_ danmakuRecorder = (*danmaku.DouyuDanmakuRecorder)(nil)
+	_ danmakuRecorder = (*danmaku.ChatDanmakuRecorder)(nil)
 )
 
 type recorder struct {
@@ -291,6 +346,15 @@ type recorder struct {
 	// 实际流头部信息（来自 StreamProbe 探测）
 	actualStreamInfo atomic.Pointer[streamprobe.StreamHeaderInfo]
 
+	// 当前录制的本地预览转发，仅 FLV 流且启用预览或转推时存在
+	preview atomic.Pointer[flvproxy.Relay]
+	// hlsPreview HLS 录制的本地预览，由 HLS 代理转发分段时同步写入
+	hlsPreview atomic.Pointer[hlsproxy.Relay]
+
+	// 当前录制的转推
+	restreamMu  sync.Mutex
+	restreamers []*restreamer
+
 	// 累积的录制文件信息，待录制结束后统一推送摘要
 	// recordedFilesMu 保护 recordedFiles 的并发访问：
 	// run() goroutine 中的 accumulateRecordedFiles 和 RestartRecorder 中的
@@ -356,6 +420,8 @@ func (r *recorder) tryRecord(ctx context.Context) {
 		if err != nil && r.stopRetryForExplicitOffline(err) {
 			return
 		}
+		// 获取直播流地址时触发风控或登录失效，交由账号池切换 Cookie
+		live.ReportRequestError(r.Live, err)
 		r.logStreamURLRetry(err)
 		// 使用可中断的等待，确保 Ctrl+C 能立即响应
 		select {
@@ -370,21 +436,10 @@ func (r *recorder) tryRecord(ctx context.Context) {
 	obj, _ := r.cache.Get(r.Live)
 	info := obj.(*live.Info)
 
-	tmpl := getDefaultFileNameTmpl()
-	// 使用层级配置的 OutputTmpl
-	if resolvedConfig.OutputTmpl != "" {
-		_tmpl, errTmpl := template.New("user_filename").Funcs(utils.GetFuncMap(cfg)).Parse(resolvedConfig.OutputTmpl)
-		if errTmpl == nil {
-			tmpl = _tmpl
-		}
-	}
-
-	buf := new(bytes.Buffer)
-	if err = tmpl.Execute(buf, info); err != nil {
+	fileName, err := RenderOutputFileName(cfg, resolvedConfig, info)
+	if err != nil {
 		panic(fmt.Sprintf("failed to render filename, err: %v", err))
 	}
-	// 使用层级配置的 OutPutPath
-	fileName := filepath.Join(resolvedConfig.OutPutPath, buf.String())
 	outputPath, _ := filepath.Split(fileName)
 
 	// TODO 根据配置选择最佳流
@@ -401,22 +456,23 @@ func (r *recorder) tryRecord(ctx context.Context) {
 	r.currentStreamHeaders = streamInfo.HeadersForDownloader
 	r.currentFileLock.Unlock()
 
-	if strings.Contains(url.Path, "m3u8") {
-		fileName = fileName[:len(fileName)-4] + ".ts"
-	}
-
-	if info.AudioOnly {
-		fileName = fileName[:strings.LastIndex(fileName, ".")] + ".aac"
-	}
+	fileName = FileNameForStream(fileName, url, info.AudioOnly)
 
 	if err = mkdir(outputPath); err != nil {
 		r.getLogger().WithError(err).Errorf("failed to create output path[%s]", outputPath)
+		r.dispatchError("mkdir", err)
 		return
 	}
 	parserCfg := map[string]string{
 		"timeout_in_us": strconv.Itoa(resolvedConfig.TimeoutInUs),
 		"audio_only":    strconv.FormatBool(info.AudioOnly),
 	}
+	// 按直播间解析下载代理（全局 -> 平台 -> 房间，代理池按健康状态选取），
+	// 本次录制的探测代理、HLS 代理和各下载器统一使用该代理
+	downloadProxy := proxy.GetDownloadProxyURLForRoom(r.Live.GetRawUrl())
+	if downloadProxy != "" {
+		parserCfg["proxy_url"] = downloadProxy
+	}
 	// 使用层级配置的下载器类型
 	downloaderType := resolvedConfig.Feature.GetEffectiveDownloaderType()
 
@@ -460,12 +516,23 @@ func (r *recorder) tryRecord(ctx context.Context) {
 	// 但 newParser 内部通过 URL 路径判断是否为 FLV 流来选择下载器类型。
 	// 如果用代理 URL 判断，所有 FLV 流都会被误判为"非 FLV"，导致 Native/录播姬下载器回退到 ffmpeg。
 	originalURL := url
+	// hlsAdProxy 启用了广告识别的 HLS 代理，录制结束后从中取出广告位置写入元数据
+	var hlsAdProxy *hlsproxy.Proxy
+	// videoStart 录制文件首帧到达的本地时间，用于校准弹幕时间轴；
+	// 仅 FLV 探测能得到，其他情况回退为下载器启动的时间
+	var videoStart time.Time
 	isFLV := streamprobe.IsStreamFLV(url)
 	if isFLV {
+		// 本地预览与转推：在探测代理转发给下载器时同步分发，不额外请求 CDN
+		var relay *flvproxy.Relay
+		if cfg.Preview.Enable || len(room.RestreamTargets) > 0 {
+			relay = flvproxy.NewRelay()
+		}
 		// FLV 流：启动探测代理
 		probeConfig := streamprobe.Config{
 			UpstreamURL: url,
 			Headers:     streamInfo.HeadersForDownloader,
+			ProxyURL:    downloadProxy,
 			OnProbed: func(info *streamprobe.StreamHeaderInfo) {
 				r.actualStreamInfo.Store(info)
 				r.getLogger().Infof("流探测完成: 编码=%s, 分辨率=%s, 帧率=%.1f, 状态=%s",
@@ -485,6 +552,9 @@ func (r *recorder) tryRecord(ctx context.Context) {
 			},
 			Logger: r.getLogger(),
 		}
+		if relay != nil {
+			probeConfig.Tee = relay
+		}
 
 		probe := streamprobe.New(probeConfig)
 		if probeErr := probe.Start(ctx); probeErr != nil {
@@ -497,6 +567,16 @@ func (r *recorder) tryRecord(ctx context.Context) {
 		} else {
 			// 代理启动成功，用代理 URL 替换原始 URL
 			defer probe.Stop()
+			if relay != nil {
+				r.preview.Store(relay)
+				defer func() {
+					r.preview.CompareAndSwap(relay, nil)
+					relay.Close()
+				}()
+			}
+			if info := probe.GetHeaderInfo(); info != nil {
+				videoStart = info.FirstMediaAt
+			}
 			streamInfo = &live.StreamUrlInfo{
 				Url:                  probe.LocalURL(),
 				HeadersForDownloader: nil, // 本地代理不需要 headers
@@ -513,14 +593,39 @@ func (r *recorder) tryRecord(ctx context.Context) {
 			url = probe.LocalURL()
 		}
 	} else if streamprobe.IsStreamHLS(url) {
-		if r.Live.GetPlatformCNName() == "SOOP" {
-			hlsFilterProxy, proxyErr := hlsproxy.New(url, streamInfo.HeadersForDownloader, true)
+		// Soop：过滤 preloading 分片；Twitch 等：识别拼接广告
+		filterPreloading := r.Live.GetPlatformCNName() == "SOOP"
+		adMode := hlsproxy.AdModeOff
+		if streamInfo.HasStitchedAds {
+			adMode = hlsproxy.ParseAdMode(resolvedConfig.Feature.HlsAdHandling)
+		}
+		// FFmpeg 不支持 SOCKS5 代理，此时由本地 HLS 代理转发分段请求
+		viaSocks := proxy.IsSocks5URL(downloadProxy)
+		// 本地预览：在 HLS 代理转发分段给下载器时同步分发，不额外请求 CDN
+		var relay *hlsproxy.Relay
+		if cfg.Preview.Enable {
+			relay = hlsproxy.NewRelay()
+		}
+		if filterPreloading || adMode != hlsproxy.AdModeOff || viaSocks || relay != nil {
+			hlsFilterProxy, proxyErr := hlsproxy.NewWithOptions(url, streamInfo.HeadersForDownloader, hlsproxy.Options{
+				FilterPreloading: filterPreloading,
+				AdMode:           adMode,
+				ProxyURL:         downloadProxy,
+				Preview:          relay,
+			})
 			if proxyErr != nil {
-				r.getLogger().WithError(proxyErr).Warn("Soop HLS 过滤代理启动失败，将直接使用上游 m3u8")
+				r.getLogger().WithError(proxyErr).Warn("HLS 过滤代理启动失败，将直接使用上游 m3u8")
 			} else if proxyErr = hlsFilterProxy.Start(ctx); proxyErr != nil {
-				r.getLogger().WithError(proxyErr).Warn("Soop HLS 过滤代理运行失败，将直接使用上游 m3u8")
+				r.getLogger().WithError(proxyErr).Warn("HLS 过滤代理运行失败，将直接使用上游 m3u8")
 			} else {
 				defer hlsFilterProxy.Stop()
+				if relay != nil {
+					r.hlsPreview.Store(relay)
+					defer func() {
+						r.hlsPreview.CompareAndSwap(relay, nil)
+						relay.Close()
+					}()
+				}
 				streamInfo = &live.StreamUrlInfo{
 					Url:                       hlsFilterProxy.LocalURL(),
 					HeadersForDownloader:      nil,
@@ -536,16 +641,23 @@ func (r *recorder) tryRecord(ctx context.Context) {
 					Name:                      streamInfo.Name,
 					AudioCodec:                streamInfo.AudioCodec,
 					AttributesForStreamSelect: streamInfo.AttributesForStreamSelect,
+					HasStitchedAds:            streamInfo.HasStitchedAds,
 				}
 				url = hlsFilterProxy.LocalURL()
-				r.getLogger().Info("Soop HLS 过滤代理已启用，将自动跳过 preloading 分片")
+				if filterPreloading {
+					r.getLogger().Info("Soop HLS 过滤代理已启用，将自动跳过 preloading 分片")
+				}
+				if adMode != hlsproxy.AdModeOff {
+					hlsAdProxy = hlsFilterProxy
+					r.getLogger().Infof("HLS 广告处理已启用: mode=%s", adMode)
+				}
 			}
 		}
 
 		// HLS 流：不使用代理，异步探测第一个 TS 分段的头部信息
 		// 使用 tryRecord 的 ctx，当录制结束/重试时自动取消探测
 		go func(probeCtx context.Context) {
-			hlsInfo, probeErr := streamprobe.ProbeHLS(probeCtx, url, streamInfo.HeadersForDownloader, r.getLogger())
+			hlsInfo, probeErr := streamprobe.ProbeHLSWithProxy(probeCtx, url, streamInfo.HeadersForDownloader, downloadProxy, r.getLogger())
 			if probeErr != nil {
 				// context 取消不算真正的错误，不需要打印
 				if probeCtx.Err() != nil {
@@ -576,29 +688,58 @@ func (r *recorder) tryRecord(ctx context.Context) {
 	p, err := newParser(originalURL, downloaderType, parserCfg, r.getLogger())
 	if err != nil {
 		r.getLogger().WithError(err).Error("failed to init parse")
+		r.dispatchError("parser_init", err)
 		return
 	}
 	r.setAndCloseParser(p)
 	r.startTime = time.Now()
+	if videoStart.IsZero() {
+		videoStart = r.startTime
+	}
 
-	// 弹幕录制（支持哔哩哔哩、抖音、斗鱼平台）
+	// 弹幕录制（支持哔哩哔哩、抖音、斗鱼、虎牙、快手、AcFun、Twitch、SOOP 平台）
 	if resolvedConfig.DanmakuEnable {
 		switch r.Live.GetPlatformCNName() {
 		case "哔哩哔哩":
-			r.startDanmakuRecorder(ctx, fileName, "哔哩哔哩", resolvedConfig,
+			r.startDanmakuRecorder(ctx, fileName, videoStart, "哔哩哔哩", resolvedConfig,
 				func(rid, cookies, assFile string, cfg configs.DanmakuConfig, logger *logrus.Entry) danmakuRecorder {
 					return danmaku.NewDanmakuRecorder(extractRoomIDFromUrl(r.Live.GetRawUrl()), cookies, assFile, cfg, logger)
 				})
 		case "抖音":
-			r.startDanmakuRecorder(ctx, fileName, "抖音", resolvedConfig,
+			r.startDanmakuRecorder(ctx, fileName, videoStart, "抖音", resolvedConfig,
 				func(rid, cookies, assFile string, cfg configs.DanmakuConfig, logger *logrus.Entry) danmakuRecorder {
 					return danmaku.NewDouyinDanmakuRecorder(rid, cookies, assFile, cfg, logger)
 				})
 		case "斗鱼":
-			r.startDanmakuRecorder(ctx, fileName, "斗鱼", resolvedConfig,
+			r.startDanmakuRecorder(ctx, fileName, videoStart, "斗鱼", resolvedConfig,
 				func(rid, cookies, assFile string, cfg configs.DanmakuConfig, logger *logrus.Entry) danmakuRecorder {
 					return danmaku.NewDouyuDanmakuRecorder(rid, cookies, assFile, cfg, logger)
 				})
+		case "虎牙":
+			r.startDanmakuRecorder(ctx, fileName, videoStart, "虎牙", resolvedConfig,
+				func(rid, cookies, assFile string, cfg configs.DanmakuConfig, logger *logrus.Entry) danmakuRecorder {
+					return danmaku.NewHuyaDanmakuRecorder(rid, cookies, assFile, cfg, logger)
+				})
+		case "快手":
+			r.startDanmakuRecorder(ctx, fileName, videoStart, "快手", resolvedConfig,
+				func(rid, cookies, assFile string, cfg configs.DanmakuConfig, logger *logrus.Entry) danmakuRecorder {
+					return danmaku.NewKuaishouDanmakuRecorder(rid, cookies, assFile, cfg, logger)
+				})
+		case "acfun":
+			r.startDanmakuRecorder(ctx, fileName, videoStart, "acfun", resolvedConfig,
+				func(rid, cookies, assFile string, cfg configs.DanmakuConfig, logger *logrus.Entry) danmakuRecorder {
+					return danmaku.NewAcfunDanmakuRecorder(rid, cookies, assFile, cfg, logger)
+				})
+		case "twitch":
+			r.startDanmakuRecorder(ctx, fileName, videoStart, "twitch", resolvedConfig,
+				func(rid, cookies, assFile string, cfg configs.DanmakuConfig, logger *logrus.Entry) danmakuRecorder {
+					return danmaku.NewTwitchDanmakuRecorder(rid, assFile, cfg, logger)
+				})
+		case "SOOP":
+			r.startDanmakuRecorder(ctx, fileName, videoStart, "SOOP", resolvedConfig,
+				func(rid, cookies, assFile string, cfg configs.DanmakuConfig, logger *logrus.Entry) danmakuRecorder {
+					return danmaku.NewSoopDanmakuRecorder(rid, cookies, assFile, cfg, logger)
+				})
 		}
 	} else {
 		// 弹幕未启用，清理旧的录制器
@@ -614,24 +755,60 @@ func (r *recorder) tryRecord(ctx context.Context) {
 	// 设置当前录制文件路径
 	r.setCurrentFilePath(fileName)
 
+	// 录播姬在进程内按大小/时长分段，弹幕需跟随分段切换文件并重新计时
+	stopSplitWatch := func() {}
+	r.currentFileLock.RLock()
+	if dmRec := r.danmakuRec; dmRec != nil && resolveParserName(downloaderType, strings.Contains(originalURL.Path, ".flv"), nil) == bililive_recorder.Name {
+		stopSplitWatch = r.watchBililiveRecorderSplits(ctx, fileName, dmRec)
+	}
+	r.currentFileLock.RUnlock()
+
+	stopRestream := r.startRestream(ctx, room.RestreamTargets, streamInfo)
+
 	r.getLogger().Debugln("Start ParseLiveStream(" + url.String() + ", " + fileName + ")")
 	err = r.parser.ParseLiveStream(ctx, streamInfo, r.Live, fileName)
+	stopSplitWatch()
+	stopRestream()
 
 	// 清除当前录制文件路径
 	r.setCurrentFilePath("")
 
+	if hlsAdProxy != nil {
+		r.saveAdBreaks(fileName, hlsAdProxy.AdBreaks())
+	}
+
 	// 停止弹幕录制
 	r.currentFileLock.RLock()
 	dmRec := r.danmakuRec
 	r.currentFileLock.RUnlock()
 	dmFile := ""
+	var dmFiles []string
+	var highlights [][]recordmeta.Highlight
 	if dmRec != nil {
 		dmFile = dmRec.OutputFile()
+		dmFiles = dmRec.OutputFiles()
 		dmRec.Stop()
+		highlights = dmRec.Highlights()
+	}
+	// 弹幕随录播姬分段切换过文件时，初始 ASS 文件对应第一个分段，按分段文件名重命名
+	if len(dmFiles) > 1 {
+		if parts := findBililiveRecorderOutputFiles(fileName); len(parts) > 0 {
+			firstAss := strings.TrimSuffix(parts[0], filepath.Ext(parts[0])) + ".ass"
+			if renameErr := os.Rename(dmFiles[0], firstAss); renameErr == nil {
+				for _, ext := range danmaku.TextSubtitleExts {
+					os.Rename(strings.TrimSuffix(dmFiles[0], ".ass")+ext, strings.TrimSuffix(firstAss, ".ass")+ext)
+				}
+				dmFiles[0] = firstAss
+			}
+		}
 	}
 
 	if err != nil {
 		r.getLogger().WithError(err).Error("failed to parse live stream")
+		if ctx.Err() == nil {
+			r.dispatchError("parse", err)
+			live.ReportRequestError(r.Live, err)
+		}
 		// 视频流快速失败时（如 404），清理没有对应视频文件的残留弹幕
 		if elapsed := time.Since(r.startTime); elapsed < 5*time.Second {
 			cleanupOrphanedDanmakuFiles(dmFile)
@@ -639,10 +816,19 @@ func (r *recorder) tryRecord(ctx context.Context) {
 		return
 	}
 
-	// 录制成功，累积弹幕文件
-	if dmFile != "" {
-		if fi, dmErr := os.Stat(dmFile); dmErr == nil && fi.Size() > 0 {
-			r.accumulateRecordedFiles(dmFile)
+	// 录制成功，保存高光片段并累积弹幕文件
+	for i, f := range dmFiles {
+		if i < len(highlights) {
+			r.saveHighlights(strings.TrimSuffix(f, ".ass")+filepath.Ext(fileName), highlights[i])
+		}
+		if fi, dmErr := os.Stat(f); dmErr == nil && fi.Size() > 0 {
+			r.accumulateRecordedFiles(f)
+		}
+		for _, ext := range danmaku.TextSubtitleExts {
+			sub := strings.TrimSuffix(f, ".ass") + ext
+			if fi, subErr := os.Stat(sub); subErr == nil && fi.Size() > 0 {
+				r.accumulateRecordedFiles(sub)
+			}
 		}
 	}
 
@@ -785,17 +971,58 @@ func (r *recorder) tryRecord(ctx context.Context) {
 		// 入队 Pipeline 任务
 		if err := pipelineManager.EnqueueRecordingTask(info, pipelineConfig, outputFiles); err != nil {
 			r.getLogger().WithError(err).Error("failed to enqueue pipeline task")
+			r.dispatchError("pipeline_enqueue", err)
 		} else {
 			r.getLogger().Infof("pipeline task enqueued: %d files, %d stages", len(outputFiles), len(pipelineConfig.Stages))
 		}
 	}
 }
 
+// saveAdBreaks 将 HLS 代理识别到的广告位置写入录制文件旁的元数据文件
+func (r *recorder) saveAdBreaks(fileName string, breaks []hlsproxy.AdBreak) {
+	if len(breaks) == 0 {
+		return
+	}
+	if _, err := os.Stat(fileName); err != nil {
+		return
+	}
+	var total float64
+	for _, b := range breaks {
+		total += b.Duration
+	}
+	if err := recordmeta.Update(fileName, func(m *recordmeta.Meta) {
+		m.AdBreaks = append(m.AdBreaks, breaks...)
+	}); err != nil {
+		r.getLogger().WithError(err).Warn("写入广告位置元数据失败")
+		return
+	}
+	r.getLogger().Infof("本次录制共识别 %d 段广告，合计 %.1f 秒，已写入 %s",
+		len(breaks), total, filepath.Base(recordmeta.PathFor(fileName)))
+}
+
+// saveHighlights 将弹幕识别出的高光片段写入视频的元数据文件，供 highlight_clips 阶段剪辑
+func (r *recorder) saveHighlights(videoFile string, highlights []recordmeta.Highlight) {
+	if len(highlights) == 0 {
+		return
+	}
+	if _, err := os.Stat(videoFile); err != nil {
+		return
+	}
+	if err := recordmeta.Update(videoFile, func(m *recordmeta.Meta) {
+		m.Highlights = highlights
+	}); err != nil {
+		r.getLogger().WithError(err).Warn("写入高光片段元数据失败")
+		return
+	}
+	r.getLogger().Infof("%s 识别到 %d 个高光片段，最高得分 %.0f，已写入 %s",
+		filepath.Base(videoFile), len(highlights), highlights[0].Score, filepath.Base(recordmeta.PathFor(videoFile)))
+}
+
 // danmakuRecorderFactory 弹幕录制器工厂函数类型
 type danmakuRecorderFactory func(roomID, cookies, outputFile string, cfg configs.DanmakuConfig, logger *logrus.Entry) danmakuRecorder
 
-// startDanmakuRecorder 弹幕录制通用启动流程：解析房间ID、创建录制器、启动、替换旧录制器。
-func (r *recorder) startDanmakuRecorder(ctx context.Context, fileName, platform string, resolvedConfig configs.ResolvedConfig, factory danmakuRecorderFactory) {
+// startDanmakuRecorder 弹幕录制通用启动流程：解析房间ID、创建录制器、启动、校准时间轴、替换旧录制器。
+func (r *recorder) startDanmakuRecorder(ctx context.Context, fileName string, videoStart time.Time, platform string, resolvedConfig configs.ResolvedConfig, factory danmakuRecorderFactory) {
 	assFile := fileName[:strings.LastIndex(fileName, ".")] + ".ass"
 	cookies := extractCookiesString(r.Live)
 
@@ -805,10 +1032,14 @@ func (r *recorder) startDanmakuRecorder(ctx context.Context, fileName, platform
 		if id := extractRoomIDFromUrl(r.Live.GetRawUrl()); id > 0 {
 			roomID = strconv.Itoa(id)
 		}
-	case "抖音":
-		roomID = extractDouyinRoomID(r.Live)
 	case "斗鱼":
 		roomID = extractDouyuRoomID(r.Live)
+	case "抖音", "虎牙", "twitch", "SOOP":
+		// 房间号、频道名或主播 ID 为路径的第一段
+		roomID = extractFirstPathSegment(r.Live)
+	case "快手", "acfun":
+		// 主播 ID 为路径的最后一段（/u/<id>、/live/<id>）
+		roomID = extractLastPathSegment(r.Live)
 	}
 
 	if roomID == "" {
@@ -822,12 +1053,19 @@ func (r *recorder) startDanmakuRecorder(ctx context.Context, fileName, platform
 		r.getLogger().WithError(dmErr).Warn("弹幕录制启动失败，继续录制视频")
 		return
 	}
+	rec.CalibrateTimeline(videoStart)
 
 	// 设置弹幕广播回调，将消息通过 SSE 推送到前端
-	if broadcastDanmakuFunc != nil {
-		liveId := r.Live.GetLiveId()
+	liveId := r.Live.GetLiveId()
+	if broadcast := broadcastDanmakuFunc; broadcast != nil {
 		rec.SetBroadcastCallback(func(msgType, username, content string, extra map[string]interface{}) {
-			broadcastDanmakuFunc(liveId, msgType, username, content, extra)
+			broadcast(liveId, msgType, username, content, extra)
+		})
+	}
+	// 会话统计在屏蔽规则之前计入，反映直播间实际收到的消息
+	if stats := danmakuStatsFunc; stats != nil {
+		rec.SetStatsCallback(func(msgType, username, content string, extra map[string]interface{}) {
+			stats(liveId, msgType, username, content, extra)
 		})
 	}
 
@@ -840,6 +1078,46 @@ func (r *recorder) startDanmakuRecorder(ctx context.Context, fileName, platform
 	}
 }
 
+// bililiveRecorderSplitPollInterval 检查录播姬新分段文件的间隔，决定弹幕分段切换的时间精度
+const bililiveRecorderSplitPollInterval = 500 * time.Millisecond
+
+// watchBililiveRecorderSplits 监视录播姬在进程内产生的分段文件（{文件名}_PARTxxx），
+// 每出现一个新分段就让弹幕录制器切换到与之同名的 ASS 文件，并以发现分段的时刻为新的时间轴零点。
+// 第一个分段沿用初始 ASS 文件。返回的函数停止监视并等待监视协程退出。
+func (r *recorder) watchBililiveRecorderSplits(ctx context.Context, fileName string, dmRec danmakuRecorder) (stop func()) {
+	watchCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	bilisentry.GoWithContext(watchCtx, func(ctx context.Context) {
+		defer close(done)
+		ticker := time.NewTicker(bililiveRecorderSplitPollInterval)
+		defer ticker.Stop()
+		seen := 1
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			parts := findBililiveRecorderOutputFiles(fileName)
+			for ; seen < len(parts); seen++ {
+				assFile := strings.TrimSuffix(parts[seen], filepath.Ext(parts[seen])) + ".ass"
+				if err := dmRec.SplitTimeline(assFile, time.Now()); err != nil {
+					r.getLogger().WithError(err).Warn("弹幕切换分段文件失败，继续写入原文件")
+				}
+			}
+		}
+	})
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// dispatchError 分发 RecorderError 事件，供事件日志等记录
+func (r *recorder) dispatchError(stage string, err error) {
+	r.ed.DispatchEvent(events.NewEvent(RecorderError, &ErrorInfo{Live: r.Live, Stage: stage, Err: err}))
+}
+
 // stopRetryForExplicitOffline 在平台已明确给出"已下播"信号时补发一次 LiveEnd，
 // 让 recorder manager 走正常回收流程，避免 recorder 永久停留在"录制准备中"。
 func (r *recorder) stopRetryForExplicitOffline(err error) bool {
@@ -851,28 +1129,30 @@ func (r *recorder) stopRetryForExplicitOffline(err error) bool {
 	return true
 }
 
-func (r *recorder) selectPreferredStream(streamInfos []*live.StreamUrlInfo) (ret *live.StreamUrlInfo) {
+func (r *recorder) selectPreferredStream(streamInfos []*live.StreamUrlInfo) *live.StreamUrlInfo {
+	streamPreference := configs.GetCurrentConfig().GetEffectiveConfigForRoom(r.Live.GetRawUrl()).StreamPreference
+	ret, matched := SelectPreferredStream(streamInfos, streamPreference)
+	if !matched {
+		quality, attrs := streamPreferenceValues(streamPreference)
+		r.getLogger().Warnf("没有流匹配配置的偏好 (quality=%s, attrs=%v)，使用第一个可用流", quality, attrs)
+	}
+	return ret
+}
+
+// SelectPreferredStream 按流偏好选择流：清晰度匹配计 100 分，每个属性匹配计 1 分，取得分最高的流。
+// 未配置偏好时返回第一个流；配置了偏好但没有任何流匹配时同样返回第一个流，matched 为 false
+func SelectPreferredStream(streamInfos []*live.StreamUrlInfo, streamPreference configs.StreamPreference) (ret *live.StreamUrlInfo, matched bool) {
 	// 如果没有可用流，直接返回 nil
 	if len(streamInfos) == 0 {
-		return nil
+		return nil, true
 	}
 
-	streamPreference := configs.GetCurrentConfig().GetEffectiveConfigForRoom(r.Live.GetRawUrl()).StreamPreference
-
 	// 如果未配置流偏好（Quality 和 Attributes 均为 nil），直接返回第一个流
 	if streamPreference.Quality == nil && streamPreference.Attributes == nil {
-		return streamInfos[0]
+		return streamInfos[0], true
 	}
 
-	// 安全获取 Quality 和 Attributes，处理 nil 情况
-	var quality string
-	if streamPreference.Quality != nil {
-		quality = *streamPreference.Quality
-	}
-	var attrs map[string]string
-	if streamPreference.Attributes != nil {
-		attrs = *streamPreference.Attributes
-	}
+	quality, attrs := streamPreferenceValues(streamPreference)
 
 	retMatchedCount := 0
 	for _, info := range streamInfos {
@@ -895,8 +1175,18 @@ func (r *recorder) selectPreferredStream(streamInfos []*live.StreamUrlInfo) (ret
 
 	// 如果没有任何匹配的流，回退到第一个可用流
 	if ret == nil {
-		r.getLogger().Warnf("没有流匹配配置的偏好 (quality=%s, attrs=%v)，使用第一个可用流", quality, attrs)
-		return streamInfos[0]
+		return streamInfos[0], false
+	}
+	return ret, true
+}
+
+// streamPreferenceValues 安全获取 Quality 和 Attributes，处理 nil 情况
+func streamPreferenceValues(streamPreference configs.StreamPreference) (quality string, attrs map[string]string) {
+	if streamPreference.Quality != nil {
+		quality = *streamPreference.Quality
+	}
+	if streamPreference.Attributes != nil {
+		attrs = *streamPreference.Attributes
 	}
 	return
 }
@@ -1155,6 +1445,10 @@ func (r *recorder) getCurrentFilePath() string {
 	return r.currentFilePath
 }
 
+func (r *recorder) CurrentFile() string {
+	return r.getCurrentFilePath()
+}
+
 func (r *recorder) GetStatus() (map[string]interface{}, error) {
 	var status map[string]interface{}
 
@@ -1209,6 +1503,17 @@ func (r *recorder) GetStatus() (map[string]interface{}, error) {
 		status["stream_codec"] = streamInfo.Codec
 	}
 
+	if restream := r.restreamStatus(); restream != nil {
+		status["restream"] = restream
+	}
+
+	// 本地预览的格式，前端据此选择预览地址和播放方式
+	if r.hlsPreview.Load() != nil {
+		status["preview_format"] = "ts"
+	} else if r.preview.Load() != nil {
+		status["preview_format"] = "flv"
+	}
+
 	// 添加实际流头部信息（来自 StreamProbe 探测）
 	if actualInfo := r.actualStreamInfo.Load(); actualInfo != nil {
 		status["probe_status"] = actualInfo.ProbeStatus()
@@ -1288,6 +1593,16 @@ func (r *recorder) RequestSegment() bool {
 	return false
 }
 
+// Preview 返回当前录制的本地预览转发，未在录制 FLV 流或未启用预览时返回 nil
+func (r *recorder) Preview() *flvproxy.Relay {
+	return r.preview.Load()
+}
+
+// HLSPreview 返回当前录制的 HLS 预览转发，未在录制 HLS 流或未启用预览时返回 nil
+func (r *recorder) HLSPreview() *hlsproxy.Relay {
+	return r.hlsPreview.Load()
+}
+
 // HasFlvProxy 检查当前是否使用 FLV 代理
 func (r *recorder) HasFlvProxy() bool {
 	p := r.getParser()
@@ -1548,9 +1863,9 @@ func extractCookiesString(l live.Live) string {
 	return strings.Join(parts, "; ")
 }
 
-// extractDouyinRoomID 从抖音直播 URL 中提取房间号（字符串）。
-// 抖音房间号是数字字符串，直接从 URL 路径中提取。
-func extractDouyinRoomID(l live.Live) string {
+// extractFirstPathSegment 提取直播 URL 路径的第一段，
+// 用于抖音房间号、虎牙房间号、Twitch 频道名与 SOOP 主播 ID。
+func extractFirstPathSegment(l live.Live) string {
 	u, err := url.Parse(l.GetRawUrl())
 	if err != nil {
 		return ""
@@ -1566,6 +1881,17 @@ func extractDouyinRoomID(l live.Live) string {
 	return roomID
 }
 
+// extractLastPathSegment 提取直播 URL 路径的最后一段，
+// 用于快手（/u/<principalId>）与 AcFun（/live/<authorId>）。
+func extractLastPathSegment(l live.Live) string {
+	u, err := url.Parse(l.GetRawUrl())
+	if err != nil {
+		return ""
+	}
+	path := strings.Trim(u.Path, "/")
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
 // extractDouyuRoomID 从斗鱼直播 URL 中提取房间号（字符串）。
 // 优先使用 Live 中已解析的数字 roomID（fetchRoomID 从页面解析），
 // 避免别名 URL（如 /lotterytimer）传入非数字 ID 导致弹幕登录失败。
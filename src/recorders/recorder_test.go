@@ -0,0 +1,58 @@
+package recorders
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/live"
+)
+
+func TestSelectPreferredStream(t *testing.T) {
+	streams := []*live.StreamUrlInfo{
+		{Quality: "高清", AttributesForStreamSelect: map[string]string{"format": "flv"}},
+		{Quality: "原画", AttributesForStreamSelect: map[string]string{"format": "hls"}},
+		{Quality: "原画", AttributesForStreamSelect: map[string]string{"format": "flv"}},
+	}
+	quality := "原画"
+	attrs := map[string]string{"format": "flv"}
+	unknown := "蓝光"
+
+	ret, matched := SelectPreferredStream(streams, configs.StreamPreference{})
+	assert.True(t, matched)
+	assert.Same(t, streams[0], ret)
+
+	// 清晰度优先于属性
+	ret, matched = SelectPreferredStream(streams, configs.StreamPreference{Quality: &quality})
+	assert.True(t, matched)
+	assert.Same(t, streams[1], ret)
+	ret, _ = SelectPreferredStream(streams, configs.StreamPreference{Quality: &quality, Attributes: &attrs})
+	assert.Same(t, streams[2], ret)
+
+	ret, matched = SelectPreferredStream(streams, configs.StreamPreference{Quality: &unknown})
+	assert.False(t, matched)
+	assert.Same(t, streams[0], ret)
+
+	ret, _ = SelectPreferredStream(nil, configs.StreamPreference{Quality: &quality})
+	assert.Nil(t, ret)
+}
+
+func TestRedactRestreamTarget(t *testing.T) {
+	assert.Equal(t, "rtmp://live.example.com/app/***", configs.RedactRestreamTarget("rtmp://live.example.com/app/secret-key"))
+	assert.Equal(t, "rtmps://live.example.com:443/app/***?***", configs.RedactRestreamTarget("rtmps://u:p@live.example.com:443/app/key?token=x"))
+	assert.Equal(t, "srt://127.0.0.1:9000?***", configs.RedactRestreamTarget("srt://127.0.0.1:9000?streamid=publish/key&passphrase=abc"))
+
+	var tail stderrTail
+	tail.Write([]byte("first\nConnection ref"))
+	tail.Write([]byte("used\n\n"))
+	assert.Equal(t, "Connection refused", tail.Last())
+
+	target := "rtmp://live.example.com/app/secret-key"
+	var logged bytes.Buffer
+	redacting := stderrTail{next: &logged, target: target, redacted: configs.RedactRestreamTarget(target)}
+	redacting.Write([]byte("[rtmp] " + target + ": I/O error\n"))
+	assert.Equal(t, "[rtmp] rtmp://live.example.com/app/***: I/O error", redacting.Last())
+	assert.NotContains(t, logged.String(), "secret-key")
+}
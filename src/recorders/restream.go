@@ -0,0 +1,284 @@
+package recorders
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/live"
+	"github.com/bililive-go/bililive-go/src/pkg/flvproxy"
+	"github.com/bililive-go/bililive-go/src/pkg/livelogger"
+	bilisentry "github.com/bililive-go/bililive-go/src/pkg/sentry"
+	"github.com/bililive-go/bililive-go/src/pkg/utils"
+)
+
+// 转推状态
+const (
+	restreamConnecting = "connecting"
+	restreamRunning    = "running"
+	restreamRetrying   = "retrying"
+)
+
+const (
+	restreamRetryMin = 3 * time.Second
+	restreamRetryMax = time.Minute
+	// restreamStableAfter 转推持续超过该时间后断开，重连间隔从最小值重新开始
+	restreamStableAfter = time.Minute
+)
+
+// restreamer 将录制中的直播流转推到一个 RTMP/SRT 地址。
+// FLV 源从预览转发读取录制器已收到的数据，不额外请求 CDN；其他格式由 FFmpeg 读取录制器使用的同一地址。
+// FFmpeg 退出后按退避间隔重连，直到录制结束。
+type restreamer struct {
+	target     string
+	relay      *flvproxy.Relay
+	source     *live.StreamUrlInfo
+	ffmpegPath string
+	logger     *livelogger.LiveLogger
+
+	mu        sync.Mutex
+	state     string
+	restarts  int
+	lastError string
+	since     time.Time
+}
+
+func (s *restreamer) setState(state string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = state
+	s.since = time.Now()
+}
+
+// status 转推状态，推流地址隐去推流码
+func (s *restreamer) status() map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return map[string]interface{}{
+		"target":     configs.RedactRestreamTarget(s.target),
+		"state":      s.state,
+		"restarts":   s.restarts,
+		"last_error": s.lastError,
+		"since":      s.since.Unix(),
+	}
+}
+
+func (s *restreamer) run(ctx context.Context) {
+	backoff := restreamRetryMin
+	for {
+		s.setState(restreamConnecting)
+		started := time.Now()
+		err := s.runOnce(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if time.Since(started) > restreamStableAfter {
+			backoff = restreamRetryMin
+		}
+		if err == nil {
+			err = errors.New("转推连接已断开")
+		}
+		s.mu.Lock()
+		s.restarts++
+		s.lastError = err.Error()
+		s.mu.Unlock()
+		s.setState(restreamRetrying)
+		s.logger.Warnf("转推 %s 中断，%s 后重连: %v", configs.RedactRestreamTarget(s.target), backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, restreamRetryMax)
+	}
+}
+
+// runOnce 运行一次 FFmpeg 转推，返回 FFmpeg 退出的原因
+func (s *restreamer) runOnce(ctx context.Context) error {
+	args := []string{"-hide_banner", "-loglevel", "error"}
+	var viewer *flvproxy.Viewer
+	if s.relay != nil {
+		v, err := s.relay.Tap()
+		if err != nil {
+			return err
+		}
+		viewer = v
+		defer s.relay.Unsubscribe(v)
+		args = append(args, "-f", "flv", "-i", "pipe:0")
+	} else {
+		for k, v := range s.source.HeadersForDownloader {
+			if k == "User-Agent" {
+				args = append(args, "-user_agent", v)
+			} else {
+				args = append(args, "-headers", k+": "+v)
+			}
+		}
+		args = append(args, "-i", s.source.Url.String())
+	}
+	format := "flv"
+	if strings.HasPrefix(s.target, "srt://") {
+		format = "mpegts"
+	}
+	args = append(args, "-c", "copy", "-f", format, s.target)
+
+	cmd := exec.CommandContext(ctx, s.ffmpegPath, args...)
+	// FFmpeg 出错时会输出完整的推流地址，写入日志和中断原因前隐去推流码
+	tail := &stderrTail{
+		next:     utils.NewLoggerWriter(s.logger),
+		target:   s.target,
+		redacted: configs.RedactRestreamTarget(s.target),
+	}
+	cmd.Stderr = tail
+	var stdin io.WriteCloser
+	if viewer != nil {
+		var err error
+		if stdin, err = cmd.StdinPipe(); err != nil {
+			return err
+		}
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	s.setState(restreamRunning)
+	if viewer != nil {
+		// 观众读取过慢被断开或录制结束时 C 关闭，FFmpeg 读到 EOF 后退出
+		bilisentry.Go(func() {
+			defer stdin.Close()
+			for data := range viewer.C {
+				if _, err := stdin.Write(data); err != nil {
+					return
+				}
+			}
+		})
+	}
+	err := cmd.Wait()
+	if line := tail.Last(); line != "" {
+		return errors.New(line)
+	}
+	return err
+}
+
+// startRestream 按直播间配置启动转推，返回的函数停止所有转推并等待退出
+func (r *recorder) startRestream(ctx context.Context, targets []string, source *live.StreamUrlInfo) func() {
+	if len(targets) == 0 {
+		return func() {}
+	}
+	ffmpegPath, err := utils.GetFFmpegPathForLive(ctx, r.Live)
+	if err != nil {
+		r.getLogger().WithError(err).Warn("FFmpeg 不可用，跳过转推")
+		return func() {}
+	}
+	relay := r.preview.Load()
+
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	restreamers := make([]*restreamer, 0, len(targets))
+	for _, ref := range targets {
+		target, err := configs.LookupSecret(ref)
+		if err == nil {
+			err = configs.ValidateRestreamTarget(target)
+		}
+		if err != nil {
+			r.getLogger().WithError(err).Warnf("跳过转推: %s", ref)
+			continue
+		}
+		s := &restreamer{
+			target:     target,
+			relay:      relay,
+			source:     source,
+			ffmpegPath: ffmpegPath,
+			logger:     r.getLogger(),
+		}
+		restreamers = append(restreamers, s)
+		wg.Add(1)
+		bilisentry.GoWithContext(ctx, func(ctx context.Context) {
+			defer wg.Done()
+			s.run(ctx)
+		})
+		r.getLogger().Infof("开始转推: %s", configs.RedactRestreamTarget(target))
+	}
+	r.restreamMu.Lock()
+	r.restreamers = restreamers
+	r.restreamMu.Unlock()
+
+	return func() {
+		cancel()
+		wg.Wait()
+		r.restreamMu.Lock()
+		r.restreamers = nil
+		r.restreamMu.Unlock()
+	}
+}
+
+// restreamStatus 当前录制的转推状态，没有转推时返回 nil
+func (r *recorder) restreamStatus() []map[string]interface{} {
+	r.restreamMu.Lock()
+	defer r.restreamMu.Unlock()
+	if len(r.restreamers) == 0 {
+		return nil
+	}
+	status := make([]map[string]interface{}, 0, len(r.restreamers))
+	for _, s := range r.restreamers {
+		status = append(status, s.status())
+	}
+	return status
+}
+
+// stderrTail 按行转发 FFmpeg 输出并保留最后一行作为转推中断的原因，
+// 输出中的推流地址替换为隐去推流码的形式
+type stderrTail struct {
+	next     io.Writer
+	target   string
+	redacted string
+
+	mu   sync.Mutex
+	buf  []byte
+	last string
+}
+
+func (t *stderrTail) redact(line string) string {
+	if t.target == "" {
+		return line
+	}
+	return strings.ReplaceAll(line, t.target, t.redacted)
+}
+
+func (t *stderrTail) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.buf = append(t.buf, p...)
+	for {
+		i := bytes.IndexByte(t.buf, '\n')
+		if i < 0 {
+			break
+		}
+		if line := t.redact(strings.TrimSpace(string(t.buf[:i]))); line != "" {
+			t.last = line
+			if t.next != nil {
+				t.next.Write([]byte(line + "\n"))
+			}
+		}
+		t.buf = t.buf[i+1:]
+	}
+	if len(t.buf) > 4096 {
+		t.buf = t.buf[len(t.buf)-4096:]
+	}
+	return len(p), nil
+}
+
+// Last 返回最后一行输出
+func (t *stderrTail) Last() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if line := t.redact(strings.TrimSpace(string(t.buf))); line != "" {
+		return line
+	}
+	return t.last
+}
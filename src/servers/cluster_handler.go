@@ -0,0 +1,94 @@
+package servers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/bililive-go/bililive-go/src/cluster"
+	"github.com/bililive-go/bililive-go/src/configs"
+)
+
+// getClusterStatus 返回本节点的集群状态，单机运行时 mode 为空
+func getClusterStatus(writer http.ResponseWriter, r *http.Request) {
+	writeJSON(writer, cluster.CurrentStatus())
+}
+
+func writeClusterError(writer http.ResponseWriter, err error) {
+	writeJsonWithStatusCode(writer, http.StatusBadRequest, commonResp{
+		ErrNo:  http.StatusBadRequest,
+		ErrMsg: err.Error(),
+	})
+}
+
+func getClusterNodes(writer http.ResponseWriter, r *http.Request) {
+	svc := cluster.Default()
+	if svc == nil {
+		writeClusterError(writer, cluster.ErrNotCoordinator)
+		return
+	}
+	nodes, err := svc.Nodes()
+	if err != nil {
+		writeClusterError(writer, err)
+		return
+	}
+	writeJSON(writer, nodes)
+}
+
+func getClusterLives(writer http.ResponseWriter, r *http.Request) {
+	svc := cluster.Default()
+	if svc == nil {
+		writeClusterError(writer, cluster.ErrNotCoordinator)
+		return
+	}
+	lives, err := svc.Lives()
+	if err != nil {
+		writeClusterError(writer, err)
+		return
+	}
+	writeJSON(writer, lives)
+}
+
+// clusterHeartbeatAuthorized 校验心跳请求携带的令牌。
+// 心跳响应包含直播间配置（含转推地址），不依赖通用鉴权中间件是否生效，未配置令牌时一律拒绝
+func clusterHeartbeatAuthorized(r *http.Request) bool {
+	cfg := configs.GetCurrentConfig()
+	if cfg == nil || cfg.RPC.Token == "" {
+		return false
+	}
+	expected, err := configs.LookupSecret(cfg.RPC.Token)
+	if err != nil || expected == "" {
+		return false
+	}
+	token := requestToken(r)
+	return token != "" && tokenEqual(token, expected)
+}
+
+// clusterHeartbeat 接收 worker 心跳，响应中包含分配给该节点的直播间
+func clusterHeartbeat(writer http.ResponseWriter, r *http.Request) {
+	if !clusterHeartbeatAuthorized(r) {
+		writeJsonWithStatusCode(writer, http.StatusUnauthorized, commonResp{
+			ErrNo:  http.StatusUnauthorized,
+			ErrMsg: "未授权：缺少或错误的访问令牌",
+		})
+		return
+	}
+	svc := cluster.Default()
+	if svc == nil {
+		writeClusterError(writer, cluster.ErrNotCoordinator)
+		return
+	}
+	var req cluster.HeartbeatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJsonWithStatusCode(writer, http.StatusBadRequest, commonResp{
+			ErrNo:  http.StatusBadRequest,
+			ErrMsg: "无效的JSON格式: " + err.Error(),
+		})
+		return
+	}
+	resp, err := svc.HandleHeartbeat(req)
+	if err != nil {
+		writeClusterError(writer, err)
+		return
+	}
+	writeJSON(writer, resp)
+}
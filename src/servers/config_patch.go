@@ -0,0 +1,146 @@
+package servers
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// errUnknownConfigKeys 表示严格模式下的部分更新包含未识别的字段
+var errUnknownConfigKeys = errors.New("存在未识别的配置项")
+
+// configPatchResult 是 PATCH /api/config 的响应数据，键以点号分隔的路径表示
+type configPatchResult struct {
+	Applied []string `json:"applied"`
+	Ignored []string `json:"ignored"`
+}
+
+// configPatch 记录 applyConfigUpdates 实际读取过的字段，用于找出被忽略的键。
+// nil 值的 configPatch 只做类型断言、不做记录，供房间/平台配置复用同一组辅助函数。
+type configPatch struct {
+	seen map[uintptr]map[string]bool
+	// raw 中的对象整体作为一个值使用（如 stream_preference.attributes），不再逐键检查
+	raw map[uintptr]bool
+}
+
+func newConfigPatch() *configPatch {
+	return &configPatch{
+		seen: make(map[uintptr]map[string]bool),
+		raw:  make(map[uintptr]bool),
+	}
+}
+
+func mapKey(m map[string]interface{}) uintptr {
+	return reflect.ValueOf(m).Pointer()
+}
+
+func (p *configPatch) mark(m map[string]interface{}, key string) {
+	if p == nil {
+		return
+	}
+	k := mapKey(m)
+	if p.seen[k] == nil {
+		p.seen[k] = make(map[string]bool)
+	}
+	p.seen[k][key] = true
+}
+
+// lookup 读取任意类型的字段，字段存在即视为已应用
+func (p *configPatch) lookup(m map[string]interface{}, key string) (interface{}, bool) {
+	v, ok := m[key]
+	if ok {
+		p.mark(m, key)
+	}
+	return v, ok
+}
+
+func (p *configPatch) str(m map[string]interface{}, key string) (string, bool) {
+	v, ok := m[key].(string)
+	if ok {
+		p.mark(m, key)
+	}
+	return v, ok
+}
+
+func (p *configPatch) flag(m map[string]interface{}, key string) (bool, bool) {
+	v, ok := m[key].(bool)
+	if ok {
+		p.mark(m, key)
+	}
+	return v, ok
+}
+
+func (p *configPatch) num(m map[string]interface{}, key string) (float64, bool) {
+	v, ok := m[key].(float64)
+	if ok {
+		p.mark(m, key)
+	}
+	return v, ok
+}
+
+func (p *configPatch) list(m map[string]interface{}, key string) ([]interface{}, bool) {
+	v, ok := m[key].([]interface{})
+	if ok {
+		p.mark(m, key)
+	}
+	return v, ok
+}
+
+// obj 读取结构化的子对象，其中的键会继续逐个检查
+func (p *configPatch) obj(m map[string]interface{}, key string) (map[string]interface{}, bool) {
+	v, ok := m[key].(map[string]interface{})
+	if ok {
+		p.mark(m, key)
+	}
+	return v, ok
+}
+
+// rawObj 读取自由格式的子对象，整体视为已应用
+func (p *configPatch) rawObj(m map[string]interface{}, key string) (map[string]interface{}, bool) {
+	v, ok := p.obj(m, key)
+	if ok && p != nil {
+		p.raw[mapKey(v)] = true
+	}
+	return v, ok
+}
+
+// null 判断字段是否显式传入了 null（表示清除该项）
+func (p *configPatch) null(m map[string]interface{}, key string) bool {
+	v, ok := m[key]
+	if ok && v == nil {
+		p.mark(m, key)
+		return true
+	}
+	return false
+}
+
+// result 对照 updates 汇总已应用和被忽略的键
+func (p *configPatch) result(updates map[string]interface{}) configPatchResult {
+	res := configPatchResult{Applied: []string{}, Ignored: []string{}}
+	p.walk(updates, "", &res)
+	sort.Strings(res.Applied)
+	sort.Strings(res.Ignored)
+	return res
+}
+
+func (p *configPatch) walk(m map[string]interface{}, prefix string, res *configPatchResult) {
+	seen := p.seen[mapKey(m)]
+	for key, v := range m {
+		path := prefix + key
+		if !seen[key] {
+			res.Ignored = append(res.Ignored, path)
+			continue
+		}
+		if sub, ok := v.(map[string]interface{}); ok && !p.raw[mapKey(sub)] {
+			p.walk(sub, path+".", res)
+			continue
+		}
+		res.Applied = append(res.Applied, path)
+	}
+}
+
+func (r configPatchResult) unknownKeysError() error {
+	return fmt.Errorf("%w: %s", errUnknownConfigKeys, strings.Join(r.Ignored, ", "))
+}
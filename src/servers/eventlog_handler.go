@@ -0,0 +1,80 @@
+package servers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bililive-go/bililive-go/src/eventlog"
+	"github.com/bililive-go/bililive-go/src/instance"
+)
+
+// eventLogResponse 事件日志查询结果
+type eventLogResponse struct {
+	Events []eventlog.Entry `json:"events"`
+	// NextSince 下一次查询使用的游标（本次最后一条事件的 id，没有新事件时等于请求的 since）
+	NextSince int64 `json:"next_since"`
+	// HasMore 是否还有更多事件，为 true 时应立即用 next_since 继续查询
+	HasMore bool `json:"has_more"`
+}
+
+// getEventLog 按游标回放事件日志
+//
+//	GET /api/events?since=<id>&limit=<n>&types=live_start,live_end&live_id=<id>
+func getEventLog(writer http.ResponseWriter, r *http.Request) {
+	inst := instance.GetInstance(r.Context())
+	store, ok := inst.EventLogStore.(eventlog.Store)
+	if !ok || store == nil {
+		writeJsonWithStatusCode(writer, http.StatusServiceUnavailable, commonResp{
+			ErrNo:  http.StatusServiceUnavailable,
+			ErrMsg: "事件日志未启用",
+		})
+		return
+	}
+
+	q := r.URL.Query()
+	query := eventlog.Query{LiveID: q.Get("live_id")}
+	if sinceStr := q.Get("since"); sinceStr != "" {
+		since, err := strconv.ParseInt(sinceStr, 10, 64)
+		if err != nil || since < 0 {
+			writeJsonWithStatusCode(writer, http.StatusBadRequest, commonResp{
+				ErrNo:  http.StatusBadRequest,
+				ErrMsg: "since 必须是非负整数",
+			})
+			return
+		}
+		query.Since = since
+	}
+	query.Limit = eventlog.DefaultLimit
+	if limitStr := q.Get("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
+			query.Limit = min(limit, eventlog.MaxLimit)
+		}
+	}
+	if typesStr := q.Get("types"); typesStr != "" {
+		for _, t := range strings.Split(typesStr, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				query.Types = append(query.Types, t)
+			}
+		}
+	}
+
+	entries, err := store.List(r.Context(), query)
+	if err != nil {
+		writeJsonWithStatusCode(writer, http.StatusInternalServerError, commonResp{
+			ErrNo:  http.StatusInternalServerError,
+			ErrMsg: "查询事件日志失败: " + err.Error(),
+		})
+		return
+	}
+
+	resp := eventLogResponse{
+		Events:    entries,
+		NextSince: query.Since,
+		HasMore:   len(entries) == query.Limit,
+	}
+	if len(entries) > 0 {
+		resp.NextSince = entries[len(entries)-1].ID
+	}
+	writeJSON(writer, resp)
+}
@@ -0,0 +1,78 @@
+package servers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/bililive-go/bililive-go/src/eventbus"
+)
+
+const (
+	eventsWSWriteTimeout = 10 * time.Second
+	eventsWSPingInterval = 30 * time.Second
+)
+
+var eventsWSUpgrader = websocket.Upgrader{
+	// 事件流面向外部服务，非浏览器客户端不受来源限制
+	CheckOrigin: checkWebSocketOrigin,
+}
+
+// eventsWSHandler 对外事件流 WebSocket 端点。
+// 每条文本消息是一个 eventbus.Message（JSON），可用 ?types=live_start,live_end 只订阅部分事件
+func eventsWSHandler(w http.ResponseWriter, r *http.Request) {
+	var types []eventbus.Type
+	if q := r.URL.Query().Get("types"); q != "" {
+		for _, t := range strings.Split(q, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				types = append(types, eventbus.Type(t))
+			}
+		}
+	}
+
+	conn, err := eventsWSUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	msgs, cancel := eventbus.GetBus().Subscribe(types...)
+	defer cancel()
+
+	// 读取并丢弃客户端消息，用于感知连接关闭
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(eventsWSPingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case <-r.Context().Done():
+			return
+		case <-ping.C:
+			conn.SetWriteDeadline(time.Now().Add(eventsWSWriteTimeout))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case msg, ok := <-msgs:
+			if !ok {
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(eventsWSWriteTimeout))
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		}
+	}
+}
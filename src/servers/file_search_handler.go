@@ -0,0 +1,109 @@
+package servers
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+)
+
+const (
+	fileSearchDefaultLimit = 100
+	fileSearchMaxLimit     = 1000
+)
+
+// fileSearchResult 文件搜索结果，Path 为相对输出目录的路径（使用 / 分隔）
+type fileSearchResult struct {
+	Path         string `json:"path"`
+	Name         string `json:"name"`
+	IsFolder     bool   `json:"is_folder"`
+	Size         int64  `json:"size"`
+	LastModified int64  `json:"last_modified"`
+}
+
+// fileSearchResponse 文件搜索响应，Truncated 表示匹配数量超过 limit，结果被截断
+type fileSearchResponse struct {
+	Query     string             `json:"query"`
+	Files     []fileSearchResult `json:"files"`
+	Truncated bool               `json:"truncated"`
+}
+
+// errFileSearchLimit 找到足够的结果后中止遍历
+var errFileSearchLimit = errors.New("file search limit reached")
+
+// searchOutputFiles 在输出目录中递归查找文件名包含 query 的文件（不区分大小写）
+func searchOutputFiles(root, query string, limit int) (*fileSearchResponse, error) {
+	absRoot, err := getSafePath(root, "")
+	if err != nil {
+		return nil, err
+	}
+	needle := strings.ToLower(query)
+	resp := &fileSearchResponse{Query: query, Files: make([]fileSearchResult, 0)}
+	err = filepath.WalkDir(absRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// 无权限等无法读取的目录直接跳过
+			if d != nil && d.IsDir() && path != absRoot {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if path == absRoot || !strings.Contains(strings.ToLower(d.Name()), needle) {
+			return nil
+		}
+		if len(resp.Files) >= limit {
+			resp.Truncated = true
+			return errFileSearchLimit
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(absRoot, path)
+		if err != nil {
+			return nil
+		}
+		resp.Files = append(resp.Files, fileSearchResult{
+			Path:         filepath.ToSlash(rel),
+			Name:         d.Name(),
+			IsFolder:     d.IsDir(),
+			Size:         info.Size(),
+			LastModified: info.ModTime().Unix(),
+		})
+		return nil
+	})
+	if err != nil && !errors.Is(err, errFileSearchLimit) {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// searchFiles 按文件名搜索输出目录：GET /api/files/search?q=<关键字>&limit=<数量>
+func searchFiles(writer http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		writeJsonWithStatusCode(writer, http.StatusBadRequest, commonResp{
+			ErrNo:  http.StatusBadRequest,
+			ErrMsg: "缺少搜索关键字 q",
+		})
+		return
+	}
+	limit := fileSearchDefaultLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = min(parsedLimit, fileSearchMaxLimit)
+		}
+	}
+	resp, err := searchOutputFiles(configs.GetCurrentConfig().OutPutPath, query, limit)
+	if err != nil {
+		writeJsonWithStatusCode(writer, http.StatusInternalServerError, commonResp{
+			ErrNo:  http.StatusInternalServerError,
+			ErrMsg: "搜索文件失败: " + err.Error(),
+		})
+		return
+	}
+	writeJSON(writer, resp)
+}
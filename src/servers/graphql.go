@@ -0,0 +1,245 @@
+package servers
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sirupsen/logrus"
+)
+
+// graphQLSchemaSDL 管理 API 的 GraphQL schema，同时作为对外发布的接口描述
+//
+//go:embed schema.graphql
+var graphQLSchemaSDL string
+
+const (
+	// graphQLWSProtocol graphql-ws 库使用的 WebSocket 子协议
+	graphQLWSProtocol = "graphql-transport-ws"
+	// graphQLWSInitTimeout 建立连接后等待 connection_init 的时间
+	graphQLWSInitTimeout = 10 * time.Second
+	graphQLMaxDepth      = 15
+)
+
+var (
+	graphQLSchemaOnce sync.Once
+	graphQLSchema     *graphql.Schema
+)
+
+// getGraphQLSchema 解析 schema 并绑定解析器，schema 与解析器不一致时 panic
+func getGraphQLSchema() *graphql.Schema {
+	graphQLSchemaOnce.Do(func() {
+		graphQLSchema = graphql.MustParseSchema(graphQLSchemaSDL, &graphQLResolver{},
+			graphql.UseStringDescriptions(),
+			graphql.MaxDepth(graphQLMaxDepth),
+		)
+	})
+	return graphQLSchema
+}
+
+// graphQLRequest 查询请求
+type graphQLRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// graphQLHandler 管理 API 的 GraphQL 端点：
+// POST 执行查询与变更，WebSocket（graphql-transport-ws 协议）执行订阅
+func graphQLHandler(writer http.ResponseWriter, r *http.Request) {
+	if websocket.IsWebSocketUpgrade(r) {
+		graphQLWSHandler(writer, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeJsonWithStatusCode(writer, http.StatusMethodNotAllowed, commonResp{
+			ErrNo:  http.StatusMethodNotAllowed,
+			ErrMsg: "查询请使用 POST，订阅请使用 WebSocket",
+		})
+		return
+	}
+	var req graphQLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJsonWithStatusCode(writer, http.StatusBadRequest, commonResp{
+			ErrNo:  http.StatusBadRequest,
+			ErrMsg: "无效的JSON格式: " + err.Error(),
+		})
+		return
+	}
+	writeJSON(writer, getGraphQLSchema().Exec(r.Context(), req.Query, req.OperationName, req.Variables))
+}
+
+// getGraphQLSchemaSDL 返回 GraphQL schema 原文，用于生成客户端
+func getGraphQLSchemaSDL(writer http.ResponseWriter, r *http.Request) {
+	writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	writer.Write([]byte(graphQLSchemaSDL))
+}
+
+var graphQLWSUpgrader = websocket.Upgrader{
+	CheckOrigin:  checkWebSocketOrigin,
+	Subprotocols: []string{graphQLWSProtocol},
+}
+
+// graphQLWSMessage graphql-transport-ws 协议消息
+type graphQLWSMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// graphQLWSConn 一条订阅连接，写操作需加锁
+type graphQLWSConn struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+
+	mu   sync.Mutex
+	subs map[string]context.CancelFunc
+}
+
+func (c *graphQLWSConn) send(id, typ string, payload any) error {
+	msg := graphQLWSMessage{ID: id, Type: typ}
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		msg.Payload = b
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(eventsWSWriteTimeout))
+	return c.conn.WriteJSON(msg)
+}
+
+func (c *graphQLWSConn) closeWith(code int, reason string) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(time.Second))
+	c.conn.Close()
+}
+
+// graphQLWSHandler 实现 graphql-transport-ws 协议
+// （https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md）
+func graphQLWSHandler(writer http.ResponseWriter, r *http.Request) {
+	conn, err := graphQLWSUpgrader.Upgrade(writer, r, nil)
+	if err != nil {
+		return
+	}
+	if conn.Subprotocol() != graphQLWSProtocol {
+		conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(4406, "Subprotocol not acceptable"), time.Now().Add(time.Second))
+		conn.Close()
+		return
+	}
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	c := &graphQLWSConn{conn: conn, subs: make(map[string]context.CancelFunc)}
+	defer conn.Close()
+
+	initialized := false
+	initTimer := time.AfterFunc(graphQLWSInitTimeout, func() {
+		c.closeWith(4408, "Connection initialisation timeout")
+	})
+	defer initTimer.Stop()
+
+	for {
+		var msg graphQLWSMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return
+		}
+		switch msg.Type {
+		case "connection_init":
+			if initialized {
+				c.closeWith(4429, "Too many initialisation requests")
+				return
+			}
+			initialized = true
+			initTimer.Stop()
+			if err := c.send("", "connection_ack", nil); err != nil {
+				return
+			}
+		case "ping":
+			if err := c.send("", "pong", nil); err != nil {
+				return
+			}
+		case "pong":
+		case "subscribe":
+			if !initialized {
+				c.closeWith(4401, "Unauthorized")
+				return
+			}
+			var req graphQLRequest
+			if msg.ID == "" || json.Unmarshal(msg.Payload, &req) != nil {
+				c.closeWith(4400, "Invalid subscribe message")
+				return
+			}
+			c.mu.Lock()
+			if _, exists := c.subs[msg.ID]; exists {
+				c.mu.Unlock()
+				c.closeWith(4409, "Subscriber for "+msg.ID+" already exists")
+				return
+			}
+			subCtx, subCancel := context.WithCancel(ctx)
+			c.subs[msg.ID] = subCancel
+			c.mu.Unlock()
+			go c.runSubscription(subCtx, msg.ID, req)
+		case "complete":
+			c.mu.Lock()
+			if subCancel, ok := c.subs[msg.ID]; ok {
+				subCancel()
+				delete(c.subs, msg.ID)
+			}
+			c.mu.Unlock()
+		default:
+			c.closeWith(4400, "Unknown message type "+msg.Type)
+			return
+		}
+	}
+}
+
+// runSubscription 执行一个操作，把每个结果作为 next 消息发送，结束后发送 complete
+func (c *graphQLWSConn) runSubscription(ctx context.Context, id string, req graphQLRequest) {
+	defer func() {
+		c.mu.Lock()
+		_, active := c.subs[id]
+		delete(c.subs, id)
+		c.mu.Unlock()
+		// 客户端主动 complete 的订阅不再回复 complete
+		if active && ctx.Err() == nil {
+			c.send(id, "complete", nil)
+		}
+	}()
+
+	results, err := getGraphQLSchema().Subscribe(ctx, req.Query, req.OperationName, req.Variables)
+	if err != nil {
+		c.send(id, "error", []map[string]string{{"message": err.Error()}})
+		return
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case result, ok := <-results:
+			if !ok {
+				return
+			}
+			if resp, ok := result.(*graphql.Response); ok && resp.Data == nil && len(resp.Errors) > 0 {
+				// 校验失败等无法执行的操作按协议返回 error
+				c.send(id, "error", resp.Errors)
+				c.mu.Lock()
+				delete(c.subs, id)
+				c.mu.Unlock()
+				return
+			}
+			if err := c.send(id, "next", result); err != nil {
+				logrus.WithError(err).Debug("GraphQL 订阅推送失败")
+				return
+			}
+		}
+	}
+}
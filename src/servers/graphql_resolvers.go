@@ -0,0 +1,589 @@
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/consts"
+	"github.com/bililive-go/bililive-go/src/eventbus"
+	"github.com/bililive-go/bililive-go/src/instance"
+	"github.com/bililive-go/bililive-go/src/live"
+	"github.com/bililive-go/bililive-go/src/livestate"
+	"github.com/bililive-go/bililive-go/src/pipeline"
+	"github.com/bililive-go/bililive-go/src/types"
+)
+
+// graphQLResolver GraphQL 根解析器，方法与 schema.graphql 中的 Query/Mutation/Subscription 字段一一对应
+type graphQLResolver struct{}
+
+// jsonScalar 对应 schema 中的 JSON 标量
+type jsonScalar struct {
+	Value any
+}
+
+func (jsonScalar) ImplementsGraphQLType(name string) bool {
+	return name == "JSON"
+}
+
+func (j *jsonScalar) UnmarshalGraphQL(input any) error {
+	j.Value = input
+	return nil
+}
+
+func (j jsonScalar) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.Value)
+}
+
+var errPipelineUnavailable = errors.New("后处理管道未启用")
+
+func graphQLTime(t time.Time) graphql.Time {
+	return graphql.Time{Time: t}
+}
+
+func graphQLTimePtr(t *time.Time) *graphql.Time {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	return &graphql.Time{Time: *t}
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func getLiveByID(ctx context.Context, id graphql.ID) (live.Live, error) {
+	l, ok := instance.GetInstance(ctx).Lives.Get(types.LiveID(id))
+	if !ok {
+		return nil, fmt.Errorf("live id: %s can not find", id)
+	}
+	return l, nil
+}
+
+func getPipelineManager(ctx context.Context) (*pipeline.Manager, error) {
+	pm := pipeline.GetManager(instance.GetInstance(ctx))
+	if pm == nil {
+		return nil, errPipelineUnavailable
+	}
+	return pm, nil
+}
+
+func parseTaskID(id graphql.ID) (int64, error) {
+	taskID, err := strconv.ParseInt(string(id), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid task id: %s", id)
+	}
+	return taskID, nil
+}
+
+// ---- Query ----
+
+func (*graphQLResolver) Info() *appInfoResolver {
+	return &appInfoResolver{consts.GetAppInfo()}
+}
+
+func (*graphQLResolver) Lives(ctx context.Context) []*liveResolver {
+	inst := instance.GetInstance(ctx)
+	lives := liveSlice(make([]*live.Info, 0, 4))
+	inst.Lives.Range(func(_ types.LiveID, v live.Live) bool {
+		lives = append(lives, parseInfo(ctx, v))
+		return true
+	})
+	sort.Sort(lives)
+	resolvers := make([]*liveResolver, 0, len(lives))
+	for _, info := range lives {
+		resolvers = append(resolvers, newLiveResolver(info))
+	}
+	return resolvers
+}
+
+func (*graphQLResolver) Live(ctx context.Context, args struct{ ID graphql.ID }) *liveResolver {
+	l, err := getLiveByID(ctx, args.ID)
+	if err != nil {
+		return nil
+	}
+	return newLiveResolver(parseInfo(ctx, l))
+}
+
+func (*graphQLResolver) Config() jsonScalar {
+	return jsonScalar{configs.GetCurrentConfig().MaskSecrets()}
+}
+
+func (*graphQLResolver) Recordings(args struct{ Path string }) (*directoryResolver, error) {
+	files, err := listOutputFiles(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	return &directoryResolver{path: args.Path, files: files}, nil
+}
+
+func (*graphQLResolver) PipelineTasks(ctx context.Context, args struct {
+	Status *string
+	LiveID *graphql.ID
+	Limit  int32
+	Offset int32
+}) ([]*pipelineTaskResolver, error) {
+	pm, err := getPipelineManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filter := pipeline.TaskFilter{}
+	if args.Status != nil {
+		s := pipeline.PipelineStatus(strings.ToLower(*args.Status))
+		filter.Status = &s
+	}
+	if args.LiveID != nil {
+		liveID := string(*args.LiveID)
+		filter.LiveID = &liveID
+	}
+	filter.Limit = int(args.Limit)
+	filter.Offset = int(args.Offset)
+	tasks, err := pm.ListTasks(filter)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*pipelineTaskResolver, 0, len(tasks))
+	for _, task := range tasks {
+		resolvers = append(resolvers, &pipelineTaskResolver{task})
+	}
+	return resolvers, nil
+}
+
+func (*graphQLResolver) PipelineTask(ctx context.Context, args struct{ ID graphql.ID }) (*pipelineTaskResolver, error) {
+	pm, err := getPipelineManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	taskID, err := parseTaskID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	task, err := pm.GetTask(taskID)
+	if err != nil || task == nil {
+		return nil, nil
+	}
+	return &pipelineTaskResolver{task}, nil
+}
+
+func (*graphQLResolver) PipelineStats(ctx context.Context) (*pipelineStatsResolver, error) {
+	pm, err := getPipelineManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := pm.GetStats()
+	if err != nil {
+		return nil, err
+	}
+	return &pipelineStatsResolver{stats}, nil
+}
+
+// ---- Mutation ----
+
+func (*graphQLResolver) AddLive(ctx context.Context, args struct {
+	URL        string
+	Listen     bool
+	NotifyOnly bool
+}) (*liveResolver, error) {
+	inst := instance.GetInstance(ctx)
+	info, err := addLiveImpl(inst.Ctx, strings.TrimSpace(args.URL), args.Listen, args.NotifyOnly, true)
+	if err != nil {
+		return nil, err
+	}
+	return newLiveResolver(info), nil
+}
+
+func (*graphQLResolver) RemoveLive(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	l, err := getLiveByID(ctx, args.ID)
+	if err != nil {
+		return false, err
+	}
+	if err := removeLiveImpl(instance.GetInstance(ctx).Ctx, l); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *graphQLResolver) StartListening(ctx context.Context, args struct{ ID graphql.ID }) (*liveResolver, error) {
+	return r.setListening(ctx, args.ID, true)
+}
+
+func (r *graphQLResolver) StopListening(ctx context.Context, args struct{ ID graphql.ID }) (*liveResolver, error) {
+	return r.setListening(ctx, args.ID, false)
+}
+
+func (*graphQLResolver) setListening(ctx context.Context, id graphql.ID, listen bool) (*liveResolver, error) {
+	l, err := getLiveByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := configs.GetCurrentConfig().GetLiveRoomByUrl(l.GetRawUrl()); err != nil {
+		return nil, fmt.Errorf("room : %s can not find", l.GetRawUrl())
+	}
+	if err := setLiveListening(instance.GetInstance(ctx).Ctx, l, listen); err != nil {
+		return nil, err
+	}
+	return newLiveResolver(parseInfo(ctx, l)), nil
+}
+
+func (*graphQLResolver) UpdateConfig(args struct{ Patch jsonScalar }) (bool, error) {
+	updates, ok := args.Patch.Value.(map[string]any)
+	if !ok {
+		return false, errors.New("patch 必须是 JSON 对象")
+	}
+	if _, err := patchConfig(updates, false); err != nil {
+		return false, fmt.Errorf("更新配置失败: %w", err)
+	}
+	return true, nil
+}
+
+func (r *graphQLResolver) RetryPipelineTask(ctx context.Context, args struct{ ID graphql.ID }) (*pipelineTaskResolver, error) {
+	return r.updatePipelineTask(ctx, args.ID, (*pipeline.Manager).RetryTask)
+}
+
+func (r *graphQLResolver) CancelPipelineTask(ctx context.Context, args struct{ ID graphql.ID }) (*pipelineTaskResolver, error) {
+	return r.updatePipelineTask(ctx, args.ID, (*pipeline.Manager).CancelTask)
+}
+
+func (*graphQLResolver) updatePipelineTask(ctx context.Context, id graphql.ID, op func(*pipeline.Manager, int64) error) (*pipelineTaskResolver, error) {
+	pm, err := getPipelineManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	taskID, err := parseTaskID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := op(pm, taskID); err != nil {
+		return nil, err
+	}
+	task, err := pm.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, fmt.Errorf("task not found: %d", taskID)
+	}
+	return &pipelineTaskResolver{task}, nil
+}
+
+// ---- Subscription ----
+
+// subscribeBus 订阅事件总线，把转换后的结果写入返回的通道，ctx 结束时关闭通道
+func subscribeBus[T any](ctx context.Context, eventTypes []eventbus.Type, convert func(eventbus.Message) (T, bool)) <-chan T {
+	msgs, cancel := eventbus.GetBus().Subscribe(eventTypes...)
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		defer cancel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-msgs:
+				if !ok {
+					return
+				}
+				v, ok := convert(msg)
+				if !ok {
+					continue
+				}
+				select {
+				case out <- v:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+func (*graphQLResolver) Events(ctx context.Context, args struct{ Types *[]string }) <-chan *eventResolver {
+	var eventTypes []eventbus.Type
+	if args.Types != nil {
+		for _, t := range *args.Types {
+			eventTypes = append(eventTypes, eventbus.Type(strings.ToLower(t)))
+		}
+	}
+	return subscribeBus(ctx, eventTypes, func(msg eventbus.Message) (*eventResolver, bool) {
+		return &eventResolver{msg}, true
+	})
+}
+
+// liveEventTypes 会改变直播间状态的事件
+var liveEventTypes = []eventbus.Type{
+	eventbus.TypeLiveStart,
+	eventbus.TypeLiveEnd,
+	eventbus.TypeRoomNameChanged,
+	eventbus.TypeRecorderStart,
+	eventbus.TypeRecorderStop,
+}
+
+func (*graphQLResolver) LiveUpdated(ctx context.Context, args struct{ IDs *[]graphql.ID }) <-chan *liveResolver {
+	var ids map[string]bool
+	if args.IDs != nil && len(*args.IDs) > 0 {
+		ids = make(map[string]bool, len(*args.IDs))
+		for _, id := range *args.IDs {
+			ids[string(id)] = true
+		}
+	}
+	return subscribeBus(ctx, liveEventTypes, func(msg eventbus.Message) (*liveResolver, bool) {
+		payload, ok := msg.Data.(eventbus.LivePayload)
+		if !ok || (ids != nil && !ids[payload.LiveID]) {
+			return nil, false
+		}
+		l, err := getLiveByID(ctx, graphql.ID(payload.LiveID))
+		if err != nil {
+			return nil, false
+		}
+		return newLiveResolver(parseInfo(ctx, l)), true
+	})
+}
+
+func (*graphQLResolver) PipelineTaskUpdated(ctx context.Context, args struct{ ID *graphql.ID }) (<-chan *pipelineTaskResolver, error) {
+	pm, err := getPipelineManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var taskID int64
+	if args.ID != nil {
+		if taskID, err = parseTaskID(*args.ID); err != nil {
+			return nil, err
+		}
+	}
+	eventTypes := []eventbus.Type{eventbus.TypePipelineTaskUpdate}
+	return subscribeBus(ctx, eventTypes, func(msg eventbus.Message) (*pipelineTaskResolver, bool) {
+		payload, ok := msg.Data.(eventbus.PipelineTaskPayload)
+		if !ok || (taskID != 0 && payload.TaskID != taskID) {
+			return nil, false
+		}
+		task, err := pm.GetTask(payload.TaskID)
+		if err != nil || task == nil {
+			return nil, false
+		}
+		return &pipelineTaskResolver{task}, true
+	}), nil
+}
+
+// ---- 类型解析器 ----
+
+type appInfoResolver struct {
+	info consts.Info
+}
+
+func (r *appInfoResolver) AppName() string    { return r.info.AppName }
+func (r *appInfoResolver) AppVersion() string { return r.info.AppVersion }
+func (r *appInfoResolver) BuildTime() string  { return r.info.BuildTime }
+func (r *appInfoResolver) GitHash() string    { return r.info.GitHash }
+func (r *appInfoResolver) Platform() string   { return r.info.Platform }
+func (r *appInfoResolver) GoVersion() string  { return r.info.GoVersion }
+
+type liveResolver struct {
+	info live.Info
+}
+
+// newLiveResolver 复制一份直播间信息，parseInfo 返回的是缓存中的共享对象
+func newLiveResolver(info *live.Info) *liveResolver {
+	return &liveResolver{info: *info}
+}
+
+func (r *liveResolver) ID() graphql.ID           { return graphql.ID(r.info.Live.GetLiveId()) }
+func (r *liveResolver) URL() string              { return r.info.Live.GetRawUrl() }
+func (r *liveResolver) Platform() string         { return r.info.Live.GetPlatformCNName() }
+func (r *liveResolver) HostName() string         { return r.info.HostName }
+func (r *liveResolver) RoomName() string         { return r.info.RoomName }
+func (r *liveResolver) Living() bool             { return r.info.Status }
+func (r *liveResolver) Listening() bool          { return r.info.Listening }
+func (r *liveResolver) Recording() bool          { return r.info.Recording }
+func (r *liveResolver) RecordingPreparing() bool { return r.info.RecordingPreparing }
+func (r *liveResolver) Initializing() bool       { return r.info.Initializing }
+func (r *liveResolver) NotifyOnly() bool         { return r.info.NotifyOnly }
+func (r *liveResolver) LastError() *string       { return optionalString(r.info.LastError) }
+func (r *liveResolver) LastStartTime() *graphql.Time {
+	t := r.info.Live.GetLastStartTime()
+	return graphQLTimePtr(&t)
+}
+
+func (r *liveResolver) Sessions(ctx context.Context, args struct{ Limit int32 }) []*liveSessionResolver {
+	manager, ok := instance.GetInstance(ctx).LiveStateManager.(*livestate.Manager)
+	if !ok || manager == nil {
+		return []*liveSessionResolver{}
+	}
+	limit := 20
+	if args.Limit > 0 {
+		limit = int(args.Limit)
+	}
+	sessions := manager.GetSessionHistory(string(r.info.Live.GetLiveId()), limit)
+	resolvers := make([]*liveSessionResolver, 0, len(sessions))
+	for _, s := range sessions {
+		resolvers = append(resolvers, &liveSessionResolver{s})
+	}
+	return resolvers
+}
+
+type liveSessionResolver struct {
+	s *livestate.LiveSession
+}
+
+func (r *liveSessionResolver) ID() graphql.ID          { return graphql.ID(strconv.FormatInt(r.s.ID, 10)) }
+func (r *liveSessionResolver) HostName() string        { return r.s.HostName }
+func (r *liveSessionResolver) RoomName() string        { return r.s.RoomName }
+func (r *liveSessionResolver) StartTime() graphql.Time { return graphQLTime(r.s.StartTime) }
+func (r *liveSessionResolver) EndTime() *graphql.Time  { return graphQLTimePtr(&r.s.EndTime) }
+func (r *liveSessionResolver) EndReason() string       { return r.s.EndReason }
+
+type directoryResolver struct {
+	path  string
+	files []outputFile
+}
+
+func (r *directoryResolver) Path() string { return r.path }
+func (r *directoryResolver) Files() []*fileResolver {
+	resolvers := make([]*fileResolver, 0, len(r.files))
+	for i := range r.files {
+		resolvers = append(resolvers, &fileResolver{&r.files[i]})
+	}
+	return resolvers
+}
+
+type fileResolver struct {
+	f *outputFile
+}
+
+func (r *fileResolver) Name() string          { return r.f.Name }
+func (r *fileResolver) IsFolder() bool        { return r.f.IsFolder }
+func (r *fileResolver) Size() float64         { return float64(r.f.Size) }
+func (r *fileResolver) SubtitleFile() *string { return optionalString(r.f.SubtitleFile) }
+func (r *fileResolver) LastModified() graphql.Time {
+	return graphQLTime(time.Unix(r.f.LastModified, 0))
+}
+
+type pipelineTaskResolver struct {
+	t *pipeline.PipelineTask
+}
+
+func (r *pipelineTaskResolver) ID() graphql.ID             { return graphql.ID(strconv.FormatInt(r.t.ID, 10)) }
+func (r *pipelineTaskResolver) Status() string             { return strings.ToUpper(string(r.t.Status)) }
+func (r *pipelineTaskResolver) LiveID() graphql.ID         { return graphql.ID(r.t.RecordInfo.LiveID) }
+func (r *pipelineTaskResolver) Platform() string           { return r.t.RecordInfo.Platform }
+func (r *pipelineTaskResolver) HostName() string           { return r.t.RecordInfo.HostName }
+func (r *pipelineTaskResolver) RoomName() string           { return r.t.RecordInfo.RoomName }
+func (r *pipelineTaskResolver) Progress() int32            { return int32(r.t.Progress) }
+func (r *pipelineTaskResolver) CurrentStage() int32        { return int32(r.t.CurrentStage) }
+func (r *pipelineTaskResolver) TotalStages() int32         { return int32(r.t.TotalStages) }
+func (r *pipelineTaskResolver) CreatedAt() graphql.Time    { return graphQLTime(r.t.CreatedAt) }
+func (r *pipelineTaskResolver) StartedAt() *graphql.Time   { return graphQLTimePtr(r.t.StartedAt) }
+func (r *pipelineTaskResolver) CompletedAt() *graphql.Time { return graphQLTimePtr(r.t.CompletedAt) }
+func (r *pipelineTaskResolver) ErrorMessage() *string      { return optionalString(r.t.ErrorMessage) }
+func (r *pipelineTaskResolver) CanRetry() bool             { return r.t.CanRetry }
+
+func (r *pipelineTaskResolver) Files() []string {
+	files := make([]string, 0, len(r.t.CurrentFiles))
+	for _, f := range r.t.CurrentFiles {
+		files = append(files, f.Path)
+	}
+	return files
+}
+
+func (r *pipelineTaskResolver) Stages() []*stageResultResolver {
+	stages := make([]*stageResultResolver, 0, len(r.t.StageResults))
+	for i := range r.t.StageResults {
+		stages = append(stages, &stageResultResolver{&r.t.StageResults[i]})
+	}
+	return stages
+}
+
+type stageResultResolver struct {
+	s *pipeline.StageResult
+}
+
+func (r *stageResultResolver) Name() string               { return r.s.StageName }
+func (r *stageResultResolver) Status() string             { return string(r.s.Status) }
+func (r *stageResultResolver) StartedAt() graphql.Time    { return graphQLTime(r.s.StartedAt) }
+func (r *stageResultResolver) CompletedAt() *graphql.Time { return graphQLTimePtr(r.s.CompletedAt) }
+func (r *stageResultResolver) ErrorMessage() *string      { return optionalString(r.s.ErrorMessage) }
+
+type pipelineStatsResolver struct {
+	s *pipeline.ManagerStats
+}
+
+func (r *pipelineStatsResolver) MaxConcurrent() int32 { return int32(r.s.MaxConcurrent) }
+func (r *pipelineStatsResolver) Running() int32       { return int32(r.s.RunningCount) }
+func (r *pipelineStatsResolver) Pending() int32       { return int32(r.s.PendingCount) }
+func (r *pipelineStatsResolver) Completed() int32     { return int32(r.s.CompletedCount) }
+func (r *pipelineStatsResolver) Failed() int32        { return int32(r.s.FailedCount) }
+func (r *pipelineStatsResolver) Cancelled() int32     { return int32(r.s.CancelledCount) }
+
+type eventResolver struct {
+	msg eventbus.Message
+}
+
+func (r *eventResolver) Seq() graphql.ID          { return graphql.ID(strconv.FormatUint(r.msg.Seq, 10)) }
+func (r *eventResolver) Type() string             { return strings.ToUpper(string(r.msg.Type)) }
+func (r *eventResolver) Time() graphql.Time       { return graphQLTime(r.msg.Time) }
+func (r *eventResolver) Data() *eventDataResolver { return &eventDataResolver{r.msg.Data} }
+
+// eventDataResolver 对应 EventData 联合类型
+type eventDataResolver struct {
+	data any
+}
+
+func (r *eventDataResolver) ToLiveEvent() (*liveEventResolver, bool) {
+	p, ok := r.data.(eventbus.LivePayload)
+	return &liveEventResolver{p}, ok
+}
+
+func (r *eventDataResolver) ToPipelineTaskEvent() (*pipelineTaskEventResolver, bool) {
+	p, ok := r.data.(eventbus.PipelineTaskPayload)
+	return &pipelineTaskEventResolver{p}, ok
+}
+
+func (r *eventDataResolver) ToDiskAlertEvent() (*diskAlertEventResolver, bool) {
+	p, ok := r.data.(eventbus.DiskAlertPayload)
+	return &diskAlertEventResolver{p}, ok
+}
+
+type liveEventResolver struct {
+	p eventbus.LivePayload
+}
+
+func (r *liveEventResolver) LiveID() graphql.ID { return graphql.ID(r.p.LiveID) }
+func (r *liveEventResolver) URL() string        { return r.p.URL }
+func (r *liveEventResolver) Platform() string   { return r.p.Platform }
+func (r *liveEventResolver) HostName() string   { return r.p.HostName }
+func (r *liveEventResolver) RoomName() string   { return r.p.RoomName }
+func (r *liveEventResolver) Living() bool       { return r.p.Living }
+
+type pipelineTaskEventResolver struct {
+	p eventbus.PipelineTaskPayload
+}
+
+func (r *pipelineTaskEventResolver) TaskID() graphql.ID {
+	return graphql.ID(strconv.FormatInt(r.p.TaskID, 10))
+}
+func (r *pipelineTaskEventResolver) LiveID() graphql.ID    { return graphql.ID(r.p.LiveID) }
+func (r *pipelineTaskEventResolver) Status() string        { return strings.ToUpper(r.p.Status) }
+func (r *pipelineTaskEventResolver) Progress() int32       { return int32(r.p.Progress) }
+func (r *pipelineTaskEventResolver) CurrentStage() int32   { return int32(r.p.CurrentStage) }
+func (r *pipelineTaskEventResolver) TotalStages() int32    { return int32(r.p.TotalStages) }
+func (r *pipelineTaskEventResolver) ErrorMessage() *string { return optionalString(r.p.ErrorMessage) }
+
+type diskAlertEventResolver struct {
+	p eventbus.DiskAlertPayload
+}
+
+func (r *diskAlertEventResolver) Path() string            { return r.p.Path }
+func (r *diskAlertEventResolver) Low() bool               { return r.p.Low }
+func (r *diskAlertEventResolver) FreeBytes() float64      { return float64(r.p.FreeBytes) }
+func (r *diskAlertEventResolver) ThresholdBytes() float64 { return float64(r.p.ThresholdBytes) }
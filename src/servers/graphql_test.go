@@ -0,0 +1,74 @@
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/eventbus"
+)
+
+func TestGraphQLSchemaMatchesResolvers(t *testing.T) {
+	// schema.graphql 与解析器不一致时 MustParseSchema 会 panic
+	assert.NotPanics(t, func() { getGraphQLSchema() })
+}
+
+func TestGraphQLConfigQueryAndMutation(t *testing.T) {
+	cfg := configs.NewConfig()
+	cfg.Interval = 30
+	configs.SetCurrentConfig(cfg)
+	defer configs.SetCurrentConfig(nil)
+	schema := getGraphQLSchema()
+
+	resp := schema.Exec(context.Background(), `{ config }`, "", nil)
+	assert.Empty(t, resp.Errors)
+	var data struct {
+		Config struct {
+			Interval int `json:"interval"`
+		} `json:"config"`
+	}
+	assert.NoError(t, json.Unmarshal(resp.Data, &data))
+	assert.Equal(t, 30, data.Config.Interval)
+
+	resp = schema.Exec(context.Background(), `mutation($p: JSON!) { updateConfig(patch: $p) }`, "",
+		map[string]any{"p": "not an object"})
+	if assert.Len(t, resp.Errors, 1) {
+		assert.Contains(t, resp.Errors[0].Message, "patch 必须是 JSON 对象")
+	}
+}
+
+func TestGraphQLEventsSubscription(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results, err := getGraphQLSchema().Subscribe(ctx, `subscription {
+		events(types: [DISK_ALERT]) {
+			type
+			data { ... on DiskAlertEvent { path low } }
+		}
+	}`, "", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// 等待订阅建立后再发布
+	deadline := time.Now().Add(time.Second)
+	for eventbus.GetBus().SubscriberCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	eventbus.GetBus().Publish(eventbus.TypeLiveStart, eventbus.LivePayload{LiveID: "ignored"})
+	eventbus.GetBus().Publish(eventbus.TypeDiskAlert, eventbus.DiskAlertPayload{Path: "/data", Low: true})
+
+	select {
+	case result := <-results:
+		resp := result.(*graphql.Response)
+		assert.Empty(t, resp.Errors)
+		assert.JSONEq(t, `{"events":{"type":"DISK_ALERT","data":{"path":"/data","low":true}}}`, string(resp.Data))
+	case <-time.After(2 * time.Second):
+		t.Fatal("未收到订阅消息")
+	}
+}
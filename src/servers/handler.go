@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"mime"
 	"net/http"
 	"net/url"
 	"os"
@@ -33,9 +34,15 @@ import (
 	soop "github.com/bililive-go/bililive-go/src/live/sooplive"
 	"github.com/bililive-go/bililive-go/src/livestate"
 	applog "github.com/bililive-go/bililive-go/src/log"
+	"github.com/bililive-go/bililive-go/src/pkg/cookiekeeper"
+	"github.com/bililive-go/bililive-go/src/pkg/cookiepool"
+	"github.com/bililive-go/bililive-go/src/pkg/flvproxy"
+	"github.com/bililive-go/bililive-go/src/pkg/hlsproxy"
 	"github.com/bililive-go/bililive-go/src/pkg/livelogger"
 	"github.com/bililive-go/bililive-go/src/pkg/memstats"
 	"github.com/bililive-go/bililive-go/src/pkg/ratelimit"
+	"github.com/bililive-go/bililive-go/src/pkg/recordmeta"
+	"github.com/bililive-go/bililive-go/src/pkg/secrets"
 	"github.com/bililive-go/bililive-go/src/pkg/utils"
 	"github.com/bililive-go/bililive-go/src/recorders"
 	"github.com/bililive-go/bililive-go/src/tools"
@@ -227,6 +234,10 @@ func getLive(writer http.ResponseWriter, r *http.Request) {
 		liveStartTime = lastStartTime.Format("2006-01-02 15:04:05")
 	}
 
+	// 原始配置中的转推地址隐去推流码
+	roomConfig := *room
+	roomConfig.RestreamTargets = configs.MaskRestreamTargets(room.RestreamTargets)
+
 	// 构造详细响应
 	detailedInfo := map[string]interface{}{
 		// 基本信息
@@ -279,7 +290,7 @@ func getLive(writer http.ResponseWriter, r *http.Request) {
 		"last_record_time": recordStartTime, // 本次录制开始时间
 
 		// 原始配置信息
-		"room_config": room,
+		"room_config": roomConfig,
 	}
 
 	// 添加可用流信息（优先使用内存缓存，否则从数据库读取）
@@ -433,38 +444,13 @@ func parseLiveAction(writer http.ResponseWriter, r *http.Request) {
 		return
 	}
 	switch vars["action"] {
-	case "start":
-		if err := startListening(inst.Ctx, live); err != nil {
-			resp.ErrNo = http.StatusBadRequest
-			resp.ErrMsg = err.Error()
-			writeJsonWithStatusCode(writer, http.StatusBadRequest, resp)
-			return
-		}
-		if _, err := configs.SetLiveRoomListening(live.GetRawUrl(), true); err != nil {
-			live.GetLogger().Error("failed to set live room listening: " + err.Error())
-		}
-		// 广播监控开启事件
-		GetSSEHub().BroadcastListChange(live.GetLiveId(), "listen_start", map[string]interface{}{
-			"live_id": string(live.GetLiveId()),
-		})
-	case "stop":
-		if err := stopListening(inst.Ctx, live.GetLiveId()); err != nil {
+	case "start", "stop":
+		if err := setLiveListening(inst.Ctx, live, vars["action"] == "start"); err != nil {
 			resp.ErrNo = http.StatusBadRequest
 			resp.ErrMsg = err.Error()
 			writeJsonWithStatusCode(writer, http.StatusBadRequest, resp)
 			return
 		}
-		if _, err := configs.SetLiveRoomListening(live.GetRawUrl(), false); err != nil {
-			live.GetLogger().Error("failed to set live room listening: " + err.Error())
-		}
-		// 记录用户停止监控（结束当前会话）
-		if manager, ok := inst.LiveStateManager.(*livestate.Manager); ok && manager != nil {
-			manager.OnUserStopMonitoring(string(live.GetLiveId()))
-		}
-		// 广播监控停止事件
-		GetSSEHub().BroadcastListChange(live.GetLiveId(), "listen_stop", map[string]interface{}{
-			"live_id": string(live.GetLiveId()),
-		})
 	case "forceRefresh":
 		// 强制刷新：忽略平台访问频率限制，立即获取最新信息
 		platformKey := configs.GetPlatformKeyFromUrl(live.GetRawUrl())
@@ -649,6 +635,35 @@ func switchStreamHandler(writer http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// setLiveListening 开始/停止监控直播间，同步写入配置并广播列表变更
+func setLiveListening(ctx context.Context, live live.Live, listen bool) error {
+	inst := instance.GetInstance(ctx)
+	// 集群模式下分配给其他节点的直播间只更新监控状态，由对应节点录制
+	if listen {
+		if err := startListening(ctx, live); err != nil && !errors.Is(err, listeners.ErrLiveNotOwned) {
+			return err
+		}
+	} else if err := stopListening(ctx, live.GetLiveId()); err != nil && listeners.IsOwnedLocally(live) {
+		return err
+	}
+	if _, err := configs.SetLiveRoomListening(live.GetRawUrl(), listen); err != nil {
+		live.GetLogger().Error("failed to set live room listening: " + err.Error())
+	}
+	action := "listen_start"
+	if !listen {
+		action = "listen_stop"
+		// 记录用户停止监控（结束当前会话）
+		if manager, ok := inst.LiveStateManager.(*livestate.Manager); ok && manager != nil {
+			manager.OnUserStopMonitoring(string(live.GetLiveId()))
+		}
+	}
+	// 广播监控开启/停止事件
+	GetSSEHub().BroadcastListChange(live.GetLiveId(), action, map[string]interface{}{
+		"live_id": string(live.GetLiveId()),
+	})
+	return nil
+}
+
 func startListening(ctx context.Context, live live.Live) error {
 	inst := instance.GetInstance(ctx)
 	return inst.ListenerManager.(listeners.Manager).AddListener(ctx, live)
@@ -926,6 +941,7 @@ func removeLiveImpl(ctx context.Context, live live.Live) error {
 		}
 	}
 	inst.Lives.Delete(liveId)
+	cookiepool.Default().Release(liveId)
 	if _, err := configs.RemoveLiveRoomByUrl(live.GetRawUrl()); err != nil {
 		return err
 	}
@@ -937,7 +953,7 @@ func removeLiveImpl(ctx context.Context, live live.Live) error {
 }
 
 func getConfig(writer http.ResponseWriter, r *http.Request) {
-	writeJSON(writer, configs.GetCurrentConfig())
+	writeJSON(writer, configs.GetCurrentConfig().MaskSecrets())
 }
 
 func putConfig(writer http.ResponseWriter, r *http.Request) {
@@ -956,7 +972,7 @@ func putConfig(writer http.ResponseWriter, r *http.Request) {
 }
 
 func getRawConfig(writer http.ResponseWriter, r *http.Request) {
-	b, err := yaml.Marshal(configs.GetCurrentConfig())
+	b, err := yaml.Marshal(configs.GetCurrentConfig().MaskSecrets())
 	if err != nil {
 		writeJsonWithStatusCode(writer, http.StatusInternalServerError, commonResp{
 			ErrNo:  http.StatusBadRequest,
@@ -964,9 +980,7 @@ func getRawConfig(writer http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	writeJSON(writer, map[string]string{
-		"config": string(b),
-	})
+	writeJSON(writer, rawConfig{Config: string(b)})
 }
 
 func putRawConfig(writer http.ResponseWriter, r *http.Request) {
@@ -980,9 +994,9 @@ func putRawConfig(writer http.ResponseWriter, r *http.Request) {
 		return
 	}
 	ctx := inst.Ctx
-	var jsonBody map[string]any
-	json.Unmarshal(b, &jsonBody)
-	newConfig, err := configs.NewConfigWithBytes([]byte(jsonBody["config"].(string)))
+	var body rawConfig
+	json.Unmarshal(b, &body)
+	newConfig, err := configs.NewConfigWithBytes([]byte(body.Config))
 	if err != nil {
 		writeJsonWithStatusCode(writer, http.StatusInternalServerError, commonResp{
 			ErrNo:  http.StatusInternalServerError,
@@ -994,6 +1008,8 @@ func putRawConfig(writer http.ResponseWriter, r *http.Request) {
 	oldConfig.RefreshLiveRoomIndexCache()
 	// 继承原配置的文件路径
 	newConfig.File = oldConfig.File
+	// 接口返回的配置中敏感字段已被遮盖，未修改的字段沿用原值
+	newConfig.RestoreMaskedSecrets(oldConfig)
 	// 预先将旧配置中的 LiveId 迁移到新配置（相同 URL）
 	oldMap := make(map[string]configs.LiveRoom, len(oldConfig.LiveRooms))
 	for _, room := range oldConfig.LiveRooms {
@@ -1158,7 +1174,7 @@ func getEffectiveConfig(writer http.ResponseWriter, r *http.Request) {
 
 	// 构建响应
 	response := &EffectiveConfigResponse{
-		Config:                   cfg,
+		Config:                   cfg.MaskSecrets(),
 		ActualOutPutPath:         actualOutPutPath,
 		ActualFfmpegPath:         actualFfmpegPath,
 		ActualLogFolder:          actualLogFolder,
@@ -1376,10 +1392,7 @@ func previewOutputTmpl(writer http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req struct {
-		Template   string `json:"template"`
-		OutPutPath string `json:"out_put_path"`
-	}
+	var req previewOutputTmplRequest
 	if err := json.Unmarshal(b, &req); err != nil {
 		writeJsonWithStatusCode(writer, http.StatusBadRequest, commonResp{
 			ErrNo:  http.StatusBadRequest,
@@ -1500,177 +1513,215 @@ func updateConfig(writer http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err = configs.UpdateWithRetry(func(c *configs.Config) error {
-		// 应用更新到配置
-		if err := applyConfigUpdates(c, updates); err != nil {
-			return err
-		}
-		// 校验配置
-		return c.Verify()
-	}, 3, 10*time.Millisecond)
-
+	// strict=true 时包含未识别字段的请求整体拒绝，不做任何修改
+	strict := r.URL.Query().Get("strict") == "true"
+	result, err := patchConfig(updates, strict)
 	if err != nil {
-		writeJsonWithStatusCode(writer, http.StatusInternalServerError, commonResp{
-			ErrNo:  http.StatusInternalServerError,
+		status := http.StatusInternalServerError
+		if errors.Is(err, errUnknownConfigKeys) {
+			status = http.StatusBadRequest
+		}
+		writeJsonWithStatusCode(writer, status, commonResp{
+			ErrNo:  status,
 			ErrMsg: "更新配置失败: " + err.Error(),
 		})
 		return
 	}
 
 	writeJSON(writer, commonResp{
-		Data: "OK",
+		Data: result,
 	})
 }
 
+// patchConfig 部分更新配置并校验，updates 的结构与 PATCH /api/config 的请求体相同。
+// 返回实际应用与被忽略的键；strict 为 true 时存在被忽略的键则不保存并返回 errUnknownConfigKeys
+func patchConfig(updates map[string]interface{}, strict bool) (configPatchResult, error) {
+	var result configPatchResult
+	_, err := configs.UpdateWithRetry(func(c *configs.Config) error {
+		old := configs.CloneConfigShallow(c)
+		// 应用更新到配置
+		patch := newConfigPatch()
+		if err := applyConfigUpdates(c, updates, patch); err != nil {
+			return err
+		}
+		result = patch.result(updates)
+		if strict && len(result.Ignored) > 0 {
+			return result.unknownKeysError()
+		}
+		// 提交的敏感字段仍为遮盖占位符时保持原值
+		c.RestoreMaskedSecrets(old)
+		// 校验配置
+		return c.Verify()
+	}, 3, 10*time.Millisecond)
+	return result, err
+}
+
 // applyConfigUpdates 将更新应用到配置
-func applyConfigUpdates(c *configs.Config, updates map[string]interface{}) error {
+func applyConfigUpdates(c *configs.Config, updates map[string]interface{}, patch *configPatch) error {
 	// 处理 RPC 配置
-	if rpc, ok := updates["rpc"].(map[string]interface{}); ok {
-		if enable, ok := rpc["enable"].(bool); ok {
+	if rpc, ok := patch.obj(updates, "rpc"); ok {
+		if enable, ok := patch.flag(rpc, "enable"); ok {
 			c.RPC.Enable = enable
 		}
-		if bind, ok := rpc["bind"].(string); ok {
+		if bind, ok := patch.str(rpc, "bind"); ok {
 			c.RPC.Bind = bind
 		}
+		if token, ok := patch.str(rpc, "token"); ok {
+			if err := c.AssignSecret(&c.RPC.Token, token); err != nil {
+				return err
+			}
+		}
 	}
 
 	// 处理基本配置
-	if debug, ok := updates["debug"].(bool); ok {
+	if debug, ok := patch.flag(updates, "debug"); ok {
 		c.Debug = debug
 	}
-	if interval, ok := updates["interval"].(float64); ok {
+	if interval, ok := patch.num(updates, "interval"); ok {
 		c.Interval = int(interval)
 	}
-	if outPutPath, ok := updates["out_put_path"].(string); ok {
+	if outPutPath, ok := patch.str(updates, "out_put_path"); ok {
 		c.OutPutPath = outPutPath
 	}
-	if ffmpegPath, ok := updates["ffmpeg_path"].(string); ok {
+	if ffmpegPath, ok := patch.str(updates, "ffmpeg_path"); ok {
 		c.FfmpegPath = ffmpegPath
 	}
-	if outputTmpl, ok := updates["out_put_tmpl"].(string); ok {
+	if outputTmpl, ok := patch.str(updates, "out_put_tmpl"); ok {
 		c.OutputTmpl = outputTmpl
 	}
-	if timeoutSec, ok := updates["timeout_in_seconds"].(float64); ok {
+	if timeoutSec, ok := patch.num(updates, "timeout_in_seconds"); ok {
 		c.TimeoutInUs = int(timeoutSec * 1000000)
 	}
-	if appDataPath, ok := updates["app_data_path"].(string); ok {
+	if appDataPath, ok := patch.str(updates, "app_data_path"); ok {
 		c.AppDataPath = appDataPath
 	}
-	if readOnlyToolFolder, ok := updates["read_only_tool_folder"].(string); ok {
+	if readOnlyToolFolder, ok := patch.str(updates, "read_only_tool_folder"); ok {
 		c.ReadOnlyToolFolder = readOnlyToolFolder
 	}
-	if toolRootFolder, ok := updates["tool_root_folder"].(string); ok {
+	if toolRootFolder, ok := patch.str(updates, "tool_root_folder"); ok {
 		c.ToolRootFolder = toolRootFolder
 	}
-	if danmakuEnable, ok := updates["danmaku_enable"].(bool); ok {
+	if danmakuEnable, ok := patch.flag(updates, "danmaku_enable"); ok {
 		c.DanmakuEnable = danmakuEnable
 	}
-	if danmaku, ok := updates["danmaku"].(map[string]interface{}); ok {
-		if fontSize, ok := danmaku["font_size"].(float64); ok {
+	if danmaku, ok := patch.obj(updates, "danmaku"); ok {
+		if fontSize, ok := patch.num(danmaku, "font_size"); ok {
 			c.Danmaku.FontSize = int(fontSize)
 		}
-		if fontName, ok := danmaku["font_name"].(string); ok {
+		if fontName, ok := patch.str(danmaku, "font_name"); ok {
 			c.Danmaku.FontName = fontName
 		}
-		if displayMode, ok := danmaku["scroll_area"].(string); ok {
+		if displayMode, ok := patch.str(danmaku, "scroll_area"); ok {
 			c.Danmaku.ScrollArea = displayMode
 		}
-		if scrollTime, ok := danmaku["scroll_time"].(float64); ok {
+		if scrollTime, ok := patch.num(danmaku, "scroll_time"); ok {
 			c.Danmaku.ScrollTime = int(scrollTime)
 		}
-		if resolution, ok := danmaku["resolution"].(string); ok {
+		if resolution, ok := patch.str(danmaku, "resolution"); ok {
 			c.Danmaku.Resolution = resolution
 		}
-		if outline, ok := danmaku["outline"].(float64); ok {
+		if outline, ok := patch.num(danmaku, "outline"); ok {
 			c.Danmaku.Outline = configs.IntPtr(int(outline))
 		}
-		if opacity, ok := danmaku["opacity"].(float64); ok {
+		if opacity, ok := patch.num(danmaku, "opacity"); ok {
 			c.Danmaku.Opacity = configs.IntPtr(int(opacity))
 		}
-		if recordGift, ok := danmaku["record_gift"].(bool); ok {
+		if streamDelay, ok := patch.num(danmaku, "stream_delay_ms"); ok {
+			c.Danmaku.StreamDelayMs = configs.IntPtr(int(streamDelay))
+		}
+		applyDanmakuFilterUpdates(&c.Danmaku, danmaku, patch)
+		if recordGift, ok := patch.flag(danmaku, "record_gift"); ok {
 			c.Danmaku.RecordGift = configs.BoolPtr(recordGift)
-		} else if _, exists := danmaku["record_gift"]; exists && danmaku["record_gift"] == nil {
+		} else if patch.null(danmaku, "record_gift") {
 			c.Danmaku.RecordGift = nil
 		}
-		if recordDouyuGift, ok := danmaku["record_douyu_gift"].(bool); ok {
+		if recordDouyuGift, ok := patch.flag(danmaku, "record_douyu_gift"); ok {
 			c.Danmaku.RecordDouyuGift = configs.BoolPtr(recordDouyuGift)
-		} else if _, exists := danmaku["record_douyu_gift"]; exists && danmaku["record_douyu_gift"] == nil {
+		} else if patch.null(danmaku, "record_douyu_gift") {
 			c.Danmaku.RecordDouyuGift = nil
 		}
-		if recordDouyinGift, ok := danmaku["record_douyin_gift"].(bool); ok {
+		if recordDouyinGift, ok := patch.flag(danmaku, "record_douyin_gift"); ok {
 			c.Danmaku.RecordDouyinGift = configs.BoolPtr(recordDouyinGift)
-		} else if _, exists := danmaku["record_douyin_gift"]; exists && danmaku["record_douyin_gift"] == nil {
+		} else if patch.null(danmaku, "record_douyin_gift") {
 			c.Danmaku.RecordDouyinGift = nil
 		}
-		if recordGuard, ok := danmaku["record_guard"].(bool); ok {
+		if recordGuard, ok := patch.flag(danmaku, "record_guard"); ok {
 			c.Danmaku.RecordGuard = configs.BoolPtr(recordGuard)
-		} else if _, exists := danmaku["record_guard"]; exists && danmaku["record_guard"] == nil {
+		} else if patch.null(danmaku, "record_guard") {
 			c.Danmaku.RecordGuard = nil
 		}
-		if recordSuperChat, ok := danmaku["record_super_chat"].(bool); ok {
+		if recordSuperChat, ok := patch.flag(danmaku, "record_super_chat"); ok {
 			c.Danmaku.RecordSuperChat = configs.BoolPtr(recordSuperChat)
-		} else if _, exists := danmaku["record_super_chat"]; exists && danmaku["record_super_chat"] == nil {
+		} else if patch.null(danmaku, "record_super_chat") {
 			c.Danmaku.RecordSuperChat = nil
 		}
-		if guardPosition, ok := danmaku["guard_position"].(string); ok {
+		if guardPosition, ok := patch.str(danmaku, "guard_position"); ok {
 			c.Danmaku.GuardPosition = guardPosition
 		}
-		if scPosition, ok := danmaku["sc_position"].(string); ok {
+		if scPosition, ok := patch.str(danmaku, "sc_position"); ok {
 			c.Danmaku.ScPosition = scPosition
 		}
 		if err := c.Danmaku.Validate(); err != nil {
 			return fmt.Errorf("弹幕参数无效: %w", err)
 		}
 	}
-	if soopAuth, ok := updates["sooplive_auth"].(map[string]interface{}); ok {
-		if username, ok := soopAuth["username"].(string); ok {
+	if soopAuth, ok := patch.obj(updates, "sooplive_auth"); ok {
+		if username, ok := patch.str(soopAuth, "username"); ok {
 			c.SoopLiveAuth.Username = username
 		}
-		if password, ok := soopAuth["password"].(string); ok {
-			c.SoopLiveAuth.Password = password
+		if password, ok := patch.str(soopAuth, "password"); ok {
+			if err := c.AssignSecret(&c.SoopLiveAuth.Password, password); err != nil {
+				return err
+			}
 		}
 	}
 
 	// 处理日志配置
-	if log, ok := updates["log"].(map[string]interface{}); ok {
-		if outPutFolder, ok := log["out_put_folder"].(string); ok {
+	if log, ok := patch.obj(updates, "log"); ok {
+		if outPutFolder, ok := patch.str(log, "out_put_folder"); ok {
 			c.Log.OutPutFolder = outPutFolder
 		}
-		if saveLastLog, ok := log["save_last_log"].(bool); ok {
+		if saveLastLog, ok := patch.flag(log, "save_last_log"); ok {
 			c.Log.SaveLastLog = saveLastLog
 		}
-		if saveEveryLog, ok := log["save_every_log"].(bool); ok {
+		if saveEveryLog, ok := patch.flag(log, "save_every_log"); ok {
 			c.Log.SaveEveryLog = saveEveryLog
 		}
-		if rotateDays, ok := log["rotate_days"].(float64); ok {
+		if rotateDays, ok := patch.num(log, "rotate_days"); ok {
 			c.Log.RotateDays = int(rotateDays)
 		}
 	}
 
 	// 处理功能特性配置
-	if feature, ok := updates["feature"].(map[string]interface{}); ok {
-		if downloaderType, ok := feature["downloader_type"].(string); ok {
+	if feature, ok := patch.obj(updates, "feature"); ok {
+		if downloaderType, ok := patch.str(feature, "downloader_type"); ok {
 			c.Feature.DownloaderType = configs.ParseDownloaderType(downloaderType)
 		}
-		if useNativeFlvParser, ok := feature["use_native_flv_parser"].(bool); ok {
+		if useNativeFlvParser, ok := patch.flag(feature, "use_native_flv_parser"); ok {
 			c.Feature.UseNativeFlvParser = useNativeFlvParser
 		}
-		if removeSymbolOther, ok := feature["remove_symbol_other_character"].(bool); ok {
+		if removeSymbolOther, ok := patch.flag(feature, "remove_symbol_other_character"); ok {
 			c.Feature.RemoveSymbolOtherCharacter = removeSymbolOther
 		}
+		if hlsAdHandling, ok := patch.str(feature, "hls_ad_handling"); ok {
+			c.Feature.HlsAdHandling = hlsAdHandling
+		}
+		if enableLiveWatcher, ok := patch.flag(feature, "enable_live_watcher"); ok {
+			c.Feature.EnableLiveWatcher = enableLiveWatcher
+		}
 	}
 
 	// 处理视频分割策略
-	if vss, ok := updates["video_split_strategies"].(map[string]interface{}); ok {
-		if onRoomNameChanged, ok := vss["on_room_name_changed"].(bool); ok {
+	if vss, ok := patch.obj(updates, "video_split_strategies"); ok {
+		if onRoomNameChanged, ok := patch.flag(vss, "on_room_name_changed"); ok {
 			c.VideoSplitStrategies.OnRoomNameChanged = onRoomNameChanged
 		}
-		if maxDuration, ok := vss["max_duration"].(float64); ok {
+		if maxDuration, ok := patch.num(vss, "max_duration"); ok {
 			c.VideoSplitStrategies.MaxDuration = time.Duration(maxDuration)
 		}
-		if maxFileSize, ok := vss["max_file_size"].(float64); ok {
+		if maxFileSize, ok := patch.num(vss, "max_file_size"); ok {
 			c.VideoSplitStrategies.MaxFileSize = configs.ByteSize(int64(maxFileSize))
-		} else if maxFileSizeStr, ok := vss["max_file_size"].(string); ok {
+		} else if maxFileSizeStr, ok := patch.str(vss, "max_file_size"); ok {
 			if parsed, err := configs.ParseByteSize(maxFileSizeStr); err == nil {
 				c.VideoSplitStrategies.MaxFileSize = parsed
 			}
@@ -1678,109 +1729,129 @@ func applyConfigUpdates(c *configs.Config, updates map[string]interface{}) error
 	}
 
 	// 处理录制完成后动作
-	if orf, ok := updates["on_record_finished"].(map[string]interface{}); ok {
-		if convertToMp4, ok := orf["convert_to_mp4"].(bool); ok {
+	if orf, ok := patch.obj(updates, "on_record_finished"); ok {
+		if convertToMp4, ok := patch.flag(orf, "convert_to_mp4"); ok {
 			c.OnRecordFinished.ConvertToMp4 = convertToMp4
 		}
-		if deleteFlv, ok := orf["delete_flv_after_convert"].(bool); ok {
+		if deleteFlv, ok := patch.flag(orf, "delete_flv_after_convert"); ok {
 			c.OnRecordFinished.DeleteFlvAfterConvert = deleteFlv
 		}
-		if customCmd, ok := orf["custom_commandline"].(string); ok {
+		if customCmd, ok := patch.str(orf, "custom_commandline"); ok {
 			c.OnRecordFinished.CustomCommandline = customCmd
 		}
-		if fixFlv, ok := orf["fix_flv_at_first"].(bool); ok {
+		if fixFlv, ok := patch.flag(orf, "fix_flv_at_first"); ok {
 			c.OnRecordFinished.FixFlvAtFirst = fixFlv
 		}
-		if burnSubtitles, ok := orf["burn_subtitles"].(bool); ok {
+		if burnSubtitles, ok := patch.flag(orf, "burn_subtitles"); ok {
 			c.OnRecordFinished.BurnSubtitles = burnSubtitles
 		}
-		if codec, ok := orf["burn_subtitles_codec"].(string); ok {
+		if codec, ok := patch.str(orf, "burn_subtitles_codec"); ok {
 			c.OnRecordFinished.BurnSubtitlesCodec = codec
 		}
-		if crf, ok := orf["burn_subtitles_crf"].(string); ok {
+		if crf, ok := patch.str(orf, "burn_subtitles_crf"); ok {
 			c.OnRecordFinished.BurnSubtitlesCrf = crf
 		}
-		if preset, ok := orf["burn_subtitles_preset"].(string); ok {
+		if preset, ok := patch.str(orf, "burn_subtitles_preset"); ok {
 			c.OnRecordFinished.BurnSubtitlesPreset = preset
 		}
-		if deleteAss, ok := orf["burn_delete_ass"].(bool); ok {
+		if deleteAss, ok := patch.flag(orf, "burn_delete_ass"); ok {
 			c.OnRecordFinished.BurnDeleteAss = deleteAss
 		}
-		if deleteSource, ok := orf["burn_delete_source"].(bool); ok {
+		if deleteSource, ok := patch.flag(orf, "burn_delete_source"); ok {
 			c.OnRecordFinished.BurnDeleteSource = deleteSource
 		}
+		if extractHighlights, ok := patch.flag(orf, "extract_highlights"); ok {
+			c.OnRecordFinished.ExtractHighlights = extractHighlights
+		}
+		if highlightCount, ok := patch.num(orf, "highlight_count"); ok && highlightCount >= 1 {
+			c.OnRecordFinished.HighlightCount = int(highlightCount)
+		}
+		if generateStoryboard, ok := patch.flag(orf, "generate_storyboard"); ok {
+			c.OnRecordFinished.GenerateStoryboard = generateStoryboard
+		}
+		if storyboardInterval, ok := patch.num(orf, "storyboard_interval"); ok && storyboardInterval >= 1 {
+			c.OnRecordFinished.StoryboardInterval = int(storyboardInterval)
+		}
 	}
 
 	// 处理通知配置
-	if notify, ok := updates["notify"].(map[string]interface{}); ok {
-		if sendRecordingSummary, ok := notify["send_recording_summary"].(bool); ok {
+	if notify, ok := patch.obj(updates, "notify"); ok {
+		if sendRecordingSummary, ok := patch.flag(notify, "send_recording_summary"); ok {
 			c.Notify.SendRecordingSummary = sendRecordingSummary
 		}
-		if telegram, ok := notify["telegram"].(map[string]interface{}); ok {
-			if enable, ok := telegram["enable"].(bool); ok {
+		if telegram, ok := patch.obj(notify, "telegram"); ok {
+			if enable, ok := patch.flag(telegram, "enable"); ok {
 				c.Notify.Telegram.Enable = enable
 			}
-			if withNotification, ok := telegram["withNotification"].(bool); ok {
+			if withNotification, ok := patch.flag(telegram, "withNotification"); ok {
 				c.Notify.Telegram.WithNotification = withNotification
 			}
-			if botToken, ok := telegram["botToken"].(string); ok {
-				c.Notify.Telegram.BotToken = botToken
+			if botToken, ok := patch.str(telegram, "botToken"); ok {
+				if err := c.AssignSecret(&c.Notify.Telegram.BotToken, botToken); err != nil {
+					return err
+				}
 			}
-			if chatID, ok := telegram["chatID"].(string); ok {
+			if chatID, ok := patch.str(telegram, "chatID"); ok {
 				c.Notify.Telegram.ChatID = chatID
 			}
 		}
-		if email, ok := notify["email"].(map[string]interface{}); ok {
-			if enable, ok := email["enable"].(bool); ok {
+		if email, ok := patch.obj(notify, "email"); ok {
+			if enable, ok := patch.flag(email, "enable"); ok {
 				c.Notify.Email.Enable = enable
 			}
-			if smtpHost, ok := email["smtpHost"].(string); ok {
+			if smtpHost, ok := patch.str(email, "smtpHost"); ok {
 				c.Notify.Email.SMTPHost = smtpHost
 			}
-			if smtpPort, ok := email["smtpPort"].(float64); ok {
+			if smtpPort, ok := patch.num(email, "smtpPort"); ok {
 				c.Notify.Email.SMTPPort = int(smtpPort)
 			}
-			if senderEmail, ok := email["senderEmail"].(string); ok {
+			if senderEmail, ok := patch.str(email, "senderEmail"); ok {
 				c.Notify.Email.SenderEmail = senderEmail
 			}
-			if senderPassword, ok := email["senderPassword"].(string); ok {
-				c.Notify.Email.SenderPassword = senderPassword
+			if senderPassword, ok := patch.str(email, "senderPassword"); ok {
+				if err := c.AssignSecret(&c.Notify.Email.SenderPassword, senderPassword); err != nil {
+					return err
+				}
 			}
-			if recipientEmail, ok := email["recipientEmail"].(string); ok {
+			if recipientEmail, ok := patch.str(email, "recipientEmail"); ok {
 				c.Notify.Email.RecipientEmail = recipientEmail
 			}
 		}
-		if barkCfg, ok := notify["bark"].(map[string]interface{}); ok {
-			if enable, ok := barkCfg["enable"].(bool); ok {
+		if barkCfg, ok := patch.obj(notify, "bark"); ok {
+			if enable, ok := patch.flag(barkCfg, "enable"); ok {
 				c.Notify.Bark.Enable = enable
 			}
-			if serverURL, ok := barkCfg["serverURL"].(string); ok {
+			if serverURL, ok := patch.str(barkCfg, "serverURL"); ok {
 				c.Notify.Bark.ServerURL = serverURL
 			}
-			if deviceKey, ok := barkCfg["deviceKey"].(string); ok {
-				c.Notify.Bark.DeviceKey = deviceKey
+			if deviceKey, ok := patch.str(barkCfg, "deviceKey"); ok {
+				if err := c.AssignSecret(&c.Notify.Bark.DeviceKey, deviceKey); err != nil {
+					return err
+				}
 			}
-			if sound, ok := barkCfg["sound"].(string); ok {
+			if sound, ok := patch.str(barkCfg, "sound"); ok {
 				c.Notify.Bark.Sound = sound
 			}
-			if group, ok := barkCfg["group"].(string); ok {
+			if group, ok := patch.str(barkCfg, "group"); ok {
 				c.Notify.Bark.Group = group
 			}
-			if icon, ok := barkCfg["icon"].(string); ok {
+			if icon, ok := patch.str(barkCfg, "icon"); ok {
 				c.Notify.Bark.Icon = icon
 			}
-			if level, ok := barkCfg["level"].(string); ok {
+			if level, ok := patch.str(barkCfg, "level"); ok {
 				c.Notify.Bark.Level = level
 			}
 		}
-		if wxpusherCfg, ok := notify["wxpusher"].(map[string]interface{}); ok {
-			if enable, ok := wxpusherCfg["enable"].(bool); ok {
+		if wxpusherCfg, ok := patch.obj(notify, "wxpusher"); ok {
+			if enable, ok := patch.flag(wxpusherCfg, "enable"); ok {
 				c.Notify.WxPusher.Enable = enable
 			}
-			if appToken, ok := wxpusherCfg["appToken"].(string); ok {
-				c.Notify.WxPusher.AppToken = appToken
+			if appToken, ok := patch.str(wxpusherCfg, "appToken"); ok {
+				if err := c.AssignSecret(&c.Notify.WxPusher.AppToken, appToken); err != nil {
+					return err
+				}
 			}
-			if uids, ok := wxpusherCfg["uids"].([]interface{}); ok {
+			if uids, ok := patch.list(wxpusherCfg, "uids"); ok {
 				uidList := make([]string, 0, len(uids))
 				for _, uid := range uids {
 					if uidStr, ok := uid.(string); ok && uidStr != "" {
@@ -1793,19 +1864,90 @@ func applyConfigUpdates(c *configs.Config, updates map[string]interface{}) error
 	}
 
 	// 处理代理配置
-	if proxy, ok := updates["proxy"].(map[string]interface{}); ok {
-		if enable, ok := proxy["enable"].(bool); ok {
-			c.Proxy.Enable = enable
+	if proxy, ok := patch.obj(updates, "proxy"); ok {
+		applyProxyUpdates(&c.Proxy, proxy, patch)
+	}
+
+	// 处理自适应检测间隔配置
+	if adaptive, ok := patch.obj(updates, "adaptive_interval"); ok {
+		applyAdaptiveIntervalUpdates(&c.AdaptiveInterval, adaptive, patch)
+	}
+
+	// 处理对外事件流配置
+	if eventStream, ok := patch.obj(updates, "event_stream"); ok {
+		if diskAlertMB, ok := patch.num(eventStream, "disk_alert_mb"); ok {
+			c.EventStream.DiskAlertMB = int(diskAlertMB)
+		}
+		if retentionDays, ok := patch.num(eventStream, "log_retention_days"); ok {
+			c.EventStream.LogRetentionDays = int(retentionDays)
+		}
+		if mqtt, ok := patch.obj(eventStream, "mqtt"); ok {
+			if enable, ok := patch.flag(mqtt, "enable"); ok {
+				c.EventStream.MQTT.Enable = enable
+			}
+			for key, field := range map[string]*string{
+				"broker":       &c.EventStream.MQTT.Broker,
+				"client_id":    &c.EventStream.MQTT.ClientID,
+				"username":     &c.EventStream.MQTT.Username,
+				"topic_prefix": &c.EventStream.MQTT.TopicPrefix,
+			} {
+				if v, ok := patch.str(mqtt, key); ok {
+					*field = v
+				}
+			}
+			if password, ok := patch.str(mqtt, "password"); ok {
+				if err := c.AssignSecret(&c.EventStream.MQTT.Password, password); err != nil {
+					return err
+				}
+			}
 		}
-		if url, ok := proxy["url"].(string); ok {
-			c.Proxy.URL = url
+	}
+
+	// 处理直播预览配置
+	if preview, ok := patch.obj(updates, "preview"); ok {
+		if enable, ok := patch.flag(preview, "enable"); ok {
+			c.Preview.Enable = enable
+		}
+		if maxViewers, ok := patch.num(preview, "max_viewers"); ok {
+			c.Preview.MaxViewers = int(maxViewers)
+		}
+		if err := c.Preview.Validate(); err != nil {
+			return fmt.Errorf("预览配置无效: %w", err)
+		}
+	}
+
+	// 处理集群配置（运行模式等需要重启后生效）
+	if cluster, ok := patch.obj(updates, "cluster"); ok {
+		for key, field := range map[string]*string{
+			"mode":            &c.Cluster.Mode,
+			"node_id":         &c.Cluster.NodeID,
+			"coordinator_url": &c.Cluster.CoordinatorURL,
+			"advertise_url":   &c.Cluster.AdvertiseURL,
+		} {
+			if v, ok := patch.str(cluster, key); ok {
+				*field = v
+			}
+		}
+		if token, ok := patch.str(cluster, "token"); ok {
+			if err := c.AssignSecret(&c.Cluster.Token, token); err != nil {
+				return err
+			}
+		}
+		if interval, ok := patch.num(cluster, "heartbeat_interval_sec"); ok {
+			c.Cluster.HeartbeatIntervalSec = int(interval)
+		}
+		if timeout, ok := patch.num(cluster, "node_timeout_sec"); ok {
+			c.Cluster.NodeTimeoutSec = int(timeout)
+		}
+		if records, ok := patch.flag(cluster, "coordinator_records"); ok {
+			c.Cluster.CoordinatorRecords = records
 		}
 	}
 
 	// 处理全局流偏好配置
-	if streamPref, ok := updates["stream_preference"].(map[string]interface{}); ok {
+	if streamPref, ok := patch.obj(updates, "stream_preference"); ok {
 		// 处理 quality
-		if quality, ok := streamPref["quality"].(string); ok {
+		if quality, ok := patch.str(streamPref, "quality"); ok {
 			if quality == "" {
 				c.StreamPreference.Quality = nil
 			} else {
@@ -1814,7 +1956,7 @@ func applyConfigUpdates(c *configs.Config, updates map[string]interface{}) error
 		}
 
 		// 处理 attributes
-		if attrs, ok := streamPref["attributes"].(map[string]interface{}); ok {
+		if attrs, ok := patch.rawObj(streamPref, "attributes"); ok {
 			if len(attrs) == 0 {
 				c.StreamPreference.Attributes = nil
 			} else {
@@ -1834,21 +1976,34 @@ func applyConfigUpdates(c *configs.Config, updates map[string]interface{}) error
 	}
 
 	// 处理自动更新配置
-	if update, ok := updates["update"].(map[string]interface{}); ok {
-		if autoCheck, ok := update["auto_check"].(bool); ok {
+	if update, ok := patch.obj(updates, "update"); ok {
+		if autoCheck, ok := patch.flag(update, "auto_check"); ok {
 			c.Update.AutoCheck = autoCheck
 		}
-		if checkIntervalHours, ok := update["check_interval_hours"].(float64); ok {
+		if checkIntervalHours, ok := patch.num(update, "check_interval_hours"); ok {
 			c.Update.CheckIntervalHours = int(checkIntervalHours)
 		}
-		if autoDownload, ok := update["auto_download"].(bool); ok {
+		if autoDownload, ok := patch.flag(update, "auto_download"); ok {
 			c.Update.AutoDownload = autoDownload
 		}
-		if includePrerelease, ok := update["include_prerelease"].(bool); ok {
+		if includePrerelease, ok := patch.flag(update, "include_prerelease"); ok {
 			c.Update.IncludePrerelease = includePrerelease
 		}
 	}
 
+	// 处理 Cookie 保活配置
+	if keeper, ok := patch.obj(updates, "cookie_keeper"); ok {
+		if enable, ok := patch.flag(keeper, "enable"); ok {
+			c.CookieKeeper.Enable = enable
+		}
+		if checkIntervalHours, ok := patch.num(keeper, "check_interval_hours"); ok {
+			c.CookieKeeper.CheckIntervalHours = int(checkIntervalHours)
+		}
+		if refreshBeforeDays, ok := patch.num(keeper, "refresh_before_days"); ok {
+			c.CookieKeeper.RefreshBeforeDays = int(refreshBeforeDays)
+		}
+	}
+
 	return nil
 }
 
@@ -2018,6 +2173,9 @@ func updateRoomConfigById(writer http.ResponseWriter, r *http.Request) {
 		if nickName, ok := updates["nick_name"].(string); ok {
 			room.NickName = nickName
 		}
+		if err := applyRestreamTargetUpdates(room, updates); err != nil {
+			return err
+		}
 
 		// 更新可覆盖配置
 		applyOverridableConfigUpdates(&room.OverridableConfig, updates)
@@ -2075,6 +2233,92 @@ func updateRoomConfigById(writer http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// applyDanmakuFilterUpdates 应用弹幕过滤及附加字幕格式字段的更新，全局配置与直播间覆盖配置共用。
+// 列表字段显式传 null 时清除覆盖，恢复继承
+func applyDanmakuFilterUpdates(d *configs.DanmakuConfig, danmaku map[string]interface{}, patch *configPatch) {
+	lists := map[string]*[]string{
+		"block_keywords":   &d.BlockKeywords,
+		"block_patterns":   &d.BlockPatterns,
+		"block_users":      &d.BlockUsers,
+		"subtitle_formats": &d.SubtitleFormats,
+	}
+	for key, target := range lists {
+		if items, ok := patch.list(danmaku, key); ok {
+			list := make([]string, 0, len(items))
+			for _, item := range items {
+				if str, ok := item.(string); ok && str != "" {
+					list = append(list, str)
+				}
+			}
+			*target = list
+		} else if patch.null(danmaku, key) {
+			*target = nil
+		}
+	}
+	ints := map[string]**int{
+		"fold_window":    &d.FoldWindow,
+		"max_per_second": &d.MaxPerSecond,
+		"min_user_level": &d.MinUserLevel,
+	}
+	for key, target := range ints {
+		if v, ok := patch.num(danmaku, key); ok {
+			*target = configs.IntPtr(int(v))
+		}
+	}
+}
+
+// applyProxyUpdates 更新代理配置（通用代理及信息获取/下载专用代理）
+func applyProxyUpdates(p *configs.Proxy, updates map[string]interface{}, patch *configPatch) {
+	if enable, ok := patch.flag(updates, "enable"); ok {
+		p.Enable = enable
+	}
+	if url, ok := patch.str(updates, "url"); ok {
+		p.URL = url
+	}
+	if pool, ok := patch.str(updates, "pool"); ok {
+		p.Pool = pool
+	}
+	for key, entry := range map[string]**configs.ProxyEntry{
+		"info_proxy":     &p.InfoProxy,
+		"download_proxy": &p.DownloadProxy,
+	} {
+		v, exists := patch.lookup(updates, key)
+		if !exists {
+			continue
+		}
+		entryUpdates, ok := v.(map[string]interface{})
+		if !ok {
+			*entry = nil
+			continue
+		}
+		if *entry == nil {
+			*entry = &configs.ProxyEntry{}
+		}
+		if enable, ok := patch.flag(entryUpdates, "enable"); ok {
+			(*entry).Enable = enable
+		}
+		if url, ok := patch.str(entryUpdates, "url"); ok {
+			(*entry).URL = url
+		}
+		if pool, ok := patch.str(entryUpdates, "pool"); ok {
+			(*entry).Pool = pool
+		}
+	}
+}
+
+// applyAdaptiveIntervalUpdates 更新自适应检测间隔配置
+func applyAdaptiveIntervalUpdates(a *configs.AdaptiveInterval, updates map[string]interface{}, patch *configPatch) {
+	if enable, ok := patch.flag(updates, "enable"); ok {
+		a.Enable = enable
+	}
+	if minInterval, ok := patch.num(updates, "min_interval"); ok {
+		a.MinInterval = int(minInterval)
+	}
+	if maxInterval, ok := patch.num(updates, "max_interval"); ok {
+		a.MaxInterval = int(maxInterval)
+	}
+}
+
 // applyOverridableConfigUpdates 统一处理可覆盖配置的更新
 func applyOverridableConfigUpdates(oc *configs.OverridableConfig, updates map[string]interface{}) {
 	if interval, ok := updates["interval"].(float64); ok {
@@ -2121,6 +2365,12 @@ func applyOverridableConfigUpdates(oc *configs.OverridableConfig, updates map[st
 		if enableFlvProxySegment, ok := feature["enable_flv_proxy_segment"].(bool); ok {
 			oc.Feature.EnableFlvProxySegment = enableFlvProxySegment
 		}
+		if hlsAdHandling, ok := feature["hls_ad_handling"].(string); ok {
+			oc.Feature.HlsAdHandling = hlsAdHandling
+		}
+		if enableLiveWatcher, ok := feature["enable_live_watcher"].(bool); ok {
+			oc.Feature.EnableLiveWatcher = enableLiveWatcher
+		}
 	}
 
 	// 也支持直接在顶层设置 downloader_type（简化前端逻辑）
@@ -2171,6 +2421,35 @@ func applyOverridableConfigUpdates(oc *configs.OverridableConfig, updates map[st
 		}
 	}
 
+	// 处理 proxy 配置（整体覆盖上一级，传 null 表示继承上一级）
+	if v, exists := updates["proxy"]; exists {
+		if v == nil {
+			oc.Proxy = nil
+		} else if proxyUpdates, ok := v.(map[string]interface{}); ok {
+			if oc.Proxy == nil {
+				oc.Proxy = &configs.Proxy{}
+			}
+			applyProxyUpdates(oc.Proxy, proxyUpdates, nil)
+		}
+	}
+
+	// 处理 adaptive_interval 配置（整体覆盖上一级，传 null 表示继承上一级）
+	if v, exists := updates["adaptive_interval"]; exists {
+		if v == nil {
+			oc.AdaptiveInterval = nil
+		} else if adaptiveUpdates, ok := v.(map[string]interface{}); ok {
+			if oc.AdaptiveInterval == nil {
+				// 从全局配置初始化，避免未设置的字段被覆盖为零值
+				adaptive := configs.NewConfig().AdaptiveInterval
+				if cfg := configs.GetCurrentConfig(); cfg != nil {
+					adaptive = cfg.AdaptiveInterval
+				}
+				oc.AdaptiveInterval = &adaptive
+			}
+			applyAdaptiveIntervalUpdates(oc.AdaptiveInterval, adaptiveUpdates, nil)
+		}
+	}
+
 	// 处理 danmaku_enable 配置
 	if danmakuEnable, ok := updates["danmaku_enable"].(bool); ok {
 		oc.DanmakuEnable = &danmakuEnable
@@ -2207,6 +2486,10 @@ func applyOverridableConfigUpdates(oc *configs.OverridableConfig, updates map[st
 		if opacity, ok := danmaku["opacity"].(float64); ok {
 			oc.Danmaku.Opacity = configs.IntPtr(int(opacity))
 		}
+		if streamDelay, ok := danmaku["stream_delay_ms"].(float64); ok {
+			oc.Danmaku.StreamDelayMs = configs.IntPtr(int(streamDelay))
+		}
+		applyDanmakuFilterUpdates(oc.Danmaku, danmaku, nil)
 		if recordGift, ok := danmaku["record_gift"].(bool); ok {
 			oc.Danmaku.RecordGift = configs.BoolPtr(recordGift)
 		} else if _, exists := danmaku["record_gift"]; exists && danmaku["record_gift"] == nil {
@@ -2244,6 +2527,31 @@ func applyOverridableConfigUpdates(oc *configs.OverridableConfig, updates map[st
 	}
 }
 
+// applyRestreamTargetUpdates 更新直播间的转推地址，显式传 null 时清除
+func applyRestreamTargetUpdates(room *configs.LiveRoom, updates map[string]interface{}) error {
+	if targets, ok := updates["restream_targets"].([]interface{}); ok {
+		list := make([]string, 0, len(targets))
+		for _, item := range targets {
+			target, ok := item.(string)
+			if !ok || strings.TrimSpace(target) == "" {
+				continue
+			}
+			list = append(list, strings.TrimSpace(target))
+		}
+		// 接口返回的转推地址已隐去推流码，未修改的地址沿用原值
+		list = configs.RestoreMaskedRestreamTargets(list, room.RestreamTargets)
+		for _, target := range list {
+			if err := configs.ValidateRestreamTarget(target); err != nil {
+				return err
+			}
+		}
+		room.RestreamTargets = list
+	} else if v, exists := updates["restream_targets"]; exists && v == nil {
+		room.RestreamTargets = nil
+	}
+	return nil
+}
+
 // updateRoomConfig 更新直播间配置
 func updateRoomConfig(writer http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -2296,6 +2604,9 @@ func updateRoomConfig(writer http.ResponseWriter, r *http.Request) {
 		if nickName, ok := updates["nick_name"].(string); ok {
 			room.NickName = nickName
 		}
+		if err := applyRestreamTargetUpdates(room, updates); err != nil {
+			return err
+		}
 
 		// 处理可覆盖配置（弹幕、interval、outPutPath、ffmpegPath 等）
 		applyOverridableConfigUpdates(&room.OverridableConfig, updates)
@@ -2355,33 +2666,123 @@ func getFileInfo(writer http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	path := vars["path"]
 
-	cfg := configs.GetCurrentConfig()
-	absPath, err := getSafePath(cfg.OutPutPath, path)
+	jsonFiles, err := listOutputFiles(path)
 	if err != nil {
 		writeJSON(writer, commonResp{
-			ErrMsg: "无效或越权路径",
+			ErrMsg: err.Error(),
 		})
 		return
 	}
 
-	files, err := os.ReadDir(absPath)
+	writeJSON(writer, fileListResponse{
+		Files: jsonFiles,
+		Path:  path,
+	})
+}
+
+// outputFile 输出目录中的文件
+type outputFile struct {
+	IsFolder     bool   `json:"is_folder"`
+	Name         string `json:"name"`
+	LastModified int64  `json:"last_modified"`
+	Size         int64  `json:"size"`
+	SubtitleFile string `json:"subtitle_file,omitempty"`
+	SrtFile      string `json:"srt_file,omitempty"`
+	VttFile      string `json:"vtt_file,omitempty"`
+}
+
+// subtitleSidecarExts 与视频同名的弹幕字幕文件扩展名，随视频一起重命名、删除
+var subtitleSidecarExts = []string{".ass", ".srt", ".vtt"}
+
+func init() {
+	// 部分系统的 MIME 表中没有 .vtt，HTML5 播放器要求 WebVTT 以 text/vtt 返回
+	mime.AddExtensionType(".vtt", "text/vtt; charset=utf-8")
+}
+
+// hasSiblingVideo 判断 path 所在目录中是否还有其他同名（扩展名不同）的录制文件，
+// 例如转换 MP4 时保留的源文件，此时附属文件仍属于该视频
+func hasSiblingVideo(path string) bool {
+	entries, err := os.ReadDir(filepath.Dir(path))
 	if err != nil {
-		writeJSON(writer, commonResp{
-			ErrMsg: "获取目录失败",
-		})
+		return false
+	}
+	name := filepath.Base(path)
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+	for _, entry := range entries {
+		other := entry.Name()
+		if other != name && !entry.IsDir() && isRecordingFile(other) && strings.TrimSuffix(other, filepath.Ext(other)) == base {
+			return true
+		}
+	}
+	return false
+}
+
+// renameVideoSidecars 将 oldPath 录制文件（视频或仅音频录制的音频）的弹幕字幕文件、录制元数据和故事板重命名为与 newPath 同名
+func renameVideoSidecars(oldPath, newPath string) {
+	if !isRecordingFile(oldPath) || hasSiblingVideo(oldPath) {
 		return
 	}
+	oldBase := strings.TrimSuffix(oldPath, filepath.Ext(oldPath))
+	newBase := strings.TrimSuffix(newPath, filepath.Ext(newPath))
+	for _, ext := range subtitleSidecarExts {
+		if _, err := os.Stat(oldBase + ext); err == nil {
+			os.Rename(oldBase+ext, newBase+ext)
+		}
+	}
+	if err := recordmeta.Rename(oldPath, newPath); err != nil {
+		applog.GetLogger().WithError(err).Warnf("重命名录制元数据失败: %s", oldPath)
+	}
+}
+
+// removeVideoSidecars 删除 path 录制文件的弹幕字幕文件、录制元数据和故事板
+func removeVideoSidecars(path string) {
+	if !isRecordingFile(path) || hasSiblingVideo(path) {
+		return
+	}
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	for _, ext := range subtitleSidecarExts {
+		if _, err := os.Stat(base + ext); err == nil {
+			os.Remove(base + ext)
+		}
+	}
+	if err := recordmeta.Remove(path); err != nil {
+		applog.GetLogger().WithError(err).Warnf("删除录制元数据失败: %s", path)
+	}
+}
 
-	type jsonFile struct {
-		IsFolder     bool   `json:"is_folder"`
-		Name         string `json:"name"`
-		LastModified int64  `json:"last_modified"`
-		Size         int64  `json:"size"`
-		SubtitleFile string `json:"subtitle_file,omitempty"`
+// listOutputFiles 列出输出目录下 path 中的文件，弹幕 ASS/SRT/WebVTT 文件附加到同名视频或音频上
+func listOutputFiles(path string) ([]outputFile, error) {
+	cfg := configs.GetCurrentConfig()
+	absPath, err := getSafePath(cfg.OutPutPath, path)
+	if err != nil {
+		return nil, errors.New("无效或越权路径")
 	}
 
-	// First pass: separate ASS files and build base-name -> ASS file map
+	files, err := os.ReadDir(absPath)
+	if err != nil {
+		return nil, errors.New("获取目录失败")
+	}
+
+	// First pass: build base-name -> subtitle file maps and collect video base names
 	assFiles := make(map[string]string) // baseName (no ext) -> ass filename
+	srtFiles := make(map[string]string)
+	vttFiles := make(map[string]string)
+	subtitles := map[string]map[string]string{".ass": assFiles, ".srt": srtFiles, ".vtt": vttFiles}
+	videoBases := make(map[string]bool)
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() {
+			continue
+		}
+		baseName := strings.TrimSuffix(name, filepath.Ext(name))
+		if m, ok := subtitles[strings.ToLower(filepath.Ext(name))]; ok {
+			m[baseName] = name
+		} else if isRecordingFile(name) {
+			videoBases[baseName] = true
+		}
+	}
+
+	// 字幕文件只有在存在同名视频时才附加到视频上并从列表中隐藏，单独存在的字幕文件照常列出
 	type fileEntry struct {
 		dir  os.DirEntry
 		info os.FileInfo
@@ -2393,25 +2794,25 @@ func getFileInfo(writer http.ResponseWriter, r *http.Request) {
 			continue
 		}
 		name := file.Name()
-		if !file.IsDir() && strings.HasSuffix(strings.ToLower(name), ".ass") {
-			// Register ASS file by base name (without extension)
-			baseName := name[:len(name)-4]
-			assFiles[baseName] = name
-		} else {
-			validFiles = append(validFiles, fileEntry{dir: file, info: info})
+		if _, ok := subtitles[strings.ToLower(filepath.Ext(name))]; ok && !file.IsDir() &&
+			videoBases[strings.TrimSuffix(name, filepath.Ext(name))] {
+			continue
 		}
+		validFiles = append(validFiles, fileEntry{dir: file, info: info})
 	}
 
 	// Second pass: build response, attaching subtitle info to video files
-	jsonFiles := make([]jsonFile, 0, len(validFiles))
+	jsonFiles := make([]outputFile, 0, len(validFiles))
 	for _, fe := range validFiles {
-		jf := jsonFile{
+		jf := outputFile{
 			IsFolder:     fe.dir.IsDir(),
 			Name:         fe.dir.Name(),
 			LastModified: fe.info.ModTime().Unix(),
 		}
 		if !fe.dir.IsDir() {
 			jf.Size = fe.info.Size()
+		}
+		if !fe.dir.IsDir() && isRecordingFile(fe.dir.Name()) {
 			// Check if this file has an associated ASS subtitle
 			baseName := fe.dir.Name()
 			if idx := strings.LastIndex(baseName, "."); idx > 0 {
@@ -2420,19 +2821,12 @@ func getFileInfo(writer http.ResponseWriter, r *http.Request) {
 			if assName, ok := assFiles[baseName]; ok {
 				jf.SubtitleFile = assName
 			}
+			jf.SrtFile = srtFiles[baseName]
+			jf.VttFile = vttFiles[baseName]
 		}
 		jsonFiles = append(jsonFiles, jf)
 	}
-
-	json := struct {
-		Files []jsonFile `json:"files"`
-		Path  string     `json:"path"`
-	}{
-		Files: jsonFiles,
-		Path:  path,
-	}
-
-	writeJSON(writer, json)
+	return jsonFiles, nil
 }
 
 // translateOSError 将系统错误转换为中文，兼容多平台。
@@ -2475,9 +2869,7 @@ func renameFile(writer http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	path := vars["path"]
 
-	var body struct {
-		NewName string `json:"new_name"`
-	}
+	var body renameFileRequest
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		writeJSON(writer, commonResp{ErrNo: 400, ErrMsg: "无效请求"})
 		return
@@ -2528,15 +2920,9 @@ func renameFile(writer http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 同步重命名关联的 ASS 弹幕文件
+	// 同步重命名关联的弹幕字幕文件、录制元数据和故事板
 	if !info.IsDir() {
-		oldBase := strings.TrimSuffix(oldAbsPath, filepath.Ext(oldAbsPath))
-		newBase := strings.TrimSuffix(newAbsPath, filepath.Ext(newAbsPath))
-		oldAss := oldBase + ".ass"
-		newAss := newBase + ".ass"
-		if _, err := os.Stat(oldAss); err == nil {
-			os.Rename(oldAss, newAss)
-		}
+		renameVideoSidecars(oldAbsPath, newAbsPath)
 	}
 
 	writeJSON(writer, commonResp{Data: "OK"})
@@ -2558,12 +2944,9 @@ func deleteFile(writer http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 删除关联的 ASS 弹幕文件
+	// 删除关联的弹幕字幕文件、录制元数据和故事板
 	if info, err := os.Stat(absPath); err == nil && !info.IsDir() {
-		assPath := strings.TrimSuffix(absPath, filepath.Ext(absPath)) + ".ass"
-		if _, err := os.Stat(assPath); err == nil {
-			os.Remove(assPath)
-		}
+		removeVideoSidecars(absPath)
 	}
 
 	if err := os.RemoveAll(absPath); err != nil {
@@ -2575,11 +2958,7 @@ func deleteFile(writer http.ResponseWriter, r *http.Request) {
 }
 
 func batchRenameFiles(writer http.ResponseWriter, r *http.Request) {
-	var body struct {
-		Paths   []string `json:"paths"`
-		Find    string   `json:"find"`
-		Replace string   `json:"replace"`
-	}
+	var body batchRenameRequest
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		writeJSON(writer, commonResp{ErrNo: 400, ErrMsg: "无效请求"})
 		return
@@ -2647,15 +3026,9 @@ func batchRenameFiles(writer http.ResponseWriter, r *http.Request) {
 			results = append(results, Result{Path: path, Success: false, Message: translateOSError(err)})
 		} else {
 			results = append(results, Result{Path: path, Success: true, Message: "成功"})
-			// 同步重命名关联的 ASS 弹幕文件
+			// 同步重命名关联的弹幕字幕文件、录制元数据和故事板
 			if !info.IsDir() {
-				oldBase := strings.TrimSuffix(oldAbsPath, filepath.Ext(oldAbsPath))
-				newBase := strings.TrimSuffix(newAbsPath, filepath.Ext(newAbsPath))
-				oldAss := oldBase + ".ass"
-				newAss := newBase + ".ass"
-				if _, err := os.Stat(oldAss); err == nil {
-					os.Rename(oldAss, newAss)
-				}
+				renameVideoSidecars(oldAbsPath, newAbsPath)
 			}
 		}
 	}
@@ -2664,9 +3037,7 @@ func batchRenameFiles(writer http.ResponseWriter, r *http.Request) {
 }
 
 func batchDeleteFiles(writer http.ResponseWriter, r *http.Request) {
-	var body struct {
-		Paths []string `json:"paths"`
-	}
+	var body batchDeleteRequest
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		writeJSON(writer, commonResp{ErrNo: 400, ErrMsg: "无效请求"})
 		return
@@ -2693,12 +3064,9 @@ func batchDeleteFiles(writer http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
-		// 删除关联的 ASS 弹幕文件
+		// 删除关联的弹幕字幕文件、录制元数据和故事板
 		if info, err := os.Stat(absPath); err == nil && !info.IsDir() {
-			assPath := strings.TrimSuffix(absPath, filepath.Ext(absPath)) + ".ass"
-			if _, err := os.Stat(assPath); err == nil {
-				os.Remove(assPath)
-			}
+			removeVideoSidecars(absPath)
 		}
 
 		if err := os.RemoveAll(absPath); err != nil {
@@ -2723,7 +3091,7 @@ func getLiveHostCookie(writer http.ResponseWriter, r *http.Request) {
 		host := urltmp.Host
 		platformName := v.GetPlatformCNName()
 		if cookie, ok := configs.GetCurrentConfig().Cookies[host]; ok {
-			tmp := &live.InfoCookie{Platform_cn_name: platformName, Host: host, Cookie: cookie}
+			tmp := &live.InfoCookie{Platform_cn_name: platformName, Host: host, Cookie: configs.MaskSecretValue(cookie), HasCookie: cookie != ""}
 			hostCookieMap[host] = tmp
 		} else {
 			tmp := &live.InfoCookie{Platform_cn_name: platformName, Host: host}
@@ -2740,6 +3108,107 @@ func getLiveHostCookie(writer http.ResponseWriter, r *http.Request) {
 	writeJSON(writer, result)
 }
 
+// getCookiePoolHealth 返回各 host 账号池的健康状态（不包含 Cookie 内容）
+func getCookiePoolHealth(writer http.ResponseWriter, _ *http.Request) {
+	writeJSON(writer, commonResp{
+		Data: cookiepool.Default().Health(),
+	})
+}
+
+// listSecrets 返回密钥库中的条目名称（不返回明文）
+func listSecrets(writer http.ResponseWriter, _ *http.Request) {
+	store := secrets.Default()
+	if store == nil {
+		writeJsonWithStatusCode(writer, http.StatusServiceUnavailable, commonResp{
+			ErrNo:  http.StatusServiceUnavailable,
+			ErrMsg: "密钥库未初始化",
+		})
+		return
+	}
+	writeJSON(writer, commonResp{
+		Data: map[string]any{
+			"names":      store.Names(),
+			"ref_prefix": configs.SecretRefPrefix,
+		},
+	})
+}
+
+// putSecret 写入密钥库条目，配置中可通过 "secret://名称" 引用
+func putSecret(writer http.ResponseWriter, r *http.Request) {
+	store := secrets.Default()
+	if store == nil {
+		writeJsonWithStatusCode(writer, http.StatusServiceUnavailable, commonResp{
+			ErrNo:  http.StatusServiceUnavailable,
+			ErrMsg: "密钥库未初始化",
+		})
+		return
+	}
+	name := mux.Vars(r)["name"]
+	var req putSecretRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJsonWithStatusCode(writer, http.StatusBadRequest, commonResp{ErrNo: http.StatusBadRequest, ErrMsg: "无效的请求体"})
+		return
+	}
+	if err := secrets.ValidateName(name); err != nil {
+		writeJsonWithStatusCode(writer, http.StatusBadRequest, commonResp{ErrNo: http.StatusBadRequest, ErrMsg: err.Error()})
+		return
+	}
+	if err := store.Set(name, req.Value); err != nil {
+		writeJsonWithStatusCode(writer, http.StatusInternalServerError, commonResp{
+			ErrNo:  http.StatusInternalServerError,
+			ErrMsg: "保存密钥失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 引用该条目的 Cookie 需要重新应用到直播间
+	cfg := configs.GetCurrentConfig()
+	var hosts []string
+	for host, v := range cfg.Cookies {
+		if configs.SecretRefName(v) == name {
+			hosts = append(hosts, host)
+		}
+	}
+	for host, pool := range cfg.CookiePools {
+		for _, account := range pool.Accounts {
+			if configs.SecretRefName(account.Cookie) == name {
+				hosts = append(hosts, host)
+				break
+			}
+		}
+	}
+	if len(hosts) > 0 {
+		applyCookiesToLives(r.Context(), cfg, hosts...)
+	}
+
+	writeJSON(writer, commonResp{
+		Data: "OK",
+	})
+}
+
+// deleteSecret 删除密钥库条目
+func deleteSecret(writer http.ResponseWriter, r *http.Request) {
+	store := secrets.Default()
+	if store == nil {
+		writeJsonWithStatusCode(writer, http.StatusServiceUnavailable, commonResp{
+			ErrNo:  http.StatusServiceUnavailable,
+			ErrMsg: "密钥库未初始化",
+		})
+		return
+	}
+	if err := store.Delete(mux.Vars(r)["name"]); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, secrets.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		writeJsonWithStatusCode(writer, status, commonResp{ErrNo: status, ErrMsg: err.Error()})
+		return
+	}
+	writeJSON(writer, commonResp{
+		Data: "OK",
+	})
+}
+
 func applyCookiesToLives(ctx context.Context, newCfg *configs.Config, hosts ...string) {
 	inst := instance.GetInstance(ctx)
 	hostSet := make(map[string]struct{}, len(hosts))
@@ -2788,6 +3257,13 @@ func putLiveHostCookie(writer http.ResponseWriter, r *http.Request) {
 
 	host := data.Get("Host").Str
 	cookie := data.Get("Cookie").Str
+	if cookie == configs.SecretMask {
+		// 提交的仍是遮盖占位符，Cookie 未修改
+		writeJSON(writer, commonResp{
+			Data: "OK",
+		})
+		return
+	}
 	if cookie == "" {
 
 	} else {
@@ -2835,7 +3311,7 @@ func getSoopLiveAuthConfig(writer http.ResponseWriter, _ *http.Request) {
 	cookieStatus := "missing"
 	storedCookie := ""
 	if cfg.Cookies != nil {
-		if cookie := strings.TrimSpace(cfg.Cookies["play.sooplive.com"]); cookie != "" {
+		if cookie := strings.TrimSpace(configs.ResolveSecret(cfg.Cookies["play.sooplive.com"])); cookie != "" {
 			storedCookie = cookie
 		}
 	}
@@ -2902,11 +3378,7 @@ func clearSoopLiveAuthConfig(writer http.ResponseWriter, r *http.Request) {
 // 2. 按当前前端选择决定是否保存明文账号密码；
 // 3. 更新当前运行中 Soop 房间的请求选项。
 func loginSoopLive(writer http.ResponseWriter, r *http.Request) {
-	var req struct {
-		Username        string `json:"username"`
-		Password        string `json:"password"`
-		SaveCredentials bool   `json:"save_credentials"`
-	}
+	var req soopLiveLoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeJsonWithStatusCode(writer, http.StatusBadRequest, commonResp{ErrNo: http.StatusBadRequest, ErrMsg: "请求体格式错误，无法解析 Soop 登录参数"})
 		return
@@ -2931,10 +3403,16 @@ func loginSoopLive(writer http.ResponseWriter, r *http.Request) {
 		if c.Cookies == nil {
 			c.Cookies = make(map[string]string)
 		}
-		c.Cookies["play.sooplive.com"] = result.Cookie
+		cookie := c.Cookies["play.sooplive.com"]
+		if err := c.AssignSecret(&cookie, result.Cookie); err != nil {
+			return err
+		}
+		c.Cookies["play.sooplive.com"] = cookie
 		if req.SaveCredentials {
 			c.SoopLiveAuth.Username = req.Username
-			c.SoopLiveAuth.Password = req.Password
+			if err := c.AssignSecret(&c.SoopLiveAuth.Password, req.Password); err != nil {
+				return err
+			}
 		} else {
 			c.SoopLiveAuth.Username = ""
 			c.SoopLiveAuth.Password = ""
@@ -2968,9 +3446,7 @@ func loginSoopLive(writer http.ResponseWriter, r *http.Request) {
 // - 账号已在平台侧退出登录；
 // - Soop 校验接口当前不可用。
 func verifySoopLiveCookie(writer http.ResponseWriter, r *http.Request) {
-	var req struct {
-		Cookie string `json:"cookie"`
-	}
+	var req cookieVerifyRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeJsonWithStatusCode(writer, http.StatusBadRequest, commonResp{ErrNo: http.StatusBadRequest, ErrMsg: "请求体格式错误，无法解析 Soop Cookie"})
 		return
@@ -3038,6 +3514,66 @@ func getLiveSessionHistory(writer http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// getLiveSessionStats 获取直播会话的弹幕与营收统计
+func getLiveSessionStats(writer http.ResponseWriter, r *http.Request) {
+	inst := instance.GetInstance(r.Context())
+	vars := mux.Vars(r)
+	liveID := vars["id"]
+
+	// 检查直播间是否存在
+	if !inst.Lives.Has(types.LiveID(liveID)) {
+		writeJsonWithStatusCode(writer, http.StatusNotFound, commonResp{
+			ErrNo:  http.StatusNotFound,
+			ErrMsg: fmt.Sprintf("live id: %s can not find", liveID),
+		})
+		return
+	}
+
+	sessionID, err := strconv.ParseInt(vars["session"], 10, 64)
+	if err != nil || sessionID <= 0 {
+		writeJsonWithStatusCode(writer, http.StatusBadRequest, commonResp{
+			ErrNo:  http.StatusBadRequest,
+			ErrMsg: fmt.Sprintf("invalid session id: %s", vars["session"]),
+		})
+		return
+	}
+
+	// 获取 top 参数
+	top := 10 // 默认 10 个
+	if topStr := r.URL.Query().Get("top"); topStr != "" {
+		if parsedTop, err := strconv.Atoi(topStr); err == nil && parsedTop >= 0 {
+			top = parsedTop
+		}
+	}
+
+	// 获取 LiveStateManager
+	manager, ok := inst.LiveStateManager.(*livestate.Manager)
+	if !ok || manager == nil {
+		writeJsonWithStatusCode(writer, http.StatusServiceUnavailable, commonResp{
+			ErrNo:  http.StatusServiceUnavailable,
+			ErrMsg: "状态持久化功能未启用",
+		})
+		return
+	}
+
+	stats, err := manager.GetSessionChatStats(liveID, sessionID, top)
+	if errors.Is(err, livestate.ErrSessionNotFound) {
+		writeJsonWithStatusCode(writer, http.StatusNotFound, commonResp{
+			ErrNo:  http.StatusNotFound,
+			ErrMsg: fmt.Sprintf("session %d of live %s can not find", sessionID, liveID),
+		})
+		return
+	}
+	if err != nil {
+		writeJsonWithStatusCode(writer, http.StatusInternalServerError, commonResp{
+			ErrNo:  http.StatusInternalServerError,
+			ErrMsg: err.Error(),
+		})
+		return
+	}
+	writeJSON(writer, stats)
+}
+
 // getLiveNameHistory 获取直播间的名称变更历史
 func getLiveNameHistory(writer http.ResponseWriter, r *http.Request) {
 	inst := instance.GetInstance(r.Context())
@@ -3433,8 +3969,9 @@ func pollBilibiliQRCode(writer http.ResponseWriter, r *http.Request) {
 	var result struct {
 		Code int `json:"code"`
 		Data struct {
-			Code int    `json:"code"`
-			Url  string `json:"url"`
+			Code         int    `json:"code"`
+			Url          string `json:"url"`
+			RefreshToken string `json:"refresh_token,omitempty"`
 		} `json:"data"`
 	}
 
@@ -3447,6 +3984,10 @@ func pollBilibiliQRCode(writer http.ResponseWriter, r *http.Request) {
 			applog.GetLogger().Error("解析登录回调 URL 失败: " + err.Error() + ", URL: " + result.Data.Url)
 		} else {
 			q := u.Query()
+			// 保存 refresh_token，供 Cookie 保活任务在过期前自动刷新
+			if err := cookiekeeper.SaveRefreshToken(r.Context(), q.Get("DedeUserID"), result.Data.RefreshToken); err != nil {
+				applog.GetLogger().Warn("保存哔哩哔哩 refresh_token 失败: " + err.Error())
+			}
 			foundExtra := false
 			if resp.Response != nil {
 				for _, cookie := range resp.Cookies() {
@@ -3475,9 +4016,7 @@ func pollBilibiliQRCode(writer http.ResponseWriter, r *http.Request) {
 
 // verifyBilibiliCookie 验证哔哩哔哩 Cookie 有效性
 func verifyBilibiliCookie(writer http.ResponseWriter, r *http.Request) {
-	var req struct {
-		Cookie string `json:"cookie"`
-	}
+	var req cookieVerifyRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeJsonWithStatusCode(writer, http.StatusBadRequest, commonResp{ErrNo: http.StatusBadRequest, ErrMsg: "无效的请求体"})
 		return
@@ -3591,3 +4130,118 @@ func stopRecordDirect(writer http.ResponseWriter, r *http.Request) {
 
 	writeJSON(writer, parseInfo(r.Context(), liveObj))
 }
+
+// getLivePreview 以 HTTP-FLV 转发录制器正在接收的直播流，供 Web 界面检查画质。
+// 观众数据来自录制连接，不会额外请求 CDN；读取过慢的观众会被断开
+func getLivePreview(writer http.ResponseWriter, r *http.Request) {
+	recorder, cfg, ok := previewRecorder(writer, r)
+	if !ok {
+		return
+	}
+	relay := recorder.Preview()
+	if relay == nil {
+		writePreviewError(writer, http.StatusConflict, "当前录制不是 FLV 直播流，HLS 直播流请使用 preview.ts")
+		return
+	}
+
+	viewer, err := relay.Subscribe(cfg.Preview.MaxViewers)
+	if err != nil {
+		if errors.Is(err, flvproxy.ErrTooManyViewers) {
+			writePreviewError(writer, http.StatusTooManyRequests, fmt.Sprintf("预览人数已达上限（%d 人）", cfg.Preview.MaxViewers))
+		} else {
+			writePreviewError(writer, http.StatusServiceUnavailable, "录制已结束")
+		}
+		return
+	}
+	defer relay.Unsubscribe(viewer)
+	streamPreview(writer, r, "video/x-flv", viewer.C)
+}
+
+// getLiveHLSPreview 把 HLS 录制正在接收的 MPEG-TS 分段拼接成连续的流转发，供 Web 界面检查画质。
+// 分段来自录制器的 HLS 代理，不会额外请求 CDN；fMP4 分段与加密的直播流不支持预览
+func getLiveHLSPreview(writer http.ResponseWriter, r *http.Request) {
+	recorder, cfg, ok := previewRecorder(writer, r)
+	if !ok {
+		return
+	}
+	relay := recorder.HLSPreview()
+	if relay == nil {
+		writePreviewError(writer, http.StatusConflict, "当前录制不是 HLS 直播流，FLV 直播流请使用 preview.flv")
+		return
+	}
+
+	viewer, err := relay.Subscribe(cfg.Preview.MaxViewers)
+	if err != nil {
+		switch {
+		case errors.Is(err, hlsproxy.ErrTooManyViewers):
+			writePreviewError(writer, http.StatusTooManyRequests, fmt.Sprintf("预览人数已达上限（%d 人）", cfg.Preview.MaxViewers))
+		case errors.Is(err, hlsproxy.ErrUnsupportedSegment):
+			writePreviewError(writer, http.StatusConflict, "当前 HLS 直播流的分段不是 MPEG-TS，不支持预览")
+		default:
+			writePreviewError(writer, http.StatusServiceUnavailable, "录制已结束")
+		}
+		return
+	}
+	defer relay.Unsubscribe(viewer)
+	streamPreview(writer, r, "video/mp2t", viewer.C)
+}
+
+// previewRecorder 检查预览是否启用并找到直播间的录制器，失败时已写入错误响应
+func previewRecorder(writer http.ResponseWriter, r *http.Request) (recorders.Recorder, *configs.Config, bool) {
+	inst := instance.GetInstance(r.Context())
+	vars := mux.Vars(r)
+
+	cfg := configs.GetCurrentConfig()
+	if !cfg.Preview.Enable {
+		writePreviewError(writer, http.StatusForbidden, "直播预览未启用")
+		return nil, nil, false
+	}
+
+	liveObj, ok := inst.Lives.Get(types.LiveID(vars["id"]))
+	if !ok {
+		writePreviewError(writer, http.StatusNotFound, fmt.Sprintf("live id: %s can not find", vars["id"]))
+		return nil, nil, false
+	}
+
+	recorderMgr, ok := inst.RecorderManager.(recorders.Manager)
+	if !ok {
+		writePreviewError(writer, http.StatusInternalServerError, "录制管理器不可用")
+		return nil, nil, false
+	}
+
+	recorder, err := recorderMgr.GetRecorder(r.Context(), liveObj.GetLiveId())
+	if err != nil {
+		writePreviewError(writer, http.StatusNotFound, "该直播间未在录制中")
+		return nil, nil, false
+	}
+	return recorder, cfg, true
+}
+
+func writePreviewError(writer http.ResponseWriter, status int, msg string) {
+	writeJsonWithStatusCode(writer, status, commonResp{ErrNo: status, ErrMsg: msg})
+}
+
+// streamPreview 把观众队列中的数据持续写给客户端，直到客户端断开或队列被关闭
+func streamPreview(writer http.ResponseWriter, r *http.Request, contentType string, c <-chan []byte) {
+	writer.Header().Set("Content-Type", contentType)
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.WriteHeader(http.StatusOK)
+	flusher, _ := writer.(http.Flusher)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case data, ok := <-c:
+			if !ok {
+				return
+			}
+			if _, err := writer.Write(data); err != nil {
+				return
+			}
+			// 积压的数据一次写完再刷新
+			if len(c) == 0 && flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+}
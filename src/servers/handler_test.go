@@ -2,12 +2,16 @@ package servers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/pkg/recordmeta"
 )
 
 func TestGetSoopLiveAuthConfigDoesNotExposeSavedPassword(t *testing.T) {
@@ -32,3 +36,123 @@ func TestGetSoopLiveAuthConfigDoesNotExposeSavedPassword(t *testing.T) {
 	_, exists := data["password"]
 	assert.False(t, exists)
 }
+
+type mapSecretBackend map[string]string
+
+func (m mapSecretBackend) Get(name string) (string, error) { return m[name], nil }
+
+func (m mapSecretBackend) Set(name, value string) error {
+	m[name] = value
+	return nil
+}
+
+func TestApplyConfigUpdatesKeepsSecretRefs(t *testing.T) {
+	backend := mapSecretBackend{"rpc": "old"}
+	configs.SetSecretBackend(backend)
+	defer configs.SetSecretBackend(nil)
+
+	cfg := configs.NewConfig()
+	cfg.RPC.Token = "secret://rpc"
+	cfg.Notify.Telegram.BotToken = "plain-token"
+	configs.SetCurrentConfig(cfg)
+	defer configs.SetCurrentConfig(nil)
+	update := func(verifyErr error) (*configs.Config, error) {
+		return configs.UpdateWithRetryTransient(func(c *configs.Config) error {
+			if err := applyConfigUpdates(c, map[string]interface{}{
+				"rpc":    map[string]interface{}{"token": "new"},
+				"notify": map[string]interface{}{"telegram": map[string]interface{}{"botToken": configs.SecretMask}},
+			}, nil); err != nil {
+				return err
+			}
+			return verifyErr
+		}, 0, 0)
+	}
+
+	// 更新被拒绝时密钥库保持不变
+	_, err := update(errors.New("校验失败"))
+	assert.Error(t, err)
+	assert.Equal(t, "old", backend["rpc"])
+
+	newCfg, err := update(nil)
+	assert.NoError(t, err)
+	// 引用字段写入明文时更新密钥库，配置中仍是引用
+	assert.Equal(t, "secret://rpc", newCfg.RPC.Token)
+	assert.Equal(t, "new", backend["rpc"])
+	// 提交遮盖占位符时保持原值
+	assert.Equal(t, "plain-token", newCfg.Notify.Telegram.BotToken)
+}
+
+func TestApplyConfigUpdatesReportsIgnoredKeys(t *testing.T) {
+	c := configs.NewConfig()
+	updates := map[string]interface{}{
+		"interval": float64(30),
+		"typo":     true,
+		"danmaku":  map[string]interface{}{"font_size": float64(40), "font_sise": float64(40)},
+		// 类型不符的值不会被应用
+		"debug": "yes",
+		"stream_preference": map[string]interface{}{
+			"attributes": map[string]interface{}{"codec": "h264"},
+		},
+	}
+	patch := newConfigPatch()
+	assert.NoError(t, applyConfigUpdates(c, updates, patch))
+	result := patch.result(updates)
+	assert.Equal(t, []string{"danmaku.font_size", "interval", "stream_preference.attributes"}, result.Applied)
+	assert.Equal(t, []string{"danmaku.font_sise", "debug", "typo"}, result.Ignored)
+	assert.ErrorIs(t, result.unknownKeysError(), errUnknownConfigKeys)
+}
+
+func TestVideoSidecarsFollowVideo(t *testing.T) {
+	dir := t.TempDir()
+	cfg := configs.NewConfig()
+	cfg.OutPutPath = dir
+	configs.SetCurrentConfig(cfg)
+	defer configs.SetCurrentConfig(nil)
+
+	for _, name := range []string{"rec.flv", "rec.ass", "rec.srt", "lonely.srt", "rec.storyboard.jpg", "audio.aac", "audio.srt"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("x"), 0644))
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "rec.storyboard.vtt"), []byte("WEBVTT\n\n00:00:00.000 --> 00:00:10.000\nrec.storyboard.jpg#xywh=0,0,160,90\n"), 0644))
+	video := filepath.Join(dir, "rec.flv")
+	board := recordmeta.Storyboard{Sprite: "rec.storyboard.jpg", VTT: "rec.storyboard.vtt", Interval: 10, Count: 1, Columns: 1, Width: 160, Height: 90}
+	assert.NoError(t, recordmeta.Update(video, func(m *recordmeta.Meta) { m.Storyboard = &board }))
+
+	// 有同名视频的字幕附加到视频上，单独存在的字幕照常列出
+	files, err := listOutputFiles("")
+	assert.NoError(t, err)
+	names := make(map[string]outputFile)
+	for _, f := range files {
+		names[f.Name] = f
+	}
+	assert.Equal(t, "rec.ass", names["rec.flv"].SubtitleFile)
+	assert.Equal(t, "rec.srt", names["rec.flv"].SrtFile)
+	assert.Contains(t, names, "lonely.srt")
+	assert.NotContains(t, names, "rec.srt")
+	// 仅音频录制保存为 .aac，字幕同样附加到音频文件上
+	assert.Equal(t, "audio.srt", names["audio.aac"].SrtFile)
+	assert.NotContains(t, names, "audio.srt")
+
+	renamed := filepath.Join(dir, "新名字.flv")
+	assert.NoError(t, os.Rename(video, renamed))
+	renameVideoSidecars(video, renamed)
+	for _, name := range []string{"新名字.ass", "新名字.srt", "新名字.meta.json", "新名字.storyboard.jpg", "新名字.storyboard.vtt"} {
+		assert.FileExists(t, filepath.Join(dir, name))
+	}
+	meta, err := recordmeta.Load(renamed)
+	assert.NoError(t, err)
+	if assert.NotNil(t, meta) && assert.NotNil(t, meta.Storyboard) {
+		assert.Equal(t, "新名字.storyboard.jpg", meta.Storyboard.Sprite)
+		assert.Equal(t, "新名字.storyboard.vtt", meta.Storyboard.VTT)
+	}
+	vtt, _ := os.ReadFile(filepath.Join(dir, "新名字.storyboard.vtt"))
+	assert.Contains(t, string(vtt), "新名字.storyboard.jpg#xywh=")
+
+	removeVideoSidecars(renamed)
+	for _, name := range []string{"新名字.ass", "新名字.srt", "新名字.meta.json", "新名字.storyboard.jpg", "新名字.storyboard.vtt"} {
+		assert.NoFileExists(t, filepath.Join(dir, name))
+	}
+	assert.FileExists(t, filepath.Join(dir, "lonely.srt"))
+
+	removeVideoSidecars(filepath.Join(dir, "audio.aac"))
+	assert.NoFileExists(t, filepath.Join(dir, "audio.srt"))
+}
@@ -1,11 +1,29 @@
 package servers
 
 import (
+	"crypto/subtle"
 	"net/http"
+	"net/url"
+	"strings"
 
+	"github.com/bililive-go/bililive-go/src/configs"
 	applog "github.com/bililive-go/bililive-go/src/log"
 )
 
+const (
+	// tokenCookieName 浏览器通过 ?token= 访问后保存令牌的 Cookie
+	tokenCookieName = "bililive_token"
+	// tokenQueryKey 通过查询参数传递令牌（WebSocket、SSE、直接打开文件链接等无法设置请求头的场景）
+	tokenQueryKey = "token"
+)
+
+// tokenProtectedPrefixes 配置了 rpc.token 时需要鉴权的路径前缀，Web 界面的静态资源不受限制。
+// /tools/、/scheduler/ 反向代理到本机的工具和调度器界面，/debug/ 为 pprof，同样需要令牌
+var tokenProtectedPrefixes = []string{
+	apiRouterPrefix + "/", "/osrp/", "/files/",
+	"/tools/", "/scheduler/", "/debug/",
+}
+
 // log 是一个 HTTP 中间件，用于记录请求日志（保留供本地调试使用）
 //
 //nolint:unused
@@ -19,3 +37,106 @@ func log(handler http.Handler) http.Handler {
 		handler.ServeHTTP(w, r)
 	})
 }
+
+// requestToken 依次从 Authorization 请求头、查询参数、Cookie 中读取令牌
+func requestToken(r *http.Request) string {
+	if token := explicitRequestToken(r); token != "" {
+		return token
+	}
+	if cookie, err := r.Cookie(tokenCookieName); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+// explicitRequestToken 读取请求方主动携带的令牌（Authorization 请求头或查询参数），不含浏览器自动附带的 Cookie
+func explicitRequestToken(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		if token, ok := strings.CutPrefix(auth, "Bearer "); ok {
+			return strings.TrimSpace(token)
+		}
+	}
+	return r.URL.Query().Get(tokenQueryKey)
+}
+
+// checkWebSocketOrigin 用作 WebSocket 的 CheckOrigin。
+// 同源和不带 Origin 的请求（非浏览器客户端）直接放行；跨站请求中浏览器会自动附带令牌 Cookie，
+// 配置了 rpc.token 时只接受请求方主动携带的令牌，防止跨站 WebSocket 劫持
+func checkWebSocketOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	cfg := configs.GetCurrentConfig()
+	if cfg == nil || cfg.RPC.Token == "" {
+		return true
+	}
+	expected, err := configs.LookupSecret(cfg.RPC.Token)
+	if err != nil || expected == "" {
+		return false
+	}
+	token := explicitRequestToken(r)
+	return token != "" && tokenEqual(token, expected)
+}
+
+func tokenEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+func isTokenProtected(path string) bool {
+	for _, prefix := range tokenProtectedPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenAuth 是一个 HTTP 中间件：配置了 rpc.token 时校验 tokenProtectedPrefixes 下请求携带的令牌。
+// 通过 ?token= 校验成功后写入 Cookie，浏览器打开一次 /?token=<令牌> 即可正常使用 Web 界面
+func tokenAuth(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cfg := configs.GetCurrentConfig()
+		// CORS 预检请求不携带凭据
+		if cfg == nil || cfg.RPC.Token == "" || r.Method == http.MethodOptions {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		expected, err := configs.LookupSecret(cfg.RPC.Token)
+		if err != nil || expected == "" {
+			// 令牌引用无法解析时拒绝访问，避免鉴权被意外关闭
+			if isTokenProtected(r.URL.Path) {
+				writeJsonWithStatusCode(w, http.StatusUnauthorized, commonResp{
+					ErrNo:  http.StatusUnauthorized,
+					ErrMsg: "访问令牌配置无效，请检查 rpc.token",
+				})
+				return
+			}
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		token := requestToken(r)
+		valid := token != "" && tokenEqual(token, expected)
+		if valid && r.URL.Query().Get(tokenQueryKey) != "" {
+			http.SetCookie(w, &http.Cookie{
+				Name:     tokenCookieName,
+				Value:    token,
+				Path:     "/",
+				HttpOnly: true,
+				SameSite: http.SameSiteLaxMode,
+			})
+		}
+		if !valid && isTokenProtected(r.URL.Path) {
+			writeJsonWithStatusCode(w, http.StatusUnauthorized, commonResp{
+				ErrNo:  http.StatusUnauthorized,
+				ErrMsg: "未授权：缺少或错误的访问令牌",
+			})
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,119 @@
+package servers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+)
+
+func TestTokenAuth(t *testing.T) {
+	cfg := configs.NewConfig()
+	cfg.RPC.Token = "s3cret"
+	configs.SetCurrentConfig(cfg)
+	defer configs.SetCurrentConfig(nil)
+
+	h := tokenAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(r *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	// 未携带令牌
+	assert.Equal(t, http.StatusUnauthorized, serve(httptest.NewRequest("GET", "/api/lives", nil)).Code)
+	assert.Equal(t, http.StatusUnauthorized, serve(httptest.NewRequest("GET", "/files/a.flv", nil)).Code)
+	for _, path := range []string{"/tools/", "/scheduler/", "/debug/pprof/"} {
+		assert.Equal(t, http.StatusUnauthorized, serve(httptest.NewRequest("GET", path, nil)).Code, path)
+	}
+	// Web 界面静态资源不需要令牌
+	assert.Equal(t, http.StatusOK, serve(httptest.NewRequest("GET", "/index.html", nil)).Code)
+
+	r := httptest.NewRequest("GET", "/api/lives", nil)
+	r.Header.Set("Authorization", "Bearer wrong")
+	assert.Equal(t, http.StatusUnauthorized, serve(r).Code)
+
+	r = httptest.NewRequest("GET", "/api/lives", nil)
+	r.Header.Set("Authorization", "Bearer s3cret")
+	assert.Equal(t, http.StatusOK, serve(r).Code)
+
+	// 查询参数中的令牌写入 Cookie，之后凭 Cookie 访问
+	w := serve(httptest.NewRequest("GET", "/?token=s3cret", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	cookies := w.Result().Cookies()
+	if assert.Len(t, cookies, 1) {
+		assert.Equal(t, tokenCookieName, cookies[0].Name)
+		r = httptest.NewRequest("GET", "/api/lives", nil)
+		r.AddCookie(cookies[0])
+		assert.Equal(t, http.StatusOK, serve(r).Code)
+	}
+
+	// 未配置令牌时不鉴权
+	cfg.RPC.Token = ""
+	assert.Equal(t, http.StatusOK, serve(httptest.NewRequest("GET", "/api/lives", nil)).Code)
+}
+
+func TestClusterHeartbeatRequiresToken(t *testing.T) {
+	cfg := configs.NewConfig()
+	configs.SetCurrentConfig(cfg)
+	defer configs.SetCurrentConfig(nil)
+
+	heartbeat := func(token string) int {
+		r := httptest.NewRequest("POST", "/api/cluster/heartbeat", strings.NewReader(`{"node_id":"fake"}`))
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		clusterHeartbeat(w, r)
+		return w.Code
+	}
+
+	// 未配置令牌时不依赖中间件，心跳同样被拒绝
+	assert.Equal(t, http.StatusUnauthorized, heartbeat(""))
+
+	cfg.RPC.Token = "s3cret"
+	assert.Equal(t, http.StatusUnauthorized, heartbeat(""))
+	assert.Equal(t, http.StatusUnauthorized, heartbeat("wrong"))
+	// 令牌正确后进入集群处理，本进程不是 coordinator
+	assert.Equal(t, http.StatusBadRequest, heartbeat("s3cret"))
+
+	cfg.Cluster.Mode = configs.ClusterModeCoordinator
+	assert.NoError(t, cfg.Verify())
+	cfg.RPC.Token = ""
+	assert.Error(t, cfg.Verify())
+}
+
+func TestCheckWebSocketOrigin(t *testing.T) {
+	cfg := configs.NewConfig()
+	configs.SetCurrentConfig(cfg)
+	defer configs.SetCurrentConfig(nil)
+
+	upgrade := func(target, origin string, cookie bool) bool {
+		r := httptest.NewRequest("GET", target, nil)
+		r.Host = "nas:8080"
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		if cookie {
+			r.AddCookie(&http.Cookie{Name: tokenCookieName, Value: "s3cret"})
+		}
+		return checkWebSocketOrigin(r)
+	}
+
+	// 未配置令牌时不限制来源
+	assert.True(t, upgrade("/api/events/ws", "https://evil.example.com", false))
+
+	cfg.RPC.Token = "s3cret"
+	assert.True(t, upgrade("/api/events/ws", "", false), "非浏览器客户端")
+	assert.True(t, upgrade("/api/events/ws", "http://nas:8080", true), "同源")
+	// 跨站请求不接受浏览器自动附带的 Cookie
+	assert.False(t, upgrade("/api/events/ws", "https://evil.example.com", true))
+	assert.False(t, upgrade("/api/events/ws?token=wrong", "https://evil.example.com", false))
+	assert.True(t, upgrade("/api/events/ws?token=s3cret", "https://dashboard.example.com", false))
+}
@@ -56,3 +56,79 @@ type batchCompleteEvent struct {
 	PersistError string    `json:"persist_error,omitempty"`
 	Timestamp    time.Time `json:"timestamp"`
 }
+
+// addLiveRequest 添加直播间请求（POST /api/lives 的请求体为该结构的数组）
+// 仅用于生成 API 文档，处理函数仍按字段宽松解析请求体
+type addLiveRequest struct {
+	URL        string `json:"url"`
+	Listen     bool   `json:"listen"`
+	NotifyOnly bool   `json:"notify_only"`
+}
+
+// rawConfig YAML 原文形式的配置
+type rawConfig struct {
+	Config string `json:"config"`
+}
+
+// previewOutputTmplRequest 输出模板预览请求
+type previewOutputTmplRequest struct {
+	Template   string `json:"template"`
+	OutPutPath string `json:"out_put_path"`
+}
+
+// fileListResponse 输出目录文件列表
+type fileListResponse struct {
+	Files []outputFile `json:"files"`
+	Path  string       `json:"path"`
+}
+
+// renameFileRequest 重命名文件请求，文件的扩展名保持不变
+type renameFileRequest struct {
+	NewName string `json:"new_name"`
+}
+
+// batchRenameRequest 批量重命名请求，把文件名中的 Find 替换为 Replace
+type batchRenameRequest struct {
+	Paths   []string `json:"paths"`
+	Find    string   `json:"find"`
+	Replace string   `json:"replace"`
+}
+
+// batchDeleteRequest 批量删除请求
+type batchDeleteRequest struct {
+	Paths []string `json:"paths"`
+}
+
+// putSecretRequest 写入密钥请求
+type putSecretRequest struct {
+	Value string `json:"value"`
+}
+
+// soopLiveLoginRequest Soop 账号登录请求
+type soopLiveLoginRequest struct {
+	Username        string `json:"username"`
+	Password        string `json:"password"`
+	SaveCredentials bool   `json:"save_credentials"`
+}
+
+// cookieVerifyRequest Cookie 校验请求
+type cookieVerifyRequest struct {
+	Cookie string `json:"cookie"`
+}
+
+// updateChannelRequest 设置更新通道请求
+type updateChannelRequest struct {
+	Channel string `json:"channel"` // "stable" or "prerelease"
+}
+
+// batchBurnRequest 批量烧录弹幕字幕请求
+type batchBurnRequest struct {
+	Paths []string `json:"paths"`
+}
+
+// batchBurnResponse 批量烧录弹幕字幕结果
+type batchBurnResponse struct {
+	Enqueued int      `json:"enqueued"`
+	Skipped  []string `json:"skipped"`
+	TaskIDs  []int64  `json:"task_ids"`
+}
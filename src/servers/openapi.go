@@ -0,0 +1,396 @@
+package servers
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3gen"
+
+	"github.com/bililive-go/bililive-go/src/cluster"
+	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/consts"
+	"github.com/bililive-go/bililive-go/src/live"
+	"github.com/bililive-go/bililive-go/src/livestate"
+	"github.com/bililive-go/bililive-go/src/pipeline"
+	"github.com/bililive-go/bililive-go/src/pkg/update"
+	"github.com/bililive-go/bililive-go/src/tools"
+)
+
+// apiOperation 描述一个 HTTP 接口，用于生成 OpenAPI 文档。
+// 新增或修改路由时需同步更新 apiOperations，测试会检查两者是否一致
+type apiOperation struct {
+	Method string
+	// Path 与注册路由时相同的模板，如 /api/file/{path:.*}
+	Path string
+	// ID 对应的处理函数名，作为 operationId 供客户端生成器使用
+	ID      string
+	Tag     string
+	Summary string
+	Query   []apiQueryParam
+	// Request 请求体类型的零值，nil 表示没有请求体；map 表示任意 JSON 对象
+	Request any
+	// Response 成功响应类型的零值，nil 表示结构不固定的 JSON
+	Response any
+	// ContentType 非 JSON 响应的类型
+	ContentType string
+	// Status 成功时的状态码，默认 200
+	Status int
+}
+
+// apiQueryParam 查询参数
+type apiQueryParam struct {
+	Name        string
+	Type        string // string、integer、boolean
+	Description string
+}
+
+type jsonObject = map[string]any
+
+var (
+	limitParam     = apiQueryParam{"limit", "integer", "返回条数"}
+	startTimeParam = apiQueryParam{"start_time", "string", "开始时间"}
+	endTimeParam   = apiQueryParam{"end_time", "string", "结束时间"}
+)
+
+// apiOperations 全部 /api 与 /osrp/v1 接口，顺序与 initMux 中的注册顺序一致
+var apiOperations = []apiOperation{
+	{Method: "GET", Path: "/api/info", ID: "getInfo", Tag: "system", Summary: "获取程序信息", Response: consts.Info{}},
+	{Method: "GET", Path: "/api/config", ID: "getConfig", Tag: "config", Summary: "获取配置（敏感字段已遮盖）", Response: configs.Config{}},
+	{Method: "PUT", Path: "/api/config", ID: "putConfig", Tag: "config", Summary: "把当前配置写入配置文件", Response: commonResp{}},
+	{Method: "PATCH", Path: "/api/config", ID: "updateConfig", Tag: "config", Summary: "部分更新配置，data 返回已应用（applied）和被忽略（ignored）的键", Request: jsonObject{}, Response: commonResp{},
+		Query: []apiQueryParam{{"strict", "boolean", "为 true 时存在无法识别的键则返回 400 且不做任何修改"}}},
+	{Method: "GET", Path: "/api/config/effective", ID: "getEffectiveConfig", Tag: "config", Summary: "获取实际生效的配置", Response: EffectiveConfigResponse{}},
+	{Method: "GET", Path: "/api/config/platforms", ID: "getPlatformStats", Tag: "config", Summary: "获取平台统计"},
+	{Method: "PUT", Path: "/api/config/platforms/{platform}", ID: "updatePlatformConfig", Tag: "config", Summary: "更新平台配置", Request: jsonObject{}},
+	{Method: "PATCH", Path: "/api/config/platforms/{platform}", ID: "patchPlatformConfig", Tag: "config", Summary: "更新平台配置", Request: jsonObject{}},
+	{Method: "DELETE", Path: "/api/config/platforms/{platform}", ID: "deletePlatformConfig", Tag: "config", Summary: "删除平台配置"},
+	{Method: "PUT", Path: "/api/config/rooms/id/{id}", ID: "updateRoomConfigById", Tag: "config", Summary: "通过 ID 更新直播间配置", Request: jsonObject{}},
+	{Method: "PATCH", Path: "/api/config/rooms/id/{id}", ID: "patchRoomConfigById", Tag: "config", Summary: "通过 ID 更新直播间配置", Request: jsonObject{}},
+	{Method: "PUT", Path: "/api/config/rooms/{url:.*}", ID: "updateRoomConfig", Tag: "config", Summary: "通过 URL 更新直播间配置", Request: jsonObject{}},
+	{Method: "PATCH", Path: "/api/config/rooms/{url:.*}", ID: "patchRoomConfig", Tag: "config", Summary: "通过 URL 更新直播间配置", Request: jsonObject{}},
+	{Method: "POST", Path: "/api/config/preview-template", ID: "previewOutputTmpl", Tag: "config", Summary: "预览输出模板生成的路径", Request: previewOutputTmplRequest{}},
+	{Method: "GET", Path: "/api/raw-config", ID: "getRawConfig", Tag: "config", Summary: "获取 YAML 原文配置", Response: rawConfig{}},
+	{Method: "PUT", Path: "/api/raw-config", ID: "putRawConfig", Tag: "config", Summary: "以 YAML 原文替换配置", Request: rawConfig{}, Response: commonResp{}},
+	{Method: "GET", Path: "/api/lives", ID: "getAllLives", Tag: "lives", Summary: "获取全部直播间", Response: []live.InfoJSON{}},
+	{Method: "POST", Path: "/api/lives", ID: "addLives", Tag: "lives", Summary: "添加直播间", Request: []addLiveRequest{}, Response: []live.InfoJSON{}},
+	{Method: "POST", Path: "/api/lives/batch", ID: "batchAddLives", Tag: "lives", Summary: "批量添加直播间（进度通过 SSE 推送）", Request: batchAddRequest{}, Response: batchAddResponse{}},
+	{Method: "GET", Path: "/api/lives/{id}", ID: "getLive", Tag: "lives", Summary: "获取直播间详情"},
+	{Method: "DELETE", Path: "/api/lives/{id}", ID: "removeLive", Tag: "lives", Summary: "删除直播间", Response: commonResp{}},
+	{Method: "GET", Path: "/api/lives/{id}/logs", ID: "getLiveLogs", Tag: "lives", Summary: "获取直播间日志",
+		Query: []apiQueryParam{{"lines", "integer", "返回最后多少行，默认 100"}}},
+	{Method: "GET", Path: "/api/lives/{id}/sessions", ID: "getLiveSessionHistory", Tag: "lives", Summary: "获取直播会话历史", Query: []apiQueryParam{limitParam}},
+	{Method: "GET", Path: "/api/lives/{id}/sessions/{session}/stats", ID: "getLiveSessionStats", Tag: "lives", Summary: "获取会话弹幕与营收统计",
+		Query: []apiQueryParam{{"top", "integer", "返回发言最多的用户数，默认 10"}}, Response: livestate.SessionChatStats{}},
+	{Method: "GET", Path: "/api/lives/{id}/name-history", ID: "getLiveNameHistory", Tag: "lives", Summary: "获取名称变更历史", Query: []apiQueryParam{limitParam}},
+	{Method: "GET", Path: "/api/lives/{id}/history", ID: "getLiveHistory", Tag: "lives", Summary: "获取直播间历史事件",
+		Query: []apiQueryParam{{"page", "integer", "页码，从 1 开始"}, {"page_size", "integer", "每页条数，最大 100"}, startTimeParam, endTimeParam}},
+	{Method: "POST", Path: "/api/lives/{id}/switchStream", ID: "switchStream", Tag: "lives", Summary: "切换流设置", Request: configs.ResolvedStreamPreference{}},
+	{Method: "POST", Path: "/api/lives/{id}/startRecord", ID: "startRecordDirect", Tag: "lives", Summary: "直接启动录制", Response: live.InfoJSON{}},
+	{Method: "POST", Path: "/api/lives/{id}/stopRecord", ID: "stopRecordDirect", Tag: "lives", Summary: "直接停止录制", Response: live.InfoJSON{}},
+	{Method: "GET", Path: "/api/lives/{id}/preview.flv", ID: "getLivePreview", Tag: "lives", Summary: "预览录制中的直播（HTTP-FLV，转发录制器接收的数据）", ContentType: "video/x-flv"},
+	{Method: "GET", Path: "/api/lives/{id}/preview.ts", ID: "getLiveHLSPreview", Tag: "lives", Summary: "预览录制中的 HLS 直播（连续的 MPEG-TS 流，转发录制器接收的分段）", ContentType: "video/mp2t"},
+	{Method: "GET", Path: "/api/lives/{id}/{action}", ID: "parseLiveAction", Tag: "lives", Summary: "直播间操作：start、stop、forceRefresh、segment", Response: live.InfoJSON{}},
+	{Method: "GET", Path: "/api/file/{path:.*}", ID: "getFileInfo", Tag: "files", Summary: "列出输出目录下的文件", Response: fileListResponse{}},
+	{Method: "PUT", Path: "/api/file/{path:.*}", ID: "renameFile", Tag: "files", Summary: "重命名文件", Request: renameFileRequest{}, Response: commonResp{}},
+	{Method: "DELETE", Path: "/api/file/{path:.*}", ID: "deleteFile", Tag: "files", Summary: "删除文件", Response: commonResp{}},
+	{Method: "GET", Path: "/api/files/search", ID: "searchFiles", Tag: "files", Summary: "按文件名搜索输出目录", Response: fileSearchResponse{},
+		Query: []apiQueryParam{{"q", "string", "文件名关键字（不区分大小写）"}, {"limit", "integer", "最多返回的条数，默认 100，最大 1000"}}},
+	{Method: "GET", Path: "/api/vod/info/{path:.*}", ID: "getVodInfo", Tag: "files", Summary: "获取录制文件的播放地址和同名弹幕字幕文件", Response: vodInfo{}},
+	{Method: "GET", Path: "/api/vod/stream/{path:.*}", ID: "getVodStream", Tag: "files", Summary: "播放录制文件（FLV/TS/MKV 转封装为 fragmented MP4：首次播放边转封装边输出，不支持 Range 与拖动，缓存后支持；同时转封装的任务数有限，超出时返回 503；正在录制的文件返回 409）", ContentType: "video/mp4"},
+	{Method: "PUT", Path: "/api/batch/file/rename", ID: "batchRenameFiles", Tag: "files", Summary: "批量重命名文件", Request: batchRenameRequest{}, Response: commonResp{}},
+	{Method: "POST", Path: "/api/batch/file/delete", ID: "batchDeleteFiles", Tag: "files", Summary: "批量删除文件", Request: batchDeleteRequest{}, Response: commonResp{}},
+	{Method: "GET", Path: "/api/cookies", ID: "getLiveHostCookie", Tag: "cookies", Summary: "获取各平台 Cookie", Response: []live.InfoCookie{}},
+	{Method: "PUT", Path: "/api/cookies", ID: "putLiveHostCookie", Tag: "cookies", Summary: "设置平台 Cookie", Request: live.InfoCookie{}, Response: commonResp{}},
+	{Method: "GET", Path: "/api/cookie-pools", ID: "getCookiePoolHealth", Tag: "cookies", Summary: "获取 Cookie 池健康状态"},
+	{Method: "GET", Path: "/api/secrets", ID: "listSecrets", Tag: "cookies", Summary: "列出密钥库条目"},
+	{Method: "PUT", Path: "/api/secrets/{name}", ID: "putSecret", Tag: "cookies", Summary: "写入密钥库条目", Request: putSecretRequest{}},
+	{Method: "DELETE", Path: "/api/secrets/{name}", ID: "deleteSecret", Tag: "cookies", Summary: "删除密钥库条目"},
+	{Method: "GET", Path: "/api/bilibili/qrcode", ID: "getBilibiliQRCode", Tag: "auth", Summary: "获取哔哩哔哩登录二维码"},
+	{Method: "GET", Path: "/api/bilibili/qrcode/poll", ID: "pollBilibiliQRCode", Tag: "auth", Summary: "轮询哔哩哔哩登录状态",
+		Query: []apiQueryParam{{"key", "string", "二维码 key"}}},
+	{Method: "POST", Path: "/api/bilibili/cookie/verify", ID: "verifyBilibiliCookie", Tag: "auth", Summary: "验证哔哩哔哩 Cookie", Request: cookieVerifyRequest{}},
+	{Method: "GET", Path: "/api/sooplive/auth", ID: "getSoopLiveAuthConfig", Tag: "auth", Summary: "获取 Soop 登录配置", Response: commonResp{}},
+	{Method: "DELETE", Path: "/api/sooplive/auth", ID: "clearSoopLiveAuthConfig", Tag: "auth", Summary: "清空 Soop 登录配置", Response: commonResp{}},
+	{Method: "POST", Path: "/api/sooplive/login", ID: "loginSoopLive", Tag: "auth", Summary: "Soop 账号登录", Request: soopLiveLoginRequest{}, Response: commonResp{}},
+	{Method: "POST", Path: "/api/sooplive/cookie/verify", ID: "verifySoopLiveCookie", Tag: "auth", Summary: "验证 Soop Cookie", Request: cookieVerifyRequest{}, Response: commonResp{}},
+	{Method: "GET", Path: "/api/sse", ID: "sse", Tag: "events", Summary: "SSE 实时推送", ContentType: "text/event-stream"},
+	{Method: "GET", Path: "/api/events/ws", ID: "eventsWS", Tag: "events", Summary: "对外事件流（WebSocket），消息格式见 docs/events.md",
+		Query: []apiQueryParam{{"types", "string", "逗号分隔的事件类型"}}, Status: http.StatusSwitchingProtocols},
+	{Method: "GET", Path: "/api/events", ID: "getEventLog", Tag: "events", Summary: "事件日志回放", Response: eventLogResponse{},
+		Query: []apiQueryParam{{"since", "integer", "只返回 ID 大于该值的事件"}, limitParam, {"types", "string", "逗号分隔的事件类型"}, {"live_id", "string", "直播间 ID"}}},
+	{Method: "POST", Path: "/api/graphql", ID: "graphQL", Tag: "graphql", Summary: "GraphQL 查询与变更，见 docs/graphql.md", Request: graphQLRequest{}},
+	{Method: "GET", Path: "/api/graphql", ID: "graphQLWS", Tag: "graphql", Summary: "GraphQL 订阅（WebSocket，graphql-transport-ws 协议）", Status: http.StatusSwitchingProtocols},
+	{Method: "GET", Path: "/api/graphql/schema", ID: "getGraphQLSchemaSDL", Tag: "graphql", Summary: "GraphQL schema", ContentType: "text/plain"},
+	{Method: "GET", Path: "/api/openapi.json", ID: "getOpenAPISpec", Tag: "system", Summary: "本 OpenAPI 文档"},
+	{Method: "GET", Path: "/api/cluster", ID: "getClusterStatus", Tag: "cluster", Summary: "获取本节点的集群状态", Response: cluster.Status{}},
+	{Method: "GET", Path: "/api/cluster/nodes", ID: "getClusterNodes", Tag: "cluster", Summary: "获取集群节点列表（仅 coordinator）", Response: []cluster.NodeStatus{}},
+	{Method: "GET", Path: "/api/cluster/lives", ID: "getClusterLives", Tag: "cluster", Summary: "获取跨节点汇总的直播间列表（仅 coordinator）", Response: []cluster.ClusterLive{}},
+	{Method: "POST", Path: "/api/cluster/heartbeat", ID: "clusterHeartbeat", Tag: "cluster", Summary: "worker 心跳，返回分配给该节点的直播间", Request: cluster.HeartbeatRequest{}, Response: cluster.HeartbeatResponse{}},
+	{Method: "GET", Path: "/api/webui/remote/status", ID: "getRemoteWebuiStatus", Tag: "system", Summary: "获取远程 WebUI 状态", Response: RemoteWebuiStatusResponse{}},
+	{Method: "GET", Path: "/api/webui/remote/check", ID: "checkRemoteWebuiUpdate", Tag: "system", Summary: "检查远程 WebUI 更新"},
+	{Method: "GET", Path: "/api/memory", ID: "getMemoryStats", Tag: "system", Summary: "获取内存统计信息"},
+	{Method: "GET", Path: "/api/update/check", ID: "checkUpdate", Tag: "update", Summary: "检查更新", Response: UpdateCheckResponse{},
+		Query: []apiQueryParam{{"prerelease", "boolean", "是否包含预发布版本"}}},
+	{Method: "GET", Path: "/api/update/latest", ID: "getLatestRelease", Tag: "update", Summary: "获取最新版本信息", Response: update.ReleaseInfo{},
+		Query: []apiQueryParam{{"prerelease", "boolean", "是否包含预发布版本"}}},
+	{Method: "POST", Path: "/api/update/download", ID: "downloadUpdate", Tag: "update", Summary: "下载更新（进度通过 SSE 推送）"},
+	{Method: "GET", Path: "/api/update/status", ID: "getUpdateStatus", Tag: "update", Summary: "获取更新状态", Response: UpdateStatusResponse{}},
+	{Method: "POST", Path: "/api/update/apply", ID: "applyUpdate", Tag: "update", Summary: "应用更新", Request: ApplyUpdateRequest{}},
+	{Method: "POST", Path: "/api/update/cancel", ID: "cancelUpdate", Tag: "update", Summary: "取消下载或等待中的更新"},
+	{Method: "PUT", Path: "/api/update/channel", ID: "setUpdateChannel", Tag: "update", Summary: "设置更新通道", Request: updateChannelRequest{}},
+	{Method: "GET", Path: "/api/update/launcher", ID: "getLauncherStatus", Tag: "update", Summary: "获取启动器状态"},
+	{Method: "GET", Path: "/api/update/rollback", ID: "getRollbackInfo", Tag: "update", Summary: "获取回滚信息"},
+	{Method: "POST", Path: "/api/update/rollback", ID: "doRollback", Tag: "update", Summary: "执行回滚"},
+	{Method: "GET", Path: "/api/metrics", ID: "metrics", Tag: "system", Summary: "Prometheus 指标", ContentType: "text/plain"},
+	{Method: "GET", Path: "/api/iostats", ID: "getIOStats", Tag: "iostats", Summary: "获取 IO 统计", Response: commonResp{},
+		Query: []apiQueryParam{{"stat_types", "string", "逗号分隔的统计类型"}, startTimeParam, endTimeParam, {"live_id", "string", "直播间 ID"}, {"platform", "string", "平台"}, {"aggregation", "string", "聚合粒度"}}},
+	{Method: "GET", Path: "/api/iostats/requests", ID: "getRequestStatus", Tag: "iostats", Summary: "获取请求状态统计", Response: commonResp{},
+		Query: []apiQueryParam{startTimeParam, endTimeParam, {"live_id", "string", "直播间 ID"}, {"platform", "string", "平台"}, {"view_mode", "string", "视图模式"}}},
+	{Method: "GET", Path: "/api/iostats/filters", ID: "getIOStatsFilters", Tag: "iostats", Summary: "获取 IO 统计筛选器选项", Response: commonResp{}},
+	{Method: "GET", Path: "/api/iostats/disk", ID: "getDiskIOStats", Tag: "iostats", Summary: "获取系统磁盘 I/O 统计", Response: commonResp{},
+		Query: []apiQueryParam{startTimeParam, endTimeParam, {"device", "string", "磁盘设备"}}},
+	{Method: "GET", Path: "/api/iostats/devices", ID: "getDiskDevices", Tag: "iostats", Summary: "获取磁盘设备列表", Response: commonResp{}},
+	{Method: "GET", Path: "/api/iostats/memory", ID: "getMemoryStatsHistory", Tag: "iostats", Summary: "获取内存统计历史", Response: commonResp{},
+		Query: []apiQueryParam{startTimeParam, endTimeParam, {"categories", "string", "逗号分隔的内存类别"}, {"aggregation", "string", "聚合粒度"}}},
+	{Method: "GET", Path: "/api/iostats/memory/categories", ID: "getMemoryCategories", Tag: "iostats", Summary: "获取内存类别列表", Response: commonResp{}},
+	{Method: "GET", Path: "/api/memory/snapshots", ID: "getMemorySnapshots", Tag: "system", Summary: "获取内存快照"},
+	{Method: "GET", Path: "/api/ffmpeg/status", ID: "getFFmpegStatus", Tag: "system", Summary: "获取 FFmpeg 就绪状态", Response: tools.FFmpegStatus{}},
+	{Method: "POST", Path: "/api/ffmpeg/retry", ID: "retryFFmpeg", Tag: "system", Summary: "重试 FFmpeg 检测与下载"},
+	{Method: "GET", Path: "/api/openlist/status", ID: "getOpenListStatus", Tag: "system", Summary: "获取 OpenList 状态", Response: OpenListStatusResponse{}},
+	{Method: "GET", Path: "/api/openlist/check-storage", ID: "checkOpenListStorageHealth", Tag: "system", Summary: "检查 OpenList 存储健康状态",
+		Response: OpenListStorageHealthResponse{}, Query: []apiQueryParam{{"name", "string", "存储名称"}}},
+
+	// 后处理任务，见 RegisterPipelineHandlers
+	{Method: "GET", Path: "/api/pipeline/tasks", ID: "listPipelineTasks", Tag: "pipeline", Summary: "列出后处理任务", Response: []pipeline.PipelineTask{},
+		Query: []apiQueryParam{{"status", "string", "任务状态"}, {"live_id", "string", "直播间 ID"}, limitParam, {"offset", "integer", "偏移量"}}},
+	{Method: "GET", Path: "/api/pipeline/tasks/stats", ID: "getPipelineStats", Tag: "pipeline", Summary: "获取队列统计", Response: pipeline.ManagerStats{}},
+	{Method: "POST", Path: "/api/pipeline/tasks/clear-completed", ID: "clearCompletedPipelineTasks", Tag: "pipeline", Summary: "清除已完成的任务"},
+	{Method: "GET", Path: "/api/pipeline/tasks/{id}", ID: "getPipelineTask", Tag: "pipeline", Summary: "获取任务", Response: pipeline.PipelineTask{}},
+	{Method: "POST", Path: "/api/pipeline/tasks/{id}/cancel", ID: "cancelPipelineTask", Tag: "pipeline", Summary: "取消任务"},
+	{Method: "POST", Path: "/api/pipeline/tasks/{id}/retry", ID: "retryPipelineTask", Tag: "pipeline", Summary: "重试任务"},
+	{Method: "DELETE", Path: "/api/pipeline/tasks/{id}", ID: "deletePipelineTask", Tag: "pipeline", Summary: "删除任务"},
+	{Method: "POST", Path: "/api/pipeline/batch-burn", ID: "batchBurn", Tag: "pipeline", Summary: "批量烧录弹幕字幕", Request: batchBurnRequest{}, Response: batchBurnResponse{}},
+
+	// OSRP 开放直播录制协议，见 RegisterOSRPRoutes；成功响应包装在 OSRPResponse.data 中
+	{Method: "GET", Path: "/osrp/v1/info", ID: "osrpGetInfo", Tag: "osrp", Summary: "服务信息", Response: OSRPServiceInfo{}},
+	{Method: "GET", Path: "/osrp/v1/capabilities", ID: "osrpGetCapabilities", Tag: "osrp", Summary: "能力声明", Response: OSRPCapabilities{}},
+	{Method: "GET", Path: "/osrp/v1/tasks", ID: "osrpGetTasks", Tag: "osrp", Summary: "任务列表", Response: OSRPTaskListResponse{}},
+	{Method: "POST", Path: "/osrp/v1/tasks", ID: "osrpAddTask", Tag: "osrp", Summary: "添加任务", Request: OSRPAddTaskRequest{}, Response: OSRPTaskInfo{}, Status: http.StatusCreated},
+	{Method: "GET", Path: "/osrp/v1/tasks/{id}", ID: "osrpGetTask", Tag: "osrp", Summary: "获取任务", Response: OSRPTaskInfo{}},
+	{Method: "DELETE", Path: "/osrp/v1/tasks/{id}", ID: "osrpDeleteTask", Tag: "osrp", Summary: "删除任务", Response: map[string]bool{}},
+	{Method: "POST", Path: "/osrp/v1/tasks/{id}/actions", ID: "osrpTaskAction", Tag: "osrp", Summary: "执行任务操作", Request: OSRPTaskActionRequest{}, Response: map[string]string{}},
+	{Method: "POST", Path: "/osrp/v1/resolve", ID: "osrpResolve", Tag: "osrp", Summary: "解析直播间地址", Request: OSRPResolveRequest{}, Response: OSRPResolveResponse{}},
+	{Method: "GET", Path: "/osrp/v1/streams/{platform}/{id}/status", ID: "osrpGetStreamStatus", Tag: "osrp", Summary: "获取直播状态", Response: OSRPStreamStatusResponse{}},
+	{Method: "GET", Path: "/osrp/v1/streams/{platform}/{id}/urls", ID: "osrpGetStreamURLs", Tag: "osrp", Summary: "获取直播流地址", Response: OSRPStreamURLsResponse{}},
+	{Method: "POST", Path: "/osrp/v1/probe", ID: "osrpProbe", Tag: "osrp", Summary: "探测直播流", Request: OSRPProbeRequest{}, Response: OSRPProbeResponse{}},
+}
+
+// routeVarPattern 匹配 mux 路由模板中的变量，如 {id} 或 {path:.*}
+var routeVarPattern = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// openAPIPath 把 mux 路由模板转换为 OpenAPI 路径，并返回其中的路径参数名
+func openAPIPath(muxPath string) (string, []string) {
+	var params []string
+	path := routeVarPattern.ReplaceAllStringFunc(muxPath, func(m string) string {
+		name := routeVarPattern.FindStringSubmatch(m)[1]
+		params = append(params, name)
+		return "{" + name + "}"
+	})
+	return path, params
+}
+
+// openAPISchemaGenerator 按 encoding/json 的规则从 Go 类型生成 schema，具名结构体放入 components
+type openAPISchemaGenerator struct {
+	gen     *openapi3gen.Generator
+	schemas openapi3.Schemas
+	names   map[string]reflect.Type
+}
+
+func newOpenAPISchemaGenerator() *openAPISchemaGenerator {
+	g := &openAPISchemaGenerator{
+		schemas: openapi3.Schemas{},
+		names:   map[string]reflect.Type{},
+	}
+	g.gen = openapi3gen.NewGenerator(
+		openapi3gen.UseAllExportedFields(),
+		// 没有 json 标签的字段按 encoding/json 的规则使用字段名（而不是 yaml 标签）
+		openapi3gen.CreateFieldNameGenerator(func(field reflect.StructField, defaultName string) string {
+			if _, ok := field.Tag.Lookup("json"); !ok {
+				return field.Name
+			}
+			return defaultName
+		}),
+		openapi3gen.CreateTypeNameGenerator(g.typeName),
+		openapi3gen.CreateComponentSchemas(openapi3gen.ExportComponentSchemasOptions{
+			ExportComponentSchemas: true,
+			ExportTopLevelSchema:   true,
+		}),
+	)
+	return g
+}
+
+// typeName 生成 components 中的名称：首字母大写，不同包的同名类型加包名前缀
+func (g *openAPISchemaGenerator) typeName(t reflect.Type) string {
+	name := t.Name()
+	if name == "" {
+		return ""
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToUpper(runes[0])
+	name = string(runes)
+	if existing, ok := g.names[name]; ok && existing != t {
+		pkg := t.PkgPath()
+		pkg = pkg[strings.LastIndex(pkg, "/")+1:]
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	g.names[name] = t
+	return name
+}
+
+func (g *openAPISchemaGenerator) schemaRef(value any) (*openapi3.SchemaRef, error) {
+	return g.gen.NewSchemaRefForValue(value, g.schemas)
+}
+
+// buildOpenAPISpec 根据 apiOperations 生成 OpenAPI 3 文档
+func buildOpenAPISpec() (*openapi3.T, error) {
+	version := consts.AppVersion
+	if version == "" {
+		version = "dev"
+	}
+	spec := &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info: &openapi3.Info{
+			Title:       "bililive-go API",
+			Description: "bililive-go 的 REST API（/api）与 OSRP 开放直播录制协议（/osrp/v1）。",
+			Version:     version,
+		},
+		Paths: openapi3.NewPaths(),
+		Components: &openapi3.Components{
+			SecuritySchemes: openapi3.SecuritySchemes{
+				"bearerAuth": &openapi3.SecuritySchemeRef{Value: &openapi3.SecurityScheme{
+					Type:        "http",
+					Scheme:      "bearer",
+					Description: "配置了 rpc.token 时需要携带，也可以使用 ?token= 查询参数",
+				}},
+			},
+		},
+		// 未配置 rpc.token 时无需鉴权
+		Security: openapi3.SecurityRequirements{
+			openapi3.NewSecurityRequirement().Authenticate("bearerAuth"),
+			openapi3.NewSecurityRequirement(),
+		},
+	}
+
+	g := newOpenAPISchemaGenerator()
+	commonRespRef, err := g.schemaRef(commonResp{})
+	if err != nil {
+		return nil, err
+	}
+	osrpRespRef, err := g.schemaRef(OSRPResponse{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range apiOperations {
+		path, params := openAPIPath(op.Path)
+		operation := &openapi3.Operation{
+			OperationID: op.ID,
+			Tags:        []string{op.Tag},
+			Summary:     op.Summary,
+			Responses:   openapi3.NewResponses(),
+		}
+		for _, name := range params {
+			operation.AddParameter(openapi3.NewPathParameter(name).WithRequired(true).WithSchema(openapi3.NewStringSchema()))
+		}
+		for _, q := range op.Query {
+			operation.AddParameter(openapi3.NewQueryParameter(q.Name).
+				WithDescription(q.Description).
+				WithSchema(&openapi3.Schema{Type: &openapi3.Types{q.Type}}))
+		}
+
+		if op.Request != nil {
+			ref, err := g.schemaRef(op.Request)
+			if err != nil {
+				return nil, err
+			}
+			operation.RequestBody = &openapi3.RequestBodyRef{
+				Value: openapi3.NewRequestBody().WithRequired(true).WithJSONSchemaRef(ref),
+			}
+		}
+
+		isOSRP := strings.HasPrefix(op.Path, "/osrp/")
+		var content openapi3.Content
+		switch {
+		case op.ContentType != "":
+			content = openapi3.NewContentWithSchema(openapi3.NewStringSchema(), []string{op.ContentType})
+		case op.Status == http.StatusSwitchingProtocols:
+		default:
+			schema := openapi3.NewSchemaRef("", &openapi3.Schema{})
+			if op.Response != nil {
+				if schema, err = g.schemaRef(op.Response); err != nil {
+					return nil, err
+				}
+			}
+			if isOSRP {
+				// OSRP 的成功响应包装在 data 字段中
+				schema = openapi3.NewSchemaRef("", &openapi3.Schema{AllOf: openapi3.SchemaRefs{
+					osrpRespRef,
+					openapi3.NewSchemaRef("", openapi3.NewObjectSchema().WithPropertyRef("data", schema)),
+				}})
+			}
+			content = openapi3.NewContentWithJSONSchemaRef(schema)
+		}
+		status := op.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		description := http.StatusText(status)
+		operation.AddResponse(status, &openapi3.Response{Description: &description, Content: content})
+
+		errorRef := commonRespRef
+		if isOSRP {
+			errorRef = osrpRespRef
+		}
+		errorDescription := "错误"
+		operation.Responses.Set("default", &openapi3.ResponseRef{Value: &openapi3.Response{
+			Description: &errorDescription,
+			Content:     openapi3.NewContentWithJSONSchemaRef(errorRef),
+		}})
+
+		spec.AddOperation(path, op.Method, operation)
+	}
+	spec.Components.Schemas = g.schemas
+	return spec, nil
+}
+
+var (
+	openAPISpecOnce sync.Once
+	openAPISpecJSON []byte
+	openAPISpecErr  error
+)
+
+// getOpenAPISpec 返回 OpenAPI 3 文档（JSON）
+func getOpenAPISpec(writer http.ResponseWriter, r *http.Request) {
+	openAPISpecOnce.Do(func() {
+		var spec *openapi3.T
+		if spec, openAPISpecErr = buildOpenAPISpec(); openAPISpecErr == nil {
+			openAPISpecJSON, openAPISpecErr = spec.MarshalJSON()
+		}
+	})
+	if openAPISpecErr != nil {
+		writeJsonWithStatusCode(writer, http.StatusInternalServerError, commonResp{
+			ErrNo:  http.StatusInternalServerError,
+			ErrMsg: "生成 OpenAPI 文档失败: " + openAPISpecErr.Error(),
+		})
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Header().Set("Content-Length", strconv.Itoa(len(openAPISpecJSON)))
+	writer.Write(openAPISpecJSON)
+}
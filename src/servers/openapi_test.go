@@ -0,0 +1,101 @@
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/instance"
+	"github.com/bililive-go/bililive-go/src/pipeline"
+)
+
+// openAPIDocPath 提交到仓库的 OpenAPI 文档，接口变化会体现在该文件的 diff 中。
+// 修改接口后运行 UPDATE_OPENAPI=1 go test ./src/servers -run TestOpenAPIDocUpToDate 重新生成
+const openAPIDocPath = "../../docs/openapi.json"
+
+func marshalOpenAPISpec(t *testing.T) []byte {
+	spec, err := buildOpenAPISpec()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	b, err := json.MarshalIndent(spec, "", "  ")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return append(b, '\n')
+}
+
+func TestOpenAPISpecValid(t *testing.T) {
+	loader := openapi3.NewLoader()
+	spec, err := loader.LoadFromData(marshalOpenAPISpec(t))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, spec.Validate(context.Background()))
+
+	ids := make(map[string]bool)
+	for _, op := range apiOperations {
+		assert.False(t, ids[op.ID], "operationId 重复: %s", op.ID)
+		ids[op.ID] = true
+	}
+}
+
+func TestOpenAPICoversAllRoutes(t *testing.T) {
+	configs.SetCurrentConfig(configs.NewConfig())
+	defer configs.SetCurrentConfig(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	inst := &instance.Instance{PipelineManager: &pipeline.Manager{}}
+	ctx = context.WithValue(ctx, instance.Key, inst)
+
+	var registered []string
+	err := initMux(ctx).Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		// 子路由器本身没有 handler
+		if err != nil || route.GetHandler() == nil || !(strings.HasPrefix(path, apiRouterPrefix+"/") || strings.HasPrefix(path, "/osrp/")) {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			// 未限定方法的路由（如 /api/metrics）按 GET 处理
+			methods = []string{"GET"}
+		}
+		for _, method := range methods {
+			if method != "OPTIONS" {
+				registered = append(registered, method+" "+path)
+			}
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+
+	var documented []string
+	for _, op := range apiOperations {
+		documented = append(documented, op.Method+" "+op.Path)
+	}
+	sort.Strings(registered)
+	sort.Strings(documented)
+	assert.Equal(t, registered, documented, "apiOperations 与注册的路由不一致")
+}
+
+func TestOpenAPIDocUpToDate(t *testing.T) {
+	generated := marshalOpenAPISpec(t)
+	if os.Getenv("UPDATE_OPENAPI") != "" {
+		assert.NoError(t, os.WriteFile(openAPIDocPath, generated, 0644))
+		return
+	}
+	committed, err := os.ReadFile(openAPIDocPath)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, string(committed), string(generated),
+		"docs/openapi.json 已过期，请运行 UPDATE_OPENAPI=1 go test ./src/servers -run TestOpenAPIDocUpToDate")
+}
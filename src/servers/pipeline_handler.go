@@ -214,12 +214,22 @@ func isVideoFile(name string) bool {
 	return false
 }
 
+// isRecordingFile 判断文件是否为录制产物：视频或仅音频录制保存的音频文件
+func isRecordingFile(name string) bool {
+	if isVideoFile(name) {
+		return true
+	}
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".aac", ".m4a", ".mp3":
+		return true
+	}
+	return false
+}
+
 // makeBatchBurnHandler 批量烧录弹幕字幕
 func makeBatchBurnHandler(pm *pipeline.Manager) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var req struct {
-			Paths []string `json:"paths"`
-		}
+		var req batchBurnRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			http.Error(w, "invalid request body", http.StatusBadRequest)
 			return
@@ -268,12 +278,7 @@ func makeBatchBurnHandler(pm *pipeline.Manager) http.HandlerFunc {
 
 		outputPath := config.OutPutPath
 
-		type batchResult struct {
-			Enqueued int      `json:"enqueued"`
-			Skipped  []string `json:"skipped"`
-			TaskIDs  []int64  `json:"task_ids"`
-		}
-		result := batchResult{
+		result := batchBurnResponse{
 			Skipped: []string{},
 		}
 
@@ -65,6 +65,7 @@ func initMux(ctx context.Context) *mux.Router {
 			)
 		})
 	} /* , log */)
+	m.Use(tokenAuth)
 
 	// api router
 	apiRoute := m.PathPrefix(apiRouterPrefix).Subrouter()
@@ -89,19 +90,29 @@ func initMux(ctx context.Context) *mux.Router {
 	apiRoute.HandleFunc("/lives/{id}", removeLive).Methods("DELETE")
 	apiRoute.HandleFunc("/lives/{id}/logs", getLiveLogs).Methods("GET")
 	apiRoute.HandleFunc("/lives/{id}/sessions", getLiveSessionHistory).Methods("GET")    // 获取直播会话历史
+	apiRoute.HandleFunc("/lives/{id}/sessions/{session}/stats", getLiveSessionStats).Methods("GET") // 获取会话弹幕与营收统计
 	apiRoute.HandleFunc("/lives/{id}/name-history", getLiveNameHistory).Methods("GET")   // 获取名称变更历史
 	apiRoute.HandleFunc("/lives/{id}/history", getLiveHistory).Methods("GET")            // 获取统一历史事件（支持分页筛选）
 	apiRoute.HandleFunc("/lives/{id}/switchStream", switchStreamHandler).Methods("POST") // 切换流设置（需要请求体，必须在通配符之前）
 	apiRoute.HandleFunc("/lives/{id}/startRecord", startRecordDirect).Methods("POST")   // 直接启动录制（适用于 NotifyOnly 房间）
 	apiRoute.HandleFunc("/lives/{id}/stopRecord", stopRecordDirect).Methods("POST")     // 直接停止录制
+	apiRoute.HandleFunc("/lives/{id}/preview.flv", getLivePreview).Methods("GET")       // 录制中直播的 HTTP-FLV 预览
+	apiRoute.HandleFunc("/lives/{id}/preview.ts", getLiveHLSPreview).Methods("GET")     // 录制中 HLS 直播的 MPEG-TS 预览
 	apiRoute.HandleFunc("/lives/{id}/{action}", parseLiveAction).Methods("GET")          // 通配符路由必须放在最后
 	apiRoute.HandleFunc("/file/{path:.*}", getFileInfo).Methods("GET")
 	apiRoute.HandleFunc("/file/{path:.*}", renameFile).Methods("PUT")
 	apiRoute.HandleFunc("/file/{path:.*}", deleteFile).Methods("DELETE")
+	apiRoute.HandleFunc("/files/search", searchFiles).Methods("GET") // 按文件名搜索输出目录
+	apiRoute.HandleFunc("/vod/info/{path:.*}", getVodInfo).Methods("GET")     // 录制文件点播信息与同名弹幕字幕
+	apiRoute.HandleFunc("/vod/stream/{path:.*}", getVodStream).Methods("GET") // 播放录制文件（FLV/TS/MKV 转封装为 MP4）
 	apiRoute.HandleFunc("/batch/file/rename", batchRenameFiles).Methods("PUT")
 	apiRoute.HandleFunc("/batch/file/delete", batchDeleteFiles).Methods("POST")
 	apiRoute.HandleFunc("/cookies", getLiveHostCookie).Methods("GET")
 	apiRoute.HandleFunc("/cookies", putLiveHostCookie).Methods("PUT")
+	apiRoute.HandleFunc("/cookie-pools", getCookiePoolHealth).Methods("GET")
+	apiRoute.HandleFunc("/secrets", listSecrets).Methods("GET")
+	apiRoute.HandleFunc("/secrets/{name}", putSecret).Methods("PUT")
+	apiRoute.HandleFunc("/secrets/{name}", deleteSecret).Methods("DELETE")
 
 	// Bilibili Login
 	apiRoute.HandleFunc("/bilibili/qrcode", getBilibiliQRCode).Methods("GET")
@@ -111,7 +122,17 @@ func initMux(ctx context.Context) *mux.Router {
 	apiRoute.HandleFunc("/sooplive/auth", clearSoopLiveAuthConfig).Methods("DELETE")
 	apiRoute.HandleFunc("/sooplive/login", loginSoopLive).Methods("POST")
 	apiRoute.HandleFunc("/sooplive/cookie/verify", verifySoopLiveCookie).Methods("POST")
-	apiRoute.HandleFunc("/sse", sseHandler).Methods("GET") // SSE 实时推送端点
+	apiRoute.HandleFunc("/sse", sseHandler).Methods("GET")                     // SSE 实时推送端点
+	apiRoute.HandleFunc("/events/ws", eventsWSHandler).Methods("GET")          // 对外事件流（WebSocket）
+	apiRoute.HandleFunc("/events", getEventLog).Methods("GET")                 // 事件日志回放（?since=<id>）
+	apiRoute.HandleFunc("/graphql", graphQLHandler).Methods("GET", "POST")     // GraphQL 管理 API（GET 仅用于 WebSocket 订阅）
+	apiRoute.HandleFunc("/graphql/schema", getGraphQLSchemaSDL).Methods("GET") // GraphQL schema
+	apiRoute.HandleFunc("/openapi.json", getOpenAPISpec).Methods("GET")        // OpenAPI 3 文档
+	// 多节点集群
+	apiRoute.HandleFunc("/cluster", getClusterStatus).Methods("GET")
+	apiRoute.HandleFunc("/cluster/nodes", getClusterNodes).Methods("GET")
+	apiRoute.HandleFunc("/cluster/lives", getClusterLives).Methods("GET")             // 跨节点汇总的直播间列表
+	apiRoute.HandleFunc("/cluster/heartbeat", clusterHeartbeat).Methods("POST")       // worker 心跳
 	// 远程 WebUI 路由
 	apiRoute.HandleFunc("/webui/remote/status", getRemoteWebuiStatus).Methods("GET")  // 获取远程 WebUI 状态
 	apiRoute.HandleFunc("/webui/remote/check", checkRemoteWebuiUpdate).Methods("GET") // 检查远程 WebUI 更新
@@ -505,9 +505,7 @@ func setUpdateChannel(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req struct {
-		Channel string `json:"channel"` // "stable" or "prerelease"
-	}
+	var req updateChannelRequest
 	if err := json.Unmarshal(body, &req); err != nil {
 		writeJsonWithStatusCode(w, http.StatusBadRequest, commonResp{
 			ErrNo:  http.StatusBadRequest,
@@ -0,0 +1,412 @@
+package servers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/instance"
+	applog "github.com/bililive-go/bililive-go/src/log"
+	"github.com/bililive-go/bililive-go/src/pkg/recordmeta"
+	bilisentry "github.com/bililive-go/bililive-go/src/pkg/sentry"
+	"github.com/bililive-go/bililive-go/src/pkg/utils"
+	"github.com/bililive-go/bililive-go/src/recorders"
+)
+
+const (
+	// vodCacheTTL 转封装缓存超过该时间未被访问即删除
+	vodCacheTTL = 24 * time.Hour
+	// vodCacheMaxSize 转封装缓存总大小上限，超出时按最近访问时间淘汰
+	vodCacheMaxSize int64 = 20 << 30
+	// vodTailInterval 转封装进行中时，追读临时文件的轮询间隔
+	vodTailInterval = 200 * time.Millisecond
+	// vodMaxRemux 同时进行的转封装任务上限，避免大量播放请求同时启动 FFmpeg
+	vodMaxRemux = 2
+)
+
+// vodRemuxExts 浏览器无法直接播放、需要转封装为 MP4 的录制格式
+var vodRemuxExts = map[string]bool{".flv": true, ".ts": true, ".mkv": true}
+
+// vodDanmaku 与视频同名的弹幕字幕文件，路径相对输出目录，可通过 /files/ 访问
+type vodDanmaku struct {
+	Ass string `json:"ass,omitempty"`
+	Srt string `json:"srt,omitempty"`
+	Vtt string `json:"vtt,omitempty"`
+}
+
+// vodInfo 录制文件的点播信息
+type vodInfo struct {
+	Path         string     `json:"path"`
+	Name         string     `json:"name"`
+	Size         int64      `json:"size"`
+	LastModified int64      `json:"last_modified"`
+	StreamURL    string     `json:"stream_url"`
+	Remux        bool       `json:"remux"`
+	Cached       bool       `json:"cached"`
+	Danmaku      vodDanmaku `json:"danmaku"`
+	// Storyboard 拖动预览缩略图，Sprite 与 VTT 为相对输出目录的路径
+	Storyboard *recordmeta.Storyboard `json:"storyboard,omitempty"`
+}
+
+// vodRemuxJob 一个正在进行的转封装任务，FFmpeg 写入 partPath，完成后重命名为缓存文件
+type vodRemuxJob struct {
+	partPath string
+	done     chan struct{}
+	err      error
+}
+
+// errVodRecording 文件正在录制中，大小持续变化，无法转封装缓存
+var errVodRecording = errors.New("文件正在录制中，请在录制结束后播放")
+
+var (
+	vodJobsMu sync.Mutex
+	vodJobs   = make(map[string]*vodRemuxJob)
+	// vodRemuxSlots 转封装任务占用的名额，容量为 vodMaxRemux
+	vodRemuxSlots = make(chan struct{}, vodMaxRemux)
+)
+
+// openVodFile 校验 path 为输出目录中的视频文件，且不是正在录制的文件
+func openVodFile(ctx context.Context, path string) (string, os.FileInfo, error) {
+	absPath, err := getSafePath(configs.GetCurrentConfig().OutPutPath, path)
+	if err != nil {
+		return "", nil, errors.New("无效或越权路径")
+	}
+	info, err := os.Stat(absPath)
+	if err != nil || info.IsDir() {
+		return "", nil, errors.New("文件不存在")
+	}
+	if !isVideoFile(absPath) {
+		return "", nil, errors.New("不是视频文件")
+	}
+	if inst := instance.GetInstance(ctx); inst != nil {
+		if rm, ok := inst.RecorderManager.(recorders.Manager); ok && rm.IsRecordingFile(absPath) {
+			return "", nil, errVodRecording
+		}
+	}
+	return absPath, info, nil
+}
+
+// writeVodFileError 返回 openVodFile 的错误，正在录制的文件返回 409
+func writeVodFileError(writer http.ResponseWriter, err error) {
+	code := http.StatusNotFound
+	if errors.Is(err, errVodRecording) {
+		code = http.StatusConflict
+	}
+	writeJsonWithStatusCode(writer, code, commonResp{
+		ErrNo:  code,
+		ErrMsg: err.Error(),
+	})
+}
+
+// vodCachePath 转封装缓存文件路径，源文件大小或修改时间变化后缓存失效
+func vodCachePath(absPath string, info os.FileInfo) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s|%d|%d", absPath, info.Size(), info.ModTime().UnixNano())))
+	dir := filepath.Join(configs.GetCurrentConfig().AppDataPath, "vod_cache")
+	return filepath.Join(dir, hex.EncodeToString(sum[:10])+".mp4")
+}
+
+// findVodDanmaku 查找与视频同名的弹幕字幕文件
+func findVodDanmaku(path, absPath string) vodDanmaku {
+	var d vodDanmaku
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	absBase := strings.TrimSuffix(absPath, filepath.Ext(absPath))
+	for ext, field := range map[string]*string{".ass": &d.Ass, ".srt": &d.Srt, ".vtt": &d.Vtt} {
+		if _, err := os.Stat(absBase + ext); err == nil {
+			*field = base + ext
+		}
+	}
+	return d
+}
+
+// findVodStoryboard 读取后处理写入录制元数据的故事板，文件已不存在时忽略
+func findVodStoryboard(path, absPath string) *recordmeta.Storyboard {
+	meta, err := recordmeta.Load(absPath)
+	if err != nil || meta == nil || meta.Storyboard == nil {
+		return nil
+	}
+	board := *meta.Storyboard
+	dir := filepath.Dir(absPath)
+	for _, name := range []string{board.Sprite, board.VTT} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return nil
+		}
+	}
+	relDir := ""
+	if i := strings.LastIndex(filepath.ToSlash(path), "/"); i >= 0 {
+		relDir = filepath.ToSlash(path)[:i+1]
+	}
+	board.Sprite = relDir + board.Sprite
+	board.VTT = relDir + board.VTT
+	return &board
+}
+
+// escapeURLPath 逐段转义相对路径，保留分隔符 /
+func escapeURLPath(path string) string {
+	segments := strings.Split(filepath.ToSlash(path), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// getVodInfo 返回录制文件的播放地址和同名弹幕字幕文件
+func getVodInfo(writer http.ResponseWriter, r *http.Request) {
+	path := mux.Vars(r)["path"]
+	absPath, info, err := openVodFile(r.Context(), path)
+	if err != nil {
+		writeVodFileError(writer, err)
+		return
+	}
+	resp := vodInfo{
+		Path:         path,
+		Name:         info.Name(),
+		Size:         info.Size(),
+		LastModified: info.ModTime().Unix(),
+		StreamURL:    "api/vod/stream/" + escapeURLPath(path),
+		Remux:        vodRemuxExts[strings.ToLower(filepath.Ext(absPath))],
+		Danmaku:      findVodDanmaku(path, absPath),
+		Storyboard:   findVodStoryboard(path, absPath),
+	}
+	if resp.Remux {
+		if _, err := os.Stat(vodCachePath(absPath, info)); err == nil {
+			resp.Cached = true
+		}
+	}
+	writeJSON(writer, resp)
+}
+
+// getVodStream 播放录制文件。MP4 等格式直接返回并支持 Range；
+// FLV/TS/MKV 由 FFmpeg 转封装为 fragmented MP4，首次播放边转封装边输出，此时不支持 Range，
+// 无法拖动到尚未转封装的位置；完成后缓存，之后的请求支持 Range 拖动
+func getVodStream(writer http.ResponseWriter, r *http.Request) {
+	absPath, info, err := openVodFile(r.Context(), mux.Vars(r)["path"])
+	if err != nil {
+		writeVodFileError(writer, err)
+		return
+	}
+	if !vodRemuxExts[strings.ToLower(filepath.Ext(absPath))] {
+		http.ServeFile(writer, r, absPath)
+		return
+	}
+
+	cachePath := vodCachePath(absPath, info)
+	if serveVodCache(writer, r, cachePath) {
+		return
+	}
+	job, err := startVodRemux(r.Context(), absPath, cachePath, info.Size())
+	if err != nil {
+		writeJsonWithStatusCode(writer, http.StatusServiceUnavailable, commonResp{
+			ErrNo:  http.StatusServiceUnavailable,
+			ErrMsg: err.Error(),
+		})
+		return
+	}
+	// 任务可能在加锁前刚好完成
+	if serveVodCache(writer, r, cachePath) {
+		return
+	}
+	tailVodRemux(writer, r, job, cachePath)
+}
+
+// serveVodCache 缓存存在时返回缓存文件并刷新访问时间
+func serveVodCache(writer http.ResponseWriter, r *http.Request, cachePath string) bool {
+	f, err := os.Open(cachePath)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	now := time.Now()
+	os.Chtimes(cachePath, now, now)
+	writer.Header().Set("Content-Type", "video/mp4")
+	http.ServeContent(writer, r, filepath.Base(cachePath), info.ModTime(), f)
+	return true
+}
+
+// startVodRemux 启动转封装任务，同一文件已在转封装时返回已有任务
+func startVodRemux(ctx context.Context, absPath, cachePath string, sourceSize int64) (*vodRemuxJob, error) {
+	vodJobsMu.Lock()
+	defer vodJobsMu.Unlock()
+	if job, ok := vodJobs[cachePath]; ok {
+		return job, nil
+	}
+	ffmpegPath, err := utils.GetFFmpegPath(ctx)
+	if err != nil {
+		return nil, errors.New("FFmpeg 不可用，无法转封装播放")
+	}
+	select {
+	case vodRemuxSlots <- struct{}{}:
+	default:
+		return nil, errors.New("转封装任务过多，请稍后再试")
+	}
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		<-vodRemuxSlots
+		return nil, err
+	}
+	// 转封装后的文件大小与源文件接近，预留出空间
+	pruneVodCache(filepath.Dir(cachePath), sourceSize)
+
+	job := &vodRemuxJob{partPath: cachePath + ".part", done: make(chan struct{})}
+	// 先创建文件，读者可以立即打开追读
+	f, err := os.Create(job.partPath)
+	if err != nil {
+		<-vodRemuxSlots
+		return nil, err
+	}
+	f.Close()
+	vodJobs[cachePath] = job
+
+	// 不随请求取消：观众断开后继续完成转封装，之后的播放直接命中缓存
+	bilisentry.Go(func() {
+		job.err = runVodRemux(ffmpegPath, absPath, job.partPath)
+		if job.err == nil {
+			job.err = os.Rename(job.partPath, cachePath)
+		}
+		if job.err != nil {
+			applog.GetLogger().Warnf("转封装 %s 失败: %v", absPath, job.err)
+		}
+		vodJobsMu.Lock()
+		delete(vodJobs, cachePath)
+		vodJobsMu.Unlock()
+		<-vodRemuxSlots
+		close(job.done)
+		if job.err != nil {
+			os.Remove(job.partPath)
+		}
+	})
+	return job, nil
+}
+
+func runVodRemux(ffmpegPath, absPath, output string) error {
+	cmd := exec.Command(ffmpegPath,
+		"-hide_banner", "-loglevel", "error", "-y",
+		"-i", absPath,
+		"-map", "0:v?", "-map", "0:a?",
+		"-c", "copy",
+		"-movflags", "frag_keyframe+empty_moov+default_base_moof",
+		"-f", "mp4", output,
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// tailVodRemux 追读正在写入的转封装文件输出给客户端，直到转封装结束
+func tailVodRemux(writer http.ResponseWriter, r *http.Request, job *vodRemuxJob, cachePath string) {
+	f, err := os.Open(job.partPath)
+	if err != nil {
+		// 临时文件在打开前已完成转封装并重命名为缓存文件
+		if serveVodCache(writer, r, cachePath) {
+			return
+		}
+		writeJsonWithStatusCode(writer, http.StatusServiceUnavailable, commonResp{
+			ErrNo:  http.StatusServiceUnavailable,
+			ErrMsg: "转封装失败",
+		})
+		return
+	}
+	defer f.Close()
+
+	writer.Header().Set("Content-Type", "video/mp4")
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.WriteHeader(http.StatusOK)
+	flusher, _ := writer.(http.Flusher)
+	buf := make([]byte, 256*1024)
+	finished := false
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			if _, werr := writer.Write(buf[:n]); werr != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			continue
+		}
+		if err != nil && err != io.EOF {
+			return
+		}
+		if finished {
+			if job.err != nil {
+				// 转封装中途失败：中断连接而不是正常结束响应，让播放器得知数据不完整
+				panic(http.ErrAbortHandler)
+			}
+			return
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-job.done:
+			// 任务结束后再读一次，取走最后写入的数据
+			finished = true
+		case <-time.After(vodTailInterval):
+		}
+	}
+}
+
+// pruneVodCache 删除长时间未访问的缓存和残留的临时文件，
+// 并按最近访问时间淘汰缓存，使总大小加上 reserve 不超过 vodCacheMaxSize
+func pruneVodCache(dir string, reserve int64) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	type cacheFile struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []cacheFile
+	var total int64
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if time.Since(info.ModTime()) > vodCacheTTL {
+			os.Remove(path)
+			continue
+		}
+		total += info.Size()
+		// 进行中的转封装临时文件不参与淘汰
+		if !strings.HasSuffix(entry.Name(), ".part") {
+			files = append(files, cacheFile{path: path, size: info.Size(), modTime: info.ModTime()})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for _, f := range files {
+		if total+reserve <= vodCacheMaxSize {
+			break
+		}
+		if os.Remove(f.path) == nil {
+			total -= f.size
+		}
+	}
+}
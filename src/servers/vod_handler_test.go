@@ -0,0 +1,121 @@
+package servers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bililive-go/bililive-go/src/configs"
+	"github.com/bililive-go/bililive-go/src/pkg/recordmeta"
+)
+
+func TestGetVodInfoPairsSidecars(t *testing.T) {
+	dir := t.TempDir()
+	cfg := configs.NewConfig()
+	cfg.OutPutPath = dir
+	cfg.AppDataPath = filepath.Join(dir, ".appdata")
+	configs.SetCurrentConfig(cfg)
+
+	room := filepath.Join(dir, "主播 A")
+	assert.NoError(t, os.MkdirAll(room, 0755))
+	for _, name := range []string{"rec #1.flv", "rec #1.ass", "rec #1.vtt", "other.srt", "rec #1.storyboard.jpg", "rec #1.storyboard.vtt"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(room, name), []byte("x"), 0644))
+	}
+	board := recordmeta.Storyboard{Sprite: "rec #1.storyboard.jpg", VTT: "rec #1.storyboard.vtt", Interval: 10, Count: 3, Columns: 3, Width: 160, Height: 90}
+	assert.NoError(t, recordmeta.Update(filepath.Join(room, "rec #1.flv"), func(m *recordmeta.Meta) { m.Storyboard = &board }))
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/vod/info/{path:.*}", getVodInfo)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/vod/info/%E4%B8%BB%E6%92%AD%20A/rec%20%231.flv", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var info vodInfo
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &info))
+	assert.Equal(t, "api/vod/stream/%E4%B8%BB%E6%92%AD%20A/rec%20%231.flv", info.StreamURL)
+	assert.True(t, info.Remux)
+	assert.False(t, info.Cached)
+	assert.Equal(t, vodDanmaku{Ass: "主播 A/rec #1.ass", Vtt: "主播 A/rec #1.vtt"}, info.Danmaku)
+	// 故事板路径转换为相对输出目录
+	if assert.NotNil(t, info.Storyboard) {
+		assert.Equal(t, "主播 A/rec #1.storyboard.jpg", info.Storyboard.Sprite)
+		assert.Equal(t, "主播 A/rec #1.storyboard.vtt", info.Storyboard.VTT)
+		assert.Equal(t, 3, info.Storyboard.Count)
+	}
+
+	// 非视频文件和越权路径返回 404
+	for _, path := range []string{"主播 A/other.srt", "../etc/passwd"} {
+		rec = httptest.NewRecorder()
+		req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/", nil), map[string]string{"path": path})
+		getVodInfo(rec, req)
+		assert.Equal(t, http.StatusNotFound, rec.Code, path)
+	}
+}
+
+func TestTailVodRemuxFallsBackToCache(t *testing.T) {
+	dir := t.TempDir()
+	cachePath := filepath.Join(dir, "cache.mp4")
+	assert.NoError(t, os.WriteFile(cachePath, []byte("mp4 data"), 0644))
+	// 临时文件已被重命名为缓存文件
+	job := &vodRemuxJob{partPath: cachePath + ".part", done: make(chan struct{})}
+	close(job.done)
+
+	rec := httptest.NewRecorder()
+	tailVodRemux(rec, httptest.NewRequest(http.MethodGet, "/", nil), job, cachePath)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "mp4 data", rec.Body.String())
+
+	os.Remove(cachePath)
+	rec = httptest.NewRecorder()
+	tailVodRemux(rec, httptest.NewRequest(http.MethodGet, "/", nil), job, cachePath)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestTailVodRemuxAbortsOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	job := &vodRemuxJob{partPath: filepath.Join(dir, "cache.mp4.part"), done: make(chan struct{}), err: errors.New("ffmpeg exited")}
+	assert.NoError(t, os.WriteFile(job.partPath, []byte("partial"), 0644))
+	close(job.done)
+
+	// 转封装中途失败时中断连接，而不是以 200 正常结束被截断的响应
+	rec := httptest.NewRecorder()
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		tailVodRemux(rec, httptest.NewRequest(http.MethodGet, "/", nil), job, filepath.Join(dir, "cache.mp4"))
+	})
+	assert.Equal(t, "partial", rec.Body.String())
+}
+
+func TestPruneVodCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	unit := vodCacheMaxSize / 4
+	for i, name := range []string{"old.mp4", "mid.mp4", "new.mp4", "job.mp4.part"} {
+		path := filepath.Join(dir, name)
+		f, err := os.Create(path)
+		assert.NoError(t, err)
+		assert.NoError(t, f.Truncate(unit))
+		f.Close()
+		modTime := now.Add(time.Duration(i-4) * time.Minute)
+		assert.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	expired := filepath.Join(dir, "expired.mp4")
+	assert.NoError(t, os.WriteFile(expired, []byte("x"), 0644))
+	assert.NoError(t, os.Chtimes(expired, now.Add(-vodCacheTTL-time.Hour), now.Add(-vodCacheTTL-time.Hour)))
+
+	// 为新的转封装预留两份空间，最早访问的两个缓存被淘汰，进行中的临时文件保留
+	pruneVodCache(dir, 2*unit)
+	for _, name := range []string{"expired.mp4", "old.mp4", "mid.mp4"} {
+		assert.NoFileExists(t, filepath.Join(dir, name))
+	}
+	for _, name := range []string{"new.mp4", "job.mp4.part"} {
+		assert.FileExists(t, filepath.Join(dir, name))
+	}
+}
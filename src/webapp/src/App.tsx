@@ -12,6 +12,7 @@ import UpdateBanner from './component/update-banner/index';
 import FFmpegBanner from './component/ffmpeg-banner/index';
 import UpdatePage from './component/update-page/index';
 import DanmakuSettings from './component/danmaku-config/index';
+import ClusterPage from './component/cluster-page/index';
 
 const App: React.FC = () => {
   return (
@@ -25,6 +26,7 @@ const App: React.FC = () => {
           <Route path="/tasks/*" element={<TaskPage />} />
           <Route path="/fileList/*" element={<FileList />} />
           <Route path="/danmaku" element={<DanmakuSettings />} />
+          <Route path="/cluster" element={<ClusterPage />} />
           <Route path="/configInfo/*" element={<ConfigInfo />} />
           <Route path="/liveInfo" element={<LiveInfo />} />
           <Route path="/" element={<LiveList />} />
@@ -0,0 +1,242 @@
+import React from 'react';
+import { Table, Tag, Card, Alert, Descriptions, Space, Button } from 'antd';
+import type { ColumnsType } from 'antd/es/table';
+import { ReloadOutlined } from '@ant-design/icons';
+import API from '../../utils/api';
+
+const api = new API();
+
+// 自动刷新间隔（毫秒）
+const REFRESH_INTERVAL = 5000;
+
+// 节点上报的直播间状态
+interface LiveStatus {
+    url: string;
+    live_id: string;
+    platform: string;
+    host_name: string;
+    room_name: string;
+    living: boolean;
+    listening: boolean;
+    recording: boolean;
+}
+
+// 集群节点
+interface NodeStatus {
+    id: string;
+    advertise_url?: string;
+    version: string;
+    local: boolean;
+    alive: boolean;
+    last_seen: string;
+    assigned_count: number;
+    lives: LiveStatus[];
+}
+
+// 汇总视图中的直播间
+interface ClusterLive {
+    url: string;
+    listening: boolean;
+    node: string;
+    node_alive: boolean;
+    status?: LiveStatus;
+}
+
+// 本节点的集群状态
+interface ClusterStatus {
+    mode: string;
+    node_id: string;
+    coordinator_url?: string;
+    last_heartbeat?: string;
+    last_error?: string;
+    assigned_count: number;
+}
+
+interface Props {
+}
+
+interface State {
+    status?: ClusterStatus;
+    nodes: NodeStatus[];
+    lives: ClusterLive[];
+    loading: boolean;
+}
+
+class ClusterPage extends React.Component<Props, State> {
+    private timer?: ReturnType<typeof setInterval>;
+
+    constructor(props: Props) {
+        super(props);
+        this.state = {
+            nodes: [],
+            lives: [],
+            loading: false
+        };
+    }
+
+    componentDidMount() {
+        this.refresh();
+        this.timer = setInterval(this.refresh, REFRESH_INTERVAL);
+    }
+
+    componentWillUnmount() {
+        if (this.timer) {
+            clearInterval(this.timer);
+        }
+    }
+
+    refresh = async () => {
+        this.setState({ loading: true });
+        try {
+            const status = await api.getClusterStatus() as ClusterStatus;
+            if (status.mode === 'coordinator') {
+                const [nodes, lives] = await Promise.all([api.getClusterNodes(), api.getClusterLives()]) as [NodeStatus[], ClusterLive[]];
+                this.setState({ status, nodes: nodes || [], lives: lives || [] });
+            } else {
+                this.setState({ status, nodes: [], lives: [] });
+            }
+        } catch (err) {
+            console.error('获取集群状态失败:', err);
+        } finally {
+            this.setState({ loading: false });
+        }
+    };
+
+    nodeColumns: ColumnsType<NodeStatus> = [
+        {
+            title: '节点',
+            dataIndex: 'id',
+            render: (id: string, node: NodeStatus) => (
+                <Space>
+                    {node.advertise_url ? <a href={node.advertise_url} target="_blank" rel="noopener noreferrer">{id}</a> : id}
+                    {node.local && <Tag color="blue">coordinator</Tag>}
+                </Space>
+            )
+        },
+        {
+            title: '状态',
+            dataIndex: 'alive',
+            render: (alive: boolean) => alive ? <Tag color="green">在线</Tag> : <Tag color="red">离线</Tag>
+        },
+        {
+            title: '分配的直播间',
+            dataIndex: 'assigned_count'
+        },
+        {
+            title: '录制中',
+            render: (_: unknown, node: NodeStatus) => (node.lives || []).filter(l => l.recording).length
+        },
+        {
+            title: '版本',
+            dataIndex: 'version'
+        },
+        {
+            title: '最后心跳',
+            dataIndex: 'last_seen',
+            render: (t: string) => new Date(t).toLocaleString()
+        }
+    ];
+
+    liveColumns: ColumnsType<ClusterLive> = [
+        {
+            title: '主播',
+            render: (_: unknown, live: ClusterLive) => live.status?.host_name || '-'
+        },
+        {
+            title: '直播间',
+            dataIndex: 'url',
+            render: (url: string, live: ClusterLive) => (
+                <a href={url} target="_blank" rel="noopener noreferrer">{live.status?.room_name || url}</a>
+            )
+        },
+        {
+            title: '节点',
+            dataIndex: 'node',
+            render: (node: string, live: ClusterLive) => {
+                if (!live.listening) {
+                    return <Tag>未监控</Tag>;
+                }
+                if (!node) {
+                    return <Tag color="orange">无可用节点</Tag>;
+                }
+                return <Tag color={live.node_alive ? 'geekblue' : 'red'}>{node}</Tag>;
+            }
+        },
+        {
+            title: '状态',
+            render: (_: unknown, live: ClusterLive) => {
+                const s = live.status;
+                if (!live.listening || !s) {
+                    return '-';
+                }
+                if (s.recording) {
+                    return <Tag color="red">录制中</Tag>;
+                }
+                if (s.living) {
+                    return <Tag color="green">直播中</Tag>;
+                }
+                return s.listening ? <Tag color="blue">监控中</Tag> : <Tag color="orange">等待分配</Tag>;
+            }
+        }
+    ];
+
+    renderWorker(status: ClusterStatus) {
+        return (
+            <Card>
+                <Descriptions column={1} bordered size="small">
+                    <Descriptions.Item label="运行模式">worker</Descriptions.Item>
+                    <Descriptions.Item label="节点">{status.node_id}</Descriptions.Item>
+                    <Descriptions.Item label="Coordinator">
+                        <a href={status.coordinator_url} target="_blank" rel="noopener noreferrer">{status.coordinator_url}</a>
+                    </Descriptions.Item>
+                    <Descriptions.Item label="最后心跳">
+                        {status.last_heartbeat ? new Date(status.last_heartbeat).toLocaleString() : '-'}
+                    </Descriptions.Item>
+                    <Descriptions.Item label="分配的直播间">{status.assigned_count}</Descriptions.Item>
+                </Descriptions>
+                {status.last_error && <Alert style={{ marginTop: 16 }} type="error" showIcon message={`心跳失败：${status.last_error}`} />}
+                <Alert style={{ marginTop: 16 }} type="info" showIcon message="集群汇总视图请在 coordinator 的 Web 界面中查看" />
+            </Card>
+        );
+    }
+
+    render() {
+        const { status, nodes, lives, loading } = this.state;
+        let content: React.ReactNode = null;
+        if (status && status.mode === 'coordinator') {
+            content = (
+                <Space direction="vertical" style={{ width: '100%' }} size="large">
+                    <Card title="节点" size="small">
+                        <Table rowKey="id" columns={this.nodeColumns} dataSource={nodes} pagination={false} size="small" />
+                    </Card>
+                    <Card title="直播间" size="small">
+                        <Table rowKey="url" columns={this.liveColumns} dataSource={lives} pagination={{ pageSize: 50 }} size="small" />
+                    </Card>
+                </Space>
+            );
+        } else if (status && status.mode === 'worker') {
+            content = this.renderWorker(status);
+        } else if (status) {
+            content = <Alert type="info" showIcon message="当前以单机模式运行" description="在配置文件的 cluster 段设置 mode 为 coordinator 或 worker 后重启即可组成集群，详见 docs/cluster.md" />;
+        }
+        return (
+            <div>
+                <div style={{
+                    padding: '16px 24px',
+                    backgroundColor: '#fff',
+                    borderBottom: '1px solid #e8e8e8',
+                    marginBottom: 16,
+                    display: 'flex',
+                    justifyContent: 'space-between',
+                    alignItems: 'center'
+                }}>
+                    <span style={{ fontSize: '20px', fontWeight: 600, color: 'rgba(0,0,0,0.85)' }}>集群</span>
+                    <Button icon={<ReloadOutlined />} loading={loading} onClick={this.refresh}>刷新</Button>
+                </div>
+                {content}
+            </div>
+        );
+    }
+}
+
+export default ClusterPage;
@@ -28,9 +28,9 @@ import CloudUploadSettings from './CloudUploadSettings';
 const api = new API();
 const { TextArea } = Input;
 
-// 功能开关：代理配置（开发中，设为 false 隐藏 UI）
+// 功能开关：代理配置（设为 false 隐藏 UI）
 // 与后端 configs.EnableProxyConfig 对应
-const ENABLE_PROXY_CONFIG = false;
+const ENABLE_PROXY_CONFIG = true;
 const ENABLE_CLOUD_UPLOAD_SETTINGS = false;
 const { Panel } = Collapse;
 
@@ -51,6 +51,11 @@ interface EffectiveConfig {
   rpc: { enable: boolean; bind: string };
   debug: boolean;
   interval: number;
+  adaptive_interval?: {
+    enable: boolean;
+    min_interval?: number;
+    max_interval?: number;
+  };
   out_put_path: string;
   actual_out_put_path: string;
   ffmpeg_path: string;
@@ -457,6 +462,33 @@ const GlobalSettings: React.FC<{
               <InputNumber min={1} max={3600} style={{ width: 200 }} />
             </Form.Item>
           </ConfigField>
+          <ConfigField
+            label="自适应检测间隔"
+            description="根据主播历史开播时间调整检测频率：临近常规开播时间时加快，很少开播的时段放慢（直播中使用检测间隔）"
+            valueDisplay={config.adaptive_interval?.enable ? '已启用' : '已禁用'}
+          >
+            <Form.Item name={['adaptive_interval', 'enable']} valuePropName="checked" noStyle>
+              <Switch />
+            </Form.Item>
+          </ConfigField>
+          <ConfigField
+            label="自适应最短间隔 (秒)"
+            description="临近常规开播时间时使用的检测间隔"
+            valueDisplay={config.adaptive_interval?.min_interval ?? 10}
+          >
+            <Form.Item name={['adaptive_interval', 'min_interval']} noStyle>
+              <InputNumber min={1} max={3600} style={{ width: 200 }} />
+            </Form.Item>
+          </ConfigField>
+          <ConfigField
+            label="自适应最长间隔 (秒)"
+            description="历史上很少开播的时段使用的检测间隔"
+            valueDisplay={config.adaptive_interval?.max_interval ?? 120}
+          >
+            <Form.Item name={['adaptive_interval', 'max_interval']} noStyle>
+              <InputNumber min={1} max={3600} style={{ width: 200 }} />
+            </Form.Item>
+          </ConfigField>
           <ConfigField
             label="输出路径"
             description="录制文件的保存目录"
@@ -680,6 +712,16 @@ const GlobalSettings: React.FC<{
               <Switch />
             </Form.Item>
           </ConfigField>
+          <ConfigField label="生成拖动预览缩略图" description="按固定间隔截取缩略图拼成雪碧图，并生成 WebVTT 缩略图轨道，在线播放时拖动进度条可预览画面">
+            <Form.Item name={['on_record_finished', 'generate_storyboard']} valuePropName="checked" noStyle>
+              <Switch />
+            </Form.Item>
+          </ConfigField>
+          <ConfigField label="缩略图间隔 (秒)" description="录制较长时会自动加大间隔，单张雪碧图最多 400 帧">
+            <Form.Item name={['on_record_finished', 'storyboard_interval']} noStyle>
+              <InputNumber min={1} style={{ width: 200 }} />
+            </Form.Item>
+          </ConfigField>
         </Card>
 
         {/* 云盘上传设置（开发中） */}
@@ -716,7 +758,7 @@ const GlobalSettings: React.FC<{
           </ConfigField>
         </Card>
 
-        {/* 代理设置（功能开关控制，开发中） */}
+        {/* 代理设置（功能开关控制） */}
         {ENABLE_PROXY_CONFIG && (
           <Card title="代理设置" size="small" style={{ marginBottom: 16 }}>
             <ConfigField
@@ -737,6 +779,15 @@ const GlobalSettings: React.FC<{
                 <Input placeholder="例如: socks5://127.0.0.1:1080 或 http://127.0.0.1:7890" style={{ width: 400 }} />
               </Form.Item>
             </ConfigField>
+            <ConfigField
+              label="代理池"
+              description="使用配置文件 proxy_pools 中定义的代理池（自动故障切换），设置后优先于通用代理地址"
+              valueDisplay={config.proxy?.pool || '(未设置)'}
+            >
+              <Form.Item name={['proxy', 'pool']} noStyle>
+                <Input placeholder="代理池名称" style={{ width: 400 }} />
+              </Form.Item>
+            </ConfigField>
 
             <Divider style={{ margin: '12px 0', fontSize: 12 }}>专用代理（可选覆盖）</Divider>
 
@@ -1693,6 +1744,21 @@ export const RoomConfigForm: React.FC<{
         </Form.Item>
       </ConfigField>
 
+      <ConfigField
+        label="转推地址"
+        description="录制时同时转推到这些地址，支持 rtmp://、rtmps://、srt://，也可填写密钥库引用 secret://名称；已保存的推流码显示为 ***，保持不变即沿用原值；断开后自动重连；需要 FFmpeg"
+      >
+        <Form.Item name="restream_targets" noStyle>
+          <Select
+            mode="tags"
+            tokenSeparators={[' ', '\n']}
+            open={false}
+            style={{ width: 480 }}
+            placeholder="rtmp://live.example.com/app/推流码"
+          />
+        </Form.Item>
+      </ConfigField>
+
       <Divider style={{ margin: '12px 0' }}>配置覆盖</Divider>
 
       <ConfigField
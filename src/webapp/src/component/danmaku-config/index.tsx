@@ -22,6 +22,14 @@ const DEFAULT_DANMAKU: DanmakuConfig = {
   record_super_chat: true,
   guard_position: 'bottom-left',
   sc_position: 'bottom-left',
+  stream_delay_ms: 0,
+  block_keywords: [],
+  block_patterns: [],
+  block_users: [],
+  fold_window: 0,
+  max_per_second: 0,
+  min_user_level: 0,
+  subtitle_formats: [],
 };
 
 interface DanmakuConfig {
@@ -39,6 +47,14 @@ interface DanmakuConfig {
   record_super_chat: boolean;
   guard_position: string;
   sc_position: string;
+  stream_delay_ms: number;
+  block_keywords: string[];
+  block_patterns: string[];
+  block_users: string[];
+  fold_window: number;
+  max_per_second: number;
+  min_user_level: number;
+  subtitle_formats: string[];
 }
 
 interface EffectiveConfig {
@@ -52,6 +68,8 @@ interface EffectiveConfig {
     burn_subtitles_preset?: string;
     burn_delete_ass?: boolean;
     burn_delete_source?: boolean;
+    extract_highlights?: boolean;
+    highlight_count?: number;
   };
 }
 
@@ -205,7 +223,70 @@ const DanmakuParamForm: React.FC<{
           rules={[{ type: 'number', min: 0, max: 255, message: '0~255' }]}>
           <InputNumber min={0} max={255} style={{ width: '100%' }} />
         </Form.Item>
+        <Form.Item
+          label={<span>直播流延迟 <span style={{ fontWeight: 400, fontSize: 12, color: '#999' }}>弹幕相对画面提前出现时调大，正值使弹幕整体后移</span></span>}
+          name={['danmaku', 'stream_delay_ms']}
+          rules={[{ type: 'number', min: -60000, max: 60000, message: '-60000~60000' }]}>
+          <InputNumber min={-60000} max={60000} step={100} style={{ width: '100%' }} addonAfter="毫秒" />
+        </Form.Item>
       </div>
+      <Form.Item
+        label={<span>附加字幕格式 <span style={{ fontWeight: 400, fontSize: 12, color: '#999' }}>ASS 始终输出；SRT/WebVTT 以聊天框形式显示在左下角，适合网页和手机播放器</span></span>}
+        name={['danmaku', 'subtitle_formats']}>
+        <Select mode="multiple" allowClear placeholder="仅输出 ASS" options={[
+          { value: 'srt', label: 'SRT' },
+          { value: 'vtt', label: 'WebVTT' },
+        ]} />
+      </Form.Item>
+
+      <Collapse
+        defaultActiveKey={[]}
+        size="small"
+        style={{ marginBottom: 16 }}
+        items={[{
+          key: 'filter',
+          label: '过滤与密度控制',
+          children: (
+            <>
+              <Form.Item
+                label={<span>屏蔽关键词 <span style={{ fontWeight: 400, fontSize: 12, color: '#999' }}>包含任一关键词的弹幕不写入字幕，输入后回车添加</span></span>}
+                name={['danmaku', 'block_keywords']}>
+                <Select mode="tags" open={false} tokenSeparators={[',', '，']} placeholder="例如：加群" />
+              </Form.Item>
+              <Form.Item
+                label={<span>屏蔽正则 <span style={{ fontWeight: 400, fontSize: 12, color: '#999' }}>匹配任一正则表达式的弹幕不写入字幕</span></span>}
+                name={['danmaku', 'block_patterns']}>
+                <Select mode="tags" open={false} placeholder="例如：^\d{6,}$" />
+              </Form.Item>
+              <Form.Item
+                label={<span>屏蔽用户 <span style={{ fontWeight: 400, fontSize: 12, color: '#999' }}>按用户名屏蔽</span></span>}
+                name={['danmaku', 'block_users']}>
+                <Select mode="tags" open={false} tokenSeparators={[',', '，']} />
+              </Form.Item>
+              <div style={{ display: 'grid', gridTemplateColumns: '1fr 1fr 1fr', gap: '0 24px' }}>
+                <Form.Item
+                  label={<span>重复合并 <span style={{ fontWeight: 400, fontSize: 12, color: '#999' }}>窗口内相同弹幕合并为一条，0 不合并</span></span>}
+                  name={['danmaku', 'fold_window']}
+                  rules={[{ type: 'number', min: 0, max: 60, message: '0~60' }]}>
+                  <InputNumber min={0} max={60} style={{ width: '100%' }} addonAfter="秒" />
+                </Form.Item>
+                <Form.Item
+                  label={<span>每秒上限 <span style={{ fontWeight: 400, fontSize: 12, color: '#999' }}>SC 与上舰不受限，0 不限制</span></span>}
+                  name={['danmaku', 'max_per_second']}
+                  rules={[{ type: 'number', min: 0, max: 200, message: '0~200' }]}>
+                  <InputNumber min={0} max={200} style={{ width: '100%' }} addonAfter="条" />
+                </Form.Item>
+                <Form.Item
+                  label={<span>最低用户等级 <span style={{ fontWeight: 400, fontSize: 12, color: '#999' }}>仅哔哩哔哩、斗鱼生效，0 不限制</span></span>}
+                  name={['danmaku', 'min_user_level']}
+                  rules={[{ type: 'number', min: 0, message: '不能为负数' }]}>
+                  <InputNumber min={0} style={{ width: '100%' }} />
+                </Form.Item>
+              </div>
+            </>
+          ),
+        }]}
+      />
 
       {showBilibiliContent && (
         <Collapse
@@ -326,11 +407,17 @@ const DanmakuParamForm: React.FC<{
   );
 };
 
-// 支持弹幕录制的平台
+// 支持弹幕录制的平台。标记为实验性的平台的协议解析按公开的协议格式实现，
+// 尚未用真实服务器数据验证
 const DANMAKU_PLATFORMS: Record<string, string> = {
   bilibili: '哔哩哔哩',
   douyin: '抖音',
   douyu: '斗鱼',
+  huya: '虎牙（实验性）',
+  kuaishou: '快手（实验性）',
+  acfun: 'AcFun（实验性）',
+  twitch: 'Twitch（实验性）',
+  sooplive: 'SOOP（实验性）',
 };
 
 const DanmakuSettings: React.FC = () => {
@@ -339,6 +426,7 @@ const DanmakuSettings: React.FC = () => {
   const [config, setConfig] = useState<EffectiveConfig | null>(null);
   const [platformRooms, setPlatformRooms] = useState<Record<string, RoomInfo[]>>({});
   const [burnForm] = Form.useForm();
+  const [highlightForm] = Form.useForm();
 
   const loadData = useCallback(async () => {
     setLoading(true);
@@ -386,6 +474,16 @@ const DanmakuSettings: React.FC = () => {
     }
   }, [config, burnForm]);
 
+  // 设置高光剪辑表单初始值
+  useEffect(() => {
+    if (config?.on_record_finished) {
+      highlightForm.setFieldsValue({
+        extract_highlights: config.on_record_finished.extract_highlights ?? false,
+        highlight_count: config.on_record_finished.highlight_count || 5,
+      });
+    }
+  }, [config, highlightForm]);
+
   const handleSaveGlobal = async (values: any) => {
     setSaving(true);
     try {
@@ -435,6 +533,29 @@ const DanmakuSettings: React.FC = () => {
     }
   };
 
+  const handleSaveHighlightSettings = async () => {
+    try {
+      const values = await highlightForm.validateFields();
+      setSaving(true);
+      await api.updateConfig({
+        on_record_finished: {
+          extract_highlights: values.extract_highlights,
+          highlight_count: values.highlight_count,
+        },
+      });
+      message.success('高光剪辑配置已保存');
+      await loadData();
+    } catch (error: any) {
+      if (error?.errorFields) {
+        message.error('表单校验失败，请检查输入项');
+      } else {
+        message.error('保存失败: ' + (error?.message || '未知错误'));
+      }
+    } finally {
+      setSaving(false);
+    }
+  };
+
   const handleResetBurnSettings = async () => {
     burnForm.setFieldsValue(DEFAULT_BURN);
     setSaving(true);
@@ -602,6 +723,33 @@ const DanmakuSettings: React.FC = () => {
         </Form>
       </Card>
 
+      <Card title="高光片段剪辑" size="small" style={{ marginBottom: 16 }}>
+        <Form form={highlightForm} layout="vertical" initialValues={{ extract_highlights: false, highlight_count: 5 }}>
+          <div style={{ display: 'grid', gridTemplateColumns: '1fr 1fr', gap: '0 24px' }}>
+            <Form.Item
+              label="剪辑高光片段"
+              name="extract_highlights"
+              valuePropName="checked"
+              extra="根据弹幕密度和礼物活跃度识别高光，录制结束后按关键帧无损剪辑（需要开启弹幕录制）"
+            >
+              <Switch />
+            </Form.Item>
+            <Form.Item
+              label="片段数量"
+              name="highlight_count"
+              extra="每个录制文件最多剪辑的片段数，按得分从高到低选取"
+            >
+              <InputNumber min={1} max={10} style={{ width: '100%' }} />
+            </Form.Item>
+          </div>
+          <Form.Item style={{ marginBottom: 0 }}>
+            <Button type="primary" onClick={handleSaveHighlightSettings} loading={saving}>
+              保存高光设置
+            </Button>
+          </Form.Item>
+        </Form>
+      </Card>
+
       {Object.keys(DANMAKU_PLATFORMS).map((platformKey) => {
         const rooms = platformRooms[platformKey];
         if (!rooms || rooms.length === 0) return null;
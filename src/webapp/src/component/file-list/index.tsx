@@ -479,6 +479,8 @@ type CurrentFolderFile = {
     last_modified: number;
     size: number;
     subtitle_file?: string;
+    srt_file?: string;
+    vtt_file?: string;
 }
 
 const FileList: React.FC = () => {
@@ -611,6 +613,11 @@ const FileList: React.FC = () => {
         return path.split("/").map(p => encodeURIComponent(p)).join("/");
     };
 
+    /**
+     * 将 files/ 下的静态文件地址转换为服务端转封装播放地址（fragmented MP4）。
+     */
+    const toVodStreamUrl = (url: string): string => url.replace(/^files\//, "api/vod/stream/");
+
     /**
      * 对路径进行双重 URL 编码，专门用于 HashRouter 导航。
      * 因为 HashRouter 会将路径中的第一个 # 视为路由分隔符，
@@ -964,9 +971,11 @@ const FileList: React.FC = () => {
                     artRef.current.destroy(true);
                 }
 
+                // MKV 浏览器无法直接播放，由服务端转封装为 MP4
+                const isMkv = fullPath.toLowerCase().endsWith('.mkv');
                 const art = new Artplayer({
                     container: '#art-container',
-                    url: `files/${encodePath(fullPath)}`,
+                    url: isMkv ? `api/vod/stream/${encodePath(fullPath)}` : `files/${encodePath(fullPath)}`,
                     title: record.name,
                     volume: 0.7,
                     autoplay: true,
@@ -998,7 +1007,8 @@ const FileList: React.FC = () => {
                                     flvPlayer.destroy();
                                 });
                             } else {
-                                art.notice.show = "不支持播放格式: flv";
+                                // 不支持 MSE 的浏览器（如 iOS Safari）改用服务端转封装的 MP4
+                                video.src = toVodStreamUrl(url);
                             }
                         },
                         ts: function (video, url, art) {
@@ -1015,13 +1025,28 @@ const FileList: React.FC = () => {
                                     tsPlayer.destroy();
                                 });
                             } else {
-                                art.notice.show = "不支持播放格式: mpegts";
+                                video.src = toVodStreamUrl(url);
                             }
                         },
                     },
                 });
                 artRef.current = art;
 
+                // 后处理生成了故事板时，进度条悬停显示对应时间的缩略图
+                api.getVodInfo(encodePath(fullPath))
+                    .then((info: any) => {
+                        const board = info?.storyboard;
+                        if (!board || artRef.current !== art) return;
+                        art.option.thumbnails = {
+                            url: `files/${encodePath(board.sprite)}`,
+                            number: board.count,
+                            column: board.columns,
+                            width: board.width,
+                            height: board.height,
+                        };
+                    })
+                    .catch(() => { /* 没有故事板时不显示缩略图 */ });
+
                 // 弹幕渲染集成（仅在 loadDanmaku 为 true 时加载）
                 if (loadDanmaku && record.subtitle_file) {
                     // 使用 API 返回的 subtitle_file 字段构造 URL，而非推导
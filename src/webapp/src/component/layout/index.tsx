@@ -13,7 +13,8 @@ import {
     LineChartOutlined,
     CloudUploadOutlined,
     CalendarOutlined,
-    CommentOutlined
+    CommentOutlined,
+    ClusterOutlined
 } from '@ant-design/icons';
 import './layout.css';
 
@@ -139,6 +140,11 @@ class RootLayout extends React.Component<Props, State> {
                                         icon: <UnorderedListOutlined />,
                                         label: <Link to="/tasks">任务队列</Link>,
                                     },
+                                    {
+                                        key: 'cluster',
+                                        icon: <ClusterOutlined />,
+                                        label: <Link to="/cluster">集群</Link>,
+                                    },
                                     {
                                         key: 'scheduler',
                                         icon: <CalendarOutlined />,
@@ -6,6 +6,7 @@ import BatchAddRoomDialog from '../batch-add-room-dialog/index';
 import LogPanel from '../log-panel/index';
 import HistoryPanel from '../history-panel/index';
 import DanmakuPanel, { DanmakuMessage } from '../danmaku-panel/index';
+import LivePreview from '../live-preview/index';
 import API from '../../utils/api';
 import { subscribeSSE, unsubscribeSSE, SSEMessage } from '../../utils/sse';
 import { isListSSEEnabled, setListSSEEnabled, getPollIntervalMs } from '../../utils/settings';
@@ -1962,6 +1963,10 @@ class LiveList extends React.Component<Props, IState> {
                                 const newMsgs = { ...prevState.danmakuMessages };
                                 delete newTabs[liveId];
                                 delete newMsgs[liveId];
+                                if (key === 'preview') {
+                                    // 预览仅在 Tab 激活时连接，切走即断开，不占用预览名额
+                                    newTabs[liveId] = 'preview';
+                                }
                                 return { expandedActiveTabs: newTabs, danmakuMessages: newMsgs };
                             });
                         }
@@ -2034,6 +2039,15 @@ class LiveList extends React.Component<Props, IState> {
                             )}
                         </div>
                     </Tabs.TabPane>
+                    <Tabs.TabPane tab="画面预览" key="preview">
+                        {this.state.expandedActiveTabs[liveId] === 'preview' && detail?.recording ? (
+                            <LivePreview liveId={liveId} format={detail?.recorder_status?.preview_format === 'ts' ? 'ts' : 'flv'} />
+                        ) : (
+                            <div style={{ padding: '40px 0', textAlign: 'center', color: '#999' }}>
+                                录制开始后可预览画面
+                            </div>
+                        )}
+                    </Tabs.TabPane>
                 </Tabs>
             </div>
         );
@@ -0,0 +1,76 @@
+import React, { useEffect, useRef, useState } from 'react';
+import { Button } from 'antd';
+import { ReloadOutlined } from '@ant-design/icons';
+import mpegtsjs from 'mpegts.js';
+
+interface LivePreviewProps {
+  liveId: string;
+  // flv：FLV 直播流；ts：HLS 直播流的 MPEG-TS 分段拼接成的连续流
+  format: 'flv' | 'ts';
+}
+
+// 预览接口返回的 HTTP 状态码对应的提示
+const STATUS_MESSAGES: { [code: number]: string } = {
+  403: '直播预览未启用，请在配置文件中设置 preview.enable: true',
+  404: '该直播间未在录制中',
+  409: '当前录制不支持预览（HLS 直播流仅支持 MPEG-TS 分段）',
+  429: '预览人数已达上限',
+  503: '录制已结束',
+};
+
+// 录制中直播的画面预览，数据由录制器转发，不会额外请求 CDN
+const LivePreview: React.FC<LivePreviewProps> = ({ liveId, format }) => {
+  const videoRef = useRef<HTMLVideoElement>(null);
+  const [error, setError] = useState<string>('');
+  const [reloadKey, setReloadKey] = useState(0);
+
+  useEffect(() => {
+    if (!videoRef.current) return;
+    if (!mpegtsjs.isSupported()) {
+      setError('当前浏览器不支持播放该预览');
+      return;
+    }
+    setError('');
+    const player = mpegtsjs.createPlayer({
+      type: format === 'ts' ? 'mpegts' : 'flv',
+      isLive: true,
+      url: `api/lives/${encodeURIComponent(liveId)}/preview.${format}`,
+    }, {
+      enableStashBuffer: false,
+      liveBufferLatencyChasing: true,
+    });
+    player.on(mpegtsjs.Events.ERROR, (type: string, detail: string, info: any) => {
+      setError(STATUS_MESSAGES[info?.code] || `预览中断: ${detail || type}`);
+    });
+    player.attachMediaElement(videoRef.current);
+    player.load();
+    const playPromise = player.play() as Promise<void> | undefined;
+    playPromise?.catch(() => { /* 自动播放被浏览器阻止时由用户手动点击播放 */ });
+    return () => {
+      player.destroy();
+    };
+  }, [liveId, format, reloadKey]);
+
+  return (
+    <div style={{ padding: '12px 16px' }}>
+      <video
+        ref={videoRef}
+        controls
+        muted
+        style={{ width: '100%', maxHeight: 480, background: '#000', borderRadius: 4 }}
+      />
+      <div style={{ marginTop: 8, display: 'flex', alignItems: 'center', gap: 12 }}>
+        <Button size="small" icon={<ReloadOutlined />} onClick={() => setReloadKey(k => k + 1)}>
+          重新连接
+        </Button>
+        {error ? (
+          <span style={{ color: '#ff4d4f', fontSize: 13 }}>{error}</span>
+        ) : (
+          <span style={{ color: '#999', fontSize: 12 }}>画面来自录制连接，延迟约为{format === 'ts' ? '一个 HLS 分段' : '一个关键帧间隔'}</span>
+        )}
+      </div>
+    </div>
+  );
+};
+
+export default LivePreview;
@@ -137,6 +137,14 @@ class API {
         return utils.requestGet(`${BASE_URL}/file/${path}`);
     }
 
+    /**
+     * 获取录制文件的播放地址、同名弹幕字幕文件和拖动预览故事板
+     * @param path 已编码的相对路径
+     */
+    getVodInfo(path: string) {
+        return utils.requestGet(`${BASE_URL}/vod/info/${path}`);
+    }
+
     /**
      * 重命名文件或文件夹
      * @param path 原路径
@@ -209,6 +217,27 @@ class API {
         return utils.requestGet(`${BASE_URL}/config/effective`);
     }
 
+    /**
+     * 获取本节点的集群状态
+     */
+    getClusterStatus() {
+        return utils.requestGet(`${BASE_URL}/cluster`);
+    }
+
+    /**
+     * 获取集群节点列表（仅 coordinator）
+     */
+    getClusterNodes() {
+        return utils.requestGet(`${BASE_URL}/cluster/nodes`);
+    }
+
+    /**
+     * 获取跨节点汇总的直播间列表（仅 coordinator）
+     */
+    getClusterLives() {
+        return utils.requestGet(`${BASE_URL}/cluster/lives`);
+    }
+
     /**
      * 获取平台统计信息
      */